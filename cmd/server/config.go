@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/subcommands"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/captcha"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
+)
+
+// newConfigCmd creates the command to check the configuration.
+func newConfigCmd() *groupCmd {
+	return &groupCmd{
+		name:     "config",
+		synopsis: "check the configuration",
+		commands: []subcommands.Command{
+			&configCheckCmd{},
+		},
+	}
+}
+
+// checkedConfig holds the services created from the config to validate it, without connecting to the database.
+type checkedConfig struct{}
+
+func newCheckedConfig(
+	_ *echo.Echo,
+	_ captcha.VerifierInterface,
+	_ webauthn.ServiceInterface,
+) *checkedConfig {
+	return &checkedConfig{}
+}
+
+type configCheckCmd struct{}
+
+func (*configCheckCmd) Name() string     { return "check" }
+func (*configCheckCmd) Synopsis() string { return "validate the configuration from the environment" }
+func (*configCheckCmd) Usage() string {
+	return "config check:\n  Validate the configuration from the environment without starting the server.\n"
+}
+func (*configCheckCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*configCheckCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	if _, err := initConfigCheck(ctx, cfg, lc); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println("config is valid")
+
+	return subcommands.ExitSuccess
+}
@@ -2,9 +2,18 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/subcommands"
 	"github.com/labstack/echo/v4"
 	oapi "github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/metrics"
 )
 
 // TODO: 1. Fix visibility of the providers init/new functions
@@ -12,30 +21,116 @@ import (
 func newServerApp(
 	server *echo.Echo,
 	handler oapi.ServerInterface,
+	m *metrics.Metrics,
 ) *serverApp {
 	return &serverApp{
 		Server:  server,
 		Handler: handler,
+		Metrics: m,
 	}
 }
 
 type serverApp struct {
 	Server  *echo.Echo
 	Handler oapi.ServerInterface
+	Metrics *metrics.Metrics
 }
 
+// configFile is the path to the YAML config file, set with the -config flag or CONFIG_FILE env variable.
+var configFile string //nolint:gochecknoglobals // global flag shared by the commands
+
 func main() {
-	cfg := config.NewConfigFromEnv()
+	flag.StringVar(&configFile, "config", os.Getenv("CONFIG_FILE"), "path to the YAML config file")
+
+	subcommands.Register(subcommands.HelpCommand(), "")
+	subcommands.Register(subcommands.CommandsCommand(), "")
+	subcommands.Register(&serveCmd{}, "")
+	subcommands.Register(newMigrateCmd(), "")
+	subcommands.Register(newUserCmd(), "admin")
+	subcommands.Register(newPostCmd(), "admin")
+	subcommands.Register(newSubscribersCmd(), "admin")
+	subcommands.Register(newNewsletterCmd(), "admin")
+	subcommands.Register(newConfigCmd(), "")
+
+	flag.Parse()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	app, err := initApp(ctx, cfg)
+	os.Exit(int(subcommands.Execute(ctx)))
+}
+
+// loadConfig reads and validates the Config from the config file and the environment variables
+// and sets up the default logger from it.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+	logging.Setup(cfg)
+
+	return cfg, nil
+}
+
+// fail prints the error to stderr and returns subcommands.ExitFailure.
+func fail(err error) subcommands.ExitStatus {
+	fmt.Fprintln(os.Stderr, err)
+	return subcommands.ExitFailure
+}
+
+// writeOutput calls the write with the file, or with stdout if the file is empty.
+// The file is closed before returning, so the errors of the buffered writes are not lost.
+func writeOutput(file string, write func(w io.Writer) error) (err error) {
+	if file == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(file)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = fmt.Errorf("close %s: %w", file, e)
+		}
+	}()
+
+	return write(f)
+}
+
+// shutdown stops the subsystems started by the command, e.g. closes the database connection.
+func shutdown(lc *lifecycle.Manager) {
+	if err := lc.Shutdown(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// groupCmd is the command with its own subcommands, e.g. `migrate up`.
+type groupCmd struct {
+	name     string
+	synopsis string
+	commands []subcommands.Command
+}
+
+func (c *groupCmd) Name() string             { return c.name }
+func (c *groupCmd) Synopsis() string         { return c.synopsis }
+func (c *groupCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (c *groupCmd) Usage() string {
+	usage := c.name + " <subcommand>:\n  " + c.synopsis + "\n\nSubcommands:\n"
+	for _, cmd := range c.commands {
+		usage += fmt.Sprintf("  %-16s %s\n", cmd.Name(), cmd.Synopsis())
 	}
 
-	oapi.RegisterHandlers(app.Server, app.Handler)
+	return usage
+}
+
+func (c *groupCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	commander := subcommands.NewCommander(f, c.name)
+	commander.Register(commander.HelpCommand(), "")
+	for _, cmd := range c.commands {
+		commander.Register(cmd, "")
+	}
 
-	app.Server.Logger.Fatal(app.Server.Start(":" + cfg.Port))
+	return commander.Execute(ctx, args...)
 }
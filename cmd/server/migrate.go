@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// newMigrateCmd creates the command to manage the database schema migrations, e.g. `migrate up` before deploying.
+func newMigrateCmd() *groupCmd {
+	return &groupCmd{
+		name:     "migrate",
+		synopsis: "manage the database schema migrations",
+		commands: []subcommands.Command{
+			&migrateUpCmd{},
+			&migrateDownCmd{},
+			&migrateStatusCmd{},
+			&migrateForceCmd{},
+		},
+	}
+}
+
+// newMigrator connects to the database from the DSN of the config. The connection is closed by the returned Manager.
+// Note: the migrations need only the DSN, so the rest of the config is not validated.
+func newMigrator() (*db.Migrator, *lifecycle.Manager, error) {
+	cfg, err := config.Read(configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.DSN == "" {
+		return nil, nil, fmt.Errorf("dsn (DSN) is required")
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	migrator, err := initMigrator(cfg.DSN, lc)
+	if err != nil {
+		shutdown(lc)
+		return nil, nil, err
+	}
+
+	return migrator, lc, nil
+}
+
+type migrateUpCmd struct{}
+
+func (*migrateUpCmd) Name() string             { return "up" }
+func (*migrateUpCmd) Synopsis() string         { return "apply all pending migrations" }
+func (*migrateUpCmd) Usage() string            { return "migrate up:\n  Apply all pending migrations.\n" }
+func (*migrateUpCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*migrateUpCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	migrator, lc, err := newMigrator()
+	if err != nil {
+		return fail(err)
+	}
+	defer shutdown(lc)
+
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("no pending migrations")
+	}
+
+	return subcommands.ExitSuccess
+}
+
+type migrateDownCmd struct {
+	steps int
+}
+
+func (*migrateDownCmd) Name() string     { return "down" }
+func (*migrateDownCmd) Synopsis() string { return "revert the last applied migrations" }
+func (*migrateDownCmd) Usage() string {
+	return "migrate down [-steps N]:\n  Revert the last N applied migrations.\n"
+}
+
+func (c *migrateDownCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&c.steps, "steps", 1, "number of migrations to revert")
+}
+
+func (c *migrateDownCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	migrator, lc, err := newMigrator()
+	if err != nil {
+		return fail(err)
+	}
+	defer shutdown(lc)
+
+	reverted, err := migrator.Down(ctx, c.steps)
+	for _, migration := range reverted {
+		fmt.Printf("reverted %d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
+
+type migrateStatusCmd struct{}
+
+func (*migrateStatusCmd) Name() string     { return "status" }
+func (*migrateStatusCmd) Synopsis() string { return "print the state of all migrations" }
+func (*migrateStatusCmd) Usage() string {
+	return "migrate status:\n  Print the state of all migrations.\n"
+}
+func (*migrateStatusCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*migrateStatusCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	migrator, lc, err := newMigrator()
+	if err != nil {
+		return fail(err)
+	}
+	defer shutdown(lc)
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Dirty:
+			state = "dirty"
+		case status.Applied:
+			state = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+
+		fmt.Printf("%06d_%s\t%s\n", status.Version, status.Name, state)
+	}
+
+	return subcommands.ExitSuccess
+}
+
+type migrateForceCmd struct {
+	version int64
+	applied bool
+}
+
+func (*migrateForceCmd) Name() string { return "force" }
+func (*migrateForceCmd) Synopsis() string {
+	return "mark the migration as applied or not applied without running it"
+}
+func (*migrateForceCmd) Usage() string {
+	return "migrate force -version N [-applied=false]:\n" +
+		"  Mark the migration as applied (or not applied) after the dirty migration was fixed manually.\n"
+}
+
+func (c *migrateForceCmd) SetFlags(f *flag.FlagSet) {
+	f.Int64Var(&c.version, "version", 0, "version of the migration")
+	f.BoolVar(&c.applied, "applied", true, "whether the migration is applied")
+}
+
+func (c *migrateForceCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.version == 0 {
+		fmt.Fprintln(os.Stderr, "-version is required")
+		return subcommands.ExitUsageError
+	}
+
+	migrator, lc, err := newMigrator()
+	if err != nil {
+		return fail(err)
+	}
+	defer shutdown(lc)
+
+	if err := migrator.Force(ctx, c.version, c.applied); err != nil {
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// newNewsletterCmd creates the command to send the posts to the subscribers.
+func newNewsletterCmd() *groupCmd {
+	return &groupCmd{
+		name:     "newsletter",
+		synopsis: "send the posts to the subscribers",
+		commands: []subcommands.Command{
+			&newsletterSendCmd{},
+		},
+	}
+}
+
+type newsletterSendCmd struct{}
+
+func (*newsletterSendCmd) Name() string     { return "send" }
+func (*newsletterSendCmd) Synopsis() string { return "send the post to all confirmed subscribers" }
+func (*newsletterSendCmd) Usage() string {
+	return "newsletter send <slug>:\n  Send the post to all confirmed subscribers. Each post can be sent only once.\n"
+}
+func (*newsletterSendCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*newsletterSendCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "post slug is required")
+		return subcommands.ExitUsageError
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	service, err := initNewsletter(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	sent, err := service.SendPost(ctx, f.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("sent %s to %d subscribers\n", f.Arg(0), sent)
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// postsExportPageSize is the number of posts loaded from the database at once during the export.
+const postsExportPageSize = 100
+
+// newPostCmd creates the command to import, export and rename the posts.
+func newPostCmd() *groupCmd {
+	return &groupCmd{
+		name:     "post",
+		synopsis: "import, export and rename the posts",
+		commands: []subcommands.Command{
+			&postImportCmd{},
+			&postExportCmd{},
+			&postRenameCmd{},
+		},
+	}
+}
+
+// postFile is the post in the import/export JSON file.
+type postFile struct {
+	Slug                string     `json:"slug"`
+	Title               string     `json:"title"`
+	Description         string     `json:"description"`
+	Keywords            []string   `json:"keywords"`
+	Content             string     `json:"content"`
+	CreatedAt           time.Time  `json:"created_at"`
+	SentToSubscribersAt *time.Time `json:"sent_to_subscribers_at,omitempty"`
+}
+
+type postImportCmd struct {
+	file   string
+	author string
+}
+
+func (*postImportCmd) Name() string     { return "import" }
+func (*postImportCmd) Synopsis() string { return "import the posts from the JSON file" }
+func (*postImportCmd) Usage() string {
+	return "post import -author EXTERNAL_ID [-file posts.json]:\n" +
+		"  Import the posts from the JSON file (or stdin) created by `post export`.\n" +
+		"  Posts with the existing slug are skipped.\n"
+}
+
+func (c *postImportCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.file, "file", "", "path to the JSON file, stdin if empty")
+	f.StringVar(&c.author, "author", "", "external ID of the author of the imported posts")
+}
+
+func (c *postImportCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.author == "" {
+		fmt.Fprintln(os.Stderr, "-author is required")
+		return subcommands.ExitUsageError
+	}
+
+	var r io.Reader = os.Stdin
+	if c.file != "" {
+		f, err := os.Open(c.file)
+		if err != nil {
+			return fail(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var posts []postFile
+	if err := json.NewDecoder(r).Decode(&posts); err != nil {
+		return fail(fmt.Errorf("decode posts: %w", err))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	database, err := initDatabase(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	author, err := database.Models().Users().GetByExternalID(ctx, c.author)
+	if err != nil {
+		return fail(fmt.Errorf("get author: %w", err))
+	}
+
+	var imported int
+	for _, p := range posts {
+		post := models.Post{
+			UserID:      author.ID,
+			Slug:        p.Slug,
+			Title:       p.Title,
+			Description: p.Description,
+			Keywords:    strings.Join(p.Keywords, ","),
+			Content:     p.Content,
+			CreatedAt:   p.CreatedAt,
+		}
+		// Note: keep the time of sending, so the imported posts are not sent to subscribers again
+		if p.SentToSubscribersAt != nil {
+			post.SentToSubscribersAt = *p.SentToSubscribersAt
+		}
+
+		if err := database.Models().Posts().Create(ctx, &post); err != nil {
+			if errors.Is(err, models.ErrDuplicate) {
+				fmt.Printf("skipped %s: already exists\n", p.Slug)
+				continue
+			}
+
+			return fail(fmt.Errorf("import %s: %w", p.Slug, err))
+		}
+
+		imported++
+	}
+
+	fmt.Printf("imported %d of %d posts\n", imported, len(posts))
+
+	return subcommands.ExitSuccess
+}
+
+type postExportCmd struct {
+	file string
+}
+
+func (*postExportCmd) Name() string     { return "export" }
+func (*postExportCmd) Synopsis() string { return "export all posts to the JSON file" }
+func (*postExportCmd) Usage() string {
+	return "post export [-file posts.json]:\n  Export all posts to the JSON file (or stdout).\n"
+}
+
+func (c *postExportCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.file, "file", "", "path to the JSON file, stdout if empty")
+}
+
+func (c *postExportCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	database, err := initDatabase(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	posts := make([]postFile, 0)
+	for page := 1; ; page++ {
+		items, err := database.Models().Posts().FindAll(ctx, page, postsExportPageSize)
+		if err != nil {
+			return fail(err)
+		}
+
+		for _, item := range items {
+			// Note: FindAll doesn't select the content
+			post, err := database.Models().Posts().GetBySlug(ctx, item.Slug)
+			if err != nil {
+				return fail(fmt.Errorf("export %s: %w", item.Slug, err))
+			}
+
+			p := postFile{
+				Slug:        post.Slug,
+				Title:       post.Title,
+				Description: post.Description,
+				Keywords:    []string{},
+				Content:     post.Content,
+				CreatedAt:   post.CreatedAt,
+			}
+			if post.Keywords != "" {
+				p.Keywords = strings.Split(post.Keywords, ",")
+			}
+			if !post.SentToSubscribersAt.IsZero() {
+				p.SentToSubscribersAt = &post.SentToSubscribersAt
+			}
+
+			posts = append(posts, p)
+		}
+
+		if len(items) < postsExportPageSize {
+			break
+		}
+	}
+
+	err = writeOutput(c.file, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(posts)
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
+
+type postRenameCmd struct {
+	slug string
+	to   string
+}
+
+func (*postRenameCmd) Name() string     { return "rename" }
+func (*postRenameCmd) Synopsis() string { return "change the URL slug of the post" }
+func (*postRenameCmd) Usage() string {
+	return "post rename -slug SLUG -to NEW_SLUG:\n" +
+		"  Change the URL slug of the post, e.g. of the post created before its slug was reserved.\n" +
+		"  The old URL of the post stops working, run it with the swapped slugs to revert.\n"
+}
+
+func (c *postRenameCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.slug, "slug", "", "current slug of the post")
+	f.StringVar(&c.to, "to", "", "new slug of the post")
+}
+
+func (c *postRenameCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.slug == "" || c.to == "" {
+		fmt.Fprintln(os.Stderr, "-slug and -to are required")
+		return subcommands.ExitUsageError
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	database, err := initDatabase(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	post, err := database.Models().Posts().GetBySlug(ctx, c.slug)
+	if err != nil {
+		return fail(fmt.Errorf("get post %s: %w", c.slug, err))
+	}
+
+	post.Slug = c.to
+	if err := database.Models().Posts().Update(ctx, post); err != nil {
+		return fail(fmt.Errorf("rename %s: %w", c.slug, err))
+	}
+
+	fmt.Printf("renamed post %d from %s to %s, revert with `post rename -slug %s -to %s`\n",
+		post.ID, c.slug, c.to, c.to, c.slug)
+	// Note: the reserved slug is allowed, so the rename can be reverted
+	if c.to == models.ReservedSlug {
+		fmt.Fprintf(os.Stderr, "warning: slug %q is reserved, the post is not reachable by its URL\n", c.to)
+	}
+
+	return subcommands.ExitSuccess
+}
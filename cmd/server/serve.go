@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/google/subcommands"
+	oapi "github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+type serveCmd struct{}
+
+func (*serveCmd) Name() string     { return "serve" }
+func (*serveCmd) Synopsis() string { return "start the HTTP server" }
+func (*serveCmd) Usage() string {
+	return "serve:\n  Start the HTTP server on the PORT until SIGINT or SIGTERM, then drain the requests and shut down.\n"
+}
+func (*serveCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*serveCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+
+	app, err := initApp(ctx, cfg, lc)
+	if err != nil {
+		// Note: close the subsystems created before the failure, e.g. the database connection
+		shutdown(lc)
+		return fail(err)
+	}
+
+	oapi.RegisterHandlers(app.Server, app.Handler)
+
+	if err := app.Metrics.ListenAndServe(lc); err != nil {
+		shutdown(lc)
+		return fail(err)
+	}
+
+	if err := lc.Run(ctx, func() error { return app.Server.Start(":" + cfg.Port) }); err != nil {
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// newSubscribersCmd creates the command to manage the subscribers.
+func newSubscribersCmd() *groupCmd {
+	return &groupCmd{
+		name:     "subscribers",
+		synopsis: "manage the subscribers",
+		commands: []subcommands.Command{
+			&subscribersExportCmd{},
+		},
+	}
+}
+
+type subscribersExportCmd struct {
+	file string
+}
+
+func (*subscribersExportCmd) Name() string { return "export" }
+func (*subscribersExportCmd) Synopsis() string {
+	return "export the confirmed subscribers to the CSV file"
+}
+func (*subscribersExportCmd) Usage() string {
+	return "subscribers export [-file subscribers.csv]:\n" +
+		"  Export the confirmed subscribers to the CSV file (or stdout) with id, email and created_at columns.\n"
+}
+
+func (c *subscribersExportCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.file, "file", "", "path to the CSV file, stdout if empty")
+}
+
+func (c *subscribersExportCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	database, err := initDatabase(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	subs, err := database.Models().Subscribers().GetConfirmed(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	err = writeOutput(c.file, func(w io.Writer) error {
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "email", "created_at"}); err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if err := cw.Write([]string{sub.ID.String(), sub.Email, sub.CreatedAt.Format(time.RFC3339)}); err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// newUserCmd creates the command to manage the users.
+func newUserCmd() *groupCmd {
+	return &groupCmd{
+		name:     "user",
+		synopsis: "manage the users",
+		commands: []subcommands.Command{
+			&userCreateAdminCmd{},
+		},
+	}
+}
+
+type userCreateAdminCmd struct {
+	externalID string
+	authMethod string
+	login      string
+}
+
+func (*userCreateAdminCmd) Name() string { return "create-admin" }
+func (*userCreateAdminCmd) Synopsis() string {
+	return "invite a new admin or promote the existing user"
+}
+func (*userCreateAdminCmd) Usage() string {
+	return "user create-admin -external-id ID [-auth-method github|oidc|email] [-login LOGIN]:\n" +
+		"  Invite a new admin, who is activated on the first sign in, or promote the existing user to admin.\n"
+}
+
+func (c *userCreateAdminCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.externalID, "external-id", "", "ID of the user in the auth method, e.g. GitHub user ID or email")
+	f.StringVar(&c.authMethod, "auth-method", string(models.GitHubAuthMethod), "auth method of the user")
+	f.StringVar(&c.login, "login", "", "login of the user, updated on the first sign in")
+}
+
+func (c *userCreateAdminCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.externalID == "" {
+		fmt.Fprintln(os.Stderr, "-external-id is required")
+		return subcommands.ExitUsageError
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail(err)
+	}
+
+	lc := lifecycle.NewManager(cfg.ShutdownTimeout)
+	defer shutdown(lc)
+
+	database, err := initDatabase(ctx, cfg, lc)
+	if err != nil {
+		return fail(err)
+	}
+
+	user, err := models.NewInvitedUser(models.AuthMethod(c.authMethod), c.externalID, c.login, models.AdminUserRole)
+	if err != nil {
+		return fail(err)
+	}
+
+	existing, err := database.Models().Users().GetByExternalID(ctx, user.ExternalID)
+	switch {
+	case err == nil:
+		existing.Role = models.AdminUserRole
+		if err := database.Models().Users().Update(ctx, existing); err != nil {
+			return fail(err)
+		}
+
+		fmt.Printf("promoted user %d (%s) to admin, status: %s\n", existing.ID, existing.Login, existing.Status)
+	case errors.Is(err, models.ErrNotFound):
+		if err := database.Models().Users().Create(ctx, user); err != nil {
+			return fail(err)
+		}
+
+		fmt.Printf("invited admin %d (%s)\n", user.ID, user.ExternalID)
+	default:
+		return fail(err)
+	}
+
+	return subcommands.ExitSuccess
+}
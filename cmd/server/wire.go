@@ -6,26 +6,42 @@ package main
 
 import (
 	"context"
-	_ "github.com/google/subcommands" //nolint:goimports // required by Wire
 	"github.com/google/wire"
 
 	"github.com/samgozman/go-bloggy/internal/captcha"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/db/cache"
 	"github.com/samgozman/go-bloggy/internal/github"
 	"github.com/samgozman/go-bloggy/internal/handler"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"github.com/samgozman/go-bloggy/internal/jwt"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
 	"github.com/samgozman/go-bloggy/internal/mailer"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/newsletter"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	"github.com/samgozman/go-bloggy/internal/server"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
 )
 
-func initApp(ctx context.Context, cfg *config.Config) (*serverApp, error) {
+func initApp(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*serverApp, error) {
 	wire.Build(
+		health.ProviderSet,
+		metrics.ProviderSet,
+		tracing.ProviderSet,
 		db.ProviderSet,
+		cache.ProviderSet,
 		github.ProviderSet,
 		jwt.ProviderSet,
 		captcha.ProviderSet,
 		mailer.ProviderSet,
+		newsletter.ProviderSet,
+		oidc.ProviderSet,
+		webauthn.ProviderSet,
+		ratelimit.ProviderSet,
 		server.ProviderSet,
 		handler.ProviderSet,
 
@@ -34,3 +50,60 @@ func initApp(ctx context.Context, cfg *config.Config) (*serverApp, error) {
 
 	return &serverApp{}, nil
 }
+
+func initMigrator(dsn config.DSN, lc *lifecycle.Manager) (*db.Migrator, error) {
+	wire.Build(
+		db.ProvideMigrator,
+	)
+
+	return &db.Migrator{}, nil
+}
+
+func initDatabase(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*db.Database, error) {
+	wire.Build(
+		health.ProviderSet,
+		metrics.ProviderSet,
+		tracing.ProviderSet,
+		db.ProviderSet,
+		cache.CommandProviderSet,
+	)
+
+	return &db.Database{}, nil
+}
+
+func initNewsletter(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*newsletter.Service, error) {
+	wire.Build(
+		health.ProviderSet,
+		metrics.ProviderSet,
+		tracing.ProviderSet,
+		db.ProviderSet,
+		cache.CommandProviderSet,
+		mailer.ProviderSet,
+		newsletter.ProviderSet,
+	)
+
+	return &newsletter.Service{}, nil
+}
+
+func initConfigCheck(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*checkedConfig, error) {
+	wire.Build(
+		metrics.ProviderSet,
+		tracing.ProviderSet,
+		jwt.ProviderSet,
+		captcha.ProviderSet,
+		server.ProviderSet,
+		// Note: the used WebAuthn challenges are not needed to validate the config
+		webauthn.ProvideConfig,
+		webauthn.ProvideService,
+		webauthn.NewMemoryChallengeStore,
+		wire.Bind(new(webauthn.ChallengeStore), new(*webauthn.MemoryChallengeStore)),
+		// Note: the rate limits are checked against the spec with the memory store, without connecting to the database
+		ratelimit.ProvideConfig,
+		ratelimit.NewMemoryStore,
+		wire.Bind(new(ratelimit.Store), new(*ratelimit.MemoryStore)),
+
+		newCheckedConfig,
+	)
+
+	return &checkedConfig{}, nil
+}
@@ -11,42 +11,169 @@ import (
 	"github.com/samgozman/go-bloggy/internal/captcha"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/db/cache"
 	"github.com/samgozman/go-bloggy/internal/github"
 	"github.com/samgozman/go-bloggy/internal/handler"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"github.com/samgozman/go-bloggy/internal/jwt"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
 	"github.com/samgozman/go-bloggy/internal/mailer"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/newsletter"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	"github.com/samgozman/go-bloggy/internal/server"
-)
-
-import (
-	_ "github.com/google/subcommands"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
 )
 
 // Injectors from wire.go:
 
-func initApp(ctx context.Context, cfg *config.Config) (*serverApp, error) {
+func initApp(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*serverApp, error) {
 	serverConfig := server.ProvideConfig(cfg)
 	jwtSecretKey := jwt.ProvideJWTSecretKey(cfg)
 	service := jwt.ProvideService(jwtSecretKey)
-	echo := server.ProvideServer(serverConfig, service)
+	metricsConfig := metrics.ProvideConfig(cfg)
+	metricsMetrics := metrics.ProvideMetrics(metricsConfig)
+	tracingConfig := tracing.ProvideConfig(cfg)
+	tracerProvider, err := tracing.ProvideTracerProvider(ctx, tracingConfig, lc)
+	if err != nil {
+		return nil, err
+	}
+	ratelimitConfig := ratelimit.ProvideConfig(cfg)
+	dsn := db.ProvideDSN(cfg)
+	dbAutoMigrate := db.ProvideAutoMigrate(cfg)
+	gormDB, err := db.ProvideConnection(ctx, dsn, dbAutoMigrate, lc)
+	if err != nil {
+		return nil, err
+	}
+	cacheConfig := cache.ProvideConfig(cfg)
+	postRepositoryInterface := cache.ProvidePostRepository(cacheConfig, gormDB, dsn, metricsMetrics, lc)
+	models := db.ProvideModels(gormDB, postRepositoryInterface, metricsMetrics)
+	store, err := ratelimit.ProvideStore(ratelimitConfig, models)
+	if err != nil {
+		return nil, err
+	}
+	echo, err := server.ProvideServer(serverConfig, service, lc, metricsMetrics, tracerProvider, ratelimitConfig, store)
+	if err != nil {
+		return nil, err
+	}
 	handlerConfig := handler.ProvideConfig(cfg)
 	githubConfig := github.ProvideConfig(cfg)
-	githubService := github.ProvideService(githubConfig)
-	dsn := db.ProvideDSN(cfg)
-	gormDB, err := db.ProvideConnection(dsn)
+	githubService := github.ProvideService(githubConfig, tracerProvider)
+	healthService := health.ProvideService()
+	database, err := db.ProvideDatabase(gormDB, models, healthService, metricsMetrics, tracerProvider)
 	if err != nil {
 		return nil, err
 	}
-	models := db.ProvideModels(gormDB)
-	database, err := db.ProvideDatabase(gormDB, models)
+	captchaConfig := captcha.ProvideConfig(cfg)
+	proofOfWorkInterface := captcha.ProvideProofOfWork(captchaConfig)
+	verifierInterface, err := captcha.ProvideVerifier(captchaConfig, proofOfWorkInterface, tracerProvider)
 	if err != nil {
 		return nil, err
 	}
-	hCaptchaSecret := captcha.ProvideHCaptchaSecret(cfg)
-	v := captcha.ProvideClient(hCaptchaSecret)
 	mailerConfig := mailer.ProvideConfig(cfg)
-	mailerService := mailer.ProvideService(mailerConfig)
-	handlerHandler := handler.ProvideHandler(handlerConfig, githubService, service, database, v, mailerService)
-	mainServerApp := newServerApp(echo, handlerHandler)
+	mailerService := mailer.ProvideService(mailerConfig, healthService, metricsMetrics, tracerProvider)
+	oidcConfig := oidc.ProvideConfig(cfg)
+	oidcService := oidc.ProvideService(oidcConfig)
+	webauthnConfig := webauthn.ProvideConfig(cfg)
+	challengeStore := webauthn.ProvideChallengeStore(models)
+	webauthnService, err := webauthn.ProvideService(webauthnConfig, challengeStore)
+	if err != nil {
+		return nil, err
+	}
+	newsletterService := newsletter.ProvideService(models, mailerService)
+	handlerHandler := handler.ProvideHandler(handlerConfig, githubService, service, database, verifierInterface, mailerService, oidcService, webauthnService, proofOfWorkInterface, newsletterService, healthService, metricsMetrics, store)
+	mainServerApp := newServerApp(echo, handlerHandler, metricsMetrics)
 	return mainServerApp, nil
 }
+
+func initMigrator(dsn config.DSN, lc *lifecycle.Manager) (*db.Migrator, error) {
+	migrator, err := db.ProvideMigrator(dsn, lc)
+	if err != nil {
+		return nil, err
+	}
+	return migrator, nil
+}
+
+func initDatabase(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*db.Database, error) {
+	dsn := db.ProvideDSN(cfg)
+	dbAutoMigrate := db.ProvideAutoMigrate(cfg)
+	gormDB, err := db.ProvideConnection(ctx, dsn, dbAutoMigrate, lc)
+	if err != nil {
+		return nil, err
+	}
+	cacheConfig := cache.ProvideConfig(cfg)
+	metricsConfig := metrics.ProvideConfig(cfg)
+	metricsMetrics := metrics.ProvideMetrics(metricsConfig)
+	postRepositoryInterface := cache.ProvideCommandPostRepository(cacheConfig, gormDB, dsn, metricsMetrics)
+	models := db.ProvideModels(gormDB, postRepositoryInterface, metricsMetrics)
+	healthService := health.ProvideService()
+	tracingConfig := tracing.ProvideConfig(cfg)
+	tracerProvider, err := tracing.ProvideTracerProvider(ctx, tracingConfig, lc)
+	if err != nil {
+		return nil, err
+	}
+	database, err := db.ProvideDatabase(gormDB, models, healthService, metricsMetrics, tracerProvider)
+	if err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+func initNewsletter(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*newsletter.Service, error) {
+	dsn := db.ProvideDSN(cfg)
+	dbAutoMigrate := db.ProvideAutoMigrate(cfg)
+	gormDB, err := db.ProvideConnection(ctx, dsn, dbAutoMigrate, lc)
+	if err != nil {
+		return nil, err
+	}
+	cacheConfig := cache.ProvideConfig(cfg)
+	metricsConfig := metrics.ProvideConfig(cfg)
+	metricsMetrics := metrics.ProvideMetrics(metricsConfig)
+	postRepositoryInterface := cache.ProvideCommandPostRepository(cacheConfig, gormDB, dsn, metricsMetrics)
+	models := db.ProvideModels(gormDB, postRepositoryInterface, metricsMetrics)
+	mailerConfig := mailer.ProvideConfig(cfg)
+	healthService := health.ProvideService()
+	tracingConfig := tracing.ProvideConfig(cfg)
+	tracerProvider, err := tracing.ProvideTracerProvider(ctx, tracingConfig, lc)
+	if err != nil {
+		return nil, err
+	}
+	mailerService := mailer.ProvideService(mailerConfig, healthService, metricsMetrics, tracerProvider)
+	newsletterService := newsletter.ProvideService(models, mailerService)
+	return newsletterService, nil
+}
+
+func initConfigCheck(ctx context.Context, cfg *config.Config, lc *lifecycle.Manager) (*checkedConfig, error) {
+	serverConfig := server.ProvideConfig(cfg)
+	jwtSecretKey := jwt.ProvideJWTSecretKey(cfg)
+	service := jwt.ProvideService(jwtSecretKey)
+	metricsConfig := metrics.ProvideConfig(cfg)
+	metricsMetrics := metrics.ProvideMetrics(metricsConfig)
+	tracingConfig := tracing.ProvideConfig(cfg)
+	tracerProvider, err := tracing.ProvideTracerProvider(ctx, tracingConfig, lc)
+	if err != nil {
+		return nil, err
+	}
+	ratelimitConfig := ratelimit.ProvideConfig(cfg)
+	memoryStore := ratelimit.NewMemoryStore()
+	echo, err := server.ProvideServer(serverConfig, service, lc, metricsMetrics, tracerProvider, ratelimitConfig, memoryStore)
+	if err != nil {
+		return nil, err
+	}
+	captchaConfig := captcha.ProvideConfig(cfg)
+	proofOfWorkInterface := captcha.ProvideProofOfWork(captchaConfig)
+	verifierInterface, err := captcha.ProvideVerifier(captchaConfig, proofOfWorkInterface, tracerProvider)
+	if err != nil {
+		return nil, err
+	}
+	webauthnConfig := webauthn.ProvideConfig(cfg)
+	memoryChallengeStore := webauthn.NewMemoryChallengeStore()
+	webauthnService, err := webauthn.ProvideService(webauthnConfig, memoryChallengeStore)
+	if err != nil {
+		return nil, err
+	}
+	mainCheckedConfig := newCheckedConfig(echo, verifierInterface, webauthnService)
+	return mainCheckedConfig, nil
+}
@@ -19,6 +19,71 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
+)
+
+// Defines values for HealthCheckResultStatus.
+const (
+	HealthCheckDisabled HealthCheckResultStatus = "disabled"
+	HealthCheckDown     HealthCheckResultStatus = "down"
+	HealthCheckUp       HealthCheckResultStatus = "up"
+)
+
+// Defines values for ReadinessResponseStatus.
+const (
+	ReadinessFail ReadinessResponseStatus = "FAIL"
+	ReadinessOK   ReadinessResponseStatus = "OK"
+)
+
+// Defines values for UserRole.
+const (
+	Admin  UserRole = "admin"
+	Author UserRole = "author"
+)
+
+// Defines values for UserStatus.
+const (
+	Active   UserStatus = "active"
+	Disabled UserStatus = "disabled"
+	Invited  UserStatus = "invited"
+)
+
+// Defines values for GetPostsParamsSort.
+const (
+	GetPostsParamsSortNewest GetPostsParamsSort = "newest"
+	GetPostsParamsSortOldest GetPostsParamsSort = "oldest"
+)
+
+// Defines values for GetPostsArchiveYearMonthParamsSort.
+const (
+	GetPostsArchiveYearMonthParamsSortNewest GetPostsArchiveYearMonthParamsSort = "newest"
+	GetPostsArchiveYearMonthParamsSortOldest GetPostsArchiveYearMonthParamsSort = "oldest"
+)
+
+// CaptchaChallengeResponse defines model for CaptchaChallengeResponse.
+type CaptchaChallengeResponse struct {
+	Algorithm string `json:"algorithm"`
+	Challenge string `json:"challenge"`
+
+	// Difficulty The number of leading zero bits of the hash
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// CaptchaVerifyRequest defines model for CaptchaVerifyRequest.
+type CaptchaVerifyRequest struct {
+	Challenge string `json:"challenge"`
+	Nonce     string `json:"nonce"`
+}
+
+// CaptchaVerifyResponse defines model for CaptchaVerifyResponse.
+type CaptchaVerifyResponse struct {
+	// Token The captcha token
+	Token string `json:"token"`
+}
+
 // ConfirmSubscriberRequest defines model for ConfirmSubscriberRequest.
 type ConfirmSubscriberRequest struct {
 	// Captcha The captcha token
@@ -35,6 +100,19 @@ type CreateSubscriberRequest struct {
 	Email   string `json:"email"`
 }
 
+// FieldError defines model for FieldError.
+type FieldError struct {
+	// Code Error code of the field, if it's known
+	Code   *string `json:"code,omitempty"`
+	Detail string  `json:"detail"`
+
+	// Parameter Name of the invalid path, query or header parameter
+	Parameter *string `json:"parameter,omitempty"`
+
+	// Pointer JSON Pointer (RFC 6901) to the invalid field of the request body
+	Pointer *string `json:"pointer,omitempty"`
+}
+
 // GitHubAuthRequestBody defines model for GitHubAuthRequestBody.
 type GitHubAuthRequestBody struct {
 	Code string `json:"code"`
@@ -45,11 +123,114 @@ type HealthCheckResponse struct {
 	Status string `json:"status"`
 }
 
+// HealthCheckResult defines model for HealthCheckResult.
+type HealthCheckResult struct {
+	// CheckedAt Time of the check, the result is cached for a few seconds
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Critical The service is not ready while the critical check is down
+	Critical bool `json:"critical"`
+
+	// Error Reason of the failed check
+	Error *string `json:"error,omitempty"`
+
+	// LatencyMs Latency of the check in milliseconds when it was performed
+	LatencyMs float64                 `json:"latency_ms"`
+	Name      string                  `json:"name"`
+	Status    HealthCheckResultStatus `json:"status"`
+}
+
+// HealthCheckResultStatus defines model for HealthCheckResult.Status.
+type HealthCheckResultStatus string
+
+// InviteUserRequest defines model for InviteUserRequest.
+type InviteUserRequest struct {
+	// AuthMethod The method of authentication used by the user
+	AuthMethod string `json:"auth_method"`
+
+	// ExternalId ID of the user in the auth method, e.g. numeric GitHub user ID
+	ExternalId string `json:"external_id"`
+
+	// Login Login of the user. It will be updated on the first sign in.
+	Login *string `json:"login,omitempty"`
+
+	// Role Role of the user. Admins can manage users, authors can only manage posts.
+	Role *UserRole `json:"role,omitempty"`
+}
+
 // JWTToken defines model for JWTToken.
 type JWTToken struct {
+	// MfaRequired If true, the token is only valid to pass the second factor
+	// via /login/webauthn/begin or /login/recovery
+	MfaRequired *bool  `json:"mfa_required,omitempty"`
+	Token       string `json:"token"`
+}
+
+// MFABeginRequestBody defines model for MFABeginRequestBody.
+type MFABeginRequestBody struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+// MFARecoveryRequestBody defines model for MFARecoveryRequestBody.
+type MFARecoveryRequestBody struct {
+	Code     string `json:"code"`
+	MfaToken string `json:"mfa_token"`
+}
+
+// MFAWebAuthnFinishRequestBody defines model for MFAWebAuthnFinishRequestBody.
+type MFAWebAuthnFinishRequestBody struct {
+	// Credential PublicKeyCredential returned by navigator.credentials.get()
+	Credential map[string]interface{} `json:"credential"`
+	MfaToken   string                 `json:"mfa_token"`
+	Session    string                 `json:"session"`
+}
+
+// MagicLinkRequestBody defines model for MagicLinkRequestBody.
+type MagicLinkRequestBody struct {
+	Email string `json:"email"`
+}
+
+// MagicLinkVerifyRequestBody defines model for MagicLinkVerifyRequestBody.
+type MagicLinkVerifyRequestBody struct {
+	// Token Token from the magic link
 	Token string `json:"token"`
 }
 
+// OIDCAuthRequestBody defines model for OIDCAuthRequestBody.
+type OIDCAuthRequestBody struct {
+	Code         string `json:"code"`
+	CodeVerifier string `json:"code_verifier"`
+	State        string `json:"state"`
+}
+
+// OIDCAuthURLResponse defines model for OIDCAuthURLResponse.
+type OIDCAuthURLResponse struct {
+	AuthorizationUrl string `json:"authorization_url"`
+
+	// CodeVerifier PKCE code verifier that has to be sent back with the code
+	CodeVerifier string `json:"code_verifier"`
+
+	// State Signed state that has to be sent back with the code
+	State string `json:"state"`
+}
+
+// PatchPostRequest JSON Merge Patch (RFC 7396) of the post, only the given fields are changed.
+// If the version is given, the post is only updated if it's not changed since.
+// The version is required, unless the If-Match header is set.
+type PatchPostRequest struct {
+	Content *string `json:"content,omitempty"`
+
+	// Description A short description of the post for the index page
+	Description *string `json:"description,omitempty"`
+
+	// Keywords Keywords for the post for SEO and search purposes, null removes all keywords
+	Keywords *[]string `json:"keywords"`
+	Title    *string   `json:"title,omitempty"`
+
+	// Version Version of the post that was read
+	Version *int `json:"version,omitempty"`
+}
+
 // PostRequest A post object to be created
 type PostRequest struct {
 	Content string `json:"content"`
@@ -78,6 +259,22 @@ type PostResponse struct {
 	Slug        string    `json:"slug"`
 	Title       string    `json:"title"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Version Version of the post, that is incremented on every update
+	Version int `json:"version"`
+}
+
+// PostsArchiveMonth defines model for PostsArchiveMonth.
+type PostsArchiveMonth struct {
+	// Count Number of the posts created in the month
+	Count int `json:"count"`
+	Month int `json:"month"`
+	Year  int `json:"year"`
+}
+
+// PostsArchiveResponse The number of the posts by the month
+type PostsArchiveResponse struct {
+	Months []PostsArchiveMonth `json:"months"`
 }
 
 // PostsListItem defines model for PostsListItem.
@@ -95,8 +292,20 @@ type PostsListItem struct {
 
 // PostsListResponse A list of posts
 type PostsListResponse struct {
+	// Next Cursor of the next page, if there is one
+	Next  *string         `json:"next,omitempty"`
+	Posts []PostsListItem `json:"posts"`
+
+	// Prev Cursor of the previous page, if there is one
+	Prev *string `json:"prev,omitempty"`
+
+	// Total Number of the posts matching the filters
+	Total int `json:"total"`
+}
+
+// PostsRelatedResponse The related posts of a post, from the most similar
+type PostsRelatedResponse struct {
 	Posts []PostsListItem `json:"posts"`
-	Total int             `json:"total"`
 }
 
 // PutPostRequest A post object to be updated
@@ -109,12 +318,48 @@ type PutPostRequest struct {
 	// Keywords Keywords for the post for SEO and search purposes
 	Keywords *[]string `json:"keywords,omitempty"`
 	Title    string    `json:"title"`
+
+	// Version Version of the post that was read, the update is rejected if the post was changed since
+	Version int `json:"version"`
 }
 
-// RequestError defines model for RequestError.
+// ReadinessResponse defines model for ReadinessResponse.
+type ReadinessResponse struct {
+	Checks []HealthCheckResult     `json:"checks"`
+	Status ReadinessResponseStatus `json:"status"`
+}
+
+// ReadinessResponseStatus defines model for ReadinessResponse.Status.
+type ReadinessResponseStatus string
+
+// RecoveryCodesResponse defines model for RecoveryCodesResponse.
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes"`
+}
+
+// RequestError Problem details of the error (RFC 9457), served as application/problem+json.
+// The code and the message are kept for backward compatibility, the message is the same as the detail.
 type RequestError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code string `json:"code"`
+
+	// CurrentVersion Current version of the resource, if the request conflicts with it
+	CurrentVersion *int `json:"current_version,omitempty"`
+
+	// Detail Explanation of this occurrence of the problem
+	Detail string `json:"detail"`
+
+	// Errors Fields of the request that are invalid
+	Errors  *[]FieldError `json:"errors,omitempty"`
+	Message string        `json:"message"`
+
+	// Status HTTP status code of the response
+	Status int `json:"status"`
+
+	// Title Summary of the problem type, the reason phrase of the status
+	Title string `json:"title"`
+
+	// Type URI of the problem type, the same for all errors with the same code
+	Type string `json:"type"`
 }
 
 // UnsubscribeRequest defines model for UnsubscribeRequest.
@@ -124,21 +369,146 @@ type UnsubscribeRequest struct {
 	SubscriptionId string  `json:"subscription_id"`
 }
 
+// UserResponse defines model for UserResponse.
+type UserResponse struct {
+	AuthMethod string    `json:"auth_method"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExternalId string    `json:"external_id"`
+	Id         int       `json:"id"`
+	Login      string    `json:"login"`
+
+	// Role Role of the user. Admins can manage users, authors can only manage posts.
+	Role UserRole `json:"role"`
+
+	// Status Status of the user. Invited users become active on the first sign in,
+	// disabled users are not allowed to sign in.
+	Status    UserStatus `json:"status"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// UserRole Role of the user. Admins can manage users, authors can only manage posts.
+type UserRole string
+
+// UserStatus Status of the user. Invited users become active on the first sign in,
+// disabled users are not allowed to sign in.
+type UserStatus string
+
+// UsersListResponse defines model for UsersListResponse.
+type UsersListResponse struct {
+	Users []UserResponse `json:"users"`
+}
+
+// WebAuthnCeremonyResponse defines model for WebAuthnCeremonyResponse.
+type WebAuthnCeremonyResponse struct {
+	// Options Options for navigator.credentials.create() or navigator.credentials.get()
+	Options map[string]interface{} `json:"options"`
+
+	// Session Signed session that has to be sent back with the authenticator response
+	Session string `json:"session"`
+}
+
+// WebAuthnCredentialResponse defines model for WebAuthnCredentialResponse.
+type WebAuthnCredentialResponse struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	Id         int        `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+}
+
+// WebAuthnCredentialsListResponse defines model for WebAuthnCredentialsListResponse.
+type WebAuthnCredentialsListResponse struct {
+	Credentials []WebAuthnCredentialResponse `json:"credentials"`
+}
+
+// WebAuthnRegistrationFinishRequestBody defines model for WebAuthnRegistrationFinishRequestBody.
+type WebAuthnRegistrationFinishRequestBody struct {
+	// Credential PublicKeyCredential returned by navigator.credentials.create()
+	Credential map[string]interface{} `json:"credential"`
+	Name       *string                `json:"name,omitempty"`
+	Session    string                 `json:"session"`
+}
+
 // GetPostsParams defines parameters for GetPosts.
 type GetPostsParams struct {
-	// Page Page number
+	// Page Page number, can't be used with the cursor
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
 
 	// Limit Number of items per page
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The next or the previous cursor of the previous response, the other parameters must be the same
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort Order of the posts by the created time
+	Sort *GetPostsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Keyword Only the posts with the keyword
+	Keyword *string `form:"keyword,omitempty" json:"keyword,omitempty"`
+
+	// From Only the posts created at or after the time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only the posts created before the time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Sent Only the posts that were (true) or were not (false) sent to the subscribers
+	Sent *bool `form:"sent,omitempty" json:"sent,omitempty"`
+
+	// Author Only the posts of the user with the login
+	Author *string `form:"author,omitempty" json:"author,omitempty"`
 }
 
+// GetPostsParamsSort defines parameters for GetPosts.
+type GetPostsParamsSort string
+
+// GetPostsArchiveYearMonthParams defines parameters for GetPostsArchiveYearMonth.
+type GetPostsArchiveYearMonthParams struct {
+	// Page Page number, can't be used with the cursor
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Number of items per page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The next or the previous cursor of the previous response, the other parameters must be the same
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort Order of the posts by the created time
+	Sort *GetPostsArchiveYearMonthParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// GetPostsArchiveYearMonthParamsSort defines parameters for GetPostsArchiveYearMonth.
+type GetPostsArchiveYearMonthParamsSort string
+
+// PostCaptchaVerifyJSONRequestBody defines body for PostCaptchaVerify for application/json ContentType.
+type PostCaptchaVerifyJSONRequestBody = CaptchaVerifyRequest
+
+// PostLoginEmailJSONRequestBody defines body for PostLoginEmail for application/json ContentType.
+type PostLoginEmailJSONRequestBody = MagicLinkRequestBody
+
+// PostLoginEmailVerifyJSONRequestBody defines body for PostLoginEmailVerify for application/json ContentType.
+type PostLoginEmailVerifyJSONRequestBody = MagicLinkVerifyRequestBody
+
 // PostLoginGithubAuthorizeJSONRequestBody defines body for PostLoginGithubAuthorize for application/json ContentType.
 type PostLoginGithubAuthorizeJSONRequestBody = GitHubAuthRequestBody
 
+// PostLoginRecoveryJSONRequestBody defines body for PostLoginRecovery for application/json ContentType.
+type PostLoginRecoveryJSONRequestBody = MFARecoveryRequestBody
+
+// PostLoginWebauthnBeginJSONRequestBody defines body for PostLoginWebauthnBegin for application/json ContentType.
+type PostLoginWebauthnBeginJSONRequestBody = MFABeginRequestBody
+
+// PostLoginWebauthnFinishJSONRequestBody defines body for PostLoginWebauthnFinish for application/json ContentType.
+type PostLoginWebauthnFinishJSONRequestBody = MFAWebAuthnFinishRequestBody
+
+// PostLoginProviderAuthorizeJSONRequestBody defines body for PostLoginProviderAuthorize for application/json ContentType.
+type PostLoginProviderAuthorizeJSONRequestBody = OIDCAuthRequestBody
+
 // PostPostsJSONRequestBody defines body for PostPosts for application/json ContentType.
 type PostPostsJSONRequestBody = PostRequest
 
+// PatchPostsSlugApplicationMergePatchPlusJSONRequestBody defines body for PatchPostsSlug for application/merge-patch+json ContentType.
+type PatchPostsSlugApplicationMergePatchPlusJSONRequestBody = PatchPostRequest
+
 // PutPostsSlugJSONRequestBody defines body for PutPostsSlug for application/json ContentType.
 type PutPostsSlugJSONRequestBody = PutPostRequest
 
@@ -151,29 +521,80 @@ type PostSubscribersJSONRequestBody = CreateSubscriberRequest
 // PostSubscribersConfirmJSONRequestBody defines body for PostSubscribersConfirm for application/json ContentType.
 type PostSubscribersConfirmJSONRequestBody = ConfirmSubscriberRequest
 
+// PostUsersJSONRequestBody defines body for PostUsers for application/json ContentType.
+type PostUsersJSONRequestBody = InviteUserRequest
+
+// PostUsersMeWebauthnCredentialsFinishJSONRequestBody defines body for PostUsersMeWebauthnCredentialsFinish for application/json ContentType.
+type PostUsersMeWebauthnCredentialsFinishJSONRequestBody = WebAuthnRegistrationFinishRequestBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Issue proof-of-work challenge
+	// (POST /captcha/challenge)
+	PostCaptchaChallenge(ctx echo.Context) error
+	// Verify proof-of-work solution
+	// (POST /captcha/verify)
+	PostCaptchaVerify(ctx echo.Context) error
 	// Health check
 	// (GET /health)
 	GetHealth(ctx echo.Context) error
+	// Liveness probe
+	// (GET /health/live)
+	GetHealthLive(ctx echo.Context) error
+	// Readiness probe
+	// (GET /health/ready)
+	GetHealthReady(ctx echo.Context) error
+	// Request a magic link to sign in by email
+	// (POST /login/email)
+	PostLoginEmail(ctx echo.Context) error
+	// Sign in with the magic link
+	// (POST /login/email/verify)
+	PostLoginEmailVerify(ctx echo.Context) error
 	// Authorize with GitHub
 	// (POST /login/github/authorize)
 	PostLoginGithubAuthorize(ctx echo.Context) error
+	// Use a recovery code as the second factor
+	// (POST /login/recovery)
+	PostLoginRecovery(ctx echo.Context) error
 	// Refresh the JWT token
 	// (POST /login/refresh)
 	PostLoginRefresh(ctx echo.Context) error
+	// Start the passkey second factor
+	// (POST /login/webauthn/begin)
+	PostLoginWebauthnBegin(ctx echo.Context) error
+	// Finish the passkey second factor
+	// (POST /login/webauthn/finish)
+	PostLoginWebauthnFinish(ctx echo.Context) error
+	// Start authorization with OpenID Connect provider
+	// (GET /login/{provider}/authorize)
+	GetLoginProviderAuthorize(ctx echo.Context, provider string) error
+	// Authorize with OpenID Connect provider
+	// (POST /login/{provider}/authorize)
+	PostLoginProviderAuthorize(ctx echo.Context, provider string) error
 	// Get all posts
 	// (GET /posts)
 	GetPosts(ctx echo.Context, params GetPostsParams) error
 	// Create a new post
 	// (POST /posts)
 	PostPosts(ctx echo.Context) error
+	// Get the archive of the posts
+	// (GET /posts/archive)
+	GetPostsArchive(ctx echo.Context) error
+	// Get the posts of the month
+	// (GET /posts/archive/{year}/{month})
+	GetPostsArchiveYearMonth(ctx echo.Context, year int, month int, params GetPostsArchiveYearMonthParams) error
 	// Get a post by slug
 	// (GET /posts/{slug})
 	GetPostsSlug(ctx echo.Context, slug string) error
+	// Partially update a post by slug
+	// (PATCH /posts/{slug})
+	PatchPostsSlug(ctx echo.Context, slug string) error
 	// Update a post by slug
 	// (PUT /posts/{slug})
 	PutPostsSlug(ctx echo.Context, slug string) error
+	// Get the related posts of a post
+	// (GET /posts/{slug}/related)
+	GetPostsSlugRelated(ctx echo.Context, slug string) error
 	// Send a post by slug via email
 	// (POST /posts/{slug}/send-email)
 	PostPostsSlugSendEmail(ctx echo.Context, slug string) error
@@ -186,6 +607,33 @@ type ServerInterface interface {
 	// Confirm subscriber's email
 	// (POST /subscribers/confirm)
 	PostSubscribersConfirm(ctx echo.Context) error
+	// List users
+	// (GET /users)
+	GetUsers(ctx echo.Context) error
+	// Invite a user
+	// (POST /users)
+	PostUsers(ctx echo.Context) error
+	// Generate recovery codes
+	// (POST /users/me/recovery-codes)
+	PostUsersMeRecoveryCodes(ctx echo.Context) error
+	// List passkeys
+	// (GET /users/me/webauthn/credentials)
+	GetUsersMeWebauthnCredentials(ctx echo.Context) error
+	// Start passkey registration
+	// (POST /users/me/webauthn/credentials/begin)
+	PostUsersMeWebauthnCredentialsBegin(ctx echo.Context) error
+	// Finish passkey registration
+	// (POST /users/me/webauthn/credentials/finish)
+	PostUsersMeWebauthnCredentialsFinish(ctx echo.Context) error
+	// Delete a passkey
+	// (DELETE /users/me/webauthn/credentials/{id})
+	DeleteUsersMeWebauthnCredentialsId(ctx echo.Context, id int) error
+	// Disable a user
+	// (POST /users/{id}/disable)
+	PostUsersIdDisable(ctx echo.Context, id int) error
+	// Re-enable a user
+	// (POST /users/{id}/enable)
+	PostUsersIdEnable(ctx echo.Context, id int) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -193,6 +641,24 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// PostCaptchaChallenge converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCaptchaChallenge(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCaptchaChallenge(ctx)
+	return err
+}
+
+// PostCaptchaVerify converts echo context to params.
+func (w *ServerInterfaceWrapper) PostCaptchaVerify(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostCaptchaVerify(ctx)
+	return err
+}
+
 // GetHealth converts echo context to params.
 func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	var err error
@@ -202,6 +668,42 @@ func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
 	return err
 }
 
+// GetHealthLive converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthLive(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthLive(ctx)
+	return err
+}
+
+// GetHealthReady converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthReady(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthReady(ctx)
+	return err
+}
+
+// PostLoginEmail converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginEmail(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginEmail(ctx)
+	return err
+}
+
+// PostLoginEmailVerify converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginEmailVerify(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginEmailVerify(ctx)
+	return err
+}
+
 // PostLoginGithubAuthorize converts echo context to params.
 func (w *ServerInterfaceWrapper) PostLoginGithubAuthorize(ctx echo.Context) error {
 	var err error
@@ -211,6 +713,15 @@ func (w *ServerInterfaceWrapper) PostLoginGithubAuthorize(ctx echo.Context) erro
 	return err
 }
 
+// PostLoginRecovery converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginRecovery(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginRecovery(ctx)
+	return err
+}
+
 // PostLoginRefresh converts echo context to params.
 func (w *ServerInterfaceWrapper) PostLoginRefresh(ctx echo.Context) error {
 	var err error
@@ -220,6 +731,56 @@ func (w *ServerInterfaceWrapper) PostLoginRefresh(ctx echo.Context) error {
 	return err
 }
 
+// PostLoginWebauthnBegin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginWebauthnBegin(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginWebauthnBegin(ctx)
+	return err
+}
+
+// PostLoginWebauthnFinish converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginWebauthnFinish(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginWebauthnFinish(ctx)
+	return err
+}
+
+// GetLoginProviderAuthorize converts echo context to params.
+func (w *ServerInterfaceWrapper) GetLoginProviderAuthorize(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", ctx.Param("provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter provider: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetLoginProviderAuthorize(ctx, provider)
+	return err
+}
+
+// PostLoginProviderAuthorize converts echo context to params.
+func (w *ServerInterfaceWrapper) PostLoginProviderAuthorize(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "provider" -------------
+	var provider string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "provider", ctx.Param("provider"), &provider, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter provider: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostLoginProviderAuthorize(ctx, provider)
+	return err
+}
+
 // GetPosts converts echo context to params.
 func (w *ServerInterfaceWrapper) GetPosts(ctx echo.Context) error {
 	var err error
@@ -240,6 +801,55 @@ func (w *ServerInterfaceWrapper) GetPosts(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
 	}
 
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "keyword" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "keyword", ctx.QueryParams(), &params.Keyword)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter keyword: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// ------------- Optional query parameter "sent" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sent", ctx.QueryParams(), &params.Sent)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sent: %s", err))
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", ctx.QueryParams(), &params.Author)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter author: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.GetPosts(ctx, params)
 	return err
@@ -249,11 +859,76 @@ func (w *ServerInterfaceWrapper) GetPosts(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) PostPosts(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostPosts(ctx)
 	return err
 }
 
+// GetPostsArchive converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPostsArchive(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPostsArchive(ctx)
+	return err
+}
+
+// GetPostsArchiveYearMonth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPostsArchiveYearMonth(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "year", ctx.Param("year"), &year, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter year: %s", err))
+	}
+
+	// ------------- Path parameter "month" -------------
+	var month int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "month", ctx.Param("month"), &month, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter month: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPostsArchiveYearMonthParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPostsArchiveYearMonth(ctx, year, month, params)
+	return err
+}
+
 // GetPostsSlug converts echo context to params.
 func (w *ServerInterfaceWrapper) GetPostsSlug(ctx echo.Context) error {
 	var err error
@@ -270,6 +945,24 @@ func (w *ServerInterfaceWrapper) GetPostsSlug(ctx echo.Context) error {
 	return err
 }
 
+// PatchPostsSlug converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchPostsSlug(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchPostsSlug(ctx, slug)
+	return err
+}
+
 // PutPostsSlug converts echo context to params.
 func (w *ServerInterfaceWrapper) PutPostsSlug(ctx echo.Context) error {
 	var err error
@@ -281,11 +974,29 @@ func (w *ServerInterfaceWrapper) PutPostsSlug(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PutPostsSlug(ctx, slug)
 	return err
 }
 
+// GetPostsSlugRelated converts echo context to params.
+func (w *ServerInterfaceWrapper) GetPostsSlugRelated(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "slug" -------------
+	var slug string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", ctx.Param("slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetPostsSlugRelated(ctx, slug)
+	return err
+}
+
 // PostPostsSlugSendEmail converts echo context to params.
 func (w *ServerInterfaceWrapper) PostPostsSlugSendEmail(ctx echo.Context) error {
 	var err error
@@ -297,6 +1008,10 @@ func (w *ServerInterfaceWrapper) PostPostsSlugSendEmail(ctx echo.Context) error
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter slug: %s", err))
 	}
 
+	ctx.Set(BearerAuthScopes, []string{})
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.PostPostsSlugSendEmail(ctx, slug)
 	return err
@@ -329,6 +1044,126 @@ func (w *ServerInterfaceWrapper) PostSubscribersConfirm(ctx echo.Context) error
 	return err
 }
 
+// GetUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsers(ctx)
+	return err
+}
+
+// PostUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsers(ctx)
+	return err
+}
+
+// PostUsersMeRecoveryCodes converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersMeRecoveryCodes(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersMeRecoveryCodes(ctx)
+	return err
+}
+
+// GetUsersMeWebauthnCredentials converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUsersMeWebauthnCredentials(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUsersMeWebauthnCredentials(ctx)
+	return err
+}
+
+// PostUsersMeWebauthnCredentialsBegin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersMeWebauthnCredentialsBegin(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersMeWebauthnCredentialsBegin(ctx)
+	return err
+}
+
+// PostUsersMeWebauthnCredentialsFinish converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersMeWebauthnCredentialsFinish(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersMeWebauthnCredentialsFinish(ctx)
+	return err
+}
+
+// DeleteUsersMeWebauthnCredentialsId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUsersMeWebauthnCredentialsId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteUsersMeWebauthnCredentialsId(ctx, id)
+	return err
+}
+
+// PostUsersIdDisable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersIdDisable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersIdDisable(ctx, id)
+	return err
+}
+
+// PostUsersIdEnable converts echo context to params.
+func (w *ServerInterfaceWrapper) PostUsersIdEnable(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostUsersIdEnable(ctx, id)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -357,60 +1192,176 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.POST(baseURL+"/captcha/challenge", wrapper.PostCaptchaChallenge)
+	router.POST(baseURL+"/captcha/verify", wrapper.PostCaptchaVerify)
 	router.GET(baseURL+"/health", wrapper.GetHealth)
+	router.GET(baseURL+"/health/live", wrapper.GetHealthLive)
+	router.GET(baseURL+"/health/ready", wrapper.GetHealthReady)
+	router.POST(baseURL+"/login/email", wrapper.PostLoginEmail)
+	router.POST(baseURL+"/login/email/verify", wrapper.PostLoginEmailVerify)
 	router.POST(baseURL+"/login/github/authorize", wrapper.PostLoginGithubAuthorize)
+	router.POST(baseURL+"/login/recovery", wrapper.PostLoginRecovery)
 	router.POST(baseURL+"/login/refresh", wrapper.PostLoginRefresh)
+	router.POST(baseURL+"/login/webauthn/begin", wrapper.PostLoginWebauthnBegin)
+	router.POST(baseURL+"/login/webauthn/finish", wrapper.PostLoginWebauthnFinish)
+	router.GET(baseURL+"/login/:provider/authorize", wrapper.GetLoginProviderAuthorize)
+	router.POST(baseURL+"/login/:provider/authorize", wrapper.PostLoginProviderAuthorize)
 	router.GET(baseURL+"/posts", wrapper.GetPosts)
 	router.POST(baseURL+"/posts", wrapper.PostPosts)
+	router.GET(baseURL+"/posts/archive", wrapper.GetPostsArchive)
+	router.GET(baseURL+"/posts/archive/:year/:month", wrapper.GetPostsArchiveYearMonth)
 	router.GET(baseURL+"/posts/:slug", wrapper.GetPostsSlug)
+	router.PATCH(baseURL+"/posts/:slug", wrapper.PatchPostsSlug)
 	router.PUT(baseURL+"/posts/:slug", wrapper.PutPostsSlug)
+	router.GET(baseURL+"/posts/:slug/related", wrapper.GetPostsSlugRelated)
 	router.POST(baseURL+"/posts/:slug/send-email", wrapper.PostPostsSlugSendEmail)
 	router.DELETE(baseURL+"/subscribers", wrapper.DeleteSubscribers)
 	router.POST(baseURL+"/subscribers", wrapper.PostSubscribers)
 	router.POST(baseURL+"/subscribers/confirm", wrapper.PostSubscribersConfirm)
+	router.GET(baseURL+"/users", wrapper.GetUsers)
+	router.POST(baseURL+"/users", wrapper.PostUsers)
+	router.POST(baseURL+"/users/me/recovery-codes", wrapper.PostUsersMeRecoveryCodes)
+	router.GET(baseURL+"/users/me/webauthn/credentials", wrapper.GetUsersMeWebauthnCredentials)
+	router.POST(baseURL+"/users/me/webauthn/credentials/begin", wrapper.PostUsersMeWebauthnCredentialsBegin)
+	router.POST(baseURL+"/users/me/webauthn/credentials/finish", wrapper.PostUsersMeWebauthnCredentialsFinish)
+	router.DELETE(baseURL+"/users/me/webauthn/credentials/:id", wrapper.DeleteUsersMeWebauthnCredentialsId)
+	router.POST(baseURL+"/users/:id/disable", wrapper.PostUsersIdDisable)
+	router.POST(baseURL+"/users/:id/enable", wrapper.PostUsersIdEnable)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xabVPcOBL+KzrtVeU+eGY8kOQ2U7V1F1iWDGEhxcvt1SUpVrZ6xlpkyZFkYJLiv19J",
-	"fhl7LDOwB4TUhUqRwZbUre7n6Va35guOZZpJAcJoPPmCdZxAStzHbSlmTKXHeaRjxSJQR/ApB23su0zJ",
-	"DJRh4EbGJDNxQuxHCnZwZpgUeIJPEkDlS2TkOQgcYLgiacYBT/A4LH4GhBAyiKIoGsRxHA/C5c8YB9gs",
-	"MjtaG8XEHF8HuFjJL6zQmdhHPokbm89fvPz7j69CEsUUZt3VrwOs4FPOFFA8eY+rJaodfqwnyOgPiI1V",
-	"Z1sBMfD0zAQpYdwKWy5Vfvpn+f8wlulaExTL3GyCXWbe5NHr3CTl5rckXXgMICm0FbqzQ9wSPhXeAOEm",
-	"2U4gPj8CnUmhoauANsTkuq3C4du1QstpPrF7v52cVHBsy6pR2jD/Yi+JdmN2yPamp5+n4wM21VNx9CLe",
-	"nr6cnmf//tf23qvhcHhLVPrUeSe1aeCvDbPXKJPaoGI0MhJFgGIHXoqDjquEAWHa+v/www/oDXAuA3Qp",
-	"Fad/+SB8wGtJ7SqhE6kMajxFcoZMAoV2M6ncH0xQuEIZmUOLDCcJ04hplC7QjClt3CSfEuewuJSK6q4G",
-	"b8s3taha7vHOISKCIg1ExQnKcpVJDRoHmBlI27h5j+eSEzHHAZYZCJKxhkMasap4QJQiC/u35vncHwBO",
-	"j/aRfdu0xhAdJzLn1HoqF+xTDk6/06P9wUwxEJQvhi3zpIuBM8ugzyyGGb7CwV9vNuUq9twC5Ubavg5q",
-	"1PRDc8nMm7BJZgYUYuaZRhGAqFCKpEIzMHFyz4Atlz8jK9M3wo3xIPxxEI5PwnDi/v0HB3gmbYbBE0yJ",
-	"gYFhKdyCBX8Cwoy2Jo7rIUwYmINahfm9gVQBoUzMz9zWuq7KMiWvWEpMSZ1yOLLDERNIQywF1U1gvgp9",
-	"yldkeFD8BjjP6H27d4UUzOLxVsxYMW4Ley1N+yik95k2UwOpJ70+TRh/2xAFYc6MPNP18U7fr30fhQN3",
-	"ieE34bPHGjdi9aaYz5mN+TOnte6E9OJpEzV/VTCzYX20LFpGZcUyapPDAxkjDeHrIuqKpSrFirnefebm",
-	"zoeukubfD12PdOi6N8Lc/rRT4mFHKaluUwiBHXjmnns2lILW1imtKW5tVL26VeG0XMmn86moed1bwyog",
-	"WvYU38U759S8XoqJ+RAdumGEB4hKJKRBGSe2PkeaXABixobkn7cCJAVfuPlczuc2XldoaJ9yp4hK8cyg",
-	"SyIcpRTEwC4AuVJVIyIWqVT+aFto5dQ5Wzlf1cXowFajA1uODuyjwZ0K1FUJXUPbGUzMZMV4Ejs7C2Iz",
-	"GT4mKdqVn1NiEZYrjic4MSbTk9FozkySR7ZoH2mSzt2g0VwOImutRScIWNYzuzX0IQ/DjZeIs3liLsH+",
-	"RhGJz0FQZ2wKF8Ctk/UzZH9bTyG7qCUhZzGU0bvU8NfpCdovn95NxVHEZTRKCROj/en2zsHxToOdeFei",
-	"LTcMvX43xQG+AKWLjYTDcDi2QyvST/DmcDwMbQAlJnHAHCWuAWA/zsETh11nALEi6GlQF7bA0CjPXPBR",
-	"uRCsiivKtY+m1OoEpmgsuMRYJDInbiMMVyI2yTLOYjd19EfJkSI3rctcvtaFQ0l7B4dvHdh0nqZELfCk",
-	"bHmg2E50r0ZczpkonTAiuUmkYp+hyqVdo+xcxQkRc0AEFS0cZMME+ttMydR64YPY3TlBv3u8W0iSVsZS",
-	"0D9izuwJgdGfCsgpoExBbM5yxX76/YOwaCNo77eTos3lMlnb4DaX7tu1d52s1/UeCpY1Wkv3Ynp/4+q6",
-	"TWqjcrh+QP/XPaQepwf4+T1Ka2Umj8QtQlEV/p3ozUcT/YtUEaMUBHLpsGJrrguu2sRBOJeXQG3UJ3EM",
-	"WrsRhKZMoIwI4CscqQGELplJSpQ3yaJgpkAn/Rw5KgY4OTVyrWquPQFXmQNJgBMgFJQDRyWU+E9jJ82V",
-	"8CrSgoYxl7lpC4gC5SL5ZlyQx36EZp0RuTGeDHXdy7Jyc/j/F93jRxN9KupASe8A8BVAe+FYALqul7wJ",
-	"cBfc4nWt1Ul076oijCiSgnFYfr+6yjsyByTyNHJAY/bRpxzUAgfV8aCsHpY2ozAjOTeu3EqZYGme9pRe",
-	"q8IOnBxbqbhSwB5OquLEJ5mzlBm/6I0wwCm5KmRvvFijyMcHpEO3Ln56vGgBro2b66AnTBa3YIggAZdV",
-	"UdUfEkXO+aoKFs122OPFRV9YrFjwEKeNZqPgVmeM8T2L7kfcdnkP9D0crw3HVs1Xj6bmthQzzmLTVtF1",
-	"TwhXQOgCwRVz1GzRtsvHZY4YfdE8n1/fnCoKGdEClV1Cf744Ll7emDP6breqMG5ruGUUL8X1k3+VxQ8d",
-	"rZ9woLainz+a6ANp0C8yF9SDRSpBi2emwKIvg7ThZNNI7kHeqeuMdsH3P2aSP5kv+jBYyOw0l1aSSf60",
-	"GPIA6azd+n7kqvkboOY3ksyefgTxh4VOQhtpEHRQf+/Jf1A9BkGrlYgQMhcxpFA0ku05t3G3hS4YKZrK",
-	"9xeKvt6h1gYiu/md8gtdTytnj/tKCvo9w/r48bVPoU6nS6LrU6guOWQVLijY5nCTdyWblvwquNygXoEG",
-	"DsZzb9y4p0KuU21FRlx2T6k/uxWOG8s+TCb03Jx5smEH88+7ezuQaLvU6Kt2ANqZu4hsjXjc64F1/YGl",
-	"i+ubYK/nbMR6eL/1fXX3Ns57ogFrjefWuGGVhqPyC9X96bT8lnhjxWcaVd8ZvtGn5cyHcm3ft9dv49uw",
-	"u8+vepxsp4PyBkIjJi4IZ3S90/t95KYW16G+Y8C+jAkvr0tbl72T0Yjbd4nUZrIZhiG+/nj93wAAAP//",
-	"Ql7j2E0wAAA=",
+	"H4sIAAAAAAAC/+x9C3PbNrPoX8Flz0zbOZQsO0mb+E7nXNeJUzUvXz/ar1+V60DkSkJDAioA2lEz/u93",
+	"8ORDICWlUeL0U6fTWhIJLBb7xu7ifZSwfM4oUCmiw/fRDHAKXP95jJMZHDMqOcvU5xREwslcEkajw+hi",
+	"BijByYzQKUoJh0SSaxCITZCcAeIg5owKiFHC6IRMCw4pmgNHbA4c6yHiSCQzyLEaWi7mEB1GQnJCp9Ht",
+	"bRw9ucDT8KRCckanCKgkcoEknro5k4JzoBJdAxeE0QoorOAJrJjwORbyBUvJhEAanliSHNygGRYS5frx",
+	"RC+nORsiVH/+6eLiFKVYApownmO5CgpC34Znzwh9K5BkelQK7yTCNNUf5hyuCSsEmuMpCPTN2ckxenjw",
+	"8OG3MYL+tI/ejIrB4F6yN2dCiv9JCi4Y/6Hf7+uv4X8jDtkPo0gNOYredMJ363609DGXyQwfz3CWAZ3C",
+	"md1z9ducq42WBPSTOJsyTuQsVx/gHc7nmRr3/Kej3sGD76K4OVEcJW7Q+huw+JkP/2Dkt4PZbPz0XTbO",
+	"02xIB/3kIKPj/GSQ/uvnLDRcSiYTkhSZXISRS4t8rIhzgjLAqSLpv4AzNCbSU/QMi1kUl7AcDPw8hEqY",
+	"AlcTwbs54SCusKwDfjA42O8N1L8Xg8Gh/vffURxZmjiMFIX0FIEtQ38bRxz+LAhXdPl7BTNxBa+1JdbA",
+	"eO0HZOM/IJEKSrtzvwAnk8UZ/FmAkMu79pH3gDKaNIbaH9x/+OD77zZasxlmjVW1EaNkb4G2yTM9AjKP",
+	"xIFFp08f8X//ep9M/u+K9TZWYEYMQq3kI8/Pi7GCZgy8fT8MdJuCvj8w//Qwxrg3Ho/HvSRJkt6g/Gc/",
+	"tF9deDIwG8EXmPHg3v0H333/8NEAj5MUJmtiJ/YrDOKJA5Zw99AEOSZZg0PMX//H/r+fsHwlCsww3Sg4",
+	"IZClTzhnPLBqlsLykvXDSP3mxNhEjREjMkFEfi3QW8pu6nh4cnZ2dfrq/OJq+PKXo+fDx1fnzy+fBmUq",
+	"yKWV54WQijokJhQxmi1Qxm6AJ1gAykBK4CJGKZkquarUV4rFDERo9DnmOAcJfHlRL3Gphwm9xhlJ0RzL",
+	"WYz+LIAvEOPIWDGoHKS6wozkRAbnZIQGZ/z5/NVLdGp+Nfr1u0eD/W+dNnZAaNyWhoCmTzRm6aI2/Vd7",
+	"IiumKwnCojdEB0+J/KkYHxVyZpngRzVHK0n8DcbUQ4RA+AlwJmfHM0jetktaIbEsRB2EV89WTmpfWz1t",
+	"kQW1FiRvIbUauCEBKhacfi52NluRSUSENmghVaYawmgCN0hAwmgq1lTVcZRwIkmCW+xlAfyaKNtQIMok",
+	"4oDTBbqZkQwMSPZlA5t6Km1wp+QF+FnHjGWAqZoWnFCoz3kGWJSm6QSTDFIzeHXQKGGUQqLeQRwmhYA0",
+	"tLQMS6DJ4ioXyxM9N7/VcKss4JxkGbE4RDczoIhIdIMFmgNXCIW0Csh+/+BBFdGsGGcVLBsjTZsSOG9Q",
+	"doolHmMR3JMKGdIiVxRWzKM4srhNicDjDFJFb+V4xXxppDh611MD9K4xVwAINVKFIC/VK5XPj8341W/8",
+	"VE2S1wvykFbIqIb2uErcIfYY0msi4VJ0aEdcyNlVDnLGWrwc85vaSfUoUOkcHEUXaLzQG1yIhkydEjkr",
+	"xkH9+E4Cpzi7IoEJh48dyagRnc+kJrZwWBeGFjlwkiAj+czDw8choyNIuWxKApbMc/V1df4+Gkp0Q7IM",
+	"jQEVc8XoKWLU6k0uJBJkShGh/drMLJEswUGNwlmmCfW/OEy06C+d7T3rSe3p7VLPLZkEFczFtY0L7f3P",
+	"v15cOIutvuX5BF+V4y5twQRpqaJXqc0iJXi07jZaTTI0x0Lo3w0rowlOJOMjek0w2tPY3buBsYKQ7o1B",
+	"Y5W7Hzgk7Br4YkSjkOTyVmbN1p6NnybkFfl5ePnXcP8lGYohPXuQHA+/G76d/+uX458f9fv9v2Fzvzg5",
+	"+lGB2ak+Fdo8dN0zlY+2zHZmkbChvlZaugeT6axH/nib9XLK5iEq+yBI43bl/uLk6FfQ9gU9IZSIFWYG",
+	"h1SJCaP0cJoSRVk4O608ZfRWnfBOi3FGkmewOPYDIA6y4NTIGYqvyRRLxvvlDKI/BfnNt1EA6C4kxJEA",
+	"IQgL/daBIPdSXF1jEGF4ShIVuulEVMBTmKqnP8BP6ASi5taHQWnz7dTXaMJZrrk9VyPqwNPf4LVXw8fH",
+	"WzFVDQFfXavVEmO1B1U/rN50Pb17ujlu16Iuz553RL0KOWOc/KXV51XBG3s/k3IuDvf2pkRmeKw2f4+p",
+	"V/bce/A/SUaAyiuSunDdwXcaxh+C4i+AkAbHPTt+YpxB9wySMyxVaEtJ+TEgAVSiMU7eohsiZ8aWM7hp",
+	"R219jnMyVfyrf/3g0Rvbs4zITfbqFMtkdsqErNhEYSk1wZmAOOT7vQA+BaRHMv7f9/ceffetsx3mTMjY",
+	"qEz1cUqugRpnUCDMlTmM6RTS/ogOzQsuQk2EeTj2w3jd68wP56grf8GOgwShCfRH9KI+lENZjAqagdXY",
+	"w0nvhQbbusREIAGyr7VxkwepBNqIW3711VfoJ8gyFqMbxrP0f43o8nY1UNYkiSMkZoxLVPm2ijntbRk3",
+	"OoV3OohdM68uZkQosPOFNcLUSyEg3sLihvE04J48s7/4qfy8509e6UCEAMyTGZoXfM4EiBjRIlPqKGfq",
+	"UANnGfKjxxGRkNfd2t+jKcuwdhLYHCiek+h1AEI1qPIAGo4c5hwv1O+SyKwhB1+sWrUlgOVF/1I/BtFL",
+	"1gyp/C/letZcr+Vg9m2Il+ps1NxnPYd52rJ8oqN26Y7YOojt71BUk4J0aCno112ePUfq1yo2+uh8xoos",
+	"1f4OJX8WoOG7PHvem3ACNM0WdVcnX/Q0WnptaNmcgpuGhB7ALqS+17GnmtetpFnq4i7axBMJ3MjVMQB1",
+	"VKq8lgnIZPaRCdYO33Io9PBDDoWWuOADSJiktRf3Q+dZVTL/aETKzRnblV7a8lbN55y9IzmWlnXs4+b8",
+	"ldBKTM4D8ih4GOeYYav0G0dWVX/c7d1ErMdGrqttpwmHHKgNXIDyOa0psVraV9lQhxzW4sXGdtaovYab",
+	"ck1t7CuOeDIj1/CCUTkLOQkFDaidl/7s1uFDeIa2AaVcD1hBwL0QueRuWv/YwzjK8TuSq6jh/kEc5YTa",
+	"D6H3F4B57fWDwcH9lZjWb7nJY7vIVRhqF3T10+wSIzZw56ZphDrUt3Ue74pYLe/VEpM3vWozQeuynhMh",
+	"hxLyYHThLsrOL1suKqdSsivhD1PFx8XvJxG8mxgOXSKqBRudtNplaGRESMV7mu+WWE2l2Sy/dayTchzH",
+	"qme0aarPaeUMOBi/EMKnlkJuyLue3QJEqLKJVgFYyzhaC0jJJM7Wk925clc1XeuweyaBi82Ul8O8mbR1",
+	"I88gU1TQLUu5ecjCpk5FrMoto2RMHwzkJMN8abs/6uYElxlcXiE39tSspt55ap/IU/u0vr6JMJk9NrEi",
+	"tfUmvuTfUA/XYkybsZ2TwG12Ypf5d6YFNAjRHk7VZ5/r89JyqkDIXV46Gtb5CSdHw+f18+BXz9Y7D/br",
+	"0C/4Tyc6aN+S6BC7pYURY06OjlkKXchhqfnD42YFAQYi4G0AaCHyJJxhcMrZOIMcmWwVnywJ6mkTKX10",
+	"/8H338Y69QFShAXC83lmz5T35ub1//5DMGoDmgoSn9qagxB4CjqK+hbmhjlVBPkG8xSpXceSjElG5CKu",
+	"vUDseSXOAWHztwExHPlsnj74JKiXry6uTl5dvnwc9OtNwvFVK1sed2ckO83pc4VUXl1GEilMgFxnKZXe",
+	"RMiUK5OwGolf7+YZppXMZKWaEwNwAqUe1+ivzqJVo442T1hBg4kgenMD4vTERLwb+U9aDGHus6SieD3+",
+	"rSS7BRjX7nN911aDXvJ7HXSdpW1+rGXLuTT2KobuD+6HdsLL88Z5SJHnmC8aGEfqfZd8pHN05jOOhZ/X",
+	"S4ZycS+ZRCdt6zJfNOe+PBu2z6tZQyc6ZZnhV1Eey+gf7dlMCULB6eGU9cYZm04Xh3bIQ6U6epTJXgvW",
+	"m1pC/VqJLbiVWkqO3WGc2+GQTLqk3lhvTXQxeG0z6jTO1eoLPxSh0z56NTfHQjFKmSYlxUbKQhL4GhCR",
+	"ys96/KM97FHvK1woY9WZCPV46RCljH6tNCvVdhaHBMg1IH2KKxCmi5zxsAtloNLgXJE0fDjaU6ejPXU8",
+	"2lNf9TbK7WvOEES0TiXqOuCs5BKtkw20FXe+kWK0Vk7QOsFPnze0hVyfqixa9ca5eXI7ocZQ4K8988gh",
+	"xS63lrIWDvu1khULiUv1bT0l6yjNCRUowRTlmOKp+V7EyJwKm180O9qftVuk2dCadFiNYNfBeN2us98F",
+	"trOC+GWhrr9vpI7p3LtUf1LHCglTpoeuigrmkMUj6lIP7TtKSSqRgzOVNK0Tr1y+2YhW1kPMTGpFevjW",
+	"JEb/c3BxS4GMOnNrmNa2tmuCYpWxaYYOEYZLOzoGDjmjHVUcTO+F2CzlyEh34++F04sMFX/zLWJ80wSk",
+	"SopROCXC/L5GUkQl8ZLxqg3SzboOJSUonSj2a+rwKmrSej1hbARrQJhiIa8KseFoLtN3DaGlH63JofWW",
+	"v4IPKnu/Njd04HilI1aZrgv+M5gSIU0p5Z1L0nNcFGKT5dzt34oxeQaLoBW0btremql6esik4EQuztVe",
+	"GeQczdX8Cq1hUU8SdHQ6VBkgZdTv6HR49ezJb+e2vjU2pnQhma2KEkUyU57n8TCKI2VF2MpaR6iH0b96",
+	"R6fDXm3hWAOiFv4jYA7cgTTWn04cx/z860W0lKb064XN3vUgmgRcBDTVhS3ClXaqmcyI5cwzKeemwJPQ",
+	"CXNxP5xoVrUAn+McPWV/5VihWaezVXPYZoXJYRM4n+qH9ryrsBQKVLE/onYfmaQ2lJHpTN6A+q+WhUBN",
+	"EUYK15ApChVfI/VfRbJIDaoWk5EELMtaCF8ML9Bz++1mIO6NMzbeyzGhe8+Hx09enj+p+HTRU4Z+1I+p",
+	"ba9Esg6jQX/Q349uy9DfYXSvv98fRHGkSpI0ee3Zmq69WkGljvEtZ2MLUQDCiMIN8o87Q2NckEz2CFXe",
+	"HJv02KR3w/hbV9PWRzp8olMGnX6ZEJqq0RhNIB5RwYz2sYW36qlZWbPhZksYTbAEquPeXinpMfS4WKIM",
+	"sJDoTVlz+ma5cLY/oq+UWYavMdFZTz7WGIJefXtNbIYaUPV8asweXzE+TK2P3yw8juLIKUmN8IPBoBG5",
+	"rsac/rBuYVnm3CXKW4ucNbM0zItnihLuD+53zF6NeK0PRS0IF5j5NIhSW2ZksalgezAYfHLYhtQ4E+gc",
+	"+DVw5B4sRXF0+Ptr5fHqSIlnggaZ+M1Wb3qW0pmfi3Z+evLOBLRNVINlKgbZMrA/BRCETjPoFaJRLhqP",
+	"qMuz0EabjSu+sQ+9qdf/Vd1rFwwTffQEJ7Mqr2GKxh4y7cYwk9zZRfkmwzsyKrCi8z8mxdeLw2/rClfy",
+	"Am63z3WNUu4OlvscZG3KYxhHpto+Lbc1Vt+6qlDBskK/c6dlQwc7ml1osE25KsWOM33YoiCdguXCOYcE",
+	"S0csTaCOTYWgZRUjGIhAxVyH/nlBqQ7HPfbDxMpJRm/sTHsZuYY3iFAhAaf9JV55CtKc/2xTNYRqYFtI",
+	"tAO7ZhRblVlBpl5iDaOt+JtzloAQFQRqdSuZwWw1HC+MlZDCHGgKNCFQxh1scaGzE3zBqKutRCkDV7gq",
+	"JObSbx4JSyy/C89NGOKO74SCkio8Kj6E2l7oSt31NqNS46vfqm+D5FiZTIf6g0eseTaZ6W1T+0ekQGZx",
+	"dlMlU08rLLsItdkuYQskTbkOydQEVMwZl7HeVw7qb+WrFdKFtCf6ORMGN8eT/RE1Z6S2XqGlCjpGjjA0",
+	"NVFpMCXcwBnD5tiuSl6dhHGm8bpFylg+Vw7QhYFCm0f3Pu3ML10VeFxa1Yy2VoJ3Ua+fr0q+pgTTF56F",
+	"baRz0E5CafbEJjOjpyRQ6mKQug7MdTzQI1bjnyNqo5KlwzAytWqjqFrPa8SP2+7qMe2ImuMY3ZGiPCDx",
+	"GQp6+gSr0xQbNdPkSGT5pYoujahkDmsleAjeESFFm1mlK4Gf2A4c27CpggWCa9lUB4HEmSSBuYT0c1k+",
+	"P+IUebswju4fPPrkIFwwhl5gunBwlCk8ljI5Gp4inKYchLhTjk+NKj3lKsm5wgqzsyFcKcusHBGoYJzh",
+	"8ybjb+YjybYSUKsOfJxpBSNt1UXpKHX9xI6Kr7q/U75JhUNr4pMI55XE3mnRGQDGUlHy04B875ODfML4",
+	"mKQpUJs7VGUTIprnYjhJXIGjPt1Dc0whu3vSqCqEOnj73PKw150l21XZ2YQwy/rgNVgau5YZOqvlG83X",
+	"R6fDEX365AK9CcRGzUzthcgmYMvBtHy8Kjj54c2INmRDl5p9quc68mvYjoQIt0jaCYeA+v6Smf0zhFN8",
+	"HlYddstmThvbVZTNT79Y2eQ51Ugns86qVHINXtaSRoyas17k3jKCqSE/fGLkHAuhjt2IQJnJiW6RKi5N",
+	"dlsGR7iFy06ehI2N+uaWVkfY2Nj/5CBfUq/aGmz84uSobIJElkK8d4+Llczs5N9LofiuviM40M2pztIT",
+	"DmLWztFn5gE9Snny7HpVWGTZhG6PTn1oYisyj6otPVxvCuXta4zrk0dbseiOrxGRArJJpwtvwYp2Ov0u",
+	"8VSnWr+jJohPqOt2yQNcUOWjelO0dnZ6CiaW7pJ7EBYCuPoRsUrKWmkB6XNIYfLKCB1R7ziYFEPD0Zqf",
+	"pkyiN9UOcG8cCzpK9tG4Ttb61S5F903bnpZdasv2iVVsawLijt0/mgrtcoT9sZKz/Fp1lOetic5+WzO8",
+	"VU+tLNls7eCW4wOTc7c9RmhvwLczOsNGZ7mZJd3tDMztG5iGQtdj2vcuy+u2HsWaQodWdO+gWh843dBI",
+	"l9SYUFSpviWzlqdPVDXd6JQ6rLWLQ7pd+diWFlp704S6RhTTtJKbLdkU5Ax4vXNdy9mmFhSnFup6pGtL",
+	"TBrqR3inEtRaoibVpL/leMmdO7RpgutTG9dQanXi1WT0ag50+Bgdm/bbfvRa+3tVVdzVAL/EWOtwNv9Y",
+	"5aOW2ceVX+u6pHr/S62iK8OBiq7b1/E6atcjLhTtaVe2YR76+Po21KN0p2Z3seLPIPXWj8S2Sg+lbn3f",
+	"kVbNqsp+9VOx9TFxli1svxWweRrqEi5/4xUx3UldgwjdzV9ZDK5tqS28icvO6RylkIGsppQZ7WqK9ji4",
+	"YI++FUCtaTjpvVQhYtOyVJ3iT3ruUq7euW17apS7hsemz87xtJp5oj6ql/0X5s6rwOVkNzOSzGyNrnhL",
+	"5kjXW80BS5Xr6zrSmG1bmNwos0p7a8McT3We4kVlEvUUzgSrJQmrw2ob5moxGk5du6AuyX+Kp669V1zP",
+	"e2ku1kl9fSVKReybNioleacwwfoejf3u9ma3cXvvHl0TpW93s8OHZna3rgSmPhhU+qwdPNgUkAvXMonx",
+	"eneiJNy0qNx+9TXTZl2JdW8UusSklvV4NHdc59aE9BVPW/qyObKSpHVCwXgL/iIKNyBkpUTUf8GyVP3x",
+	"Ol4DONe/2ADm6cn2z2mBqvw1aDa4zjibzu7wgfWumn6d0t7B1wKJOttuAeNvlUevCekYJozDKiAl6wTx",
+	"0VZANJ2AgAP6RvICdHGr/qiUzze66/W3RlpJVq1dGJvOW0FiNC19lmjf3+ywEqjqvR+e1lxxeWhKX6od",
+	"Qp8v6QqbqFsz25Y7woXtt3jpYs1e5WbN0Az2+b3aLZyVizG73tHP2Dste9VLLbteql2AWbmKsvMdk6Fy",
+	"G0f3jHm1bP+4IZ3xo20K3eMNxLK+L+nDZaXn7n3dkWrZIPjyUHt7F4z5DjuzZh26DoOBrHct+Gyt4rzt",
+	"dN6ZNdtw26o97tZy1/Y/8tTtLH/s+55XiHN9+rrdHSz8084RFXifPq57bDuKNRxQJqRPxDCp8U2J8L5W",
+	"gv7769uaiFjm/dLr3MOmM/DKuG5Hm+IFYF42gWNUujJlwo3BpcP8FF1eHFc6cRqr1zzvqo/9ANaiLZWL",
+	"iw5XXN1G17iq5+saunZ5b7YlcrRte6PZBPofYnLszIgN0bVCh+sgmCGVGpMFeHXvveK42733mlVuVx/J",
+	"tHZZt1xpmMrFjEx8Rp+omGo0z2U3eKE4zZsba7DXb4D5C9fGvB4sCcS5bXv1NWLcplu7j0M8evToUTUS",
+	"8ej7QTgYEZjUdVlfY9ZNWszfxrt40C4etKV40M5L3nnJOy95My95KZplBH9Fv75X1xF061PTUF4xvnrW",
+	"KM45Kw83ymOSdINDklYtem7uR+g8Zmi7Lyp8lGxvXGhXdnMsJXD14v/7Hff+GvQe9V7/9399hkjdzmLe",
+	"iZS7EFW5OyfRSs6kDISyHHUgYHVksCauTK6KTAKd8y7NZQPhazHZpDGQEW0dl21WGl24Gx1M3jMrpMuE",
+	"Ufg0ArR2faNJjIYs1R0DPEBuGCdo1Ts2c8ydaZeSVzkv7mC76b1o0E8vL2JEaJIVqbu8xLV9V++6ezj7",
+	"I/qEaBMzdDsn47UXa5d6uhN0Rm3feXsUX3IsuwZ+w4mUQIO53O4i1M+hA4Kifp2QcK6ooadpbEOeWLr4",
+	"9ROn9GyqcXbh4f/w8PCdVgt3Ln69dFsNItX7bkgf+jVh6tajTSAXGane3zEyLdr2Dz5DizadMK178qIT",
+	"03XrwxbsFcryapXocHes1BdeuzaICB8Ztwg5ePh5EXJmJXaJEgrtKpQu61BteWy+9E0ORE4xl0Rn0Nk7",
+	"lgJmUiFbjaRWHzB4HbioLq55zY0iBsQoVHLuSosBUiLD9gIaToLoNPeFx3pW40EUwlGVMn0gy4K2RiG/",
+	"OEtjQ+1ev2NtZ1vsbIudbfFPsS0qec4uJc4KJ0ztAQETMh7RnU3yGWySTRTzZVgdNyO0e/aez5Unn+Xu",
+	"i7i826x5D6ijGjep+lvMsDJifNTBpTdIeCdH1C5Xd5z3P1VAaLlwtDQVBHLBEEwXUl+gSihKWJ4z6jW+",
+	"z3kwOA9lYBBRqUdwN9eYDr0fL1NCmQX2+tX/1Fj00vWzu5j0Lia9i0l/UExatl/UHBD1AmjaW68nroYF",
+	"U8oKmkBuM+RVskolQx5dE2zbjjr10poIq+SeGtr1mv3sftFyemw4j3XnFOycgv9Qp8DbuS5htlopU7bc",
+	"7bJI4/odU00btSps3HGYFypGgFXkjWHSDGToxtfyYtTSXlQ3HC2JpMd6hPNaoc82wiSBq1rXCpUEDQh0",
+	"bEG647kh7duwqpqi3GffgCO4fUqbbH/zDFDlPJsXW9xpbXL3G5d30NgKgmlKjT3t1PG83eQ5Ng9URvxa",
+	"GKhWUZ99c1tEaEb/QCocLC/0bnQtqum/ZkegL7ZbbAcRaYr0t8oGgywqRVSb1/qxMrwSuBEXvXI3cOs+",
+	"DyJ4B5C+5nab5RjL9+h2tO/Y2a4fodUHNfu9WRBQ05UhvVYFbO5vRthM55qP2ouwEdF3isqFCYHZouUs",
+	"U2lD+pZlpdZGNHTPc7Wpu0xMXM4P1iTilg6MJR1/fPFq1m3ub/4spZT1q6PbSyl3Lug/kY3v0tGPhvDv",
+	"lEXWZEhF4e3l4Nuk9xKWGiZq6wVLFfvrasaWdumlVnbn+hqZtn4lS+1Dpsjxmr318f7yezFTtyytvG9S",
+	"C54X4HqfH2vQtygLahOtLRR2HPm5FKsn1jp9Nkjft2pt3KXebgPaRpL+YKlG562W3gtw3Vkrt7tHn6Jb",
+	"cMtl8jtj8K4ag47A1qDUjZt3+wJ117tbMsT1df1KvZiSeTN9WI53SeIAgZcdue9QT+wdeX8u8jbdRh2B",
+	"GboztLQOra9qpm3vAg600pYShDR0rwu9peuGVaH3/oge2VZeWDYveHEeR/B2CsSod/1Hm3LIVnt1O+44",
+	"q2D6A5t2729RMX0ZHpZjgyo13eVu3jv/66Om3pXCwHlhTm9CupkUNBz4wWLwPUlvu87ZzOkZwn6CtexU",
+	"81a7nBqulQw1fOxms5OH8wFIuk42QNlG4fUXeAS3EwfbEAd3JyvAMldnMlC3HGgyapX5FZPv2YSIdovn",
+	"sXnABnXqd067EC+mi5xxWONQwhsqw9QOvBnLKxi2xe+DTxbS3d2csxMf2xcfGsSlpKI7c6nEhoKsJoaW",
+	"xBjQbil2Bj3zCMK+8VG28OlgetDNxNcTupNeO+m1k1476bWG9KqKHyu/9Ov8Oiw2nrMEZ7bqJIqjgmfR",
+	"YTSTcn64t5ep32ZMyMN7g8Egun19+/8HANx58VbEzgAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// UnknownOperation is the operation of the requests that do not match any operation of the spec, e.g. 404.
+const UnknownOperation = "unknown"
+
+// Operations resolves the operation IDs of the spec by the echo routes.
+// Note: the route template is used instead of the raw path, so the slugs and IDs are not part of the key.
+type Operations map[string]string
+
+// NewOperations maps the operations of the spec to their echo routes, e.g. "GET /posts/:slug" to "GetPostsSlug".
+func NewOperations(spec *openapi3.T) Operations {
+	operations := make(Operations)
+	for path, item := range spec.Paths.Map() {
+		for method, operation := range item.Operations() {
+			operations[method+" "+echoPath(path)] = operation.OperationID
+		}
+	}
+
+	return operations
+}
+
+// ID returns the operation ID of the matched route or UnknownOperation.
+func (o Operations) ID(ctx echo.Context) string {
+	if id, ok := o[ctx.Request().Method+" "+ctx.Path()]; ok {
+		return id
+	}
+
+	return UnknownOperation
+}
+
+// echoPath converts the path of the spec to the echo route, e.g. "/posts/{slug}" to "/posts/:slug".
+func echoPath(path string) string {
+	path = strings.ReplaceAll(path, "{", ":")
+	return strings.ReplaceAll(path, "}", "")
+}
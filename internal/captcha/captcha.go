@@ -1,10 +1,29 @@
 package captcha
 
-import "github.com/kataras/hcaptcha"
+import (
+	"context"
+	"time"
+)
 
-// Client is a type alias for the HCaptcha client.
-type Client = hcaptcha.Client
+// Result is the provider-neutral result of the captcha verification.
+type Result struct {
+	Success     bool      // Success is true if the token is valid and passed the provider checks, e.g. the score.
+	Score       float64   // Score from 0.0 (bot) to 1.0 (human), only returned by reCAPTCHA v3.
+	Action      string    // Action the token was issued for, if supported by the provider.
+	Hostname    string    // Hostname of the site where the captcha was solved.
+	ChallengeTS time.Time // ChallengeTS is the time when the captcha was solved.
+	ErrorCodes  []string  // ErrorCodes explain why the token is not valid, e.g. "invalid-input-response".
+}
 
-type ClientInterface interface {
-	VerifyToken(tkn string) (response hcaptcha.Response)
+// VerifierInterface verifies the captcha token sent by the client.
+// Error is only returned if the token can't be verified, e.g. the provider is unavailable.
+type VerifierInterface interface {
+	Verify(ctx context.Context, token, remoteIP string) (*Result, error)
 }
+
+// Error codes of the Result, the same as used by the remote providers.
+const (
+	ErrorCodeMissingInput = "missing-input-response"
+	ErrorCodeInvalidInput = "invalid-input-response"
+	ErrorCodeLowScore     = "score-threshold-not-met"
+)
@@ -0,0 +1,12 @@
+package captcha
+
+import "errors"
+
+var (
+	ErrUnknownProvider  = errors.New("unknown captcha provider")
+	ErrSecretRequired   = errors.New("captcha secret is required")
+	ErrRequest          = errors.New("error verifying captcha with the provider")
+	ErrInvalidResponse  = errors.New("invalid response from the captcha provider")
+	ErrInvalidChallenge = errors.New("invalid or expired proof-of-work challenge")
+	ErrInvalidSolution  = errors.New("invalid proof-of-work solution")
+)
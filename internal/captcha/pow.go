@@ -0,0 +1,233 @@
+package captcha
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	powAlgorithm    = "SHA-256"
+	powChallengeTTL = 10 * time.Minute // powChallengeTTL is how long the client has to solve the challenge
+	powTokenTTL     = 5 * time.Minute  // powTokenTTL is how long the token of the solved challenge can be used
+)
+
+// kind of the signed payload, so the challenge can't be used as the token.
+const (
+	powChallengeKind = "challenge"
+	powTokenKind     = "token"
+)
+
+// ProofOfWork is a self-hosted captcha without any third party.
+// The client has to find a nonce, so that SHA-256 of the challenge concatenated with the nonce
+// has at least the required number of leading zero bits. The solution is exchanged for a single-use token.
+//
+// Challenges and tokens are signed with HMAC, so only used ones have to be stored to prevent replays.
+// Note: the used ones are stored in memory, so the replay protection only holds for a single instance,
+// another replica accepts the same token once more.
+type ProofOfWork struct {
+	key        []byte // key is used to sign the challenges and tokens.
+	difficulty int    // difficulty is the number of leading zero bits of the hash.
+
+	mu   sync.Mutex
+	used map[string]time.Time // used challenges and tokens until they expire
+}
+
+// NewProofOfWork creates a new ProofOfWork with the given sign key and difficulty in bits.
+func NewProofOfWork(key string, difficulty int) *ProofOfWork {
+	return &ProofOfWork{
+		key:        []byte(key),
+		difficulty: difficulty,
+		used:       make(map[string]time.Time),
+	}
+}
+
+// Challenge is the proof-of-work challenge to be solved by the client.
+type Challenge struct {
+	Challenge  string
+	Algorithm  string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+type ProofOfWorkInterface interface {
+	VerifierInterface
+	NewChallenge() (*Challenge, error)
+	Redeem(challenge, nonce string) (token string, err error)
+}
+
+// powPayload is the signed payload of the challenge and the token.
+type powPayload struct {
+	Kind       string `json:"k"`
+	Salt       string `json:"s"`
+	Difficulty int    `json:"d"`
+	ExpiresAt  int64  `json:"e"`
+}
+
+// NewChallenge issues a new challenge.
+func (p *ProofOfWork) NewChallenge() (*Challenge, error) {
+	salt, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(powChallengeTTL)
+	challenge, err := p.sign(&powPayload{
+		Kind:       powChallengeKind,
+		Salt:       salt,
+		Difficulty: p.difficulty,
+		ExpiresAt:  expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Challenge{
+		Challenge:  challenge,
+		Algorithm:  powAlgorithm,
+		Difficulty: p.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Redeem checks the solution of the challenge and returns the token to be sent as the captcha.
+// Each challenge can be redeemed only once.
+func (p *ProofOfWork) Redeem(challenge, nonce string) (token string, err error) {
+	payload, err := p.parse(challenge, powChallengeKind)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(challenge + nonce))
+	if leadingZeroBits(hash[:]) < payload.Difficulty {
+		return "", ErrInvalidSolution
+	}
+
+	if !p.use(challenge, time.Unix(payload.ExpiresAt, 0)) {
+		return "", ErrInvalidChallenge
+	}
+
+	salt, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	return p.sign(&powPayload{
+		Kind:      powTokenKind,
+		Salt:      salt,
+		ExpiresAt: time.Now().Add(powTokenTTL).Unix(),
+	})
+}
+
+// Verify verifies the token returned by Redeem. Each token can be used only once.
+func (p *ProofOfWork) Verify(_ context.Context, token, _ string) (*Result, error) {
+	if token == "" {
+		return &Result{ErrorCodes: []string{ErrorCodeMissingInput}}, nil
+	}
+
+	payload, err := p.parse(token, powTokenKind)
+	if err != nil || !p.use(token, time.Unix(payload.ExpiresAt, 0)) {
+		return &Result{ErrorCodes: []string{ErrorCodeInvalidInput}}, nil //nolint:nilerr // invalid token is not an error
+	}
+
+	return &Result{
+		Success:     true,
+		ChallengeTS: time.Unix(payload.ExpiresAt, 0).Add(-powTokenTTL),
+	}, nil
+}
+
+// use marks the challenge or the token as used. It returns false if it was already used.
+func (p *ProofOfWork) use(value string, expiresAt time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for v, exp := range p.used {
+		if now.After(exp) {
+			delete(p.used, v)
+		}
+	}
+
+	if _, ok := p.used[value]; ok {
+		return false
+	}
+
+	p.used[value] = expiresAt
+	return true
+}
+
+// sign serializes and signs the payload.
+func (p *ProofOfWork) sign(payload *powPayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling payload: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(p.mac(encoded)), nil
+}
+
+// parse verifies the signature, the kind and the expiration of the payload.
+func (p *ProofOfWork) parse(raw, kind string) (*powPayload, error) {
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, ErrInvalidChallenge
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, p.mac(encoded)) {
+		return nil, ErrInvalidChallenge
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	var payload powPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidChallenge, err)
+	}
+
+	if payload.Kind != kind || time.Now().After(time.Unix(payload.ExpiresAt, 0)) {
+		return nil, ErrInvalidChallenge
+	}
+
+	return &payload, nil
+}
+
+func (p *ProofOfWork) mac(data string) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// leadingZeroBits returns the number of leading zero bits of the hash.
+func leadingZeroBits(hash []byte) int {
+	count := 0
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+
+	return count
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random string: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
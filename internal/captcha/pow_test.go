@@ -0,0 +1,128 @@
+package captcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// solve finds the nonce of the challenge by brute force, like the client does.
+func solve(challenge *Challenge) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		hash := sha256.Sum256([]byte(challenge.Challenge + nonce))
+		if leadingZeroBits(hash[:]) >= challenge.Difficulty {
+			return nonce
+		}
+	}
+}
+
+func TestProofOfWork(t *testing.T) {
+	pow := NewProofOfWork("key", 8)
+
+	t.Run("challenge", func(t *testing.T) {
+		challenge, err := pow.NewChallenge()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, challenge.Challenge)
+		assert.Equal(t, "SHA-256", challenge.Algorithm)
+		assert.Equal(t, 8, challenge.Difficulty)
+		assert.WithinDuration(t, time.Now().Add(powChallengeTTL), challenge.ExpiresAt, time.Second)
+	})
+
+	t.Run("solve and verify", func(t *testing.T) {
+		challenge, err := pow.NewChallenge()
+		assert.NoError(t, err)
+
+		token, err := pow.Redeem(challenge.Challenge, solve(challenge))
+		assert.NoError(t, err)
+
+		result, err := pow.Verify(context.Background(), token, "127.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("challenge is single use", func(t *testing.T) {
+		challenge, err := pow.NewChallenge()
+		assert.NoError(t, err)
+
+		nonce := solve(challenge)
+		_, err = pow.Redeem(challenge.Challenge, nonce)
+		assert.NoError(t, err)
+
+		_, err = pow.Redeem(challenge.Challenge, nonce)
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("token is single use", func(t *testing.T) {
+		challenge, err := pow.NewChallenge()
+		assert.NoError(t, err)
+
+		token, err := pow.Redeem(challenge.Challenge, solve(challenge))
+		assert.NoError(t, err)
+
+		result, err := pow.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+
+		result, err = pow.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{ErrorCodeInvalidInput}, result.ErrorCodes)
+	})
+
+	t.Run("invalid solution", func(t *testing.T) {
+		hard := NewProofOfWork("key", 256)
+		challenge, err := hard.NewChallenge()
+		assert.NoError(t, err)
+
+		_, err = hard.Redeem(challenge.Challenge, "0")
+		assert.ErrorIs(t, err, ErrInvalidSolution)
+	})
+
+	t.Run("challenge signed with another key", func(t *testing.T) {
+		challenge, err := NewProofOfWork("another", 8).NewChallenge()
+		assert.NoError(t, err)
+
+		_, err = pow.Redeem(challenge.Challenge, solve(challenge))
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("challenge can't be used as token", func(t *testing.T) {
+		challenge, err := pow.NewChallenge()
+		assert.NoError(t, err)
+
+		result, err := pow.Verify(context.Background(), challenge.Challenge, "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+	})
+
+	t.Run("expired challenge", func(t *testing.T) {
+		challenge, err := pow.sign(&powPayload{
+			Kind:       powChallengeKind,
+			Salt:       "salt",
+			Difficulty: 8,
+			ExpiresAt:  time.Now().Add(-time.Minute).Unix(),
+		})
+		assert.NoError(t, err)
+
+		_, err = pow.Redeem(challenge, solve(&Challenge{Challenge: challenge, Difficulty: 8}))
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		result, err := pow.Verify(context.Background(), "", "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{ErrorCodeMissingInput}, result.ErrorCodes)
+	})
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	assert.Equal(t, 0, leadingZeroBits([]byte{0xff}))
+	assert.Equal(t, 7, leadingZeroBits([]byte{0x01}))
+	assert.Equal(t, 12, leadingZeroBits([]byte{0x00, 0x08}))
+	assert.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
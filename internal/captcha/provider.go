@@ -1,24 +1,84 @@
 package captcha
 
 import (
+	"fmt"
 	"github.com/google/wire"
-	"github.com/kataras/hcaptcha"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/signed"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ProvideHCaptchaSecret is a Wire provider function that returns HCaptcha secret from the config.
-func ProvideHCaptchaSecret(cfg *config.Config) config.HCaptchaSecret {
-	return cfg.HCaptchaSecret
+// Config is a struct that holds the configuration for the captcha verifier.
+type Config struct {
+	Provider      string
+	Secret        string
+	VerifyURL     string
+	MinScore      float64
+	PoWDifficulty int
+	PoWKey        string
 }
 
-// ProvideClient is a Wire provider function that creates a new HCaptcha client.
-func ProvideClient(secret config.HCaptchaSecret) *Client {
-	return hcaptcha.New(string(secret))
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	return &Config{
+		Provider:      cfg.Captcha.Provider,
+		Secret:        cfg.Captcha.Secret,
+		VerifyURL:     cfg.Captcha.VerifyURL,
+		MinScore:      cfg.Captcha.MinScore,
+		PoWDifficulty: cfg.Captcha.PoWDifficulty,
+		PoWKey:        signed.DeriveKey(string(cfg.JWTSecretKey), "captcha-pow"),
+	}
 }
 
-// ProviderSet is a wire provider set for HCaptcha.
+// ProvideProofOfWork is a Wire provider function that creates a ProofOfWork.
+// It returns nil if the proof-of-work provider is not enabled, so its endpoints are not available.
+func ProvideProofOfWork(cfg *Config) ProofOfWorkInterface {
+	if cfg.Provider != ProviderProofOfWork {
+		return nil
+	}
+
+	return NewProofOfWork(cfg.PoWKey, cfg.PoWDifficulty)
+}
+
+// ProvideVerifier is a Wire provider function that creates the verifier of the configured provider.
+func ProvideVerifier(cfg *Config, pow ProofOfWorkInterface, tp trace.TracerProvider) (VerifierInterface, error) {
+	switch cfg.Provider {
+	case ProviderProofOfWork:
+		return pow, nil
+	case ProviderNone:
+		return NewNop(), nil
+	}
+
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("%w: %s", ErrSecretRequired, cfg.Provider)
+	}
+
+	httpClient := tracing.HTTPClient(tp)
+	httpClient.Timeout = verifyTimeout
+
+	verifyURL := func(fallback string) string {
+		if cfg.VerifyURL != "" {
+			return cfg.VerifyURL
+		}
+		return fallback
+	}
+
+	switch cfg.Provider {
+	case ProviderHCaptcha:
+		return NewHCaptcha(cfg.Secret, verifyURL(HCaptchaVerifyURL), httpClient), nil
+	case ProviderTurnstile:
+		return NewTurnstile(cfg.Secret, verifyURL(TurnstileVerifyURL), httpClient), nil
+	case ProviderReCaptcha:
+		return NewReCaptcha(cfg.Secret, verifyURL(ReCaptchaVerifyURL), cfg.MinScore, httpClient), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, cfg.Provider)
+}
+
+// ProviderSet is a wire provider set that includes all the providers from the captcha package.
 var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
-	ProvideHCaptchaSecret,
-	ProvideClient,
-	wire.Bind(new(ClientInterface), new(*Client)),
+	ProvideConfig,
+	ProvideProofOfWork,
+	ProvideVerifier,
 )
@@ -1,25 +1,120 @@
 package captcha
 
 import (
+	"context"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/signed"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
 	"testing"
 )
 
-func TestProvideClient(t *testing.T) {
-	t.Run("ProvideClient", func(t *testing.T) {
-		secret := config.HCaptchaSecret("test")
-		got := ProvideClient(secret)
-		assert.NotNil(t, got)
+func TestProvideConfig(t *testing.T) {
+	t.Run("ProvideConfig", func(t *testing.T) {
+		cfg := &config.Config{
+			JWTSecretKey: "test_jwt",
+			Captcha: config.CaptchaConfig{
+				Provider:      "recaptcha",
+				Secret:        "test",
+				VerifyURL:     "http://localhost/siteverify",
+				MinScore:      0.5,
+				PoWDifficulty: 20,
+			},
+		}
+		got := ProvideConfig(cfg)
+		assert.Equal(t, &Config{
+			Provider:      "recaptcha",
+			Secret:        "test",
+			VerifyURL:     "http://localhost/siteverify",
+			MinScore:      0.5,
+			PoWDifficulty: 20,
+			PoWKey:        signed.DeriveKey("test_jwt", "captcha-pow"),
+		}, got)
 	})
 }
 
-func TestProvideHCaptchaSecret(t *testing.T) {
-	t.Run("ProvideHCaptchaSecret", func(t *testing.T) {
-		cfg := &config.Config{
-			HCaptchaSecret: "test",
+func TestProvideVerifier(t *testing.T) {
+	tp := noop.NewTracerProvider()
+	// siteVerifierOf returns the siteVerifier of the remote provider, so its HTTP client can be checked.
+	siteVerifierOf := func(v VerifierInterface) *siteVerifier {
+		switch v := v.(type) {
+		case *HCaptcha:
+			return &v.siteVerifier
+		case *Turnstile:
+			return &v.siteVerifier
+		case *ReCaptcha:
+			return &v.siteVerifier
+		}
+		return nil
+	}
+	// assertVerifier compares the verifiers without the HTTP client, it has to be traced with the timeout.
+	assertVerifier := func(t *testing.T, want, got VerifierInterface) {
+		t.Helper()
+
+		sv := siteVerifierOf(got)
+		if assert.NotNil(t, sv) && assert.NotNil(t, sv.httpClient) {
+			assert.Equal(t, verifyTimeout, sv.httpClient.Timeout)
+			assert.NotNil(t, sv.httpClient.Transport)
+			sv.httpClient = nil
 		}
-		got := ProvideHCaptchaSecret(cfg)
-		assert.Equal(t, cfg.HCaptchaSecret, got)
+		assert.Equal(t, want, got)
+	}
+
+	testCases := []struct {
+		provider string
+		want     VerifierInterface
+	}{
+		{ProviderHCaptcha, NewHCaptcha("test", HCaptchaVerifyURL, nil)},
+		{ProviderTurnstile, NewTurnstile("test", TurnstileVerifyURL, nil)},
+		{ProviderReCaptcha, NewReCaptcha("test", ReCaptchaVerifyURL, 0.5, nil)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.provider, func(t *testing.T) {
+			cfg := &Config{Provider: tc.provider, Secret: "test", MinScore: 0.5}
+			got, err := ProvideVerifier(cfg, ProvideProofOfWork(cfg), tp)
+			assert.NoError(t, err)
+			assertVerifier(t, tc.want, got)
+		})
+	}
+
+	t.Run("custom verify URL", func(t *testing.T) {
+		cfg := &Config{Provider: ProviderTurnstile, Secret: "test", VerifyURL: "http://localhost/siteverify"}
+		got, err := ProvideVerifier(cfg, nil, tp)
+		assert.NoError(t, err)
+		assertVerifier(t, NewTurnstile("test", "http://localhost/siteverify", nil), got)
+	})
+
+	t.Run("proof-of-work", func(t *testing.T) {
+		cfg := &Config{Provider: ProviderProofOfWork, PoWKey: "key", PoWDifficulty: 20}
+		pow := ProvideProofOfWork(cfg)
+		assert.NotNil(t, pow)
+
+		got, err := ProvideVerifier(cfg, pow, tp)
+		assert.NoError(t, err)
+		assert.Same(t, pow, got)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		got, err := ProvideVerifier(&Config{Provider: ProviderNone}, nil, tp)
+		assert.NoError(t, err)
+		assert.Equal(t, NewNop(), got)
+
+		result, err := got.Verify(context.Background(), "", "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("proof-of-work is not provided for other providers", func(t *testing.T) {
+		assert.Nil(t, ProvideProofOfWork(&Config{Provider: ProviderHCaptcha}))
+	})
+
+	t.Run("secret is required", func(t *testing.T) {
+		_, err := ProvideVerifier(&Config{Provider: ProviderHCaptcha}, nil, tp)
+		assert.ErrorIs(t, err, ErrSecretRequired)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := ProvideVerifier(&Config{Provider: "unknown", Secret: "test"}, nil, tp)
+		assert.ErrorIs(t, err, ErrUnknownProvider)
 	})
 }
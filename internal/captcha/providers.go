@@ -0,0 +1,111 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+)
+
+// Names of the supported providers, used in config.
+const (
+	ProviderHCaptcha    = "hcaptcha"
+	ProviderTurnstile   = "turnstile"
+	ProviderReCaptcha   = "recaptcha"
+	ProviderProofOfWork = "pow"
+	ProviderNone        = "none" // ProviderNone disables the captcha, any token is accepted
+)
+
+// Default siteverify URLs of the remote providers.
+const (
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	ReCaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+)
+
+// HCaptcha verifies tokens of https://www.hcaptcha.com/.
+type HCaptcha struct {
+	siteVerifier
+}
+
+// NewHCaptcha creates a new HCaptcha verifier with the given secret key, siteverify URL and HTTP client.
+func NewHCaptcha(secret, verifyURL string, httpClient *http.Client) *HCaptcha {
+	return &HCaptcha{
+		siteVerifier: siteVerifier{
+			secret:     secret,
+			verifyURL:  verifyURL,
+			httpClient: httpClient,
+		},
+	}
+}
+
+// Verify verifies the hCaptcha token.
+func (h *HCaptcha) Verify(ctx context.Context, token, remoteIP string) (*Result, error) {
+	return h.verify(ctx, token, remoteIP)
+}
+
+// Turnstile verifies tokens of Cloudflare Turnstile https://developers.cloudflare.com/turnstile/.
+type Turnstile struct {
+	siteVerifier
+}
+
+// NewTurnstile creates a new Turnstile verifier with the given secret key, siteverify URL and HTTP client.
+func NewTurnstile(secret, verifyURL string, httpClient *http.Client) *Turnstile {
+	return &Turnstile{
+		siteVerifier: siteVerifier{
+			secret:     secret,
+			verifyURL:  verifyURL,
+			httpClient: httpClient,
+		},
+	}
+}
+
+// Verify verifies the Turnstile token.
+func (t *Turnstile) Verify(ctx context.Context, token, remoteIP string) (*Result, error) {
+	return t.verify(ctx, token, remoteIP)
+}
+
+// ReCaptcha verifies tokens of Google reCAPTCHA v3 https://developers.google.com/recaptcha/docs/v3.
+type ReCaptcha struct {
+	siteVerifier
+	minScore float64 // minScore is the threshold from 0.0 to 1.0, tokens with a lower score are rejected.
+}
+
+// NewReCaptcha creates a new reCAPTCHA v3 verifier with the given secret key, siteverify URL, score threshold
+// and HTTP client.
+func NewReCaptcha(secret, verifyURL string, minScore float64, httpClient *http.Client) *ReCaptcha {
+	return &ReCaptcha{
+		siteVerifier: siteVerifier{
+			secret:     secret,
+			verifyURL:  verifyURL,
+			httpClient: httpClient,
+		},
+		minScore: minScore,
+	}
+}
+
+// Verify verifies the reCAPTCHA token and checks that its score is not lower than the threshold.
+func (r *ReCaptcha) Verify(ctx context.Context, token, remoteIP string) (*Result, error) {
+	result, err := r.verify(ctx, token, remoteIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Success && result.Score < r.minScore {
+		result.Success = false
+		result.ErrorCodes = append(result.ErrorCodes, ErrorCodeLowScore)
+	}
+
+	return result, nil
+}
+
+// Nop accepts any token, used if the captcha is disabled.
+type Nop struct{}
+
+// NewNop creates a new Nop verifier.
+func NewNop() *Nop {
+	return &Nop{}
+}
+
+// Verify accepts the token without verification.
+func (n *Nop) Verify(_ context.Context, _, _ string) (*Result, error) {
+	return &Result{Success: true}, nil
+}
@@ -0,0 +1,143 @@
+package captcha
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testcaptcha "github.com/samgozman/go-bloggy/testutils/test-captcha"
+)
+
+func TestHCaptcha_Verify(t *testing.T) {
+	server := testcaptcha.NewServer(testcaptcha.HCaptcha, "secret")
+	defer server.Close()
+
+	verifier := NewHCaptcha("secret", server.URL(), http.DefaultClient)
+
+	t.Run("valid token", func(t *testing.T) {
+		token := server.Solve("", 0, "127.0.0.1")
+
+		result, err := verifier.Verify(context.Background(), token, "127.0.0.1")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, testcaptcha.Hostname, result.Hostname)
+		assert.False(t, result.ChallengeTS.IsZero())
+		assert.Empty(t, result.ErrorCodes)
+	})
+
+	t.Run("token is single use", func(t *testing.T) {
+		token := server.Solve("", 0, "")
+
+		result, err := verifier.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+
+		result, err = verifier.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{ErrorCodeInvalidInput}, result.ErrorCodes)
+	})
+
+	t.Run("remote IP mismatch", func(t *testing.T) {
+		token := server.Solve("", 0, "127.0.0.1")
+
+		result, err := verifier.Verify(context.Background(), token, "10.0.0.1")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		result, err := verifier.Verify(context.Background(), "", "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{ErrorCodeMissingInput}, result.ErrorCodes)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		token := server.Solve("", 0, "")
+
+		result, err := NewHCaptcha("wrong", server.URL(), http.DefaultClient).Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{"invalid-input-secret"}, result.ErrorCodes)
+	})
+}
+
+func TestTurnstile_Verify(t *testing.T) {
+	server := testcaptcha.NewServer(testcaptcha.Turnstile, "secret")
+	defer server.Close()
+
+	verifier := NewTurnstile("secret", server.URL(), http.DefaultClient)
+
+	t.Run("valid token", func(t *testing.T) {
+		token := server.Solve("subscribe", 0, "")
+
+		result, err := verifier.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "subscribe", result.Action)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		result, err := verifier.Verify(context.Background(), "invalid", "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{ErrorCodeInvalidInput}, result.ErrorCodes)
+	})
+}
+
+func TestReCaptcha_Verify(t *testing.T) {
+	server := testcaptcha.NewServer(testcaptcha.ReCaptcha, "secret")
+	defer server.Close()
+
+	verifier := NewReCaptcha("secret", server.URL(), 0.5, http.DefaultClient)
+
+	t.Run("score above threshold", func(t *testing.T) {
+		token := server.Solve("subscribe", 0.9, "")
+
+		result, err := verifier.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.InDelta(t, 0.9, result.Score, 0)
+		assert.Equal(t, "subscribe", result.Action)
+	})
+
+	t.Run("score below threshold", func(t *testing.T) {
+		token := server.Solve("subscribe", 0.1, "")
+
+		result, err := verifier.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.InDelta(t, 0.1, result.Score, 0)
+		assert.Equal(t, []string{ErrorCodeLowScore}, result.ErrorCodes)
+	})
+}
+
+func TestSiteVerifier_Errors(t *testing.T) {
+	t.Run("provider error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := NewHCaptcha("secret", server.URL, http.DefaultClient).Verify(context.Background(), "token", "")
+		assert.ErrorIs(t, err, ErrRequest)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		_, err := NewHCaptcha("secret", server.URL, http.DefaultClient).Verify(context.Background(), "token", "")
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+	})
+
+	t.Run("provider is unavailable", func(t *testing.T) {
+		_, err := NewHCaptcha("secret", "http://127.0.0.1:0", http.DefaultClient).Verify(context.Background(), "token", "")
+		assert.ErrorIs(t, err, ErrRequest)
+	})
+}
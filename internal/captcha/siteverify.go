@@ -0,0 +1,90 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/samgozman/go-bloggy/internal/logging"
+)
+
+// verifyTimeout is the timeout of the siteverify requests, so the slow provider can't hang the requests to the API.
+const verifyTimeout = 10 * time.Second
+
+// siteVerifier verifies tokens with the siteverify API, which is the same for hCaptcha, Turnstile and reCAPTCHA.
+type siteVerifier struct {
+	secret     string
+	verifyURL  string
+	httpClient *http.Client
+}
+
+// siteVerifyResponse is the union of the siteverify responses of the supported providers.
+type siteVerifyResponse struct {
+	Success     bool     `json:"success"`
+	ChallengeTS string   `json:"challenge_ts"`
+	Hostname    string   `json:"hostname"`
+	ErrorCodes  []string `json:"error-codes"`
+	Score       float64  `json:"score"`  // reCAPTCHA v3 only
+	Action      string   `json:"action"` // reCAPTCHA v3 and Turnstile only
+}
+
+func (s *siteVerifier) verify(ctx context.Context, token, remoteIP string) (*Result, error) {
+	if token == "" {
+		return &Result{ErrorCodes: []string{ErrorCodeMissingInput}}, nil
+	}
+
+	formData := url.Values{
+		"secret":   {s.secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		formData.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.verifyURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating request: %w", ErrRequest, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req) //nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer func(b io.ReadCloser) {
+		e := b.Close()
+		if e != nil {
+			logging.FromContext(ctx).Warn("[captcha] Error closing response body", "error", e)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status code %d", ErrRequest, resp.StatusCode)
+	}
+
+	var body siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	result := &Result{
+		Success:    body.Success,
+		Score:      body.Score,
+		Action:     body.Action,
+		Hostname:   body.Hostname,
+		ErrorCodes: body.ErrorCodes,
+	}
+
+	// Note: the timestamp is informational, so the parsing error is ignored
+	if ts, err := time.Parse(time.RFC3339, body.ChallengeTS); err == nil {
+		result.ChallengeTS = ts
+	}
+
+	return result, nil
+}
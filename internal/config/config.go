@@ -1,80 +1,273 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
 	"os"
-	"strconv"
-	"strings"
+	"time"
 )
 
 type DSN string
 type JWTSecretKey string
-type HCaptchaSecret string
+type DBAutoMigrate bool
 type AdminsExternalIDs []string
 
 type Config struct {
-	GithubClientID     string            // GithubClientID is the client ID for GitHub OAuth.
-	GithubClientSecret string            // GithubClientSecret is the secret key for GitHub OAuth.
-	JWTSecretKey       JWTSecretKey      // JWTSecretKey is the secret key for JWT token creation.
-	Port               string            // Port for server to listen on.
-	DSN                DSN               // DSN - Database Source Name for Postgres.
-	AdminsExternalIDs  AdminsExternalIDs // AdminsExternalIDs list of admins allowed to auth, separated by comma.
-	HCaptchaSecret     HCaptchaSecret    // HCaptchaSecret is the secret key for HCaptcha verification.
-	MailerJet          MailerConfig      // MailerJet is the configuration for Mailjet.
-	SentryDSN          string            // SentryDSN is the DSN for Sentry.
+	GithubClientID     string            `yaml:"github_client_id"`     // GithubClientID is the client ID for GitHub OAuth.
+	GithubClientSecret string            `yaml:"github_client_secret"` // GithubClientSecret is the secret key for GitHub OAuth.
+	JWTSecretKey       JWTSecretKey      `yaml:"jwt_secret_key"`       // JWTSecretKey is the secret key for JWT token creation.
+	JWT                JWTConfig         `yaml:"jwt"`                  // JWT is the expiration of the issued tokens.
+	Port               string            `yaml:"port"`                 // Port for server to listen on.
+	DSN                DSN               `yaml:"dsn"`                  // DSN - Database Source Name for Postgres.
+	DBAutoMigrate      DBAutoMigrate     `yaml:"db_auto_migrate"`      // DBAutoMigrate applies pending migrations on startup instead of refusing to start.
+	AdminsExternalIDs  AdminsExternalIDs `yaml:"admins_external_ids"`  // AdminsExternalIDs bootstrap list of admins allowed to auth, separated by comma.
+	Captcha            CaptchaConfig     `yaml:"captcha"`              // Captcha is the configuration of the captcha provider for subscriptions.
+	MailerJet          MailerConfig      `yaml:"mailjet"`              // MailerJet is the configuration for Mailjet.
+	SentryDSN          string            `yaml:"sentry_dsn"`           // SentryDSN is the DSN for Sentry, errors are not reported if empty.
+	OIDCProviders      []OIDCProvider    `yaml:"oidc_providers"`       // OIDCProviders is the list of OpenID Connect providers allowed to sign in.
+	WebAuthn           WebAuthnConfig    `yaml:"webauthn"`             // WebAuthn is the relying party configuration for passkeys.
+	APIKeys            []string          `yaml:"api_keys"`             // APIKeys are the static keys for automation, separated by comma.
+	ShutdownTimeout    time.Duration     `yaml:"shutdown_timeout"`     // ShutdownTimeout is the time to drain the requests and stop the subsystems, e.g. "15s".
+	Metrics            MetricsConfig     `yaml:"metrics"`              // Metrics is the configuration of the Prometheus /metrics endpoint.
+	Tracing            TracingConfig     `yaml:"tracing"`              // Tracing is the configuration of the OpenTelemetry tracing.
+	Log                LogConfig         `yaml:"log"`                  // Log is the configuration of the logs.
+	RateLimit          RateLimitConfig   `yaml:"rate_limit"`           // RateLimit is the configuration of the rate limits of the public endpoints.
+	CORS               CORSConfig        `yaml:"cors"`                 // CORS is the policy of the cross-origin requests from the frontends.
+	SecurityHeaders    SecurityHeaders   `yaml:"security_headers"`     // SecurityHeaders are the security headers of all responses.
+	CacheControl       map[string]string `yaml:"cache_control"`        // CacheControl are the headers of the successful responses by the operation ID.
+	PostCache          PostCacheConfig   `yaml:"post_cache"`           // PostCache is the in-process cache of the posts.
+	RelatedPosts       int               `yaml:"related_posts"`        // RelatedPosts is the maximal number of the related posts of a post.
+	ValidateResponses  bool              `yaml:"validate_responses"`   // ValidateResponses logs the responses that don't match the API spec, e.g. on staging.
+}
+
+type JWTConfig struct {
+	SessionTTL time.Duration `yaml:"session_ttl"` // SessionTTL is how long the token issued on sign in is valid, e.g. "2m".
+	RefreshTTL time.Duration `yaml:"refresh_ttl"` // RefreshTTL is how long the token issued by /login/refresh is valid.
+	MFATTL     time.Duration `yaml:"mfa_ttl"`     // MFATTL is how long the user has to pass the second factor after the first one.
+}
+
+type CaptchaConfig struct {
+	Provider      string  `yaml:"provider"`       // Provider is one of "hcaptcha", "turnstile", "recaptcha", "pow" (built-in proof-of-work) or "none".
+	Secret        string  `yaml:"secret"`         // Secret is the secret key of the remote provider.
+	VerifyURL     string  `yaml:"verify_url"`     // VerifyURL overrides the siteverify URL of the remote provider, e.g. for a proxy.
+	MinScore      float64 `yaml:"min_score"`      // MinScore is the reCAPTCHA v3 score threshold from 0.0 to 1.0.
+	PoWDifficulty int     `yaml:"pow_difficulty"` // PoWDifficulty is the number of leading zero bits required by the proof-of-work.
+}
+
+type MetricsConfig struct {
+	Token string `yaml:"token"` // Token is required as the bearer token to scrape the metrics. Required on the API port.
+	Port  string `yaml:"port"`  // Port serves the metrics on the separate port, e.g. only reachable inside the cluster.
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // Exporter is one of "otlp", "stdout" (local debugging) or "none".
+	Endpoint    string  `yaml:"endpoint"`     // Endpoint is the URL of the OTLP/HTTP collector, e.g. "http://localhost:4318".
+	SampleRate  float64 `yaml:"sample_rate"`  // SampleRate is the share of the traces started by the service from 0.0 to 1.0.
+	ServiceName string  `yaml:"service_name"` // ServiceName is the name of the service in the traces.
+}
+
+type LogConfig struct {
+	Format string `yaml:"format"` // Format is "json" for production or "text" for development.
+	Level  string `yaml:"level"`  // Level is one of "debug", "info", "warn" or "error".
+	Redact bool   `yaml:"redact"` // Redact masks the emails and removes the tokens from the logs.
+}
+
+type RateLimitConfig struct {
+	Store           string          `yaml:"store"`            // Store is "memory" for a single instance or "postgres" to share the limits between replicas.
+	TrustedProxies  []string        `yaml:"trusted_proxies"`  // TrustedProxies are the IPs or CIDRs of the reverse proxies, whose X-Forwarded-For is trusted.
+	Operations      map[string]Rate `yaml:"operations"`       // Operations are the rates per client IP by the operation ID, e.g. "PostSubscribers: 10/1h".
+	SubscriberEmail Rate            `yaml:"subscriber_email"` // SubscriberEmail is the rate of the confirmation emails sent to the same email.
+	MagicLinkEmail  Rate            `yaml:"magic_link_email"` // MagicLinkEmail is the rate of the magic links sent to the same email.
+	MagicLinkIP     Rate            `yaml:"magic_link_ip"`    // MagicLinkIP is the rate of the magic link requests per client IP.
+	MFAUser         Rate            `yaml:"mfa_user"`         // MFAUser is the rate of the second factor attempts of the same user.
+}
+
+type CORSConfig struct {
+	AllowOrigins     []string      `yaml:"allow_origins"`     // AllowOrigins e.g. "https://example.com", "https://*.example.com" for subdomains or "*".
+	AllowMethods     []string      `yaml:"allow_methods"`     // AllowMethods are the methods of the cross-origin requests, e.g. "GET".
+	AllowCredentials bool          `yaml:"allow_credentials"` // AllowCredentials allows the cookies in the cross-origin requests.
+	MaxAge           time.Duration `yaml:"max_age"`           // MaxAge is how long the preflight response is cached, e.g. "1h".
+}
+
+type PostCacheConfig struct {
+	Enabled bool          `yaml:"enabled"` // Enabled caches the reads of the posts in memory of each instance.
+	Size    int           `yaml:"size"`    // Size is the maximal number of the cached reads, e.g. the posts and the pages of the list.
+	TTL     time.Duration `yaml:"ttl"`     // TTL is how long the reads are cached, e.g. "5m".
+	Notify  bool          `yaml:"notify"`  // Notify invalidates the caches of the other replicas with Postgres LISTEN/NOTIFY.
+}
+
+// SecurityHeaders are set to the responses, unless the value is empty. Production defaults, relax them per environment.
+type SecurityHeaders struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age"`            // HSTSMaxAge of Strict-Transport-Security, 0 disables it.
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains"` // HSTSIncludeSubdomains applies HSTS to the subdomains.
+	HSTSPreload           bool          `yaml:"hsts_preload"`            // HSTSPreload allows to submit the domain to the preload lists.
+	ContentSecurityPolicy string        `yaml:"content_security_policy"` // ContentSecurityPolicy of any served HTML.
+	ReferrerPolicy        string        `yaml:"referrer_policy"`         // ReferrerPolicy e.g. "no-referrer".
+	PermissionsPolicy     string        `yaml:"permissions_policy"`      // PermissionsPolicy disables the browser features, e.g. "camera=()".
+}
+
+type WebAuthnConfig struct {
+	RPID          string   `yaml:"rp_id"`           // RPID is the domain of the admin panel, e.g. "example.com".
+	RPDisplayName string   `yaml:"rp_display_name"` // RPDisplayName is the name shown by the authenticator.
+	RPOrigins     []string `yaml:"rp_origins"`      // RPOrigins are the allowed origins of the admin panel, e.g. "https://example.com".
+}
+
+type OIDCProvider struct {
+	Name         string   `yaml:"name"`          // Name of the provider, used in the /login/{provider}/authorize path.
+	IssuerURL    string   `yaml:"issuer_url"`    // IssuerURL is the URL of the provider used for discovery.
+	ClientID     string   `yaml:"client_id"`     // ClientID is the client ID of the OAuth application in the provider.
+	ClientSecret string   `yaml:"client_secret"` // ClientSecret is the secret of the OAuth application in the provider.
+	RedirectURL  string   `yaml:"redirect_url"`  // RedirectURL is the frontend URL that receives the authorization code.
+	Scopes       []string `yaml:"scopes"`        // Scopes to request from the provider.
 }
 
 type MailerConfig struct {
-	PublicKey                    string // PublicKey is the public key for Mailjet API.
-	PrivateKey                   string // PrivateKey is the private key for Mailjet API.
-	FromEmail                    string // FromEmail is the email address to send emails from.
-	FromName                     string // FromName is the name to send emails from.
-	ConfirmationTemplateID       int    // ConfirmationTemplateID is the ID of the Mailjet template for confirmation emails.
-	ConfirmationTemplateURLParam string // ConfirmationTemplateURLParam e.g. "https://example.com/confirm?token="
-	PostTemplateID               int    // PostTemplateID is the ID of the Mailjet template for post-emails.
-	PostTemplateURLParam         string // PostTemplateURLParam e.g. "https://example.com/post/" to append the posts slug.
-	UnsubscribeURLParam          string // UnsubscribeURLParam e.g. "https://example.com/unsubscribe?id="
-}
-
-// NewConfigFromEnv creates a new Config.
-func NewConfigFromEnv() *Config {
-	admins := getEnvOrPanic("ADMINS_EXTERNAL_IDS")
-	var adminsExternalIDs AdminsExternalIDs
-	if admins != "" {
-		// admins is a comma-separated list of external IDs
-		adminsExternalIDs = strings.Split(admins, ",")
+	Enabled                      bool   `yaml:"enabled"`                         // Enabled is false to only log the emails instead of sending them.
+	PublicKey                    string `yaml:"public_key"`                      // PublicKey is the public key for Mailjet API.
+	PrivateKey                   string `yaml:"private_key"`                     // PrivateKey is the private key for Mailjet API.
+	FromEmail                    string `yaml:"from_email"`                      // FromEmail is the email address to send emails from.
+	FromName                     string `yaml:"from_name"`                       // FromName is the name to send emails from.
+	ConfirmationTemplateID       int    `yaml:"confirmation_template_id"`        // ConfirmationTemplateID is the ID of the Mailjet template for confirmation emails.
+	ConfirmationTemplateURLParam string `yaml:"confirmation_template_url_param"` // ConfirmationTemplateURLParam e.g. "https://example.com/confirm?token="
+	PostTemplateID               int    `yaml:"post_template_id"`                // PostTemplateID is the ID of the Mailjet template for post-emails.
+	PostTemplateURLParam         string `yaml:"post_template_url_param"`         // PostTemplateURLParam e.g. "https://example.com/post/" to append the posts slug.
+	UnsubscribeURLParam          string `yaml:"unsubscribe_url_param"`           // UnsubscribeURLParam e.g. "https://example.com/unsubscribe?id="
+	MagicLinkTemplateID          int    `yaml:"magic_link_template_id"`          // MagicLinkTemplateID is the ID of the Mailjet template for login emails.
+	MagicLinkTemplateURLParam    string `yaml:"magic_link_template_url_param"`   // MagicLinkTemplateURLParam e.g. "https://example.com/login/email?token="
+}
+
+// ValidationError lists all problems found in the config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	msg := "invalid config:"
+	for _, p := range e.Problems {
+		msg += "\n  - " + p
 	}
 
-	confirmationTemplateID, _ := strconv.Atoi(getEnvOrPanic("MAILJET_CONFIRMATION_TEMPLATE_ID"))
-	postTemplateID, _ := strconv.Atoi(getEnvOrPanic("MAILJET_POST_TEMPLATE_ID"))
+	return msg
+}
 
+// Default returns the Config with the default values of the optional settings.
+func Default() *Config {
 	return &Config{
-		GithubClientID:     getEnvOrPanic("GITHUB_CLIENT_ID"),
-		GithubClientSecret: getEnvOrPanic("GITHUB_CLIENT_SECRET"),
-		JWTSecretKey:       JWTSecretKey(getEnvOrPanic("JWT_SECRET_KEY")),
-		Port:               getEnvOrPanic("PORT"),
-		DSN:                DSN(getEnvOrPanic("DSN")),
-		AdminsExternalIDs:  adminsExternalIDs,
-		HCaptchaSecret:     HCaptchaSecret(getEnvOrPanic("HCAPTCHA_SECRET")),
+		Port:            "3000",
+		ShutdownTimeout: 15 * time.Second,
+		JWT: JWTConfig{
+			SessionTTL: 2 * time.Minute,
+			RefreshTTL: 2 * time.Minute,
+			MFATTL:     5 * time.Minute,
+		},
+		Captcha: CaptchaConfig{
+			Provider:      "hcaptcha",
+			MinScore:      0.5,
+			PoWDifficulty: 20,
+		},
 		MailerJet: MailerConfig{
-			PublicKey:                    getEnvOrPanic("MAILJET_PUBLIC_KEY"),
-			PrivateKey:                   getEnvOrPanic("MAILJET_PRIVATE_KEY"),
-			FromEmail:                    getEnvOrPanic("MAILJET_MAIL_FROM"),
-			FromName:                     getEnvOrPanic("MAILJET_MAIL_FROM_NAME"),
-			ConfirmationTemplateID:       confirmationTemplateID,
-			ConfirmationTemplateURLParam: getEnvOrPanic("MAILJET_CONFIRMATION_TEMPLATE_URL_PARAM"),
-			PostTemplateID:               postTemplateID,
-			PostTemplateURLParam:         getEnvOrPanic("MAILJET_POST_TEMPLATE_URL_PARAM"),
-			UnsubscribeURLParam:          getEnvOrPanic("MAILJET_UNSUBSCRIBE_URL_PARAM"),
+			Enabled: true,
+		},
+		Log: LogConfig{
+			Format: "json",
+			Level:  "info",
+			Redact: true,
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"*"},
+			AllowMethods: []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+			MaxAge:       time.Hour,
+		},
+		SecurityHeaders: SecurityHeaders{
+			HSTSMaxAge:            365 * 24 * time.Hour,
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+			ReferrerPolicy:        "no-referrer",
+			PermissionsPolicy:     "camera=(), geolocation=(), microphone=(), payment=(), usb=()",
+		},
+		CacheControl: map[string]string{
+			"GetPosts":                 "public, max-age=60",
+			"GetPostsSlug":             "public, max-age=60",
+			"GetPostsArchive":          "public, max-age=60",
+			"GetPostsArchiveYearMonth": "public, max-age=60",
+			"GetPostsSlugRelated":      "public, max-age=60",
+		},
+		PostCache: PostCacheConfig{
+			Enabled: true,
+			Size:    1000,
+			TTL:     5 * time.Minute,
+			Notify:  true,
+		},
+		RelatedPosts: 3,
+		RateLimit: RateLimitConfig{
+			Store: "memory",
+			Operations: map[string]Rate{
+				"PostSubscribers":          {Limit: 10, Period: time.Hour},
+				"PostSubscribersConfirm":   {Limit: 30, Period: time.Hour},
+				"PostLoginGithubAuthorize": {Limit: 30, Period: time.Hour},
+			},
+			SubscriberEmail: Rate{Limit: 3, Period: time.Hour},
+			MagicLinkEmail:  Rate{Limit: 3, Period: time.Hour},
+			MagicLinkIP:     Rate{Limit: 20, Period: time.Hour},
+			MFAUser:         Rate{Limit: 10, Period: 15 * time.Minute},
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			SampleRate:  1.0,
+			ServiceName: "go-bloggy",
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:          "localhost",
+			RPDisplayName: "go-bloggy",
+			RPOrigins:     []string{"http://localhost:3000"},
 		},
-		SentryDSN: getEnvOrPanic("SENTRY_DSN"),
 	}
 }
 
-// getEnvOrPanic returns the value of the environment variable or panics if it is not set.
-func getEnvOrPanic(key string) string {
-	value, ok := os.LookupEnv(key)
-	if !ok {
-		panic("missing env variable " + key)
+// Load reads the Config with Read and validates it. All problems are reported at once with ValidationError.
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+
+	var problems []string
+	var vErr *ValidationError
+	switch {
+	case errors.As(err, &vErr):
+		problems = vErr.Problems
+	case err != nil:
+		return nil, err
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+// Read reads the Config without validation: the defaults are overridden by the YAML file (if path is not empty),
+// which is overridden by the environment variables. Each variable can be also read from the file set
+// in the <NAME>_FILE variable, e.g. JWT_SECRET_KEY_FILE=/run/secrets/jwt.
+// If some variables are malformed, the Config is returned with ValidationError.
+func Read(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
 	}
-	return value
+
+	env := &envReader{}
+	env.apply(cfg)
+	if len(env.problems) > 0 {
+		return cfg, &ValidationError{Problems: env.problems}
+	}
+
+	return cfg, nil
 }
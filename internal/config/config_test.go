@@ -2,15 +2,41 @@ package config
 
 import (
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
-func TestNewConfigFromEnv(t *testing.T) {
+// setRequiredEnv sets the required environment variables with mail and captcha disabled.
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("GITHUB_CLIENT_ID", "test_id")
+	t.Setenv("GITHUB_CLIENT_SECRET", "test_secret")
+	t.Setenv("JWT_SECRET_KEY", "test_jwt")
+	t.Setenv("DSN", "test_dsn")
+	t.Setenv("CAPTCHA_PROVIDER", "none")
+	t.Setenv("MAIL_ENABLED", "false")
+}
+
+// writeFile writes the content to the file in the temporary directory of the test and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
 	t.Setenv("GITHUB_CLIENT_ID", "test_id")
 	t.Setenv("GITHUB_CLIENT_SECRET", "test_secret")
 	t.Setenv("JWT_SECRET_KEY", "test_jwt")
 	t.Setenv("PORT", "3000")
 	t.Setenv("DSN", "test_dsn")
+	t.Setenv("DB_AUTO_MIGRATE", "true")
 	t.Setenv("ADMINS_EXTERNAL_IDS", "test_admin1,test_admin2")
 	t.Setenv("HCAPTCHA_SECRET", "test_h")
 	t.Setenv("MAILJET_PUBLIC_KEY", "test_public_key")
@@ -22,17 +48,28 @@ func TestNewConfigFromEnv(t *testing.T) {
 	t.Setenv("MAILJET_POST_TEMPLATE_ID", "2")
 	t.Setenv("MAILJET_POST_TEMPLATE_URL_PARAM", "test_post_template_url_param")
 	t.Setenv("MAILJET_UNSUBSCRIBE_URL_PARAM", "test_unsubscribe_url_param")
+	t.Setenv("MAILJET_MAGIC_LINK_TEMPLATE_ID", "3")
+	t.Setenv("MAILJET_MAGIC_LINK_TEMPLATE_URL_PARAM", "test_magic_link_template_url_param")
 	t.Setenv("SENTRY_DSN", "test_sentry_dsn")
+	t.Setenv("API_KEYS", "test_key1,test_key2")
+	t.Setenv("VALIDATE_RESPONSES", "true")
 
-	config := NewConfigFromEnv()
+	config, err := Load("")
+	assert.NoError(t, err)
 
 	assert.Equal(t, "test_id", config.GithubClientID)
 	assert.Equal(t, "test_secret", config.GithubClientSecret)
 	assert.Equal(t, "test_jwt", string(config.JWTSecretKey))
 	assert.Equal(t, "3000", config.Port)
 	assert.Equal(t, "test_dsn", string(config.DSN))
+	assert.True(t, bool(config.DBAutoMigrate))
 	assert.Equal(t, []string{"test_admin1", "test_admin2"}, []string(config.AdminsExternalIDs))
-	assert.Equal(t, "test_h", string(config.HCaptchaSecret))
+	assert.Equal(t, "hcaptcha", config.Captcha.Provider)
+	assert.Equal(t, "test_h", config.Captcha.Secret)
+	assert.Equal(t, "", config.Captcha.VerifyURL)
+	assert.InDelta(t, 0.5, config.Captcha.MinScore, 0)
+	assert.Equal(t, 20, config.Captcha.PoWDifficulty)
+	assert.True(t, config.MailerJet.Enabled)
 	assert.Equal(t, "test_public_key", config.MailerJet.PublicKey)
 	assert.Equal(t, "test_private_key", config.MailerJet.PrivateKey)
 	assert.Equal(t, "test_mail_from", config.MailerJet.FromEmail)
@@ -42,18 +79,579 @@ func TestNewConfigFromEnv(t *testing.T) {
 	assert.Equal(t, 2, config.MailerJet.PostTemplateID)
 	assert.Equal(t, "test_post_template_url_param", config.MailerJet.PostTemplateURLParam)
 	assert.Equal(t, "test_unsubscribe_url_param", config.MailerJet.UnsubscribeURLParam)
+	assert.Equal(t, "localhost", config.WebAuthn.RPID)
+	assert.Equal(t, "go-bloggy", config.WebAuthn.RPDisplayName)
+	assert.Equal(t, []string{"http://localhost:3000"}, config.WebAuthn.RPOrigins)
+	assert.Equal(t, 3, config.MailerJet.MagicLinkTemplateID)
+	assert.Equal(t, "test_magic_link_template_url_param", config.MailerJet.MagicLinkTemplateURLParam)
+	assert.Equal(t, "test_sentry_dsn", config.SentryDSN)
+	assert.Equal(t, []string{"test_key1", "test_key2"}, config.APIKeys)
+	assert.True(t, config.ValidateResponses)
+}
+
+func TestLoad_Captcha(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("HCAPTCHA_SECRET", "test_h")
+	t.Setenv("CAPTCHA_PROVIDER", "recaptcha")
+	t.Setenv("CAPTCHA_SECRET", "test_r")
+	t.Setenv("CAPTCHA_VERIFY_URL", "http://localhost/siteverify")
+	t.Setenv("RECAPTCHA_MIN_SCORE", "0.7")
+	t.Setenv("POW_DIFFICULTY", "16")
+
+	config, err := Load("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, CaptchaConfig{
+		Provider:      "recaptcha",
+		Secret:        "test_r",
+		VerifyURL:     "http://localhost/siteverify",
+		MinScore:      0.7,
+		PoWDifficulty: 16,
+	}, config.Captcha)
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setRequiredEnv(t)
+
+	config, err := Load("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3000", config.Port)
+	assert.False(t, bool(config.DBAutoMigrate))
+	assert.Equal(t, "none", config.Captcha.Provider)
+	assert.False(t, config.MailerJet.Enabled)
+	assert.Empty(t, config.SentryDSN)
+	assert.Nil(t, config.OIDCProviders)
+	assert.Nil(t, config.APIKeys)
+	assert.Equal(t, 15*time.Second, config.ShutdownTimeout)
+	assert.Equal(t, JWTConfig{SessionTTL: 2 * time.Minute, RefreshTTL: 2 * time.Minute, MFATTL: 5 * time.Minute}, config.JWT)
+	assert.Equal(t, MetricsConfig{}, config.Metrics)
+	assert.Equal(t, TracingConfig{Exporter: "none", SampleRate: 1.0, ServiceName: "go-bloggy"}, config.Tracing)
+	assert.Equal(t, LogConfig{Format: "json", Level: "info", Redact: true}, config.Log)
+	assert.Equal(t, "memory", config.RateLimit.Store)
+	assert.Nil(t, config.RateLimit.TrustedProxies)
+	assert.Equal(t, Rate{Limit: 10, Period: time.Hour}, config.RateLimit.Operations["PostSubscribers"])
+	assert.Equal(t, Rate{Limit: 3, Period: time.Hour}, config.RateLimit.SubscriberEmail)
+	assert.Equal(t, Rate{Limit: 3, Period: time.Hour}, config.RateLimit.MagicLinkEmail)
+	assert.Equal(t, Rate{Limit: 20, Period: time.Hour}, config.RateLimit.MagicLinkIP)
+	assert.Equal(t, Rate{Limit: 10, Period: 15 * time.Minute}, config.RateLimit.MFAUser)
+	assert.Equal(t, []string{"*"}, config.CORS.AllowOrigins)
+	assert.False(t, config.CORS.AllowCredentials)
+	assert.Equal(t, 365*24*time.Hour, config.SecurityHeaders.HSTSMaxAge)
+	assert.Equal(t, "no-referrer", config.SecurityHeaders.ReferrerPolicy)
+	assert.Equal(t, "public, max-age=60", config.CacheControl["GetPostsSlug"])
+	assert.Equal(t, PostCacheConfig{Enabled: true, Size: 1000, TTL: 5 * time.Minute, Notify: true}, config.PostCache)
+	assert.Equal(t, 3, config.RelatedPosts)
+}
+
+func TestLoad_File(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+github_client_id: file_id
+github_client_secret: file_secret
+jwt_secret_key: file_jwt
+port: "8080"
+dsn: file_dsn
+shutdown_timeout: 30s
+jwt:
+  session_ttl: 15m
+  mfa_ttl: 1m
+metrics:
+  port: "9100"
+tracing:
+  exporter: otlp
+  endpoint: http://collector:4318
+  sample_rate: 0.25
+rate_limit:
+  store: postgres
+  trusted_proxies: [10.0.0.0/8]
+  operations:
+    PostSubscribers: 5/1m
+  magic_link_ip: 50/1h
+cors:
+  allow_origins: ["https://example.com", "https://*.example.com"]
+  allow_methods: [GET, POST]
+  allow_credentials: true
+  max_age: 10m
+security_headers:
+  hsts_max_age: 0s
+  content_security_policy: "default-src 'self'"
+cache_control:
+  GetPosts: public, max-age=30
+post_cache:
+  size: 100
+  notify: false
+related_posts: 5
+admins_external_ids: [admin1, admin2]
+captcha:
+  provider: pow
+  pow_difficulty: 18
+mailjet:
+  enabled: false
+oidc_providers:
+  - name: gitlab
+    issuer_url: https://gitlab.com
+    client_id: gitlab_id
+    redirect_url: https://example.com/gitlab
+webauthn:
+  rp_id: example.com
+  rp_origins: [https://example.com]
+`)
+
+	// Note: environment variables override the file, e.g. for the secrets
+	t.Setenv("PORT", "9090")
+	t.Setenv("OIDC_GITLAB_CLIENT_SECRET", "env_gitlab_secret")
+	t.Setenv("METRICS_TOKEN", "env_metrics_token")
+	t.Setenv("RATE_LIMIT_OPERATIONS", "PostLoginEmail=2/1s")
+	t.Setenv("CACHE_CONTROL", "GetPostsSlug=public, max-age=300, stale-while-revalidate=60;GetUsersMe=no-store")
+	t.Setenv("POST_CACHE_TTL", "1m")
+	t.Setenv("JWT_REFRESH_TTL", "10m")
+	t.Setenv("RATE_LIMIT_MFA_USER", "5/1m")
+
+	config, err := Load(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "file_id", config.GithubClientID)
+	assert.Equal(t, "file_jwt", string(config.JWTSecretKey))
+	assert.Equal(t, "9090", config.Port)
+	assert.Equal(t, "file_dsn", string(config.DSN))
+	assert.Equal(t, 30*time.Second, config.ShutdownTimeout)
+	assert.Equal(t, JWTConfig{SessionTTL: 15 * time.Minute, RefreshTTL: 10 * time.Minute, MFATTL: time.Minute}, config.JWT)
+	assert.Equal(t, MetricsConfig{Token: "env_metrics_token", Port: "9100"}, config.Metrics)
+	assert.Equal(t, TracingConfig{
+		Exporter:    "otlp",
+		Endpoint:    "http://collector:4318",
+		SampleRate:  0.25,
+		ServiceName: "go-bloggy",
+	}, config.Tracing)
+	assert.Equal(t, "postgres", config.RateLimit.Store)
+	assert.Equal(t, []string{"10.0.0.0/8"}, config.RateLimit.TrustedProxies)
+	assert.Equal(t, map[string]Rate{
+		"PostSubscribers":          {Limit: 5, Period: time.Minute},
+		"PostSubscribersConfirm":   {Limit: 30, Period: time.Hour},
+		"PostLoginGithubAuthorize": {Limit: 30, Period: time.Hour},
+		"PostLoginEmail":           {Limit: 2, Period: time.Second},
+	}, config.RateLimit.Operations)
+	assert.Equal(t, Rate{Limit: 50, Period: time.Hour}, config.RateLimit.MagicLinkIP)
+	assert.Equal(t, Rate{Limit: 5, Period: time.Minute}, config.RateLimit.MFAUser)
+	assert.Equal(t, CORSConfig{
+		AllowOrigins:     []string{"https://example.com", "https://*.example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, config.CORS)
+	assert.Equal(t, SecurityHeaders{
+		ContentSecurityPolicy: "default-src 'self'",
+		ReferrerPolicy:        "no-referrer",
+		PermissionsPolicy:     "camera=(), geolocation=(), microphone=(), payment=(), usb=()",
+	}, config.SecurityHeaders)
+	// Note: the operations of the file are merged into the defaults
+	assert.Equal(t, map[string]string{
+		"GetPosts":                 "public, max-age=30",
+		"GetPostsSlug":             "public, max-age=300, stale-while-revalidate=60",
+		"GetPostsArchive":          "public, max-age=60",
+		"GetPostsArchiveYearMonth": "public, max-age=60",
+		"GetPostsSlugRelated":      "public, max-age=60",
+		"GetUsersMe":               "no-store",
+	}, config.CacheControl)
+	assert.Equal(t, PostCacheConfig{Enabled: true, Size: 100, TTL: time.Minute, Notify: false}, config.PostCache)
+	assert.Equal(t, 5, config.RelatedPosts)
+	assert.Equal(t, []string{"admin1", "admin2"}, []string(config.AdminsExternalIDs))
+	assert.Equal(t, "pow", config.Captcha.Provider)
+	assert.Equal(t, 18, config.Captcha.PoWDifficulty)
+	assert.InDelta(t, 0.5, config.Captcha.MinScore, 0)
+	assert.False(t, config.MailerJet.Enabled)
+	assert.Equal(t, []OIDCProvider{
+		{
+			Name:         "gitlab",
+			IssuerURL:    "https://gitlab.com",
+			ClientID:     "gitlab_id",
+			ClientSecret: "env_gitlab_secret",
+			RedirectURL:  "https://example.com/gitlab",
+			Scopes:       []string{"openid", "profile", "email"},
+		},
+	}, config.OIDCProviders)
+	assert.Equal(t, "example.com", config.WebAuthn.RPID)
+	assert.Equal(t, "go-bloggy", config.WebAuthn.RPDisplayName)
+	assert.Equal(t, []string{"https://example.com"}, config.WebAuthn.RPOrigins)
+}
+
+func TestLoad_SecretFiles(t *testing.T) {
+	setRequiredEnv(t)
+	_ = os.Unsetenv("JWT_SECRET_KEY") // restored by t.Setenv in setRequiredEnv
+	t.Setenv("JWT_SECRET_KEY_FILE", writeFile(t, "jwt", "file_jwt\n"))
+	t.Setenv("GITHUB_CLIENT_SECRET_FILE", writeFile(t, "github", "ignored"))
+
+	config, err := Load("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "file_jwt", string(config.JWTSecretKey))
+	// Note: the variable itself has priority over the file
+	assert.Equal(t, "test_secret", config.GithubClientSecret)
 }
 
-func TestGetEnvOrPanic(t *testing.T) {
+func TestLoad_Errors(t *testing.T) {
+	t.Run("reports all problems", func(t *testing.T) {
+		t.Setenv("PORT", "http")
+		t.Setenv("MAILJET_POST_TEMPLATE_ID", "abc")
+		t.Setenv("RECAPTCHA_MIN_SCORE", "high")
+		t.Setenv("DB_AUTO_MIGRATE", "yes please")
+		t.Setenv("DSN_FILE", filepath.Join(t.TempDir(), "missing"))
+		t.Setenv("SHUTDOWN_TIMEOUT", "15")
+		t.Setenv("GITHUB_CLIENT_ID", "test_id")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Contains(t, vErr.Problems, `MAILJET_POST_TEMPLATE_ID must be an integer, got "abc"`)
+		assert.Contains(t, vErr.Problems, `RECAPTCHA_MIN_SCORE must be a number, got "high"`)
+		assert.Contains(t, vErr.Problems, `DB_AUTO_MIGRATE must be true or false, got "yes please"`)
+		assert.Contains(t, vErr.Problems, `SHUTDOWN_TIMEOUT must be a duration, e.g. "15s", got "15"`)
+		assert.Contains(t, vErr.Problems, "github_client_secret (GITHUB_CLIENT_SECRET) is required")
+		assert.Contains(t, vErr.Problems, "jwt_secret_key (JWT_SECRET_KEY) is required")
+		assert.Contains(t, vErr.Problems, "dsn (DSN) is required")
+		assert.Contains(t, vErr.Problems, `port (PORT) must be a number from 1 to 65535, got "http"`)
+		assert.Contains(t, vErr.Problems, "captcha.secret (CAPTCHA_SECRET) is required")
+		assert.Contains(t, vErr.Problems, "mailjet.public_key (MAILJET_PUBLIC_KEY) is required")
+		assert.Contains(t, vErr.Problems, "mailjet.confirmation_template_id (MAILJET_CONFIRMATION_TEMPLATE_ID) "+
+			"must be a positive template ID")
+		assert.Len(t, vErr.Problems, 21)
+		assert.Contains(t, err.Error(), "invalid config:\n  - ")
+	})
+
+	t.Run("optional subsystems are not validated if disabled", func(t *testing.T) {
+		setRequiredEnv(t)
+
+		_, err := Load("")
+		assert.NoError(t, err)
+	})
+
+	t.Run("github is optional", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("GITHUB_CLIENT_ID", "")
+		t.Setenv("GITHUB_CLIENT_SECRET", "")
+
+		_, err := Load("")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid captcha provider", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CAPTCHA_PROVIDER", "unknown")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			`captcha.provider (CAPTCHA_PROVIDER) must be one of [hcaptcha turnstile recaptcha pow none], got "unknown"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid proof-of-work difficulty", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CAPTCHA_PROVIDER", "pow")
+		t.Setenv("POW_DIFFICULTY", "64")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{"captcha.pow_difficulty (POW_DIFFICULTY) must be from 1 to 32, got 64"}, vErr.Problems)
+	})
+
+	t.Run("non-positive shutdown timeout", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("SHUTDOWN_TIMEOUT", "0s")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{"shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive, got 0s"}, vErr.Problems)
+	})
+
+	t.Run("metrics on the API port", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("METRICS_PORT", "3000")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{"metrics.port (METRICS_PORT) must differ from the port (PORT)"}, vErr.Problems)
+	})
+
+	t.Run("invalid log", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("LOG_FORMAT", "yaml")
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			`log.format (LOG_FORMAT) must be one of [json text], got "yaml"`,
+			`log.level (LOG_LEVEL) must be one of debug, info, warn or error, got "verbose"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid tracing", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("TRACING_EXPORTER", "stdout")
+		t.Setenv("TRACING_ENDPOINT", "localhost:4318")
+		t.Setenv("TRACING_SAMPLE_RATE", "2")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			"tracing.sample_rate (TRACING_SAMPLE_RATE) must be from 0.0 to 1.0, got 2",
+			`tracing.endpoint (TRACING_ENDPOINT) must be a URL, e.g. "http://localhost:4318", got "localhost:4318"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid rate limit", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("RATE_LIMIT_STORE", "redis")
+		t.Setenv("RATE_LIMIT_TRUSTED_PROXIES", "10.0.0.0/8,proxy")
+		t.Setenv("RATE_LIMIT_OPERATIONS", "PostSubscribers=10,PostLoginEmail")
+		t.Setenv("RATE_LIMIT_SUBSCRIBER_EMAIL", "3/0s")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			`RATE_LIMIT_OPERATIONS: PostSubscribers: rate must be "<limit>/<period>", e.g. "10/1h", got "10"`,
+			`RATE_LIMIT_OPERATIONS must be a list of "<operation>=<rate>", got "PostLoginEmail"`,
+			`RATE_LIMIT_SUBSCRIBER_EMAIL: rate period must be a positive duration, e.g. "1h", got "0s"`,
+			`rate_limit.store (RATE_LIMIT_STORE) must be one of [memory postgres], got "redis"`,
+			`rate_limit.trusted_proxies (RATE_LIMIT_TRUSTED_PROXIES) must be IPs or CIDRs, e.g. "10.0.0.0/8", got "proxy"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid cache control", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CACHE_CONTROL", "GetPosts=no-cache;GetPostsSlug")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			`CACHE_CONTROL must be a list of "<operation>=<value>", got "GetPostsSlug"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid post cache", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("POST_CACHE_SIZE", "0")
+		t.Setenv("POST_CACHE_TTL", "-1m")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			"post_cache.size (POST_CACHE_SIZE) must be positive, got 0",
+			"post_cache.ttl (POST_CACHE_TTL) must be positive, got -1m0s",
+		}, vErr.Problems)
+	})
+
+	t.Run("post cache is not validated if disabled", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("POST_CACHE_ENABLED", "false")
+		t.Setenv("POST_CACHE_SIZE", "0")
+
+		_, err := Load("")
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid jwt ttls", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("JWT_SESSION_TTL", "0s")
+		t.Setenv("JWT_MFA_TTL", "-1m")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			"jwt.session_ttl (JWT_SESSION_TTL) must be positive, got 0s",
+			"jwt.mfa_ttl (JWT_MFA_TTL) must be positive, got -1m0s",
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid related posts", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("RELATED_POSTS", "0")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{"related_posts (RELATED_POSTS) must be positive, got 0"}, vErr.Problems)
+	})
+
+	t.Run("invalid cors", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("CORS_ALLOW_ORIGINS", "*,https://example.com/,https://*.example.com,ftp://example.com,https://a.*.com")
+		t.Setenv("CORS_ALLOW_METHODS", "GET,TRACE")
+		t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+		t.Setenv("CORS_MAX_AGE", "-1s")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		origins := `cors.allow_origins (CORS_ALLOW_ORIGINS) must be origins, e.g. "https://example.com" or "https://*.example.com", got `
+		assert.Equal(t, []string{
+			origins + `"https://example.com/"`,
+			origins + `"ftp://example.com"`,
+			origins + `"https://a.*.com"`,
+			`cors.allow_credentials (CORS_ALLOW_CREDENTIALS) can't be used with any origin "*"`,
+			`cors.allow_methods (CORS_ALLOW_METHODS) must be some of [GET HEAD POST PUT PATCH DELETE], got "TRACE"`,
+			"cors.max_age (CORS_MAX_AGE) must not be negative, got -1s",
+		}, vErr.Problems)
+	})
+
+	t.Run("invalid security headers", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("HSTS_PRELOAD", "true")
+		t.Setenv("REFERRER_POLICY", "never")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			"security_headers.hsts_preload (HSTS_PRELOAD) requires hsts_include_subdomains " +
+				"and hsts_max_age of at least 1 year (8760h)",
+			"security_headers.referrer_policy (REFERRER_POLICY) must be one of [no-referrer no-referrer-when-downgrade " +
+				"origin origin-when-cross-origin same-origin strict-origin strict-origin-when-cross-origin unsafe-url], " +
+				`got "never"`,
+		}, vErr.Problems)
+	})
+
+	t.Run("malformed rate in config file", func(t *testing.T) {
+		_, err := Load(writeFile(t, "config.yaml", "rate_limit:\n  subscriber_email: 3 per hour"))
+		assert.ErrorContains(t, err, `rate must be "<limit>/<period>"`)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("malformed config file", func(t *testing.T) {
+		_, err := Load(writeFile(t, "config.yaml", "port: [1"))
+		assert.ErrorContains(t, err, "parse config file")
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("does not validate", func(t *testing.T) {
+		t.Setenv("DSN", "test_dsn")
+
+		config, err := Read("")
+		assert.NoError(t, err)
+		assert.Equal(t, "test_dsn", string(config.DSN))
+	})
+
+	t.Run("returns config with malformed variables", func(t *testing.T) {
+		t.Setenv("DSN", "test_dsn")
+		t.Setenv("POW_DIFFICULTY", "hard")
+
+		config, err := Read("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{`POW_DIFFICULTY must be an integer, got "hard"`}, vErr.Problems)
+		assert.Equal(t, "test_dsn", string(config.DSN))
+	})
+}
+
+func TestOIDCProvidersFromEnv(t *testing.T) {
 	t.Run("OK", func(t *testing.T) {
-		t.Setenv("TEST_ENV", "test_value")
+		t.Setenv("OIDC_PROVIDERS", "gitlab, Key-Cloak")
+		t.Setenv("OIDC_GITLAB_ISSUER_URL", "https://gitlab.com")
+		t.Setenv("OIDC_GITLAB_CLIENT_ID", "gitlab_id")
+		t.Setenv("OIDC_GITLAB_CLIENT_SECRET", "gitlab_secret")
+		t.Setenv("OIDC_GITLAB_REDIRECT_URL", "https://example.com/gitlab")
+		t.Setenv("OIDC_KEY_CLOAK_ISSUER_URL", "https://keycloak.example.com/realms/blog")
+		t.Setenv("OIDC_KEY_CLOAK_CLIENT_ID", "keycloak_id")
+		t.Setenv("OIDC_KEY_CLOAK_CLIENT_SECRET", "keycloak_secret")
+		t.Setenv("OIDC_KEY_CLOAK_REDIRECT_URL", "https://example.com/keycloak")
+		t.Setenv("OIDC_KEY_CLOAK_SCOPES", "openid,email")
+
+		var providers []OIDCProvider
+		r := &envReader{}
+		r.oidcProviders(&providers)
+
+		assert.Empty(t, r.problems)
+		assert.Equal(t, []OIDCProvider{
+			{
+				Name:         "gitlab",
+				IssuerURL:    "https://gitlab.com",
+				ClientID:     "gitlab_id",
+				ClientSecret: "gitlab_secret",
+				RedirectURL:  "https://example.com/gitlab",
+				Scopes:       []string{"openid", "profile", "email"},
+			},
+			{
+				Name:         "key-cloak",
+				IssuerURL:    "https://keycloak.example.com/realms/blog",
+				ClientID:     "keycloak_id",
+				ClientSecret: "keycloak_secret",
+				RedirectURL:  "https://example.com/keycloak",
+				Scopes:       []string{"openid", "email"},
+			},
+		}, providers)
+	})
 
-		value := getEnvOrPanic("TEST_ENV")
+	t.Run("Empty", func(t *testing.T) {
+		var providers []OIDCProvider
+		(&envReader{}).oidcProviders(&providers)
 
-		assert.Equal(t, "test_value", value)
+		assert.Nil(t, providers)
 	})
 
-	t.Run("Panic", func(t *testing.T) {
-		assert.Panics(t, func() { getEnvOrPanic("NON_EXISTING_ENV") })
+	t.Run("Missing provider config", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("OIDC_PROVIDERS", "gitlab")
+		t.Setenv("OIDC_GITLAB_CLIENT_ID", "gitlab_id")
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			"oidc_providers[gitlab].issuer_url (OIDC_GITLAB_ISSUER_URL) is required",
+			"oidc_providers[gitlab].client_secret (OIDC_GITLAB_CLIENT_SECRET) is required",
+			"oidc_providers[gitlab].redirect_url (OIDC_GITLAB_REDIRECT_URL) is required",
+		}, vErr.Problems)
+	})
+
+	t.Run("Invalid provider names", func(t *testing.T) {
+		setRequiredEnv(t)
+		t.Setenv("OIDC_PROVIDERS", "email,key_cloak")
+		for _, name := range []string{"EMAIL", "KEY_CLOAK"} {
+			t.Setenv("OIDC_"+name+"_ISSUER_URL", "https://example.com")
+			t.Setenv("OIDC_"+name+"_CLIENT_ID", "client_id")
+			t.Setenv("OIDC_"+name+"_CLIENT_SECRET", "client_secret")
+			t.Setenv("OIDC_"+name+"_REDIRECT_URL", "https://example.com/callback")
+		}
+
+		_, err := Load("")
+
+		var vErr *ValidationError
+		assert.ErrorAs(t, err, &vErr)
+		assert.Equal(t, []string{
+			`oidc_providers[0].name "email" is reserved`,
+			`oidc_providers[1].name "key_cloak" must contain only a-z, 0-9 and -`,
+		}, vErr.Problems)
 	})
 }
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReader overrides the Config with the environment variables and collects the malformed ones.
+type envReader struct {
+	problems []string
+}
+
+// apply overrides the cfg with the set environment variables.
+func (r *envReader) apply(cfg *Config) {
+	r.string("GITHUB_CLIENT_ID", &cfg.GithubClientID)
+	r.string("GITHUB_CLIENT_SECRET", &cfg.GithubClientSecret)
+	r.string("JWT_SECRET_KEY", (*string)(&cfg.JWTSecretKey))
+	r.duration("JWT_SESSION_TTL", &cfg.JWT.SessionTTL)
+	r.duration("JWT_REFRESH_TTL", &cfg.JWT.RefreshTTL)
+	r.duration("JWT_MFA_TTL", &cfg.JWT.MFATTL)
+	r.string("PORT", &cfg.Port)
+	r.string("DSN", (*string)(&cfg.DSN))
+	r.bool("DB_AUTO_MIGRATE", (*bool)(&cfg.DBAutoMigrate))
+	r.list("ADMINS_EXTERNAL_IDS", (*[]string)(&cfg.AdminsExternalIDs))
+	r.list("API_KEYS", &cfg.APIKeys)
+	r.duration("SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout)
+
+	r.string("CAPTCHA_PROVIDER", &cfg.Captcha.Provider)
+	// Note: HCAPTCHA_SECRET is kept for backward compatibility
+	r.string("HCAPTCHA_SECRET", &cfg.Captcha.Secret)
+	r.string("CAPTCHA_SECRET", &cfg.Captcha.Secret)
+	r.string("CAPTCHA_VERIFY_URL", &cfg.Captcha.VerifyURL)
+	r.float("RECAPTCHA_MIN_SCORE", &cfg.Captcha.MinScore)
+	r.int("POW_DIFFICULTY", &cfg.Captcha.PoWDifficulty)
+
+	r.bool("MAIL_ENABLED", &cfg.MailerJet.Enabled)
+	r.string("MAILJET_PUBLIC_KEY", &cfg.MailerJet.PublicKey)
+	r.string("MAILJET_PRIVATE_KEY", &cfg.MailerJet.PrivateKey)
+	r.string("MAILJET_MAIL_FROM", &cfg.MailerJet.FromEmail)
+	r.string("MAILJET_MAIL_FROM_NAME", &cfg.MailerJet.FromName)
+	r.int("MAILJET_CONFIRMATION_TEMPLATE_ID", &cfg.MailerJet.ConfirmationTemplateID)
+	r.string("MAILJET_CONFIRMATION_TEMPLATE_URL_PARAM", &cfg.MailerJet.ConfirmationTemplateURLParam)
+	r.int("MAILJET_POST_TEMPLATE_ID", &cfg.MailerJet.PostTemplateID)
+	r.string("MAILJET_POST_TEMPLATE_URL_PARAM", &cfg.MailerJet.PostTemplateURLParam)
+	r.string("MAILJET_UNSUBSCRIBE_URL_PARAM", &cfg.MailerJet.UnsubscribeURLParam)
+	r.int("MAILJET_MAGIC_LINK_TEMPLATE_ID", &cfg.MailerJet.MagicLinkTemplateID)
+	r.string("MAILJET_MAGIC_LINK_TEMPLATE_URL_PARAM", &cfg.MailerJet.MagicLinkTemplateURLParam)
+
+	r.string("SENTRY_DSN", &cfg.SentryDSN)
+	r.bool("VALIDATE_RESPONSES", &cfg.ValidateResponses)
+
+	r.string("METRICS_TOKEN", &cfg.Metrics.Token)
+	r.string("METRICS_PORT", &cfg.Metrics.Port)
+
+	r.string("LOG_FORMAT", &cfg.Log.Format)
+	r.string("LOG_LEVEL", &cfg.Log.Level)
+	r.bool("LOG_REDACT", &cfg.Log.Redact)
+
+	r.string("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	r.string("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	r.float("TRACING_SAMPLE_RATE", &cfg.Tracing.SampleRate)
+	r.string("TRACING_SERVICE_NAME", &cfg.Tracing.ServiceName)
+
+	r.string("RATE_LIMIT_STORE", &cfg.RateLimit.Store)
+	r.list("RATE_LIMIT_TRUSTED_PROXIES", &cfg.RateLimit.TrustedProxies)
+	r.rates("RATE_LIMIT_OPERATIONS", &cfg.RateLimit.Operations)
+	r.rate("RATE_LIMIT_SUBSCRIBER_EMAIL", &cfg.RateLimit.SubscriberEmail)
+	r.rate("RATE_LIMIT_MAGIC_LINK_EMAIL", &cfg.RateLimit.MagicLinkEmail)
+	r.rate("RATE_LIMIT_MAGIC_LINK_IP", &cfg.RateLimit.MagicLinkIP)
+	r.rate("RATE_LIMIT_MFA_USER", &cfg.RateLimit.MFAUser)
+
+	r.list("CORS_ALLOW_ORIGINS", &cfg.CORS.AllowOrigins)
+	r.list("CORS_ALLOW_METHODS", &cfg.CORS.AllowMethods)
+	r.bool("CORS_ALLOW_CREDENTIALS", &cfg.CORS.AllowCredentials)
+	r.duration("CORS_MAX_AGE", &cfg.CORS.MaxAge)
+
+	r.duration("HSTS_MAX_AGE", &cfg.SecurityHeaders.HSTSMaxAge)
+	r.bool("HSTS_INCLUDE_SUBDOMAINS", &cfg.SecurityHeaders.HSTSIncludeSubdomains)
+	r.bool("HSTS_PRELOAD", &cfg.SecurityHeaders.HSTSPreload)
+	r.string("CONTENT_SECURITY_POLICY", &cfg.SecurityHeaders.ContentSecurityPolicy)
+	r.string("REFERRER_POLICY", &cfg.SecurityHeaders.ReferrerPolicy)
+	r.string("PERMISSIONS_POLICY", &cfg.SecurityHeaders.PermissionsPolicy)
+
+	r.cacheControl("CACHE_CONTROL", &cfg.CacheControl)
+	r.bool("POST_CACHE_ENABLED", &cfg.PostCache.Enabled)
+	r.int("POST_CACHE_SIZE", &cfg.PostCache.Size)
+	r.duration("POST_CACHE_TTL", &cfg.PostCache.TTL)
+	r.bool("POST_CACHE_NOTIFY", &cfg.PostCache.Notify)
+	r.int("RELATED_POSTS", &cfg.RelatedPosts)
+
+	r.oidcProviders(&cfg.OIDCProviders)
+
+	r.string("WEBAUTHN_RP_ID", &cfg.WebAuthn.RPID)
+	r.string("WEBAUTHN_RP_DISPLAY_NAME", &cfg.WebAuthn.RPDisplayName)
+	r.list("WEBAUTHN_RP_ORIGINS", &cfg.WebAuthn.RPOrigins)
+}
+
+// oidcProviders replaces the providers with the ones listed in OIDC_PROVIDERS, separated by comma.
+// Each provider, including the ones from the config file, is configured with OIDC_<NAME>_ISSUER_URL,
+// OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL and optional OIDC_<NAME>_SCOPES.
+func (r *envReader) oidcProviders(dst *[]OIDCProvider) {
+	if names, ok := r.lookup("OIDC_PROVIDERS"); ok {
+		providers := make([]OIDCProvider, 0)
+		for _, name := range strings.Split(names, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			providers = append(providers, OIDCProvider{Name: name})
+		}
+		*dst = providers
+	}
+
+	for i := range *dst {
+		p := &(*dst)[i]
+		prefix := "OIDC_" + envName(p.Name) + "_"
+		r.string(prefix+"ISSUER_URL", &p.IssuerURL)
+		r.string(prefix+"CLIENT_ID", &p.ClientID)
+		r.string(prefix+"CLIENT_SECRET", &p.ClientSecret)
+		r.string(prefix+"REDIRECT_URL", &p.RedirectURL)
+		r.list(prefix+"SCOPES", &p.Scopes)
+
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "profile", "email"}
+		}
+	}
+}
+
+// rates overrides the rates listed in the variable as "<operation>=<rate>" separated by comma,
+// e.g. "PostSubscribers=10/1h,PostSubscribersConfirm=30/1h". The rates that are not listed are kept.
+func (r *envReader) rates(key string, dst *map[string]Rate) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, rate, ok := strings.Cut(item, "=")
+		if !ok {
+			r.problems = append(r.problems, fmt.Sprintf("%s must be a list of \"<operation>=<rate>\", got %q", key, item))
+			continue
+		}
+
+		v, err := ParseRate(rate)
+		if err != nil {
+			r.problems = append(r.problems, fmt.Sprintf("%s: %s: %v", key, strings.TrimSpace(name), err))
+			continue
+		}
+
+		if *dst == nil {
+			*dst = make(map[string]Rate)
+		}
+		(*dst)[strings.TrimSpace(name)] = v
+	}
+}
+
+// cacheControl overrides the headers listed in the variable as "<operation>=<value>" separated by semicolon,
+// as the values themselves are separated by comma, e.g. "GetPosts=public, max-age=60;GetPostsSlug=no-cache".
+// The headers that are not listed are kept.
+func (r *envReader) cacheControl(key string, dst *map[string]string) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, header, ok := strings.Cut(item, "=")
+		if !ok {
+			r.problems = append(r.problems, fmt.Sprintf("%s must be a list of \"<operation>=<value>\", got %q", key, item))
+			continue
+		}
+
+		if *dst == nil {
+			*dst = make(map[string]string)
+		}
+		(*dst)[strings.TrimSpace(name)] = strings.TrimSpace(header)
+	}
+}
+
+// envName converts the name to the part of the environment variable, e.g. "key-cloak" to "KEY_CLOAK".
+func envName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// lookup returns the value of the environment variable or the content of the file from the <key>_FILE variable.
+func (r *envReader) lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+
+	path, ok := os.LookupEnv(key + "_FILE")
+	if !ok {
+		return "", false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s_FILE: %v", key, err))
+		return "", false
+	}
+
+	// Note: secret files usually end with a newline
+	return strings.TrimRight(string(data), "\r\n"), true
+}
+
+func (r *envReader) string(key string, dst *string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (r *envReader) list(key string, dst *[]string) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func (r *envReader) int(key string, dst *int) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be an integer, got %q", key, value))
+		return
+	}
+	*dst = v
+}
+
+func (r *envReader) float(key string, dst *float64) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be a number, got %q", key, value))
+		return
+	}
+	*dst = v
+}
+
+func (r *envReader) bool(key string, dst *bool) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be true or false, got %q", key, value))
+		return
+	}
+	*dst = v
+}
+
+func (r *envReader) rate(key string, dst *Rate) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	v, err := ParseRate(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s: %v", key, err))
+		return
+	}
+	*dst = v
+}
+
+func (r *envReader) duration(key string, dst *time.Duration) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		r.problems = append(r.problems, fmt.Sprintf("%s must be a duration, e.g. \"15s\", got %q", key, value))
+		return
+	}
+	*dst = v
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is the number of requests allowed per period, written as "<limit>/<period>", e.g. "10/1h".
+// The zero limit, e.g. "0/1h", disables the rate limit.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate parses the Rate from the "<limit>/<period>" string, e.g. "10/1h".
+func ParseRate(s string) (Rate, error) {
+	limit, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate must be \"<limit>/<period>\", e.g. \"10/1h\", got %q", s)
+	}
+
+	l, err := strconv.Atoi(limit)
+	if err != nil || l < 0 {
+		return Rate{}, fmt.Errorf("rate limit must be a non-negative integer, got %q", limit)
+	}
+
+	p, err := time.ParseDuration(period)
+	if err != nil || p <= 0 {
+		return Rate{}, fmt.Errorf("rate period must be a positive duration, e.g. \"1h\", got %q", period)
+	}
+
+	return Rate{Limit: l, Period: p}, nil
+}
+
+// UnmarshalText parses the Rate from the config file, see ParseRate.
+func (r *Rate) UnmarshalText(text []byte) error {
+	rate, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*r = rate
+
+	return nil
+}
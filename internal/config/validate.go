@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// captchaProviders are the names of the supported captcha providers, "none" disables the captcha.
+var captchaProviders = []string{"hcaptcha", "turnstile", "recaptcha", "pow", "none"} //nolint:gochecknoglobals // constant
+
+// logFormats are the names of the supported log formats.
+var logFormats = []string{"json", "text"} //nolint:gochecknoglobals // constant
+
+// rateLimitStores are the names of the supported stores of the rate limits.
+var rateLimitStores = []string{"memory", "postgres"} //nolint:gochecknoglobals // constant
+
+// corsMethods are the methods that can be allowed for the cross-origin requests.
+var corsMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"} //nolint:gochecknoglobals // constant
+
+// referrerPolicies are the values of the Referrer-Policy header.
+var referrerPolicies = []string{ //nolint:gochecknoglobals // constant
+	"no-referrer", "no-referrer-when-downgrade", "origin", "origin-when-cross-origin", "same-origin",
+	"strict-origin", "strict-origin-when-cross-origin", "unsafe-url",
+}
+
+// tracingExporters are the names of the supported tracing exporters, "none" disables the tracing.
+var tracingExporters = []string{"otlp", "stdout", "none"} //nolint:gochecknoglobals // constant
+
+// oidcProviderName is the pattern of the OIDC provider name, it's used in the login URL path and in the env names.
+var oidcProviderName = regexp.MustCompile(`^[a-z0-9-]+$`) //nolint:gochecknoglobals // constant
+
+// reservedOIDCProviderNames are the fixed segments of the login routes that can't be used as the OIDC provider names.
+var reservedOIDCProviderNames = []string{ //nolint:gochecknoglobals // constant
+	"github", "email", "mfa", "webauthn", "recovery", "refresh",
+}
+
+// validate returns the problems of the Config. Optional subsystems are only validated if they are enabled.
+func (c *Config) validate() []string {
+	var problems []string
+	required := func(value, key, env string) {
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("%s (%s) is required", key, env))
+		}
+	}
+
+	// Note: GitHub sign in is optional, e.g. with only the OIDC providers or the magic links
+	if c.GithubClientID != "" || c.GithubClientSecret != "" {
+		required(c.GithubClientID, "github_client_id", "GITHUB_CLIENT_ID")
+		required(c.GithubClientSecret, "github_client_secret", "GITHUB_CLIENT_SECRET")
+	}
+	required(string(c.JWTSecretKey), "jwt_secret_key", "JWT_SECRET_KEY")
+	required(string(c.DSN), "dsn", "DSN")
+
+	positive := func(value time.Duration, key, env string) {
+		if value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s (%s) must be positive, got %s", key, env, value))
+		}
+	}
+	positive(c.JWT.SessionTTL, "jwt.session_ttl", "JWT_SESSION_TTL")
+	positive(c.JWT.RefreshTTL, "jwt.refresh_ttl", "JWT_REFRESH_TTL")
+	positive(c.JWT.MFATTL, "jwt.mfa_ttl", "JWT_MFA_TTL")
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("port (PORT) must be a number from 1 to 65535, got %q", c.Port))
+	}
+
+	if c.Metrics.Port != "" {
+		if port, err := strconv.Atoi(c.Metrics.Port); err != nil || port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("metrics.port (METRICS_PORT) must be a number from 1 to 65535, got %q",
+				c.Metrics.Port))
+		} else if c.Metrics.Port == c.Port {
+			problems = append(problems, "metrics.port (METRICS_PORT) must differ from the port (PORT)")
+		}
+	}
+
+	if !slices.Contains(logFormats, c.Log.Format) {
+		problems = append(problems, fmt.Sprintf("log.format (LOG_FORMAT) must be one of %v, got %q", logFormats, c.Log.Format))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		problems = append(problems, fmt.Sprintf("log.level (LOG_LEVEL) must be one of debug, info, warn or error, got %q",
+			c.Log.Level))
+	}
+
+	if t := c.Tracing; !slices.Contains(tracingExporters, t.Exporter) {
+		problems = append(problems, fmt.Sprintf("tracing.exporter (TRACING_EXPORTER) must be one of %v, got %q",
+			tracingExporters, t.Exporter))
+	} else if t.Exporter != "none" {
+		if t.SampleRate < 0 || t.SampleRate > 1 {
+			problems = append(problems, fmt.Sprintf("tracing.sample_rate (TRACING_SAMPLE_RATE) must be from 0.0 to 1.0, got %v",
+				t.SampleRate))
+		}
+		if u, err := url.Parse(t.Endpoint); t.Endpoint != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+			problems = append(problems, fmt.Sprintf("tracing.endpoint (TRACING_ENDPOINT) must be a URL, e.g. %q, got %q",
+				"http://localhost:4318", t.Endpoint))
+		}
+		required(t.ServiceName, "tracing.service_name", "TRACING_SERVICE_NAME")
+	}
+
+	if !slices.Contains(rateLimitStores, c.RateLimit.Store) {
+		problems = append(problems, fmt.Sprintf("rate_limit.store (RATE_LIMIT_STORE) must be one of %v, got %q",
+			rateLimitStores, c.RateLimit.Store))
+	}
+	for _, proxy := range c.RateLimit.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf(
+				"rate_limit.trusted_proxies (RATE_LIMIT_TRUSTED_PROXIES) must be IPs or CIDRs, e.g. %q, got %q",
+				"10.0.0.0/8", proxy))
+		}
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		problems = append(problems, `cors.allow_origins (CORS_ALLOW_ORIGINS) is required, use "*" to allow any origin`)
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if !isValidOrigin(origin) {
+			problems = append(problems, fmt.Sprintf("cors.allow_origins (CORS_ALLOW_ORIGINS) must be origins, e.g. %q or %q, got %q",
+				"https://example.com", "https://*.example.com", origin))
+		}
+	}
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowOrigins, "*") {
+		problems = append(problems, `cors.allow_credentials (CORS_ALLOW_CREDENTIALS) can't be used with any origin "*"`)
+	}
+	for _, method := range c.CORS.AllowMethods {
+		if !slices.Contains(corsMethods, method) {
+			problems = append(problems, fmt.Sprintf("cors.allow_methods (CORS_ALLOW_METHODS) must be some of %v, got %q",
+				corsMethods, method))
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		problems = append(problems, fmt.Sprintf("cors.max_age (CORS_MAX_AGE) must not be negative, got %s", c.CORS.MaxAge))
+	}
+
+	if h := c.SecurityHeaders; h.HSTSMaxAge < 0 {
+		problems = append(problems, fmt.Sprintf("security_headers.hsts_max_age (HSTS_MAX_AGE) must not be negative, got %s",
+			h.HSTSMaxAge))
+	} else if h.HSTSPreload && (!h.HSTSIncludeSubdomains || h.HSTSMaxAge < 365*24*time.Hour) {
+		problems = append(problems, "security_headers.hsts_preload (HSTS_PRELOAD) requires hsts_include_subdomains "+
+			"and hsts_max_age of at least 1 year (8760h)")
+	}
+	if p := c.SecurityHeaders.ReferrerPolicy; p != "" && !slices.Contains(referrerPolicies, p) {
+		problems = append(problems, fmt.Sprintf("security_headers.referrer_policy (REFERRER_POLICY) must be one of %v, got %q",
+			referrerPolicies, p))
+	}
+
+	if pc := c.PostCache; pc.Enabled {
+		if pc.Size <= 0 {
+			problems = append(problems, fmt.Sprintf("post_cache.size (POST_CACHE_SIZE) must be positive, got %d", pc.Size))
+		}
+		if pc.TTL <= 0 {
+			problems = append(problems, fmt.Sprintf("post_cache.ttl (POST_CACHE_TTL) must be positive, got %s", pc.TTL))
+		}
+	}
+
+	if c.RelatedPosts <= 0 {
+		problems = append(problems, fmt.Sprintf("related_posts (RELATED_POSTS) must be positive, got %d", c.RelatedPosts))
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive, got %s", c.ShutdownTimeout))
+	}
+
+	switch {
+	case !slices.Contains(captchaProviders, c.Captcha.Provider):
+		problems = append(problems, fmt.Sprintf("captcha.provider (CAPTCHA_PROVIDER) must be one of %v, got %q",
+			captchaProviders, c.Captcha.Provider))
+	case c.Captcha.Provider == "pow":
+		if c.Captcha.PoWDifficulty < 1 || c.Captcha.PoWDifficulty > 32 {
+			problems = append(problems, fmt.Sprintf("captcha.pow_difficulty (POW_DIFFICULTY) must be from 1 to 32, got %d",
+				c.Captcha.PoWDifficulty))
+		}
+	case c.Captcha.Provider != "none":
+		required(c.Captcha.Secret, "captcha.secret", "CAPTCHA_SECRET")
+	}
+
+	if c.Captcha.Provider == "recaptcha" && (c.Captcha.MinScore < 0 || c.Captcha.MinScore > 1) {
+		problems = append(problems, fmt.Sprintf("captcha.min_score (RECAPTCHA_MIN_SCORE) must be from 0.0 to 1.0, got %v",
+			c.Captcha.MinScore))
+	}
+
+	if m := c.MailerJet; m.Enabled {
+		required(m.PublicKey, "mailjet.public_key", "MAILJET_PUBLIC_KEY")
+		required(m.PrivateKey, "mailjet.private_key", "MAILJET_PRIVATE_KEY")
+		required(m.FromEmail, "mailjet.from_email", "MAILJET_MAIL_FROM")
+		required(m.FromName, "mailjet.from_name", "MAILJET_MAIL_FROM_NAME")
+		required(m.ConfirmationTemplateURLParam, "mailjet.confirmation_template_url_param",
+			"MAILJET_CONFIRMATION_TEMPLATE_URL_PARAM")
+		required(m.PostTemplateURLParam, "mailjet.post_template_url_param", "MAILJET_POST_TEMPLATE_URL_PARAM")
+		required(m.UnsubscribeURLParam, "mailjet.unsubscribe_url_param", "MAILJET_UNSUBSCRIBE_URL_PARAM")
+		required(m.MagicLinkTemplateURLParam, "mailjet.magic_link_template_url_param",
+			"MAILJET_MAGIC_LINK_TEMPLATE_URL_PARAM")
+
+		templateID := func(id int, key, env string) {
+			if id <= 0 {
+				problems = append(problems, fmt.Sprintf("%s (%s) must be a positive template ID", key, env))
+			}
+		}
+		templateID(m.ConfirmationTemplateID, "mailjet.confirmation_template_id", "MAILJET_CONFIRMATION_TEMPLATE_ID")
+		templateID(m.PostTemplateID, "mailjet.post_template_id", "MAILJET_POST_TEMPLATE_ID")
+		templateID(m.MagicLinkTemplateID, "mailjet.magic_link_template_id", "MAILJET_MAGIC_LINK_TEMPLATE_ID")
+	}
+
+	names := make(map[string]bool)
+	for i, p := range c.OIDCProviders {
+		if p.Name == "" {
+			problems = append(problems, fmt.Sprintf("oidc_providers[%d].name is required", i))
+			continue
+		}
+		if !oidcProviderName.MatchString(p.Name) {
+			problems = append(problems,
+				fmt.Sprintf("oidc_providers[%d].name %q must contain only a-z, 0-9 and -", i, p.Name))
+		}
+		if slices.Contains(reservedOIDCProviderNames, p.Name) {
+			problems = append(problems, fmt.Sprintf("oidc_providers[%d].name %q is reserved", i, p.Name))
+		}
+		if names[p.Name] {
+			problems = append(problems, fmt.Sprintf("oidc_providers[%d].name %q is duplicated", i, p.Name))
+		}
+		names[p.Name] = true
+
+		key := fmt.Sprintf("oidc_providers[%s]", p.Name)
+		env := "OIDC_" + envName(p.Name) + "_"
+		required(p.IssuerURL, key+".issuer_url", env+"ISSUER_URL")
+		required(p.ClientID, key+".client_id", env+"CLIENT_ID")
+		required(p.ClientSecret, key+".client_secret", env+"CLIENT_SECRET")
+		required(p.RedirectURL, key+".redirect_url", env+"REDIRECT_URL")
+	}
+
+	required(c.WebAuthn.RPID, "webauthn.rp_id", "WEBAUTHN_RP_ID")
+	if len(c.WebAuthn.RPOrigins) == 0 {
+		problems = append(problems, "webauthn.rp_origins (WEBAUTHN_RP_ORIGINS) is required")
+	}
+
+	return problems
+}
+
+// isValidOrigin reports whether the origin is "*" or the scheme and the host with the optional port,
+// e.g. "https://example.com:8443". The host can start with the "*." wildcard of the subdomains.
+func isValidOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && !strings.Contains(u.Host, "*") &&
+		u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == ""
+}
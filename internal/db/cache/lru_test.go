@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_lru(t *testing.T) {
+	now := time.Now()
+	newCache := func() *lru {
+		c := newLRU(2, time.Minute)
+		c.now = func() time.Time { return now }
+
+		return c
+	}
+
+	t.Run("get added value", func(t *testing.T) {
+		c := newCache()
+		c.add("a", 1, c.currentGeneration())
+
+		v, ok := c.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		_, ok = c.get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("evict least recently used", func(t *testing.T) {
+		c := newCache()
+		c.add("a", 1, c.currentGeneration())
+		c.add("b", 2, c.currentGeneration())
+		c.get("a")
+		c.add("c", 3, c.currentGeneration())
+
+		assert.Equal(t, 2, c.len())
+		_, ok := c.get("b")
+		assert.False(t, ok, "b is evicted as the least recently used")
+		_, ok = c.get("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("replace value", func(t *testing.T) {
+		c := newCache()
+		c.add("a", 1, c.currentGeneration())
+		c.add("a", 2, c.currentGeneration())
+
+		v, _ := c.get("a")
+		assert.Equal(t, 2, v)
+		assert.Equal(t, 1, c.len())
+	})
+
+	t.Run("value expires after ttl", func(t *testing.T) {
+		c := newCache()
+		c.add("a", 1, c.currentGeneration())
+
+		c.now = func() time.Time { return now.Add(time.Minute) }
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		assert.Zero(t, c.len())
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		c := newCache()
+		c.add("a", 1, c.currentGeneration())
+		c.purge()
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+
+	t.Run("value loaded before purge is not added", func(t *testing.T) {
+		c := newCache()
+		generation := c.currentGeneration()
+		c.purge()
+		c.add("a", 1, generation)
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// channel of the notifications about the changed posts.
+const channel = "bloggy_posts_changed"
+
+// Notifier sends and receives the notifications about the changed posts with Postgres LISTEN/NOTIFY,
+// so the caches of all instances are invalidated. The payload is the ID of the sender, so it ignores its own changes.
+type Notifier struct {
+	conn *gorm.DB
+	dsn  string
+	id   string
+}
+
+// NewNotifier creates a new Notifier, that sends the notifications with the conn and listens with its own
+// connection to the dsn, as LISTEN is bound to the session and can't use the pool.
+func NewNotifier(conn *gorm.DB, dsn string) *Notifier {
+	return &Notifier{
+		conn: conn,
+		dsn:  dsn,
+		id:   uuid.New().String(),
+	}
+}
+
+// Notify notifies the other instances about the changed posts.
+func (n *Notifier) Notify(ctx context.Context) error {
+	if err := n.conn.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, n.id).Error; err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+
+	return nil
+}
+
+// Listen calls the onChange on the notifications of the other instances until the ctx is done.
+// The connection is re-established on the errors. The onChange is also called after each connection,
+// as the notifications are missed while the instance is disconnected.
+func (n *Notifier) Listen(ctx context.Context, onChange func()) error {
+	bf := backoff.NewExponentialBackOff()
+	bf.MaxInterval = 30 * time.Second
+	bf.MaxElapsedTime = 0
+
+	for {
+		err := n.listen(ctx, onChange, bf.Reset)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := bf.NextBackOff()
+		slog.Warn("[cache] Listening to the changed posts failed, reconnecting...", "error", err, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// listen listens to the notifications on the new connection until it fails. The connected is called once listening.
+func (n *Notifier) listen(ctx context.Context, onChange func(), connected func()) error {
+	conn, err := pgx.Connect(ctx, n.dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	connected()
+	onChange()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		if notification.Payload != n.id {
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package cache caches the reads of the public posts in memory of the instance.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+)
+
+// postsCache is the name of the cache in the metrics.
+const postsCache = "posts"
+
+// notifier notifies the other instances about the changed posts, see Notifier.
+type notifier interface {
+	Notify(ctx context.Context) error
+}
+
+// PostRepository is the models.PostRepositoryInterface, that caches the reads of the posts.
+// The whole cache is invalidated on any change, as the change of one post also changes the lists and the count.
+// The posts are copied, so the callers can change them without changing the cached ones.
+type PostRepository struct {
+	posts    models.PostRepositoryInterface
+	cache    *lru
+	metrics  *metrics.Metrics
+	notifier notifier // notifier is nil if the other instances are not notified
+}
+
+// NewPostRepository creates a new PostRepository, that caches at most size reads of the posts for the ttl.
+// If the notifier is not nil, the other instances are notified about the changes.
+func NewPostRepository(
+	posts models.PostRepositoryInterface,
+	size int,
+	ttl time.Duration,
+	m *metrics.Metrics,
+	n notifier,
+) *PostRepository {
+	return &PostRepository{
+		posts:    posts,
+		cache:    newLRU(size, ttl),
+		metrics:  m,
+		notifier: n,
+	}
+}
+
+// Create creates a new Post and invalidates the cache.
+func (r *PostRepository) Create(ctx context.Context, p *models.Post) error {
+	if err := r.posts.Create(ctx, p); err != nil {
+		return err
+	}
+	r.changed(ctx)
+
+	return nil
+}
+
+// Update updates the Post and invalidates the cache. The cache is also invalidated on models.ErrConflict,
+// as the post was changed, e.g. by another instance, that hasn't notified this one yet.
+func (r *PostRepository) Update(ctx context.Context, p *models.Post) error {
+	if err := r.posts.Update(ctx, p); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			r.Invalidate(metrics.InvalidationLocal)
+		}
+		return err
+	}
+	r.changed(ctx)
+
+	return nil
+}
+
+// GetBySlug returns the cached Post by its URL Slug.
+func (r *PostRepository) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	v, err := r.load("post:"+slug, func() (any, error) {
+		return r.posts.GetBySlug(ctx, slug)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	post := *v.(*models.Post)
+	return &post, nil
+}
+
+// FindAll returns the cached page of the posts.
+func (r *PostRepository) FindAll(ctx context.Context, page, perPage int) ([]*models.Post, error) {
+	v, err := r.load(fmt.Sprintf("posts:%d:%d", page, perPage), func() (any, error) {
+		return r.posts.FindAll(ctx, page, perPage)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cached := v.([]*models.Post)
+	posts := make([]*models.Post, len(cached))
+	for i, p := range cached {
+		post := *p
+		posts[i] = &post
+	}
+
+	return posts, nil
+}
+
+// FindPage returns the cached page of the posts matching the query.
+func (r *PostRepository) FindPage(ctx context.Context, q *models.PostQuery) (*models.PostPage, error) {
+	key, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	v, err := r.load("page:"+string(key), func() (any, error) {
+		return r.posts.FindPage(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cached := v.(*models.PostPage)
+	page := *cached
+	page.Posts = make([]*models.Post, len(cached.Posts))
+	for i, p := range cached.Posts {
+		post := *p
+		page.Posts[i] = &post
+	}
+	if cached.Next != nil {
+		next := *cached.Next
+		page.Next = &next
+	}
+	if cached.Prev != nil {
+		prev := *cached.Prev
+		page.Prev = &prev
+	}
+
+	return &page, nil
+}
+
+// Archive returns the cached number of the posts by the month.
+func (r *PostRepository) Archive(ctx context.Context) ([]*models.ArchiveMonth, error) {
+	v, err := r.load("archive", func() (any, error) {
+		return r.posts.Archive(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cached := v.([]*models.ArchiveMonth)
+	months := make([]*models.ArchiveMonth, len(cached))
+	for i, m := range cached {
+		month := *m
+		months[i] = &month
+	}
+
+	return months, nil
+}
+
+// Related returns the cached related posts of the post of the slug. The scores are precomputed by the posts
+// on any change, and the cache is invalidated with the change, so the posts are read again after it.
+// Like the other reads, the entries of every slug and limit are bounded by the size of the cache.
+func (r *PostRepository) Related(ctx context.Context, slug string, limit int) ([]*models.Post, error) {
+	v, err := r.load(fmt.Sprintf("related:%d:%s", limit, slug), func() (any, error) {
+		return r.posts.Related(ctx, slug, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cached := v.([]*models.Post)
+	posts := make([]*models.Post, len(cached))
+	for i, p := range cached {
+		post := *p
+		posts[i] = &post
+	}
+
+	return posts, nil
+}
+
+// Count returns the cached total number of posts.
+func (r *PostRepository) Count(ctx context.Context) (int64, error) {
+	v, err := r.load("count", func() (any, error) {
+		return r.posts.Count(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return v.(int64), nil
+}
+
+// LastUpdatedAt returns the cached time of the last update of any post.
+func (r *PostRepository) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+	v, err := r.load("last-updated-at", func() (any, error) {
+		return r.posts.LastUpdatedAt(ctx)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return v.(time.Time), nil
+}
+
+// Invalidate removes all cached posts, e.g. if the posts are changed by another instance.
+func (r *PostRepository) Invalidate(source string) {
+	r.cache.purge()
+	r.metrics.CacheInvalidated(postsCache, source)
+}
+
+// load returns the cached value of the key or loads it with the fn and caches it. The errors are not cached.
+func (r *PostRepository) load(key string, fn func() (any, error)) (any, error) {
+	if v, ok := r.cache.get(key); ok {
+		r.metrics.CacheHit(postsCache)
+		return v, nil
+	}
+	r.metrics.CacheMiss(postsCache)
+
+	generation := r.cache.currentGeneration()
+	v, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	r.cache.add(key, v, generation)
+
+	return v, nil
+}
+
+// changed invalidates the cache and notifies the other instances after the posts are changed.
+// The error of the notification is only logged, as the caches of the other instances expire after the TTL anyway.
+func (r *PostRepository) changed(ctx context.Context) {
+	r.Invalidate(metrics.InvalidationLocal)
+
+	if r.notifier == nil {
+		return
+	}
+	if err := r.notifier.Notify(ctx); err != nil {
+		logging.FromContext(ctx).Warn("Error notifying about the changed posts", "error", err)
+	}
+}
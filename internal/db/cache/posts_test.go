@@ -0,0 +1,283 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	modelsMock "github.com/samgozman/go-bloggy/mocks/db/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeNotifier counts the notifications.
+type fakeNotifier struct {
+	notified int
+	err      error
+}
+
+func (n *fakeNotifier) Notify(_ context.Context) error {
+	n.notified++
+	return n.err
+}
+
+// newTestRepository creates a new PostRepository with the mocked posts repository.
+func newTestRepository(t *testing.T) (
+	*PostRepository,
+	*modelsMock.MockPostRepositoryInterface,
+	*fakeNotifier,
+	*metrics.Metrics,
+) {
+	posts := modelsMock.NewMockPostRepositoryInterface(t)
+	n := &fakeNotifier{}
+	m := metrics.New(&metrics.Config{})
+
+	return NewPostRepository(posts, 10, time.Minute, m, n), posts, n, m
+}
+
+// scrape returns the metrics in the Prometheus text format.
+func scrape(m *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	return rec.Body.String()
+}
+
+func TestPostRepository_GetBySlug(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("read is cached", func(t *testing.T) {
+		r, posts, _, m := newTestRepository(t)
+		posts.On("GetBySlug", ctx, "first").Return(&models.Post{ID: 1, Slug: "first", Title: "Title"}, nil).Once()
+
+		for range 3 {
+			post, err := r.GetBySlug(ctx, "first")
+			assert.NoError(t, err)
+			assert.Equal(t, "Title", post.Title)
+		}
+
+		stats := scrape(m)
+		assert.Contains(t, stats, `bloggy_cache_requests_total{cache="posts",result="hit"} 2`)
+		assert.Contains(t, stats, `bloggy_cache_requests_total{cache="posts",result="miss"} 1`)
+	})
+
+	t.Run("cached post can't be changed by the caller", func(t *testing.T) {
+		r, posts, _, _ := newTestRepository(t)
+		posts.On("GetBySlug", ctx, "first").Return(&models.Post{ID: 1, Slug: "first", Title: "Title"}, nil).Once()
+
+		post, err := r.GetBySlug(ctx, "first")
+		assert.NoError(t, err)
+		post.Title = "Changed"
+
+		post, err = r.GetBySlug(ctx, "first")
+		assert.NoError(t, err)
+		assert.Equal(t, "Title", post.Title)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		r, posts, _, _ := newTestRepository(t)
+		posts.On("GetBySlug", ctx, "missing").Return(nil, models.ErrNotFound).Twice()
+
+		for range 2 {
+			_, err := r.GetBySlug(ctx, "missing")
+			assert.ErrorIs(t, err, models.ErrNotFound)
+		}
+	})
+}
+
+func TestPostRepository_List(t *testing.T) {
+	ctx := context.Background()
+	r, posts, _, _ := newTestRepository(t)
+
+	updatedAt := time.Now()
+	posts.On("FindAll", ctx, 1, 20).Return([]*models.Post{{Slug: "first"}, {Slug: "second"}}, nil).Once()
+	posts.On("FindAll", ctx, 2, 20).Return([]*models.Post{}, nil).Once()
+	posts.On("Count", ctx).Return(int64(2), nil).Once()
+	posts.On("LastUpdatedAt", ctx).Return(updatedAt, nil).Once()
+	posts.On("Archive", ctx).Return([]*models.ArchiveMonth{{Year: 2024, Month: 8, Count: 2}}, nil).Once()
+
+	for range 2 {
+		page, err := r.FindAll(ctx, 1, 20)
+		assert.NoError(t, err)
+		assert.Len(t, page, 2)
+		page[0].Slug = "changed"
+
+		page, err = r.FindAll(ctx, 2, 20)
+		assert.NoError(t, err)
+		assert.Empty(t, page)
+
+		count, err := r.Count(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		lastUpdatedAt, err := r.LastUpdatedAt(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedAt, lastUpdatedAt)
+
+		months, err := r.Archive(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), months[0].Count)
+		months[0].Count = 0
+	}
+
+	page, err := r.FindAll(ctx, 1, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", page[0].Slug)
+}
+
+func TestPostRepository_FindPage(t *testing.T) {
+	ctx := context.Background()
+	r, posts, _, _ := newTestRepository(t)
+
+	sent := true
+	first := &models.PostQuery{Filter: models.PostFilter{Keyword: "go", Sent: &sent}, Limit: 2}
+	next := &models.PostQuery{Filter: models.PostFilter{Keyword: "go", Sent: &sent}, Limit: 2, Cursor: &models.PostCursor{ID: 2}}
+	posts.On("FindPage", ctx, first).Return(&models.PostPage{
+		Posts: []*models.Post{{ID: 1, Slug: "first"}, {ID: 2, Slug: "second"}},
+		Total: 3,
+		Next:  &models.PostCursor{ID: 2},
+	}, nil).Once()
+	posts.On("FindPage", ctx, next).Return(&models.PostPage{
+		Posts: []*models.Post{{ID: 3, Slug: "third"}},
+		Total: 3,
+		Prev:  &models.PostCursor{ID: 3, Before: true},
+	}, nil).Once()
+
+	for range 2 {
+		page, err := r.FindPage(ctx, first)
+		assert.NoError(t, err)
+		assert.Len(t, page.Posts, 2)
+		assert.Equal(t, 2, page.Next.ID)
+		page.Posts[0].Slug = "changed"
+		page.Next.ID = 0
+
+		page, err = r.FindPage(ctx, next)
+		assert.NoError(t, err)
+		assert.Equal(t, "third", page.Posts[0].Slug)
+		assert.Nil(t, page.Next)
+	}
+
+	// the same query with another filter value is not cached
+	notSent := false
+	other := &models.PostQuery{Filter: models.PostFilter{Keyword: "go", Sent: &notSent}, Limit: 2}
+	posts.On("FindPage", ctx, other).Return(&models.PostPage{Posts: []*models.Post{}}, nil).Once()
+
+	page, err := r.FindPage(ctx, other)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Posts)
+
+	page, err = r.FindPage(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", page.Posts[0].Slug)
+}
+
+func TestPostRepository_Related(t *testing.T) {
+	ctx := context.Background()
+	r, posts, _, _ := newTestRepository(t)
+
+	posts.On("Related", ctx, "first", 2).Return([]*models.Post{{ID: 2, Slug: "second"}}, nil).Once()
+	posts.On("Related", ctx, "first", 3).Return([]*models.Post{{ID: 2, Slug: "second"}, {ID: 3, Slug: "third"}}, nil).Once()
+
+	for range 2 {
+		related, err := r.Related(ctx, "first", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "second", related[0].Slug)
+		related[0].Slug = "changed"
+
+		related, err = r.Related(ctx, "first", 3)
+		assert.NoError(t, err)
+		assert.Len(t, related, 2)
+	}
+
+	// the scores are ranked again after the posts are changed
+	post := &models.Post{Slug: "new"}
+	posts.On("Create", ctx, post).Return(nil)
+	posts.On("Related", ctx, "first", 2).Return([]*models.Post{{ID: 4, Slug: "new"}}, nil).Once()
+
+	assert.NoError(t, r.Create(ctx, post))
+	related, err := r.Related(ctx, "first", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", related[0].Slug)
+}
+
+func TestPostRepository_Invalidate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("on create", func(t *testing.T) {
+		r, posts, n, m := newTestRepository(t)
+		post := &models.Post{Slug: "new"}
+		posts.On("Count", ctx).Return(int64(1), nil).Once()
+		posts.On("Create", ctx, post).Return(nil)
+		posts.On("Count", ctx).Return(int64(2), nil).Once()
+
+		count, _ := r.Count(ctx)
+		assert.Equal(t, int64(1), count)
+
+		assert.NoError(t, r.Create(ctx, post))
+		assert.Equal(t, 1, n.notified)
+
+		count, _ = r.Count(ctx)
+		assert.Equal(t, int64(2), count)
+		assert.Contains(t, scrape(m), `bloggy_cache_invalidations_total{cache="posts",source="local"} 1`)
+	})
+
+	t.Run("on update", func(t *testing.T) {
+		r, posts, n, _ := newTestRepository(t)
+		post := &models.Post{Slug: "first", Title: "Title"}
+		posts.On("GetBySlug", ctx, "first").Return(post, nil).Once()
+		posts.On("Update", ctx, &models.Post{Slug: "first", Title: "Changed"}).Return(nil)
+		posts.On("GetBySlug", ctx, "first").Return(&models.Post{Slug: "first", Title: "Changed"}, nil).Once()
+
+		cached, _ := r.GetBySlug(ctx, "first")
+		cached.Title = "Changed"
+		assert.NoError(t, r.Update(ctx, cached))
+		assert.Equal(t, 1, n.notified)
+
+		updated, _ := r.GetBySlug(ctx, "first")
+		assert.Equal(t, "Changed", updated.Title)
+	})
+
+	t.Run("failed change is not notified", func(t *testing.T) {
+		r, posts, n, _ := newTestRepository(t)
+		post := &models.Post{Slug: "first"}
+		posts.On("Update", ctx, post).Return(models.ErrDuplicate)
+
+		assert.ErrorIs(t, r.Update(ctx, post), models.ErrDuplicate)
+		assert.Zero(t, n.notified)
+	})
+
+	t.Run("on conflict", func(t *testing.T) {
+		r, posts, n, _ := newTestRepository(t)
+		post := &models.Post{Slug: "first", Version: 1}
+		posts.On("GetBySlug", ctx, "first").Return(post, nil).Twice()
+		posts.On("Update", ctx, post).Return(models.ErrConflict)
+
+		_, _ = r.GetBySlug(ctx, "first")
+		assert.ErrorIs(t, r.Update(ctx, post), models.ErrConflict)
+		assert.Zero(t, n.notified)
+		_, _ = r.GetBySlug(ctx, "first")
+	})
+
+	t.Run("failed notification is not returned", func(t *testing.T) {
+		r, posts, n, _ := newTestRepository(t)
+		n.err = errors.New("connection refused")
+		post := &models.Post{Slug: "first"}
+		posts.On("Update", ctx, post).Return(nil)
+
+		assert.NoError(t, r.Update(ctx, post))
+	})
+
+	t.Run("by another instance", func(t *testing.T) {
+		r, posts, _, m := newTestRepository(t)
+		posts.On("Count", ctx).Return(int64(1), nil).Twice()
+
+		_, _ = r.Count(ctx)
+		r.Invalidate(metrics.InvalidationRemote)
+		_, _ = r.Count(ctx)
+
+		assert.Contains(t, scrape(m), `bloggy_cache_invalidations_total{cache="posts",source="remote"} 1`)
+	})
+}
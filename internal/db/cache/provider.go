@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"gorm.io/gorm"
+)
+
+// Config of the cache of the posts.
+type Config struct {
+	Enabled bool
+	Size    int           // Size is the maximal number of the cached reads, e.g. the posts and the pages of the list.
+	TTL     time.Duration // TTL is how long the reads are cached.
+	Notify  bool          // Notify invalidates the caches of the other instances with Postgres LISTEN/NOTIFY.
+}
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	return &Config{
+		Enabled: cfg.PostCache.Enabled,
+		Size:    cfg.PostCache.Size,
+		TTL:     cfg.PostCache.TTL,
+		Notify:  cfg.PostCache.Notify,
+	}
+}
+
+// ProvidePostRepository is a Wire provider function that creates the repository of the posts,
+// which is cached if enabled. The notifications of the other instances are received until the shutdown.
+func ProvidePostRepository(
+	cfg *Config,
+	conn *gorm.DB,
+	dsn config.DSN,
+	m *metrics.Metrics,
+	lc *lifecycle.Manager,
+) models.PostRepositoryInterface {
+	posts := models.NewPostRepository(conn)
+	if !cfg.Enabled {
+		return posts
+	}
+
+	if !cfg.Notify {
+		return NewPostRepository(posts, cfg.Size, cfg.TTL, m, nil)
+	}
+
+	n := NewNotifier(conn, string(dsn))
+	r := NewPostRepository(posts, cfg.Size, cfg.TTL, m, n)
+	lc.Go("post cache notifications", func(ctx context.Context) error {
+		return n.Listen(ctx, func() { r.Invalidate(metrics.InvalidationRemote) })
+	})
+
+	return r
+}
+
+// ProvideCommandPostRepository is a Wire provider function that creates the repository of the posts
+// for the one-shot commands. The changes are notified to the running instances, but the notifications
+// of the other instances are not received, so no worker is started.
+func ProvideCommandPostRepository(
+	cfg *Config,
+	conn *gorm.DB,
+	dsn config.DSN,
+	m *metrics.Metrics,
+) models.PostRepositoryInterface {
+	posts := models.NewPostRepository(conn)
+	if !cfg.Enabled || !cfg.Notify {
+		return posts
+	}
+
+	return NewPostRepository(posts, cfg.Size, cfg.TTL, m, NewNotifier(conn, string(dsn)))
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the cache package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvidePostRepository,
+)
+
+// CommandProviderSet is a Wire provider set of the cache package for the one-shot commands.
+var CommandProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideCommandPostRepository,
+)
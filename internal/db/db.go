@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/logging"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"log/slog"
@@ -15,17 +16,32 @@ type Models struct {
 	users       models.UserRepositoryInterface
 	posts       models.PostRepositoryInterface
 	subscribers models.SubscriberRepositoryInterface
+	magicLinks  models.MagicLinkRepositoryInterface
+	webAuthn    models.WebAuthnCredentialRepositoryInterface
+	recovery    models.RecoveryCodeRepositoryInterface
+	rateLimits  models.RateLimitRepositoryInterface
+	challenges  models.WebAuthnChallengeRepositoryInterface
 }
 
 // NewModels creates a new Models instance.
 func NewModels(users models.UserRepositoryInterface,
 	posts models.PostRepositoryInterface,
 	subscribers models.SubscriberRepositoryInterface,
+	magicLinks models.MagicLinkRepositoryInterface,
+	webAuthn models.WebAuthnCredentialRepositoryInterface,
+	recovery models.RecoveryCodeRepositoryInterface,
+	rateLimits models.RateLimitRepositoryInterface,
+	challenges models.WebAuthnChallengeRepositoryInterface,
 ) *Models {
 	return &Models{
 		users:       users,
 		posts:       posts,
 		subscribers: subscribers,
+		magicLinks:  magicLinks,
+		webAuthn:    webAuthn,
+		recovery:    recovery,
+		rateLimits:  rateLimits,
+		challenges:  challenges,
 	}
 }
 
@@ -44,10 +60,40 @@ func (m *Models) Subscribers() models.SubscriberRepositoryInterface {
 	return m.subscribers
 }
 
+// MagicLinks returns the models.MagicLinkRepository.
+func (m *Models) MagicLinks() models.MagicLinkRepositoryInterface {
+	return m.magicLinks
+}
+
+// WebAuthnCredentials returns the models.WebAuthnCredentialRepository.
+func (m *Models) WebAuthnCredentials() models.WebAuthnCredentialRepositoryInterface {
+	return m.webAuthn
+}
+
+// RecoveryCodes returns the models.RecoveryCodeRepository.
+func (m *Models) RecoveryCodes() models.RecoveryCodeRepositoryInterface {
+	return m.recovery
+}
+
+// RateLimits returns the models.RateLimitRepository.
+func (m *Models) RateLimits() models.RateLimitRepositoryInterface {
+	return m.rateLimits
+}
+
+// WebAuthnChallenges returns the models.WebAuthnChallengeRepository.
+func (m *Models) WebAuthnChallenges() models.WebAuthnChallengeRepositoryInterface {
+	return m.challenges
+}
+
 type ModelsInterface interface {
 	Users() models.UserRepositoryInterface
 	Posts() models.PostRepositoryInterface
 	Subscribers() models.SubscriberRepositoryInterface
+	MagicLinks() models.MagicLinkRepositoryInterface
+	WebAuthnCredentials() models.WebAuthnCredentialRepositoryInterface
+	RecoveryCodes() models.RecoveryCodeRepositoryInterface
+	RateLimits() models.RateLimitRepositoryInterface
+	WebAuthnChallenges() models.WebAuthnChallengeRepositoryInterface
 }
 
 // Database is the database connection.
@@ -80,7 +126,11 @@ type DatabaseInterface interface {
 	Models() ModelsInterface
 }
 
+// slowQueryThreshold is the duration after which the query is logged as slow.
+const slowQueryThreshold = 200 * time.Millisecond
+
 // connectToPG connects to the Postgres database and retries until it is ready.
+// The queries are logged with the logger of their context, e.g. with the request ID.
 func connectToPG(dsn string) (*gorm.DB, error) {
 	bf := backoff.NewExponentialBackOff()
 	bf.InitialInterval = 10 * time.Second
@@ -90,9 +140,12 @@ func connectToPG(dsn string) (*gorm.DB, error) {
 	db, err := backoff.RetryWithData[*gorm.DB](func() (*gorm.DB, error) {
 		conn, err := gorm.Open(postgres.New(postgres.Config{
 			DSN: dsn,
-		}))
+		}), &gorm.Config{
+			Logger:  logging.NewGormLogger(slowQueryThreshold),
+			NowFunc: models.Now,
+		})
 		if err != nil {
-			slog.Info("[connectToPG] Postgres not yet ready...")
+			slog.Info("[connectToPG] Postgres not yet ready...", "error", err)
 			return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
 		}
 		slog.Info("[connectToPG] Connected to Postgres!")
@@ -15,6 +15,9 @@ func TestNewDatabase(t *testing.T) {
 			users:       modelsMock.NewMockUserRepositoryInterface(t),
 			posts:       modelsMock.NewMockPostRepositoryInterface(t),
 			subscribers: modelsMock.NewMockSubscriberRepositoryInterface(t),
+			magicLinks:  modelsMock.NewMockMagicLinkRepositoryInterface(t),
+			webAuthn:    modelsMock.NewMockWebAuthnCredentialRepositoryInterface(t),
+			recovery:    modelsMock.NewMockRecoveryCodeRepositoryInterface(t),
 		}
 		got := NewDatabase(conn, models)
 		assert.NotNil(t, got)
@@ -25,5 +28,8 @@ func TestNewDatabase(t *testing.T) {
 		assert.NotNil(t, got.models.Users())
 		assert.NotNil(t, got.models.Posts())
 		assert.NotNil(t, got.models.Subscribers())
+		assert.NotNil(t, got.models.MagicLinks())
+		assert.NotNil(t, got.models.WebAuthnCredentials())
+		assert.NotNil(t, got.models.RecoveryCodes())
 	})
 }
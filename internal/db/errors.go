@@ -5,4 +5,8 @@ import "errors"
 var (
 	ErrFailedToConnectDatabase = errors.New("ERR_FAILED_TO_CONNECT_DATABASE")
 	ErrFailedToMigrateDatabase = errors.New("ERR_FAILED_TO_MIGRATE_DATABASE")
+	ErrInvalidMigration        = errors.New("ERR_INVALID_MIGRATION")
+	ErrMigrationNotFound       = errors.New("ERR_MIGRATION_NOT_FOUND")
+	ErrPendingMigrations       = errors.New("ERR_PENDING_MIGRATIONS")
+	ErrDirtyDatabase           = errors.New("ERR_DIRTY_DATABASE")
 )
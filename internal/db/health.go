@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samgozman/go-bloggy/internal/health"
+	"gorm.io/gorm"
+)
+
+// registerHealthChecks registers the readiness checks of the connection and the schema migrations.
+func registerHealthChecks(hs *health.Service, conn *gorm.DB) error {
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToConnectDatabase, err)
+	}
+
+	migrator, err := NewMigrator(conn)
+	if err != nil {
+		return err
+	}
+
+	hs.Register(health.NewChecker("database", func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	}))
+	hs.Register(health.NewChecker("migrations", migrator.CheckUnlocked))
+
+	return nil
+}
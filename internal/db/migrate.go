@@ -0,0 +1,482 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLockKey is the key of the Postgres advisory lock held while migrating,
+// so concurrent replicas do not apply the same migrations.
+const migrationsLockKey int64 = 7_260_104_530_211_592
+
+// migrationFileRegexp matches migration files, e.g. "000001_baseline.up.sql".
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change with the SQL to apply and to revert it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is the state of the migration in the database.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool       // Dirty is true if the migration failed and the schema has to be fixed manually.
+	AppliedAt *time.Time // AppliedAt is nil if the migration is not applied.
+}
+
+// Migrator applies the versioned SQL migrations embedded into the binary.
+// Applied versions are stored in the schema_migrations table.
+//
+// Each migration runs in a transaction. The version is marked as dirty until the transaction is committed
+// or rolled back, so the migration interrupted e.g. by a lost connection is detected and never retried blindly.
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+// NewMigrator creates a new Migrator for the embedded migrations.
+func NewMigrator(conn *gorm.DB) (*Migrator, error) {
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+	}
+
+	sub, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMigration, err)
+	}
+
+	return newMigrator(sqlDB, sub)
+}
+
+func newMigrator(sqlDB *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         sqlDB,
+		migrations: migrations,
+	}, nil
+}
+
+// Migrations returns all known migrations ordered by version.
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Up applies all pending migrations and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var applied []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		states, err := m.states(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := checkDirty(states); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := states[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the given number of the last applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var reverted []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		states, err := m.states(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := checkDirty(states); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := states[migration.Version]; !ok {
+				continue
+			}
+
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Force marks the migration as applied or not applied without running it, e.g. after fixing
+// the dirty migration manually.
+func (m *Migrator) Force(ctx context.Context, version int64, applied bool) error {
+	if m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrMigrationNotFound, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		if applied {
+			_, err = conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty, applied_at) VALUES ($1, false, now())
+				ON CONFLICT (version) DO UPDATE SET dirty = false, applied_at = now()`, version)
+		} else {
+			_, err = conn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, version)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: error forcing version %d: %w", ErrFailedToMigrateDatabase, version, err)
+		}
+
+		return nil
+	})
+}
+
+// Status returns the state of all known migrations.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		states, err := m.states(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		statuses = m.statuses(states)
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Check returns ErrDirtyDatabase if any migration failed, or ErrPendingMigrations
+// if the schema is not up to date.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	return checkStatuses(statuses)
+}
+
+// CheckUnlocked is Check without the advisory lock and without creating the schema_migrations table,
+// so it's not blocked by the running migration, e.g. for the readiness probe.
+func (m *Migrator) CheckUnlocked(ctx context.Context) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+	}
+	defer conn.Close()
+
+	states, err := m.states(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return checkStatuses(m.statuses(states))
+}
+
+// statuses returns the state of all known migrations from the rows of the schema_migrations table.
+func (m *Migrator) statuses(states map[int64]migrationState) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if state, ok := states[migration.Version]; ok {
+			status.Applied = !state.dirty
+			status.Dirty = state.dirty
+			status.AppliedAt = &state.appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// checkStatuses returns ErrDirtyDatabase or ErrPendingMigrations for the statuses.
+func checkStatuses(statuses []MigrationStatus) error {
+	var pending []int64
+	for _, status := range statuses {
+		if status.Dirty {
+			return fmt.Errorf("%w: version %d", ErrDirtyDatabase, status.Version)
+		}
+		if !status.Applied {
+			pending = append(pending, status.Version)
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: versions %v", ErrPendingMigrations, pending)
+	}
+
+	return nil
+}
+
+// migrationState is the row of the schema_migrations table.
+type migrationState struct {
+	dirty     bool
+	appliedAt time.Time
+}
+
+// withLock runs fn on a single connection holding the advisory lock.
+// Note: the advisory lock belongs to the session, so all the queries must use the same connection.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationsLockKey); err != nil {
+		return fmt.Errorf("%w: error acquiring lock: %w", ErrFailedToMigrateDatabase, err)
+	}
+	defer func() {
+		// Note: the lock is released with the session anyway, if the connection is broken
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationsLockKey)
+	}()
+
+	if err := createMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func createMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		dirty      boolean NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("%w: error creating schema_migrations table: %w", ErrFailedToMigrateDatabase, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) states(ctx context.Context, conn *sql.Conn) (map[int64]migrationState, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, dirty, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading schema_migrations: %w", ErrFailedToMigrateDatabase, err)
+	}
+	defer rows.Close()
+
+	states := make(map[int64]migrationState)
+	for rows.Next() {
+		var version int64
+		var state migrationState
+		if err := rows.Scan(&version, &state.dirty, &state.appliedAt); err != nil {
+			return nil, fmt.Errorf("%w: error reading schema_migrations: %w", ErrFailedToMigrateDatabase, err)
+		}
+
+		states[version] = state
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error reading schema_migrations: %w", ErrFailedToMigrateDatabase, err)
+	}
+
+	return states, nil
+}
+
+// apply runs the up migration and marks its version as applied.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	_, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty, applied_at) VALUES ($1, true, now())`,
+		migration.Version)
+	if err != nil {
+		return fmt.Errorf("%w: error marking version %d: %w", ErrFailedToMigrateDatabase, migration.Version, err)
+	}
+
+	err = runInTx(ctx, conn, migration, migration.Up,
+		`UPDATE schema_migrations SET dirty = false, applied_at = now() WHERE version = $1`)
+	if err != nil {
+		// Note: the transaction is rolled back, so the migration is not applied
+		_, _ = conn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	}
+
+	return nil
+}
+
+// revert runs the down migration and removes its version.
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	_, err := conn.ExecContext(ctx, `UPDATE schema_migrations SET dirty = true WHERE version = $1`, migration.Version)
+	if err != nil {
+		return fmt.Errorf("%w: error marking version %d: %w", ErrFailedToMigrateDatabase, migration.Version, err)
+	}
+
+	err = runInTx(ctx, conn, migration, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`)
+	if err != nil {
+		// Note: the transaction is rolled back, so the migration is still applied
+		_, _ = conn.ExecContext(ctx, `UPDATE schema_migrations SET dirty = false WHERE version = $1`, migration.Version)
+		return err
+	}
+
+	return nil
+}
+
+// runInTx runs the migration query and the query updating its version in a single transaction.
+func runInTx(ctx context.Context, conn *sql.Conn, migration *Migration, query, versionQuery string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("%w: version %d (%s): %w", ErrFailedToMigrateDatabase, migration.Version, migration.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, versionQuery, migration.Version); err != nil {
+		return fmt.Errorf("%w: error updating version %d: %w", ErrFailedToMigrateDatabase, migration.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+
+	return nil
+}
+
+func checkDirty(states map[int64]migrationState) error {
+	for version, state := range states {
+		if state.dirty {
+			return fmt.Errorf("%w: version %d", ErrDirtyDatabase, version)
+		}
+	}
+
+	return nil
+}
+
+// loadMigrations reads the migrations from the root of fsys.
+// Every version must have both up and down files.
+func loadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMigration, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidMigration, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: invalid version of %s", ErrInvalidMigration, entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidMigration, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: version %d has different names", ErrInvalidMigration, version)
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("%w: version %d must have up and down files", ErrInvalidMigration, migration.Version)
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// migrate applies pending migrations if autoMigrate is set, otherwise it only checks the schema is up to date.
+func migrate(ctx context.Context, conn *gorm.DB, autoMigrate bool) error {
+	migrator, err := NewMigrator(conn)
+	if err != nil {
+		return err
+	}
+
+	if !autoMigrate {
+		return migrator.Check(ctx)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
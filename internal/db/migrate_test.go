@@ -0,0 +1,340 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// allModels returns the models of the tables created by the migrations.
+func allModels() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Post{},
+		&models.Subscriber{},
+		&models.MagicLink{},
+		&models.WebAuthnCredential{},
+		&models.WebAuthnChallenge{},
+		&models.RecoveryCode{},
+		&models.RateLimitBucket{},
+	}
+}
+
+// schemaOf returns the columns and the foreign keys of the tables of the models, so the schemas can be compared.
+func schemaOf(t *testing.T, conn *gorm.DB) []string {
+	t.Helper()
+
+	var tables []string
+	for _, model := range allModels() {
+		stmt := &gorm.Statement{DB: conn}
+		assert.NoError(t, stmt.Parse(model))
+		tables = append(tables, stmt.Table)
+	}
+
+	var columns []struct {
+		TableName     string
+		ColumnName    string
+		DataType      string
+		IsNullable    string
+		ColumnDefault *string
+	}
+	err := conn.Raw(`SELECT table_name, column_name, data_type, is_nullable, column_default
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name IN ?
+		ORDER BY table_name, column_name`, tables).Scan(&columns).Error
+	assert.NoError(t, err)
+
+	var foreignKeys []struct {
+		TableName      string
+		ConstraintName string
+		UpdateRule     string
+		DeleteRule     string
+	}
+	err = conn.Raw(`SELECT tc.table_name, tc.constraint_name, rc.update_rule, rc.delete_rule
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.referential_constraints rc
+			ON rc.constraint_schema = tc.constraint_schema AND rc.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = current_schema() AND tc.constraint_type = 'FOREIGN KEY' AND tc.table_name IN ?
+		ORDER BY tc.table_name, tc.constraint_name`, tables).Scan(&foreignKeys).Error
+	assert.NoError(t, err)
+
+	schema := make([]string, 0, len(columns)+len(foreignKeys))
+	for _, c := range columns {
+		columnDefault := "<none>"
+		if c.ColumnDefault != nil {
+			columnDefault = *c.ColumnDefault
+		}
+		schema = append(schema, fmt.Sprintf("column %s.%s %s nullable=%s default=%s",
+			c.TableName, c.ColumnName, c.DataType, c.IsNullable, columnDefault))
+	}
+	for _, fk := range foreignKeys {
+		schema = append(schema, fmt.Sprintf("foreign key %s.%s on update %s on delete %s",
+			fk.TableName, fk.ConstraintName, fk.UpdateRule, fk.DeleteRule))
+	}
+
+	return schema
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("embedded migrations", func(t *testing.T) {
+		sub, err := fs.Sub(migrationsFS, "migrations")
+		assert.NoError(t, err)
+
+		migrations, err := loadMigrations(sub)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, migrations)
+		assert.Equal(t, int64(1), migrations[0].Version)
+		assert.Equal(t, "baseline", migrations[0].Name)
+	})
+
+	t.Run("ordered by version", func(t *testing.T) {
+		migrations, err := loadMigrations(fstest.MapFS{
+			"000010_second.up.sql":   {Data: []byte("SELECT 2")},
+			"000010_second.down.sql": {Data: []byte("SELECT -2")},
+			"000002_first.up.sql":    {Data: []byte("SELECT 1")},
+			"000002_first.down.sql":  {Data: []byte("SELECT -1")},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Migration{
+			{Version: 2, Name: "first", Up: "SELECT 1", Down: "SELECT -1"},
+			{Version: 10, Name: "second", Up: "SELECT 2", Down: "SELECT -2"},
+		}, migrations)
+	})
+
+	testCases := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{"000001_first.up.sql": {Data: []byte("SELECT 1")}},
+		},
+		{
+			name: "different names of the version",
+			fsys: fstest.MapFS{
+				"000001_first.up.sql":   {Data: []byte("SELECT 1")},
+				"000001_other.down.sql": {Data: []byte("SELECT -1")},
+			},
+		},
+		{
+			name: "unexpected file",
+			fsys: fstest.MapFS{"README.md": {Data: []byte("# Migrations")}},
+		},
+		{
+			name: "zero version",
+			fsys: fstest.MapFS{
+				"000000_first.up.sql":   {Data: []byte("SELECT 1")},
+				"000000_first.down.sql": {Data: []byte("SELECT -1")},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loadMigrations(tc.fsys)
+			assert.ErrorIs(t, err, ErrInvalidMigration)
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	t.Run("pending migrations on empty database", func(t *testing.T) {
+		assert.ErrorIs(t, migrator.Check(ctx), ErrPendingMigrations)
+		assert.ErrorIs(t, migrator.CheckUnlocked(ctx), ErrPendingMigrations)
+		assert.ErrorIs(t, migrate(ctx, conn, false), ErrPendingMigrations)
+	})
+
+	t.Run("concurrent up applies migrations once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		applied := make([][]*Migration, 3)
+		errs := make([]error, 3)
+		for i := range applied {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				applied[i], errs[i] = migrator.Up(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		total := 0
+		for i := range applied {
+			assert.NoError(t, errs[i])
+			total += len(applied[i])
+		}
+		assert.Equal(t, len(migrator.Migrations()), total)
+		assert.NoError(t, migrator.Check(ctx))
+		assert.NoError(t, migrator.CheckUnlocked(ctx))
+	})
+
+	t.Run("baseline matches the models", func(t *testing.T) {
+		for _, model := range allModels() {
+			stmt := &gorm.Statement{DB: conn}
+			assert.NoError(t, stmt.Parse(model))
+
+			assert.True(t, conn.Migrator().HasTable(model), stmt.Table)
+			for _, column := range stmt.Schema.DBNames {
+				assert.True(t, conn.Migrator().HasColumn(model, column), stmt.Table+"."+column)
+			}
+			for _, index := range stmt.Schema.ParseIndexes() {
+				assert.True(t, conn.Migrator().HasIndex(model, index.Name), index.Name)
+			}
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		statuses, err := migrator.Status(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, len(migrator.Migrations()))
+		for _, status := range statuses {
+			assert.True(t, status.Applied)
+			assert.False(t, status.Dirty)
+			assert.NotNil(t, status.AppliedAt)
+		}
+	})
+
+	t.Run("down and up again", func(t *testing.T) {
+		reverted, err := migrator.Down(ctx, len(migrator.Migrations()))
+		assert.NoError(t, err)
+		assert.Len(t, reverted, len(migrator.Migrations()))
+		assert.False(t, conn.Migrator().HasTable(&models.User{}))
+
+		applied, err := migrator.Up(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, applied, len(migrator.Migrations()))
+		assert.True(t, conn.Migrator().HasTable(&models.User{}))
+	})
+
+	t.Run("failed migration is rolled back", func(t *testing.T) {
+		sqlDB, err := conn.DB()
+		assert.NoError(t, err)
+
+		broken, err := newMigrator(sqlDB, fstest.MapFS{
+			"999999_broken.up.sql":   {Data: []byte("CREATE TABLE broken (id bigint); SELECT * FROM missing_table")},
+			"999999_broken.down.sql": {Data: []byte("DROP TABLE broken")},
+		})
+		assert.NoError(t, err)
+
+		_, err = broken.Up(ctx)
+		assert.ErrorIs(t, err, ErrFailedToMigrateDatabase)
+		assert.False(t, conn.Migrator().HasTable("broken"))
+		assert.ErrorIs(t, broken.Check(ctx), ErrPendingMigrations)
+	})
+
+	t.Run("dirty database", func(t *testing.T) {
+		err := conn.Exec("UPDATE schema_migrations SET dirty = true WHERE version = 1").Error
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, migrator.Check(ctx), ErrDirtyDatabase)
+		assert.ErrorIs(t, migrator.CheckUnlocked(ctx), ErrDirtyDatabase)
+		assert.ErrorIs(t, migrate(ctx, conn, true), ErrDirtyDatabase)
+
+		_, err = migrator.Down(ctx, 1)
+		assert.ErrorIs(t, err, ErrDirtyDatabase)
+
+		assert.NoError(t, migrator.Force(ctx, 1, true))
+		assert.NoError(t, migrator.Check(ctx))
+	})
+
+	t.Run("force unknown version", func(t *testing.T) {
+		assert.ErrorIs(t, migrator.Force(ctx, 999, true), ErrMigrationNotFound)
+	})
+}
+
+func TestMigrator_SchemaMatchesModels(t *testing.T) {
+	migrated, err := testdb.InitDatabaseTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, migrate(context.Background(), migrated, true))
+
+	autoMigrated, err := testdb.InitDatabaseTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, autoMigrated.AutoMigrate(allModels()...))
+
+	assert.Equal(t, schemaOf(t, autoMigrated), schemaOf(t, migrated))
+}
+
+func TestMigrator_Baseline_ExistingDatabase(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Database created by AutoMigrate before the migrations were introduced
+	err = conn.AutoMigrate(
+		&models.User{},
+		&models.Post{},
+		&models.Subscriber{},
+		&models.MagicLink{},
+		&models.WebAuthnCredential{},
+		&models.RecoveryCode{},
+	)
+	assert.NoError(t, err)
+
+	// Constraint created by the models before the users with posts were protected from deletion
+	err = conn.Exec(`ALTER TABLE "posts" DROP CONSTRAINT "fk_users_posts", ADD CONSTRAINT "fk_users_posts"
+		FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL ON UPDATE CASCADE`).Error
+	assert.NoError(t, err)
+
+	err = conn.Create(&models.User{ExternalID: "1", Login: "admin", AuthMethod: models.GitHubAuthMethod}).Error
+	assert.NoError(t, err)
+
+	assert.NoError(t, migrate(context.Background(), conn, true))
+
+	var count int64
+	assert.NoError(t, conn.Model(&models.User{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// ON DELETE SET NULL is replaced, since it fails on the NOT NULL user_id
+	assert.Contains(t, schemaOf(t, conn), "foreign key posts.fk_users_posts on update CASCADE on delete NO ACTION")
+}
+
+func TestMigrator_Baseline_UsersWithoutRoles(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Users table created by AutoMigrate before the roles and statuses were introduced
+	err = conn.Exec(`CREATE TABLE "users" (
+		"id" bigserial,
+		"external_id" text,
+		"login" text,
+		"auth_method" text,
+		"created_at" timestamptz,
+		"updated_at" timestamptz,
+		PRIMARY KEY ("id")
+	)`).Error
+	assert.NoError(t, err)
+	err = conn.Exec(`INSERT INTO "users" ("external_id", "login", "auth_method") VALUES ('1', 'admin', 'github')`).Error
+	assert.NoError(t, err)
+
+	assert.NoError(t, migrate(context.Background(), conn, true))
+
+	var user models.User
+	assert.NoError(t, conn.Where("external_id = ?", "1").First(&user).Error)
+	assert.Equal(t, models.AuthorUserRole, user.Role)
+	assert.Equal(t, models.ActiveUserStatus, user.Status)
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "recovery_codes";
+DROP TABLE IF EXISTS "web_authn_credentials";
+DROP TABLE IF EXISTS "magic_links";
+DROP TABLE IF EXISTS "subscribers";
+DROP TABLE IF EXISTS "posts";
+DROP TABLE IF EXISTS "users";
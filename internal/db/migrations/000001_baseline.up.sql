@@ -0,0 +1,89 @@
+-- Baseline of the schema previously created by GORM AutoMigrate.
+-- Tables and indexes are only created if missing, so the baseline can be applied to existing databases.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id"          bigserial,
+    "external_id" text,
+    "login"       text,
+    "auth_method" text,
+    "role"        text NOT NULL DEFAULT 'author',
+    "status"      text NOT NULL DEFAULT 'active',
+    "created_at"  timestamptz,
+    "updated_at"  timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_external_id" ON "users" ("external_id");
+-- Users of the databases created before the roles are the authors, that can sign in.
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" text NOT NULL DEFAULT 'author';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'active';
+
+CREATE TABLE IF NOT EXISTS "posts" (
+    "id"                     bigserial,
+    "slug"                   text,
+    "title"                  text,
+    "description"            text,
+    "keywords"               text,
+    "content"                text,
+    "reading_time"           bigint,
+    "user_id"                bigint NOT NULL,
+    "sent_to_subscribers_at" timestamptz DEFAULT null,
+    "created_at"             timestamptz,
+    "updated_at"             timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_posts_slug" ON "posts" ("slug");
+-- Posts of the existing databases reference the users with ON DELETE SET NULL, that fails on the NOT NULL column,
+-- so the constraint is recreated and the users with posts can't be deleted.
+ALTER TABLE "posts" DROP CONSTRAINT IF EXISTS "fk_users_posts";
+ALTER TABLE "posts" ADD CONSTRAINT "fk_users_posts" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE CASCADE;
+
+CREATE TABLE IF NOT EXISTS "subscribers" (
+    "id"           uuid,
+    "email"        text,
+    "is_confirmed" boolean,
+    "created_at"   timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscribers_email" ON "subscribers" ("email");
+
+CREATE TABLE IF NOT EXISTS "magic_links" (
+    "id"         uuid,
+    "user_id"    bigint NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at"    timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_magic_links_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_magic_links_user_id" ON "magic_links" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "web_authn_credentials" (
+    "id"               bigserial,
+    "user_id"          bigint NOT NULL,
+    "name"             text,
+    "credential_id"    bytea NOT NULL,
+    "public_key"       bytea NOT NULL,
+    "attestation_type" text,
+    "transports"       text,
+    "aa_guid"          bytea,
+    "sign_count"       bigint,
+    "backup_eligible"  boolean,
+    "backup_state"     boolean,
+    "last_used_at"     timestamptz,
+    "created_at"       timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_web_authn_credentials_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_web_authn_credentials_credential_id" ON "web_authn_credentials" ("credential_id");
+CREATE INDEX IF NOT EXISTS "idx_web_authn_credentials_user_id" ON "web_authn_credentials" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+    "id"         bigserial,
+    "user_id"    bigint NOT NULL,
+    "code_hash"  text NOT NULL,
+    "used_at"    timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recovery_codes_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_recovery_codes_user_hash" ON "recovery_codes" ("user_id", "code_hash");
@@ -0,0 +1 @@
+ALTER TABLE "posts" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_posts_search";
+DROP INDEX IF EXISTS "idx_posts_keywords";
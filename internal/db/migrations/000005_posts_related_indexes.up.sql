@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS "idx_posts_keywords" ON "posts" USING gin (string_to_array("keywords", ','));
+CREATE INDEX IF NOT EXISTS "idx_posts_search" ON "posts" USING gin (to_tsvector('english', "title" || ' ' || "description"));
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "web_authn_challenges";
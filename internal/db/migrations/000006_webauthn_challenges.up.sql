@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS "web_authn_challenges" (
+    "challenge"  text,
+    "expires_at" timestamptz NOT NULL,
+    PRIMARY KEY ("challenge")
+);
+CREATE INDEX IF NOT EXISTS "idx_web_authn_challenges_expires_at" ON "web_authn_challenges" ("expires_at");
@@ -0,0 +1,19 @@
+-- Each shared keyword scores 1, and the rank of the text similarity is mostly below 0.1, so the shared keywords
+-- rank first, and the text similarity ranks the posts with the same number of shared keywords, or without any.
+-- The words of the post are joined with OR instead of AND of plainto_tsquery, as any shared word is relevant.
+CREATE MATERIALIZED VIEW IF NOT EXISTS "post_related_scores" AS
+WITH post AS (
+    SELECT id,
+        string_to_array(keywords, ',') AS keywords,
+        replace(plainto_tsquery('english', title || ' ' || description)::text, '&', '|')::tsquery AS words
+    FROM posts
+)
+SELECT post.id AS post_id, p.id AS related_id,
+    cardinality(ARRAY(
+        SELECT unnest(string_to_array(p.keywords, ',')) INTERSECT SELECT unnest(post.keywords)
+    )) + ts_rank(to_tsvector('english', p.title || ' ' || p.description), post.words) AS score
+FROM post
+JOIN posts p ON p.id <> post.id
+    AND (string_to_array(p.keywords, ',') && post.keywords
+        OR to_tsvector('english', p.title || ' ' || p.description) @@ post.words);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_post_related_scores_post_related" ON "post_related_scores" ("post_id", "related_id");
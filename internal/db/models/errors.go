@@ -11,11 +11,16 @@ var (
 	ErrValidationFailed = errors.New("ERR_VALIDATION_FAILED")
 	ErrNotFound         = errors.New("ERR_NOT_FOUND") // ErrNotFound is returned if item is not found
 	ErrDuplicate        = errors.New("ERR_DUPLICATE") // ErrDuplicate is returned if item already exists
+	ErrConflict         = errors.New("ERR_CONFLICT")  // ErrConflict is returned if item was changed since it was read
 
 	ErrUserLoginRequired      = errors.New("ERR_USER_LOGIN_REQUIRED")
 	ErrUserExternalIDRequired = errors.New("ERR_USER_EXTERNAL_ID_REQUIRED")
 	ErrUserAuthMethodRequired = errors.New("ERR_USER_AUTH_METHOD_REQUIRED")
+	ErrUserInvalidRole        = errors.New("ERR_USER_INVALID_ROLE")
+	ErrUserInvalidStatus      = errors.New("ERR_USER_INVALID_STATUS")
+	ErrUserInvalidEmail       = errors.New("ERR_USER_INVALID_EMAIL")
 	ErrFailedToCreateUser     = errors.New("ERR_FAILED_TO_CREATE_USER")
+	ErrFailedToUpdateUser     = errors.New("ERR_FAILED_TO_UPDATE_USER")
 	ErrFailedToGetUser        = errors.New("ERR_FAILED_TO_GET_USER")
 
 	ErrPostURLRequired         = errors.New("ERR_POST_URL_REQUIRED")
@@ -24,6 +29,7 @@ var (
 	ErrPostContentRequired     = errors.New("ERR_POST_CONTENT_REQUIRED")
 	ErrPostWrongKeywordsString = errors.New("ERR_POST_WRONG_KEYWORDS_STRING")
 	ErrPostInvalidSlug         = errors.New("ERR_POST_INVALID_SLUG")
+	ErrPostReservedSlug        = errors.New("ERR_POST_RESERVED_SLUG")
 	ErrPostUserIDRequired      = errors.New("ERR_POST_USER_ID_REQUIRED")
 
 	ErrCreateSubscription        = errors.New("ERR_CREATE_SUBSCRIPTION")
@@ -32,6 +38,27 @@ var (
 	ErrGetSubscriptionEmails     = errors.New("ERR_GET_SUBSCRIPTION_EMAILS")
 	ErrDeleteSubscription        = errors.New("ERR_DELETE_SUBSCRIPTION")
 	ErrUpdateSubscription        = errors.New("ERR_UPDATE_SUBSCRIPTION")
+
+	ErrMagicLinkUserIDRequired    = errors.New("ERR_MAGIC_LINK_USER_ID_REQUIRED")
+	ErrMagicLinkExpiresAtRequired = errors.New("ERR_MAGIC_LINK_EXPIRES_AT_REQUIRED")
+	ErrCreateMagicLink            = errors.New("ERR_CREATE_MAGIC_LINK")
+	ErrUseMagicLink               = errors.New("ERR_USE_MAGIC_LINK")
+
+	ErrWebAuthnCredentialUserIDRequired = errors.New("ERR_WEBAUTHN_CREDENTIAL_USER_ID_REQUIRED")
+	ErrWebAuthnCredentialKeyRequired    = errors.New("ERR_WEBAUTHN_CREDENTIAL_KEY_REQUIRED")
+	ErrCreateWebAuthnCredential         = errors.New("ERR_CREATE_WEBAUTHN_CREDENTIAL")
+	ErrUpdateWebAuthnCredential         = errors.New("ERR_UPDATE_WEBAUTHN_CREDENTIAL")
+	ErrGetWebAuthnCredentials           = errors.New("ERR_GET_WEBAUTHN_CREDENTIALS")
+	ErrDeleteWebAuthnCredential         = errors.New("ERR_DELETE_WEBAUTHN_CREDENTIAL")
+	ErrUseWebAuthnChallenge             = errors.New("ERR_USE_WEBAUTHN_CHALLENGE")
+
+	ErrRecoveryCodeRequired = errors.New("ERR_RECOVERY_CODE_REQUIRED")
+	ErrReplaceRecoveryCodes = errors.New("ERR_REPLACE_RECOVERY_CODES")
+	ErrUseRecoveryCode      = errors.New("ERR_USE_RECOVERY_CODE")
+	ErrGetRecoveryCodes     = errors.New("ERR_GET_RECOVERY_CODES")
+
+	ErrUpdateRateLimit  = errors.New("ERR_UPDATE_RATE_LIMIT")
+	ErrDeleteRateLimits = errors.New("ERR_DELETE_RATE_LIMITS")
 )
 
 // mapGormError maps gorm errors to application errors if possible.
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// MagicLinkRepository is the database for the magic links sent to the users for the passwordless login.
+type MagicLinkRepository struct {
+	conn *gorm.DB
+}
+
+// NewMagicLinkRepository creates a new MagicLinkRepository.
+func NewMagicLinkRepository(conn *gorm.DB) *MagicLinkRepository {
+	return &MagicLinkRepository{
+		conn: conn,
+	}
+}
+
+// MagicLink is the model for the single-use login link.
+type MagicLink struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	UserID    int        `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"` // UsedAt is set when the link is exchanged for the JWT token
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (m *MagicLink) Validate() error {
+	if m.UserID == 0 {
+		return ErrMagicLinkUserIDRequired
+	}
+	if m.ExpiresAt.IsZero() {
+		return ErrMagicLinkExpiresAtRequired
+	}
+	return nil
+}
+
+func (m *MagicLink) BeforeCreate(_ *gorm.DB) error {
+	err := m.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+
+	m.CreatedAt = time.Now()
+	return nil
+}
+
+// MagicLinkRepositoryInterface is the interface for the MagicLinkRepository.
+type MagicLinkRepositoryInterface interface {
+	Create(ctx context.Context, link *MagicLink) error
+	Use(ctx context.Context, id string) (*MagicLink, error)
+}
+
+// Create inserts a new MagicLink.
+func (db *MagicLinkRepository) Create(ctx context.Context, link *MagicLink) error {
+	err := db.conn.WithContext(ctx).Create(link).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCreateMagicLink, mapGormError(err))
+	}
+
+	return nil
+}
+
+// Use marks the MagicLink as used and returns it.
+// It returns ErrNotFound if the link doesn't exist, is expired or was already used,
+// so the same link can't be used twice even with concurrent requests.
+func (db *MagicLinkRepository) Use(ctx context.Context, id string) (*MagicLink, error) {
+	var links []*MagicLink
+	now := time.Now()
+	err := db.conn.WithContext(ctx).
+		Model(&links).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUseMagicLink, mapGormError(err))
+	}
+
+	if len(links) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, gorm.ErrRecordNotFound)
+	}
+
+	return links[0], nil
+}
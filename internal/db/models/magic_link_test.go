@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"github.com/google/uuid"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMagicLinksDB(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &MagicLink{})
+	assert.NoError(t, err)
+
+	userDB := NewUserRepository(conn)
+	magicLinkDB := NewMagicLinkRepository(conn)
+
+	user := &User{
+		ExternalID: genEmail(),
+		Login:      "guest",
+		AuthMethod: EmailAuthMethod,
+	}
+	err = userDB.Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("create a new magic link", func(t *testing.T) {
+			link := &MagicLink{
+				UserID:    user.ID,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}
+
+			err := magicLinkDB.Create(context.Background(), link)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, link.ID)
+			assert.NotZero(t, link.CreatedAt)
+			assert.Nil(t, link.UsedAt)
+		})
+
+		t.Run("return error if user is not set", func(t *testing.T) {
+			err := magicLinkDB.Create(context.Background(), &MagicLink{ExpiresAt: time.Now().Add(time.Minute)})
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrMagicLinkUserIDRequired)
+		})
+
+		t.Run("return error if expiration is not set", func(t *testing.T) {
+			err := magicLinkDB.Create(context.Background(), &MagicLink{UserID: user.ID})
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrMagicLinkExpiresAtRequired)
+		})
+	})
+
+	t.Run("Use", func(t *testing.T) {
+		t.Run("should use the link only once", func(t *testing.T) {
+			link := &MagicLink{
+				UserID:    user.ID,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}
+			err := magicLinkDB.Create(context.Background(), link)
+			assert.NoError(t, err)
+
+			used, err := magicLinkDB.Use(context.Background(), link.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, link.ID, used.ID)
+			assert.Equal(t, user.ID, used.UserID)
+			assert.NotNil(t, used.UsedAt)
+
+			_, err = magicLinkDB.Use(context.Background(), link.ID.String())
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("should return error if link is expired", func(t *testing.T) {
+			link := &MagicLink{
+				UserID:    user.ID,
+				ExpiresAt: time.Now().Add(-time.Minute),
+			}
+			err := magicLinkDB.Create(context.Background(), link)
+			assert.NoError(t, err)
+
+			_, err = magicLinkDB.Use(context.Background(), link.ID.String())
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("should return error if not found", func(t *testing.T) {
+			_, err := magicLinkDB.Use(context.Background(), uuid.New().String())
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"gorm.io/gorm"
 	"regexp"
@@ -12,6 +13,15 @@ import (
 // AvgWordsPerMinute is the average number of words per minute a person can read.
 const AvgWordsPerMinute = 250
 
+// ReservedSlug is the slug that can't be used by the posts, as its URL is the archive of the posts.
+const ReservedSlug = "archive"
+
+// Now returns the current time with the microseconds precision of Postgres. It is used as the gorm.Config NowFunc,
+// so the CreatedAt and UpdatedAt of the saved models are the same as the stored ones, e.g. for the ETag of the post.
+func Now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
 // PostRepository is the database for the post data.
 type PostRepository struct {
 	conn *gorm.DB
@@ -35,6 +45,7 @@ type Post struct {
 	ReadingTime         int       `json:"reading_time"` // ReadingTime is the estimated time to read the post in seconds
 	UserID              int       `json:"user_id" gorm:"not null;constraint:OnUpdate:CASCADE;foreignKey:ID;references:ID"`
 	SentToSubscribersAt time.Time `json:"sent_to_subscribers_at" gorm:"default:null"` // If not null, the post was sent
+	Version             int       `json:"version" gorm:"not null;default:1"`          // Version is incremented on every update
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -71,6 +82,13 @@ func (p *Post) BeforeCreate(_ *gorm.DB) error {
 		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
 	}
 
+	// Note: only the new posts are checked, as the slug can only be changed with the `post rename` command,
+	// so the posts created before the slug was reserved can still be updated until they are renamed
+	if p.Slug == ReservedSlug {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, ErrPostReservedSlug)
+	}
+
+	p.Version = 1
 	// Note: store the reading time in a database for a faster retrieval list of posts (without content)
 	p.ReadingTime = int(p.CountReadingTime().Seconds())
 
@@ -83,7 +101,7 @@ func (p *Post) BeforeUpdate(_ *gorm.DB) error {
 		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
 	}
 
-	p.UpdatedAt = time.Now()
+	p.UpdatedAt = Now()
 	p.ReadingTime = int(p.CountReadingTime().Seconds())
 
 	return nil
@@ -102,13 +120,23 @@ type PostRepositoryInterface interface {
 	Create(ctx context.Context, p *Post) error
 	GetBySlug(ctx context.Context, slug string) (*Post, error)
 	FindAll(ctx context.Context, page, perPage int) ([]*Post, error)
+	FindPage(ctx context.Context, q *PostQuery) (*PostPage, error)
+	Archive(ctx context.Context) ([]*ArchiveMonth, error)
+	Related(ctx context.Context, slug string, limit int) ([]*Post, error)
 	Update(ctx context.Context, p *Post) error
 	Count(ctx context.Context) (int64, error)
+	LastUpdatedAt(ctx context.Context) (time.Time, error)
 }
 
 // Create creates a new Post.
 func (db *PostRepository) Create(ctx context.Context, p *Post) error {
-	err := db.conn.WithContext(ctx).Create(p).Error
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+
+		return refreshRelated(tx)
+	})
 	if err != nil {
 		return mapGormError(err)
 	}
@@ -145,14 +173,39 @@ func (db *PostRepository) FindAll(ctx context.Context, page, perPage int) ([]*Po
 	return posts, nil
 }
 
-// Update updates the Post.
+// Update updates the Post if its Version is not changed since it was read and increments the Version.
+// If the Post was changed, it returns ErrConflict and sets the Version of p to the current one.
 func (db *PostRepository) Update(ctx context.Context, p *Post) error {
-	err := db.conn.WithContext(ctx).Save(p).Error
+	version := p.Version
+	p.Version++
+
+	var updated bool
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(p).Where("version = ?", version).Select("*").Updates(p)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		updated = true
+
+		return refreshRelated(tx)
+	})
+	if err != nil {
+		p.Version = version
+		return mapGormError(err)
+	}
+	if updated {
+		return nil
+	}
+
+	var current Post
+	err = db.conn.WithContext(ctx).Select("version").Where("id = ?", p.ID).First(&current).Error
 	if err != nil {
+		p.Version = version
 		return mapGormError(err)
 	}
+	p.Version = current.Version
 
-	return nil
+	return fmt.Errorf("%w: post version is %d, not %d", ErrConflict, current.Version, version)
 }
 
 // Count returns the total number of posts.
@@ -165,3 +218,14 @@ func (db *PostRepository) Count(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+// LastUpdatedAt returns the time of the last update of any post, or zero time if there are no posts.
+func (db *PostRepository) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+	var updatedAt sql.NullTime
+	err := db.conn.WithContext(ctx).Model(&Post{}).Select("max(updated_at)").Scan(&updatedAt).Error
+	if err != nil {
+		return time.Time{}, mapGormError(err)
+	}
+
+	return updatedAt.Time, nil
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PostSort is the order of the posts, by the created time and then by the ID.
+type PostSort string
+
+const (
+	PostSortNewest PostSort = "newest" // PostSortNewest sorts the newest posts first, it's the default
+	PostSortOldest PostSort = "oldest" // PostSortOldest sorts the oldest posts first
+)
+
+// PostFilter filters the posts. The zero fields are not applied.
+type PostFilter struct {
+	Keyword string    // Keyword is one of the comma separated keywords of the post
+	From    time.Time // From is the inclusive lower bound of the created time
+	To      time.Time // To is the exclusive upper bound of the created time
+	Sent    *bool     // Sent selects the posts that were or were not sent to the subscribers
+	Author  string    // Author is the login of the user that created the post
+}
+
+// PostCursor is the position in the sorted list of posts, i.e. the key of the post (created_at, id).
+type PostCursor struct {
+	CreatedAt time.Time
+	ID        int
+	Before    bool // Before selects the posts before the cursor, i.e. the previous page, instead of after it
+}
+
+// PostQuery selects the page of the posts. The page is selected with the Cursor if it's set, or with the Page.
+type PostQuery struct {
+	Filter PostFilter
+	Sort   PostSort
+	Limit  int
+	Page   int
+	Cursor *PostCursor
+}
+
+// PostPage is the page of the posts with the cursors of the adjacent pages, which are nil if there are no such pages.
+type PostPage struct {
+	Posts []*Post
+	Total int64 // Total is the number of the posts matching the filter
+	Next  *PostCursor
+	Prev  *PostCursor
+}
+
+// ArchiveMonth is the number of the posts created in the month in UTC.
+type ArchiveMonth struct {
+	Year  int
+	Month int
+	Count int64
+}
+
+// FindPage returns the page of the posts matching the query.
+// Selects only the same fields as FindAll and the ID, which is needed for the cursors.
+//
+// Note: the cursors don't skip or repeat the posts if the posts are created while paging, unlike the pages,
+// as the posts are selected after the key of the last post of the page instead of the offset.
+func (db *PostRepository) FindPage(ctx context.Context, q *PostQuery) (*PostPage, error) {
+	var total int64
+	err := db.filter(ctx, &q.Filter).Count(&total).Error
+	if err != nil {
+		return nil, mapGormError(err)
+	}
+
+	// Note: the previous page is selected in the reverse order from the cursor, and then reversed
+	desc := q.Sort != PostSortOldest
+	before := q.Cursor != nil && q.Cursor.Before
+	if before {
+		desc = !desc
+	}
+
+	tx := db.filter(ctx, &q.Filter).
+		Select("id, slug, title, description, keywords, reading_time, created_at, sent_to_subscribers_at")
+	if desc {
+		tx = tx.Order("created_at desc, id desc")
+	} else {
+		tx = tx.Order("created_at asc, id asc")
+	}
+
+	switch {
+	case q.Cursor != nil && desc:
+		tx = tx.Where("(created_at, id) < (?, ?)", q.Cursor.CreatedAt, q.Cursor.ID)
+	case q.Cursor != nil:
+		tx = tx.Where("(created_at, id) > (?, ?)", q.Cursor.CreatedAt, q.Cursor.ID)
+	case q.Page > 1:
+		tx = tx.Offset((q.Page - 1) * q.Limit)
+	}
+
+	// Note: one more post is selected to know if there is a page after this one
+	var posts []*Post
+	if err := tx.Limit(q.Limit + 1).Find(&posts).Error; err != nil {
+		return nil, mapGormError(err)
+	}
+
+	more := len(posts) > q.Limit
+	if more {
+		posts = posts[:q.Limit]
+	}
+
+	page := &PostPage{Posts: posts, Total: total}
+	if before {
+		for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+	if len(posts) == 0 {
+		return page, nil
+	}
+
+	first, last := posts[0], posts[len(posts)-1]
+	hasPrev := q.Cursor != nil || q.Page > 1
+	hasNext := more
+	if before {
+		hasPrev, hasNext = more, true
+	}
+
+	if hasPrev {
+		page.Prev = &PostCursor{CreatedAt: first.CreatedAt, ID: first.ID, Before: true}
+	}
+	if hasNext {
+		page.Next = &PostCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// Archive returns the number of the posts by the month of their creation in UTC, from the newest month.
+// The months without posts are not returned.
+func (db *PostRepository) Archive(ctx context.Context) ([]*ArchiveMonth, error) {
+	var months []*ArchiveMonth
+	err := db.conn.WithContext(ctx).
+		Model(&Post{}).
+		Select("extract(year from created_at at time zone 'UTC')::int AS year, " +
+			"extract(month from created_at at time zone 'UTC')::int AS month, " +
+			"count(*) AS count").
+		Group("year, month").
+		Order("year desc, month desc").
+		Scan(&months).Error
+	if err != nil {
+		return nil, mapGormError(err)
+	}
+
+	return months, nil
+}
+
+// relatedQuery selects the posts related to the post of the slug from the highest score
+// of the post_related_scores view of the 000007 migration.
+const relatedQuery = `
+SELECT p.id, p.slug, p.title, p.description, p.keywords, p.reading_time, p.created_at, p.sent_to_subscribers_at
+FROM post_related_scores s
+JOIN posts p ON p.id = s.related_id
+WHERE s.post_id = (SELECT id FROM posts WHERE slug = ?)
+ORDER BY s.score DESC, p.created_at DESC, p.id DESC
+LIMIT ?`
+
+// Related returns up to the limit of the other posts, that are the most similar to the post of the slug
+// by the shared keywords and the text of the title and the description. Selects the same fields as FindPage.
+// The posts without anything in common are not returned, as well as any posts if the slug is not found.
+//
+// Note: the scores are precomputed on Create and Update of any post, see refreshRelated.
+func (db *PostRepository) Related(ctx context.Context, slug string, limit int) ([]*Post, error) {
+	var posts []*Post
+	err := db.conn.WithContext(ctx).Raw(relatedQuery, slug, limit).Scan(&posts).Error
+	if err != nil {
+		return nil, mapGormError(err)
+	}
+
+	return posts, nil
+}
+
+// refreshRelated computes again the scores of the related posts in the tx, as the change of any post
+// changes the scores of the others. Concurrently, so the Related reads are not blocked.
+func refreshRelated(tx *gorm.DB) error {
+	return tx.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY post_related_scores").Error
+}
+
+// filter returns the query of the posts matching the f.
+func (db *PostRepository) filter(ctx context.Context, f *PostFilter) *gorm.DB {
+	tx := db.conn.WithContext(ctx).Model(&Post{})
+
+	if f.Keyword != "" {
+		tx = tx.Where("? = ANY(string_to_array(keywords, ','))", f.Keyword)
+	}
+	if !f.From.IsZero() {
+		tx = tx.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		tx = tx.Where("created_at < ?", f.To)
+	}
+	// Note: the zero time is stored instead of null, if the post was updated before it was sent
+	if f.Sent != nil && *f.Sent {
+		tx = tx.Where("sent_to_subscribers_at > ?", time.Time{})
+	}
+	if f.Sent != nil && !*f.Sent {
+		tx = tx.Where("(sent_to_subscribers_at IS NULL OR sent_to_subscribers_at <= ?)", time.Time{})
+	}
+	if f.Author != "" {
+		tx = tx.Where("user_id IN (?)", db.conn.Model(&User{}).Select("id").Where("login = ?", f.Author))
+	}
+
+	return tx
+}
@@ -42,6 +42,7 @@ func TestPostDB(t *testing.T) {
 			assert.NotEmpty(t, post.ID)
 			assert.NotZero(t, post.CreatedAt)
 			assert.NotZero(t, post.UpdatedAt)
+			assert.Equal(t, 1, post.Version)
 		})
 
 		t.Run("return error if slug is not unique", func(t *testing.T) {
@@ -187,12 +188,45 @@ func TestPostDB(t *testing.T) {
 		post.Title = "Updated Title"
 		err = postDB.Update(context.Background(), post)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, post.Version)
 
 		updatedPost, err := postDB.GetBySlug(context.Background(), post.Slug)
 		assert.NoError(t, err)
 		assert.Equal(t, "Updated Title", updatedPost.Title)
 		assert.NotZero(t, updatedPost.UpdatedAt)
 		assert.NotZero(t, updatedPost.SentToSubscribersAt)
+		assert.Equal(t, 2, updatedPost.Version)
+
+		t.Run("return conflict if the version is changed", func(t *testing.T) {
+			stale, err := postDB.GetBySlug(context.Background(), post.Slug)
+			assert.NoError(t, err)
+
+			post.Title = "Newer Title"
+			err = postDB.Update(context.Background(), post)
+			assert.NoError(t, err)
+
+			stale.Title = "Stale Title"
+			err = postDB.Update(context.Background(), stale)
+			assert.ErrorIs(t, err, ErrConflict)
+			assert.Equal(t, 3, stale.Version)
+
+			current, err := postDB.GetBySlug(context.Background(), post.Slug)
+			assert.NoError(t, err)
+			assert.Equal(t, "Newer Title", current.Title)
+		})
+
+		t.Run("return not found if the post is missing", func(t *testing.T) {
+			err := postDB.Update(context.Background(), &Post{
+				ID:          -1,
+				UserID:      user.ID,
+				Slug:        uuid.New().String(),
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+				Version:     1,
+			})
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
 	})
 }
 
@@ -310,6 +344,20 @@ func TestPost_BeforeCreate(t *testing.T) {
 		assert.ErrorIs(t, err, ErrValidationFailed)
 	})
 
+	t.Run("return error if slug is reserved", func(t *testing.T) {
+		post := &Post{
+			Slug:        ReservedSlug,
+			Title:       "Test",
+			Description: "Test Description",
+			Content:     "Test Content",
+			UserID:      1,
+		}
+
+		err := post.BeforeCreate(nil)
+		assert.ErrorIs(t, err, ErrValidationFailed)
+		assert.ErrorIs(t, err, ErrPostReservedSlug)
+	})
+
 	t.Run("return nil if valid", func(t *testing.T) {
 		post := &Post{
 			Slug:        uuid.New().String(),
@@ -355,6 +403,20 @@ func TestPost_BeforeUpdate(t *testing.T) {
 		assert.NotZero(t, post.UpdatedAt)
 		assert.Equal(t, 1, post.ReadingTime) // reading time should be recalculated
 	})
+
+	t.Run("return nil if slug is reserved", func(t *testing.T) {
+		// Note: the post could be created before the slug was reserved
+		post := &Post{
+			Slug:        ReservedSlug,
+			Title:       "Test",
+			Description: "Test Description",
+			Content:     "Test Content",
+			UserID:      1,
+		}
+
+		err := post.BeforeUpdate(nil)
+		assert.NoError(t, err)
+	})
 }
 
 func TestPost_CountReadingTime(t *testing.T) {
@@ -413,3 +475,337 @@ func TestPostDB_Count(t *testing.T) {
 		assert.Equal(t, int64(5), count)
 	})
 }
+
+func TestPostDB_LastUpdatedAt(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &Post{})
+	assert.NoError(t, err)
+
+	// insert a user to db
+	user := &User{
+		ExternalID: uuid.New().String(),
+		Login:      uuid.New().String(),
+		AuthMethod: GitHubAuthMethod,
+	}
+
+	err = conn.WithContext(context.Background()).Create(user).Error
+	assert.NoError(t, err)
+
+	postDB := NewPostRepository(conn)
+
+	t.Run("return zero time if there are no posts", func(t *testing.T) {
+		updatedAt, err := postDB.LastUpdatedAt(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, updatedAt.IsZero())
+	})
+
+	t.Run("return time of the last updated post", func(t *testing.T) {
+		var posts []*Post
+		for range 2 {
+			post := &Post{
+				UserID:      user.ID,
+				Slug:        uuid.New().String(),
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+			}
+
+			err := postDB.Create(context.Background(), post)
+			assert.NoError(t, err)
+			posts = append(posts, post)
+		}
+
+		posts[0].Title = "Updated Title"
+		err := postDB.Update(context.Background(), posts[0])
+		assert.NoError(t, err)
+
+		updatedAt, err := postDB.LastUpdatedAt(context.Background())
+		assert.NoError(t, err)
+		assert.WithinDuration(t, posts[0].UpdatedAt, updatedAt, time.Millisecond)
+	})
+}
+
+func TestPostDB_FindPage(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &Post{})
+	assert.NoError(t, err)
+
+	// insert the users to db
+	users := make([]*User, 2)
+	for i := range users {
+		users[i] = &User{
+			ExternalID: uuid.New().String(),
+			Login:      uuid.New().String(),
+			AuthMethod: GitHubAuthMethod,
+		}
+
+		err = conn.WithContext(context.Background()).Create(users[i]).Error
+		assert.NoError(t, err)
+	}
+
+	postDB := NewPostRepository(conn)
+
+	// create posts, from the oldest to the newest
+	createPost := func(user *User, keywords string, sent bool) *Post {
+		post := &Post{
+			UserID:      user.ID,
+			Slug:        uuid.New().String(),
+			Title:       "Test Title",
+			Description: "Test Description",
+			Content:     "Test Content",
+			Keywords:    keywords,
+		}
+		if sent {
+			post.SentToSubscribersAt = time.Now()
+		}
+
+		err := postDB.Create(context.Background(), post)
+		assert.NoError(t, err)
+
+		return post
+	}
+	posts := []*Post{
+		createPost(users[0], "go,sql", true),
+		createPost(users[0], "go", true),
+		createPost(users[1], "sql", false),
+		createPost(users[0], "go,openapi", false),
+		createPost(users[1], "", false),
+	}
+
+	// ids returns the IDs of the posts
+	ids := func(posts []*Post) []int {
+		ids := make([]int, 0, len(posts))
+		for _, p := range posts {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		page, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Page: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), page.Total)
+		assert.Equal(t, []int{posts[4].ID, posts[3].ID}, ids(page.Posts))
+		assert.Nil(t, page.Prev)
+		assert.Equal(t, &PostCursor{CreatedAt: page.Posts[1].CreatedAt, ID: posts[3].ID}, page.Next)
+
+		page, err = postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Page: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[0].ID}, ids(page.Posts))
+		assert.NotNil(t, page.Prev)
+		assert.Nil(t, page.Next)
+
+		// check that unnecessary fields are empty
+		assert.Empty(t, page.Posts[0].Content)
+		assert.Empty(t, page.Posts[0].UserID)
+	})
+
+	t.Run("cursors", func(t *testing.T) {
+		first, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2})
+		assert.NoError(t, err)
+
+		second, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Cursor: first.Next})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[2].ID, posts[1].ID}, ids(second.Posts))
+		assert.NotNil(t, second.Prev)
+		assert.NotNil(t, second.Next)
+
+		prev, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Cursor: second.Prev})
+		assert.NoError(t, err)
+		assert.Equal(t, ids(first.Posts), ids(prev.Posts))
+		assert.Nil(t, prev.Prev)
+		assert.NotNil(t, prev.Next)
+
+		last, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Cursor: second.Next})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[0].ID}, ids(last.Posts))
+		assert.Nil(t, last.Next)
+	})
+
+	t.Run("oldest first", func(t *testing.T) {
+		first, err := postDB.FindPage(context.Background(), &PostQuery{Sort: PostSortOldest, Limit: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[0].ID, posts[1].ID, posts[2].ID}, ids(first.Posts))
+
+		next, err := postDB.FindPage(context.Background(), &PostQuery{Sort: PostSortOldest, Limit: 3, Cursor: first.Next})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[3].ID, posts[4].ID}, ids(next.Posts))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		// Note: the created time is read, as Postgres rounds the nanoseconds of the created posts
+		stored, err := postDB.FindPage(context.Background(), &PostQuery{Sort: PostSortOldest, Limit: 5})
+		assert.NoError(t, err)
+
+		sent, notSent := true, false
+		tests := []struct {
+			name   string
+			filter PostFilter
+			want   []*Post
+		}{
+			{name: "keyword", filter: PostFilter{Keyword: "go"}, want: []*Post{posts[3], posts[1], posts[0]}},
+			{name: "part of keyword", filter: PostFilter{Keyword: "o"}, want: []*Post{}},
+			{name: "sent", filter: PostFilter{Sent: &sent}, want: []*Post{posts[1], posts[0]}},
+			{name: "not sent", filter: PostFilter{Sent: &notSent}, want: []*Post{posts[4], posts[3], posts[2]}},
+			{name: "author", filter: PostFilter{Author: users[1].Login}, want: []*Post{posts[4], posts[2]}},
+			{
+				name:   "created time",
+				filter: PostFilter{From: stored.Posts[1].CreatedAt, To: stored.Posts[3].CreatedAt},
+				want:   []*Post{posts[2], posts[1]},
+			},
+			{
+				name:   "all",
+				filter: PostFilter{Keyword: "sql", Author: users[0].Login, Sent: &sent},
+				want:   []*Post{posts[0]},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := postDB.FindPage(context.Background(), &PostQuery{Filter: tt.filter, Limit: 5})
+				assert.NoError(t, err)
+				assert.Equal(t, ids(tt.want), ids(page.Posts))
+				assert.Equal(t, int64(len(tt.want)), page.Total)
+			})
+		}
+	})
+
+	t.Run("cursor doesn't repeat posts created while paging", func(t *testing.T) {
+		first, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2})
+		assert.NoError(t, err)
+
+		createPost(users[0], "", false)
+
+		second, err := postDB.FindPage(context.Background(), &PostQuery{Limit: 2, Cursor: first.Next})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{posts[2].ID, posts[1].ID}, ids(second.Posts))
+	})
+}
+
+func TestPostDB_Archive(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &Post{})
+	assert.NoError(t, err)
+
+	// insert a user to db
+	user := &User{
+		ExternalID: uuid.New().String(),
+		Login:      uuid.New().String(),
+		AuthMethod: GitHubAuthMethod,
+	}
+
+	err = conn.WithContext(context.Background()).Create(user).Error
+	assert.NoError(t, err)
+
+	postDB := NewPostRepository(conn)
+
+	t.Run("return empty archive if there are no posts", func(t *testing.T) {
+		months, err := postDB.Archive(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, months)
+	})
+
+	t.Run("return count of posts by month", func(t *testing.T) {
+		// Note: the month is in UTC, e.g. the first post is created in January in UTC, but in December in New York
+		newYork := time.FixedZone("EST", -5*60*60)
+
+		for _, createdAt := range []time.Time{
+			time.Date(2023, 12, 31, 20, 0, 0, 0, newYork),
+			time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		} {
+			post := &Post{
+				UserID:      user.ID,
+				Slug:        uuid.New().String(),
+				Title:       "Test Title",
+				Description: "Test Description",
+				Content:     "Test Content",
+				CreatedAt:   createdAt,
+			}
+
+			err := postDB.Create(context.Background(), post)
+			assert.NoError(t, err)
+		}
+
+		months, err := postDB.Archive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []*ArchiveMonth{
+			{Year: 2024, Month: 12, Count: 1},
+			{Year: 2024, Month: 3, Count: 2},
+			{Year: 2024, Month: 1, Count: 2},
+		}, months)
+	})
+}
+
+func TestPostDB_Related(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &Post{})
+	assert.NoError(t, err)
+
+	// insert a user to db
+	user := &User{
+		ExternalID: uuid.New().String(),
+		Login:      uuid.New().String(),
+		AuthMethod: GitHubAuthMethod,
+	}
+
+	err = conn.WithContext(context.Background()).Create(user).Error
+	assert.NoError(t, err)
+
+	postDB := NewPostRepository(conn)
+
+	posts := make(map[string]*Post)
+	for _, p := range []*Post{
+		{Slug: "source", Keywords: "go,postgres", Title: "Full-text search in Postgres", Description: "Ranking documents"},
+		{Slug: "keywords", Keywords: "postgres,go", Title: "Cooking pasta", Description: "The recipe"},
+		{Slug: "keyword-and-text", Keywords: "go", Title: "Search engines", Description: "How search works"},
+		{Slug: "text", Keywords: "rust", Title: "Postgres tricks", Description: "Indexes for the search"},
+		{Slug: "unrelated", Keywords: "garden", Title: "Growing tomatoes", Description: "In the garden"},
+	} {
+		p.UserID = user.ID
+		p.Content = "Test Content"
+		err := postDB.Create(context.Background(), p)
+		assert.NoError(t, err)
+		posts[p.Slug] = p
+	}
+
+	slugs := func(posts []*Post) []string {
+		s := make([]string, 0, len(posts))
+		for _, p := range posts {
+			s = append(s, p.Slug)
+		}
+		return s
+	}
+
+	t.Run("rank by shared keywords, then by text", func(t *testing.T) {
+		related, err := postDB.Related(context.Background(), "source", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"keywords", "keyword-and-text", "text"}, slugs(related))
+		assert.Equal(t, posts["keywords"].ID, related[0].ID)
+		assert.Empty(t, related[0].Content)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		related, err := postDB.Related(context.Background(), "source", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"keywords", "keyword-and-text"}, slugs(related))
+	})
+
+	t.Run("nothing in common", func(t *testing.T) {
+		related, err := postDB.Related(context.Background(), "unrelated", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, related)
+	})
+
+	t.Run("unknown slug", func(t *testing.T) {
+		related, err := postDB.Related(context.Background(), "unknown", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, related)
+	})
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// RateLimitRepository is the database for the token buckets of the rate limits shared by the replicas.
+type RateLimitRepository struct {
+	conn *gorm.DB
+}
+
+// NewRateLimitRepository creates a new RateLimitRepository.
+func NewRateLimitRepository(conn *gorm.DB) *RateLimitRepository {
+	return &RateLimitRepository{
+		conn: conn,
+	}
+}
+
+// RateLimitBucket is the model for the token bucket of the rate limit, e.g. of the IP address.
+type RateLimitBucket struct {
+	Key        string    `json:"key" gorm:"primaryKey"`
+	Tokens     float64   `json:"tokens" gorm:"not null;type:double precision"`
+	RefilledAt time.Time `json:"refilled_at" gorm:"not null"`      // RefilledAt is zero for the new bucket
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null;index"` // ExpiresAt is when the bucket is full again
+}
+
+// RateLimitRepositoryInterface is the interface for the RateLimitRepository.
+type RateLimitRepositoryInterface interface {
+	Update(ctx context.Context, key string, update func(bucket *RateLimitBucket)) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Update creates the RateLimitBucket of the key, if it doesn't exist, and saves it after the update.
+// The bucket is locked until it's saved, so the concurrent updates of the same key are applied one by one.
+func (db *RateLimitRepository) Update(ctx context.Context, key string, update func(bucket *RateLimitBucket)) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		bucket := RateLimitBucket{Key: key}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bucket).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).Take(&bucket).Error; err != nil {
+			return err
+		}
+
+		update(&bucket)
+
+		return tx.Save(&bucket).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateRateLimit, mapGormError(err))
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the buckets that are full again at the now and returns the number of the deleted ones.
+func (db *RateLimitRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := db.conn.WithContext(ctx).Where("expires_at <= ?", now).Delete(&RateLimitBucket{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("%w: %w", ErrDeleteRateLimits, mapGormError(res.Error))
+	}
+
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"github.com/google/uuid"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitsDB(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&RateLimitBucket{})
+	assert.NoError(t, err)
+
+	rateLimitDB := NewRateLimitRepository(conn)
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("should create a new bucket", func(t *testing.T) {
+			key := uuid.NewString()
+			now := time.Now()
+
+			err := rateLimitDB.Update(context.Background(), key, func(bucket *RateLimitBucket) {
+				assert.Equal(t, key, bucket.Key)
+				assert.True(t, bucket.RefilledAt.IsZero())
+
+				bucket.Tokens = 2
+				bucket.RefilledAt = now
+				bucket.ExpiresAt = now.Add(time.Minute)
+			})
+			assert.NoError(t, err)
+
+			err = rateLimitDB.Update(context.Background(), key, func(bucket *RateLimitBucket) {
+				assert.InDelta(t, 2, bucket.Tokens, 0)
+				assert.WithinDuration(t, now, bucket.RefilledAt, time.Millisecond)
+			})
+			assert.NoError(t, err)
+		})
+
+		t.Run("should apply concurrent updates one by one", func(t *testing.T) {
+			key := uuid.NewString()
+
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := rateLimitDB.Update(context.Background(), key, func(bucket *RateLimitBucket) {
+						bucket.Tokens++
+					})
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			err := rateLimitDB.Update(context.Background(), key, func(bucket *RateLimitBucket) {
+				assert.InDelta(t, 10, bucket.Tokens, 0)
+			})
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		now := time.Now()
+		expired, active := uuid.NewString(), uuid.NewString()
+		for key, expiresAt := range map[string]time.Time{expired: now.Add(-time.Second), active: now.Add(time.Hour)} {
+			err := rateLimitDB.Update(context.Background(), key, func(bucket *RateLimitBucket) {
+				bucket.RefilledAt = now
+				bucket.ExpiresAt = expiresAt
+			})
+			assert.NoError(t, err)
+		}
+
+		deleted, err := rateLimitDB.DeleteExpired(context.Background(), now)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		var keys []string
+		err = conn.Model(&RateLimitBucket{}).Where("key IN ?", []string{expired, active}).Pluck("key", &keys).Error
+		assert.NoError(t, err)
+		assert.Equal(t, []string{active}, keys)
+	})
+}
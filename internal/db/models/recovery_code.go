@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RecoveryCodeRepository is the database for the one-time recovery codes,
+// used to sign in when the user lost access to the second factor.
+type RecoveryCodeRepository struct {
+	conn *gorm.DB
+}
+
+// NewRecoveryCodeRepository creates a new RecoveryCodeRepository.
+func NewRecoveryCodeRepository(conn *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		conn: conn,
+	}
+}
+
+// RecoveryCode is the model for the recovery code. Only the hash of the code is stored.
+type RecoveryCode struct {
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int        `json:"user_id" gorm:"not null;uniqueIndex:idx_recovery_codes_user_hash"`
+	User      User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CodeHash  string     `json:"-" gorm:"not null;uniqueIndex:idx_recovery_codes_user_hash"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (c *RecoveryCode) BeforeCreate(_ *gorm.DB) error {
+	if c.UserID == 0 || c.CodeHash == "" {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, ErrRecoveryCodeRequired)
+	}
+
+	c.CreatedAt = time.Now()
+	return nil
+}
+
+// RecoveryCodeRepositoryInterface is the interface for the RecoveryCodeRepository.
+type RecoveryCodeRepositoryInterface interface {
+	Replace(ctx context.Context, userID int, codeHashes []string) error
+	Use(ctx context.Context, userID int, codeHash string) error
+	CountUnused(ctx context.Context, userID int) (int64, error)
+}
+
+// Replace deletes all the recovery codes of the user and inserts the new ones.
+func (db *RecoveryCodeRepository) Replace(ctx context.Context, userID int, codeHashes []string) error {
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]*RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, &RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w. user_id=%v", ErrReplaceRecoveryCodes, mapGormError(err), userID)
+	}
+
+	return nil
+}
+
+// Use marks the unused recovery code of the user as used.
+// It returns ErrNotFound if the code doesn't exist or was already used.
+func (db *RecoveryCodeRepository) Use(ctx context.Context, userID int, codeHash string) error {
+	res := db.conn.WithContext(ctx).
+		Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return fmt.Errorf("%w: %w", ErrUseRecoveryCode, mapGormError(res.Error))
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: %w", ErrNotFound, gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// CountUnused returns the number of recovery codes left for the user.
+func (db *RecoveryCodeRepository) CountUnused(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	err := db.conn.WithContext(ctx).
+		Model(&RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrGetRecoveryCodes, mapGormError(err))
+	}
+
+	return count, nil
+}
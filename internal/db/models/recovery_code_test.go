@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecoveryCodesDB(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &RecoveryCode{})
+	assert.NoError(t, err)
+
+	recoveryDB := NewRecoveryCodeRepository(conn)
+
+	user, err := testCreateUser(context.Background(), conn)
+	assert.NoError(t, err)
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Run("should replace old codes", func(t *testing.T) {
+			err := recoveryDB.Replace(context.Background(), user.ID, []string{"hash1", "hash2"})
+			assert.NoError(t, err)
+
+			err = recoveryDB.Replace(context.Background(), user.ID, []string{"hash3", "hash4", "hash5"})
+			assert.NoError(t, err)
+
+			count, err := recoveryDB.CountUnused(context.Background(), user.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), count)
+
+			err = recoveryDB.Use(context.Background(), user.ID, "hash1")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("return error if hash is empty", func(t *testing.T) {
+			err := recoveryDB.Replace(context.Background(), user.ID, []string{""})
+			assert.ErrorIs(t, err, ErrReplaceRecoveryCodes)
+			assert.ErrorIs(t, err, ErrRecoveryCodeRequired)
+		})
+	})
+
+	t.Run("Use", func(t *testing.T) {
+		t.Run("should use the code only once", func(t *testing.T) {
+			err := recoveryDB.Replace(context.Background(), user.ID, []string{"used1", "used2"})
+			assert.NoError(t, err)
+
+			err = recoveryDB.Use(context.Background(), user.ID, "used1")
+			assert.NoError(t, err)
+
+			err = recoveryDB.Use(context.Background(), user.ID, "used1")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			count, err := recoveryDB.CountUnused(context.Background(), user.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+		})
+
+		t.Run("should not use code of another user", func(t *testing.T) {
+			anotherUser, err := testCreateUser(context.Background(), conn)
+			assert.NoError(t, err)
+
+			err = recoveryDB.Replace(context.Background(), user.ID, []string{"own"})
+			assert.NoError(t, err)
+
+			err = recoveryDB.Use(context.Background(), anotherUser.ID, "own")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,25 @@ type AuthMethod string
 
 const (
 	GitHubAuthMethod AuthMethod = "github"
+	OIDCAuthMethod   AuthMethod = "oidc"  // OIDCAuthMethod external ID is "<provider>:<subject>"
+	EmailAuthMethod  AuthMethod = "email" // EmailAuthMethod external ID is the lowercase email, used for the magic link
+)
+
+// UserRole is the access level of the user.
+type UserRole string
+
+const (
+	AdminUserRole  UserRole = "admin"  // AdminUserRole can manage users and posts
+	AuthorUserRole UserRole = "author" // AuthorUserRole can manage posts only
+)
+
+// UserStatus is the state of the user account.
+type UserStatus string
+
+const (
+	InvitedUserStatus  UserStatus = "invited" // InvitedUserStatus is set until the first sign in of the user
+	ActiveUserStatus   UserStatus = "active"
+	DisabledUserStatus UserStatus = "disabled" // DisabledUserStatus users are not allowed to sign in
 )
 
 // UserRepository is the database for the user data.
@@ -33,7 +54,9 @@ type User struct {
 	ExternalID string     `json:"external_id" gorm:"uniqueIndex"` // ExternalID is the ID of the user in the AuthMethod
 	Login      string     `json:"login"`
 	AuthMethod AuthMethod `json:"auth_method"` // AuthMethod is the method of authentication used by the user
-	Posts      []Post     `json:"posts" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Role       UserRole   `json:"role" gorm:"not null;default:author"`
+	Status     UserStatus `json:"status" gorm:"not null;default:active"`
+	Posts      []Post     `json:"posts" gorm:"constraint:OnUpdate:CASCADE;"` // Users with posts can't be deleted
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 }
@@ -48,10 +71,59 @@ func (u *User) Validate() error {
 	if u.AuthMethod == "" {
 		return ErrUserAuthMethodRequired
 	}
+	if u.Role != "" && u.Role != AdminUserRole && u.Role != AuthorUserRole {
+		return ErrUserInvalidRole
+	}
+	if u.Status != "" && u.Status != InvitedUserStatus && u.Status != ActiveUserStatus && u.Status != DisabledUserStatus {
+		return ErrUserInvalidStatus
+	}
 	return nil
 }
 
+// NewInvitedUser creates the User invited by an admin, who is activated on the first sign in.
+func NewInvitedUser(authMethod AuthMethod, externalID, login string, role UserRole) (*User, error) {
+	// Note: magic link is sent to the external ID, so it must be a valid email in the same case as entered on login
+	if authMethod == EmailAuthMethod {
+		externalID = strings.ToLower(strings.TrimSpace(externalID))
+		if !IsValidEmail(externalID) {
+			return nil, ErrUserInvalidEmail
+		}
+	}
+
+	// Note: login is updated on the first sign in, so external ID is good enough as a placeholder
+	if login == "" {
+		login = externalID
+	}
+
+	return &User{
+		ExternalID: externalID,
+		AuthMethod: authMethod,
+		Login:      login,
+		Role:       role,
+		Status:     InvitedUserStatus,
+	}, nil
+}
+
+// IsValidEmail reports whether the email is valid. The email is expected to be in lowercase.
+func IsValidEmail(email string) bool {
+	return emailRegexp.MatchString(email)
+}
+
+var emailRegexp = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,24}$`) //nolint:gochecknoglobals // compiled once
+
+// IsAdmin reports whether the user is an active admin.
+func (u *User) IsAdmin() bool {
+	return u.Role == AdminUserRole && u.Status == ActiveUserStatus
+}
+
 func (u *User) BeforeCreate(_ *gorm.DB) error {
+	if u.Role == "" {
+		u.Role = AuthorUserRole
+	}
+	if u.Status == "" {
+		u.Status = ActiveUserStatus
+	}
+
 	err := u.Validate()
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
@@ -64,11 +136,24 @@ func (u *User) BeforeCreate(_ *gorm.DB) error {
 	return nil
 }
 
+func (u *User) BeforeUpdate(_ *gorm.DB) error {
+	err := u.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
 // UserRepositoryInterface is the interface for the UserRepository.
 type UserRepositoryInterface interface {
 	Upsert(ctx context.Context, user *User) error
+	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error
 	GetByExternalID(ctx context.Context, externalID string) (*User, error)
 	GetByID(ctx context.Context, id int) (*User, error)
+	FindAll(ctx context.Context) ([]*User, error)
 }
 
 // Upsert inserts or updates the User data.
@@ -87,6 +172,26 @@ func (db *UserRepository) Upsert(ctx context.Context, user *User) error {
 	return nil
 }
 
+// Create inserts a new User, e.g. when the user is invited by an admin.
+func (db *UserRepository) Create(ctx context.Context, user *User) error {
+	err := db.conn.WithContext(ctx).Create(user).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCreateUser, mapGormError(err))
+	}
+
+	return nil
+}
+
+// Update updates the User data.
+func (db *UserRepository) Update(ctx context.Context, user *User) error {
+	err := db.conn.WithContext(ctx).Save(user).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w. id=%v", ErrFailedToUpdateUser, mapGormError(err), user.ID)
+	}
+
+	return nil
+}
+
 // GetByExternalID returns the User data by the User.ExternalID.
 func (db *UserRepository) GetByExternalID(ctx context.Context, externalID string) (*User, error) {
 	var user User
@@ -108,3 +213,14 @@ func (db *UserRepository) GetByID(ctx context.Context, id int) (*User, error) {
 
 	return &user, nil
 }
+
+// FindAll returns all the users sorted by the ID.
+func (db *UserRepository) FindAll(ctx context.Context) ([]*User, error) {
+	var users []*User
+	err := db.conn.WithContext(ctx).Order("id asc").Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetUser, mapGormError(err))
+	}
+
+	return users, nil
+}
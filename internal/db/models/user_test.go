@@ -84,6 +84,99 @@ func TestUserDB(t *testing.T) {
 		})
 	})
 
+	t.Run("Create", func(t *testing.T) {
+		t.Run("should create invited user", func(t *testing.T) {
+			user := &User{
+				ExternalID: uuid.New().String(),
+				Login:      uuid.New().String(),
+				AuthMethod: GitHubAuthMethod,
+				Status:     InvitedUserStatus,
+			}
+
+			err := userDB.Create(context.Background(), user)
+			assert.NoError(t, err)
+
+			var u User
+			err = userDB.conn.First(&u, user.ID).Error
+			assert.NoError(t, err)
+			assert.Equal(t, InvitedUserStatus, u.Status)
+			assert.Equal(t, AuthorUserRole, u.Role)
+		})
+
+		t.Run("should return ErrDuplicate if external id is taken", func(t *testing.T) {
+			ctx := context.Background()
+			u, err := testCreateUser(ctx, userDB.conn)
+			assert.NoError(t, err)
+
+			err = userDB.Create(ctx, &User{
+				ExternalID: u.ExternalID,
+				Login:      uuid.New().String(),
+				AuthMethod: GitHubAuthMethod,
+			})
+			assert.Error(t, err)
+			assert.ErrorIs(t, err, ErrDuplicate)
+		})
+
+		t.Run("should return error if role is invalid", func(t *testing.T) {
+			err := userDB.Create(context.Background(), &User{
+				ExternalID: uuid.New().String(),
+				Login:      uuid.New().String(),
+				AuthMethod: GitHubAuthMethod,
+				Role:       "superuser",
+			})
+			assert.Error(t, err)
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrUserInvalidRole)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Run("should update role and status", func(t *testing.T) {
+			ctx := context.Background()
+			u, err := testCreateUser(ctx, userDB.conn)
+			assert.NoError(t, err)
+			assert.Equal(t, ActiveUserStatus, u.Status)
+
+			u.Role = AdminUserRole
+			u.Status = DisabledUserStatus
+			err = userDB.Update(ctx, &u)
+			assert.NoError(t, err)
+
+			user, err := userDB.GetByID(ctx, u.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, AdminUserRole, user.Role)
+			assert.Equal(t, DisabledUserStatus, user.Status)
+		})
+
+		t.Run("should return error if status is invalid", func(t *testing.T) {
+			ctx := context.Background()
+			u, err := testCreateUser(ctx, userDB.conn)
+			assert.NoError(t, err)
+
+			u.Status = "deleted"
+			err = userDB.Update(ctx, &u)
+			assert.Error(t, err)
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrUserInvalidStatus)
+		})
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		t.Run("should return all users sorted by id", func(t *testing.T) {
+			ctx := context.Background()
+			u, err := testCreateUser(ctx, userDB.conn)
+			assert.NoError(t, err)
+
+			users, err := userDB.FindAll(ctx)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, users)
+			assert.Equal(t, u.ID, users[len(users)-1].ID)
+			for i := 0; i < len(users)-1; i++ { //nolint:intrange
+				assert.Less(t, users[i].ID, users[i+1].ID)
+			}
+		})
+	})
+
 	t.Run("GetByExternalID", func(t *testing.T) {
 		t.Run("should get user by external id", func(t *testing.T) {
 			ctx := context.Background()
@@ -160,6 +253,30 @@ func TestUser_Validate(t *testing.T) {
 		assert.ErrorIs(t, err, ErrUserAuthMethodRequired)
 	})
 
+	t.Run("should return ErrUserInvalidRole if role is unknown", func(t *testing.T) {
+		user := User{
+			Login:      uuid.New().String(),
+			ExternalID: uuid.New().String(),
+			AuthMethod: GitHubAuthMethod,
+			Role:       "superuser",
+		}
+		err := user.Validate()
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrUserInvalidRole)
+	})
+
+	t.Run("should return ErrUserInvalidStatus if status is unknown", func(t *testing.T) {
+		user := User{
+			Login:      uuid.New().String(),
+			ExternalID: uuid.New().String(),
+			AuthMethod: GitHubAuthMethod,
+			Status:     "deleted",
+		}
+		err := user.Validate()
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrUserInvalidStatus)
+	})
+
 	t.Run("should return nil if user is valid", func(t *testing.T) {
 		user := User{
 			Login:      uuid.New().String(),
@@ -170,3 +287,49 @@ func TestUser_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestUser_IsAdmin(t *testing.T) {
+	t.Run("should return true for active admin", func(t *testing.T) {
+		user := User{Role: AdminUserRole, Status: ActiveUserStatus}
+		assert.True(t, user.IsAdmin())
+	})
+
+	t.Run("should return false for disabled admin", func(t *testing.T) {
+		user := User{Role: AdminUserRole, Status: DisabledUserStatus}
+		assert.False(t, user.IsAdmin())
+	})
+
+	t.Run("should return false for author", func(t *testing.T) {
+		user := User{Role: AuthorUserRole, Status: ActiveUserStatus}
+		assert.False(t, user.IsAdmin())
+	})
+}
+
+func TestNewInvitedUser(t *testing.T) {
+	t.Run("should create invited user with external ID as login", func(t *testing.T) {
+		user, err := NewInvitedUser(GitHubAuthMethod, "12345", "", AdminUserRole)
+		assert.NoError(t, err)
+		assert.Equal(t, "12345", user.ExternalID)
+		assert.Equal(t, "12345", user.Login)
+		assert.Equal(t, AdminUserRole, user.Role)
+		assert.Equal(t, InvitedUserStatus, user.Status)
+		assert.NoError(t, user.Validate())
+	})
+
+	t.Run("should keep the given login", func(t *testing.T) {
+		user, err := NewInvitedUser(GitHubAuthMethod, "12345", "octocat", AuthorUserRole)
+		assert.NoError(t, err)
+		assert.Equal(t, "octocat", user.Login)
+	})
+
+	t.Run("should normalize email external ID", func(t *testing.T) {
+		user, err := NewInvitedUser(EmailAuthMethod, " Admin@Example.com ", "", AdminUserRole)
+		assert.NoError(t, err)
+		assert.Equal(t, "admin@example.com", user.ExternalID)
+	})
+
+	t.Run("should return error for invalid email", func(t *testing.T) {
+		_, err := NewInvitedUser(EmailAuthMethod, "not-an-email", "", AdminUserRole)
+		assert.ErrorIs(t, err, ErrUserInvalidEmail)
+	})
+}
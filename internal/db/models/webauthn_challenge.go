@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// WebAuthnChallengeRepository is the database for the challenges of the finished WebAuthn ceremonies.
+type WebAuthnChallengeRepository struct {
+	conn *gorm.DB
+}
+
+// NewWebAuthnChallengeRepository creates a new WebAuthnChallengeRepository.
+func NewWebAuthnChallengeRepository(conn *gorm.DB) *WebAuthnChallengeRepository {
+	return &WebAuthnChallengeRepository{
+		conn: conn,
+	}
+}
+
+// WebAuthnChallenge is the model for the used challenge, that is kept until the ceremony expires,
+// so the same authenticator response can't be used twice.
+type WebAuthnChallenge struct {
+	Challenge string    `json:"challenge" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// WebAuthnChallengeRepositoryInterface is the interface for the WebAuthnChallengeRepository.
+type WebAuthnChallengeRepositoryInterface interface {
+	Use(ctx context.Context, challenge string, expiresAt time.Time) error
+}
+
+// Use marks the challenge as used until the expiresAt.
+// It returns ErrDuplicate if the challenge was already used, even with concurrent requests.
+//
+// Note: the expired challenges are deleted on use, as they are rejected by the expiration anyway.
+func (db *WebAuthnChallengeRepository) Use(ctx context.Context, challenge string, expiresAt time.Time) error {
+	err := db.conn.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&WebAuthnChallenge{}).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUseWebAuthnChallenge, mapGormError(err))
+	}
+
+	res := db.conn.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&WebAuthnChallenge{Challenge: challenge, ExpiresAt: expiresAt})
+	if res.Error != nil {
+		return fmt.Errorf("%w: %w", ErrUseWebAuthnChallenge, mapGormError(res.Error))
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: challenge was already used", ErrDuplicate)
+	}
+
+	return nil
+}
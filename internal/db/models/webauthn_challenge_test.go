@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"github.com/google/uuid"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebAuthnChallengesDB(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&WebAuthnChallenge{})
+	assert.NoError(t, err)
+
+	challengeDB := NewWebAuthnChallengeRepository(conn)
+
+	t.Run("challenge can be used once", func(t *testing.T) {
+		challenge := uuid.New().String()
+
+		err := challengeDB.Use(context.Background(), challenge, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+
+		err = challengeDB.Use(context.Background(), challenge, time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrDuplicate)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		challenge := uuid.New().String()
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = challengeDB.Use(context.Background(), challenge, time.Now().Add(time.Minute))
+			}()
+		}
+		wg.Wait()
+
+		used := 0
+		for _, err := range errs {
+			if err == nil {
+				used++
+			} else {
+				assert.ErrorIs(t, err, ErrDuplicate)
+			}
+		}
+		assert.Equal(t, 1, used)
+	})
+
+	t.Run("expired challenges are deleted", func(t *testing.T) {
+		expired := uuid.New().String()
+		err := challengeDB.Use(context.Background(), expired, time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+
+		err = challengeDB.Use(context.Background(), uuid.New().String(), time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+
+		var count int64
+		err = conn.Model(&WebAuthnChallenge{}).Where("challenge = ?", expired).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
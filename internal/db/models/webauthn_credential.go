@@ -0,0 +1,111 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// WebAuthnCredentialRepository is the database for the WebAuthn credentials (passkeys) of the users.
+type WebAuthnCredentialRepository struct {
+	conn *gorm.DB
+}
+
+// NewWebAuthnCredentialRepository creates a new WebAuthnCredentialRepository.
+func NewWebAuthnCredentialRepository(conn *gorm.DB) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		conn: conn,
+	}
+}
+
+// WebAuthnCredential is the model for the public key credential registered by the user authenticator.
+type WebAuthnCredential struct {
+	ID              int        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID          int        `json:"user_id" gorm:"not null;index"`
+	User            User       `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Name            string     `json:"name"`                                      // Name is set by the user to tell the passkeys apart
+	CredentialID    []byte     `json:"credential_id" gorm:"not null;uniqueIndex"` // CredentialID is the ID generated by the authenticator
+	PublicKey       []byte     `json:"public_key" gorm:"not null"`                // PublicKey is COSE encoded public key
+	AttestationType string     `json:"attestation_type"`
+	Transports      string     `json:"transports"` // Transports supported by the authenticator, separated by comma
+	AAGUID          []byte     `json:"aaguid"`
+	SignCount       uint32     `json:"sign_count"` // SignCount is used to detect cloned authenticators
+	BackupEligible  bool       `json:"backup_eligible"`
+	BackupState     bool       `json:"backup_state"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func (c *WebAuthnCredential) Validate() error {
+	if c.UserID == 0 {
+		return ErrWebAuthnCredentialUserIDRequired
+	}
+	if len(c.CredentialID) == 0 || len(c.PublicKey) == 0 {
+		return ErrWebAuthnCredentialKeyRequired
+	}
+	return nil
+}
+
+func (c *WebAuthnCredential) BeforeCreate(_ *gorm.DB) error {
+	err := c.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	}
+
+	c.CreatedAt = time.Now()
+	return nil
+}
+
+// WebAuthnCredentialRepositoryInterface is the interface for the WebAuthnCredentialRepository.
+type WebAuthnCredentialRepositoryInterface interface {
+	Create(ctx context.Context, credential *WebAuthnCredential) error
+	Update(ctx context.Context, credential *WebAuthnCredential) error
+	FindByUserID(ctx context.Context, userID int) ([]*WebAuthnCredential, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+// Create inserts a new WebAuthnCredential.
+func (db *WebAuthnCredentialRepository) Create(ctx context.Context, credential *WebAuthnCredential) error {
+	err := db.conn.WithContext(ctx).Create(credential).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCreateWebAuthnCredential, mapGormError(err))
+	}
+
+	return nil
+}
+
+// Update updates the WebAuthnCredential data, e.g. the sign counter after the assertion.
+func (db *WebAuthnCredentialRepository) Update(ctx context.Context, credential *WebAuthnCredential) error {
+	err := db.conn.WithContext(ctx).Save(credential).Error
+	if err != nil {
+		return fmt.Errorf("%w: %w. id=%v", ErrUpdateWebAuthnCredential, mapGormError(err), credential.ID)
+	}
+
+	return nil
+}
+
+// FindByUserID returns all the credentials of the user sorted by the ID.
+func (db *WebAuthnCredentialRepository) FindByUserID(ctx context.Context, userID int) ([]*WebAuthnCredential, error) {
+	var credentials []*WebAuthnCredential
+	err := db.conn.WithContext(ctx).Where("user_id = ?", userID).Order("id asc").Find(&credentials).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w. user_id=%v", ErrGetWebAuthnCredentials, mapGormError(err), userID)
+	}
+
+	return credentials, nil
+}
+
+// Delete deletes the credential of the user by its ID.
+func (db *WebAuthnCredentialRepository) Delete(ctx context.Context, userID, id int) error {
+	res := db.conn.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&WebAuthnCredential{})
+	if res.Error != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteWebAuthnCredential, mapGormError(res.Error))
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("%w: %w", ErrNotFound, gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
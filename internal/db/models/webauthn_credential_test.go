@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"github.com/google/uuid"
+	testdb "github.com/samgozman/go-bloggy/testutils/test-db"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWebAuthnCredentialsDB(t *testing.T) {
+	conn, err := testdb.InitDatabaseTest()
+	assert.NoError(t, err)
+	err = conn.AutoMigrate(&User{}, &WebAuthnCredential{})
+	assert.NoError(t, err)
+
+	credentialDB := NewWebAuthnCredentialRepository(conn)
+
+	user, err := testCreateUser(context.Background(), conn)
+	assert.NoError(t, err)
+
+	newCredential := func() *WebAuthnCredential {
+		return &WebAuthnCredential{
+			UserID:       user.ID,
+			Name:         "YubiKey",
+			CredentialID: []byte(uuid.New().String()),
+			PublicKey:    []byte("public-key"),
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("create a new credential", func(t *testing.T) {
+			credential := newCredential()
+
+			err := credentialDB.Create(context.Background(), credential)
+			assert.NoError(t, err)
+			assert.NotZero(t, credential.ID)
+			assert.NotZero(t, credential.CreatedAt)
+		})
+
+		t.Run("return error if credential ID is duplicated", func(t *testing.T) {
+			credential := newCredential()
+			err := credentialDB.Create(context.Background(), credential)
+			assert.NoError(t, err)
+
+			duplicate := newCredential()
+			duplicate.CredentialID = credential.CredentialID
+			err = credentialDB.Create(context.Background(), duplicate)
+			assert.ErrorIs(t, err, ErrDuplicate)
+		})
+
+		t.Run("return error if user is not set", func(t *testing.T) {
+			credential := newCredential()
+			credential.UserID = 0
+
+			err := credentialDB.Create(context.Background(), credential)
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrWebAuthnCredentialUserIDRequired)
+		})
+
+		t.Run("return error if public key is not set", func(t *testing.T) {
+			credential := newCredential()
+			credential.PublicKey = nil
+
+			err := credentialDB.Create(context.Background(), credential)
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			assert.ErrorIs(t, err, ErrWebAuthnCredentialKeyRequired)
+		})
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		credential := newCredential()
+		err := credentialDB.Create(context.Background(), credential)
+		assert.NoError(t, err)
+
+		now := time.Now()
+		credential.SignCount = 5
+		credential.LastUsedAt = &now
+		err = credentialDB.Update(context.Background(), credential)
+		assert.NoError(t, err)
+
+		credentials, err := credentialDB.FindByUserID(context.Background(), user.ID)
+		assert.NoError(t, err)
+		for _, c := range credentials {
+			if c.ID == credential.ID {
+				assert.Equal(t, uint32(5), c.SignCount)
+				assert.NotNil(t, c.LastUsedAt)
+			}
+		}
+	})
+
+	t.Run("FindByUserID", func(t *testing.T) {
+		t.Run("should return only credentials of the user", func(t *testing.T) {
+			anotherUser, err := testCreateUser(context.Background(), conn)
+			assert.NoError(t, err)
+
+			credential := newCredential()
+			credential.UserID = anotherUser.ID
+			err = credentialDB.Create(context.Background(), credential)
+			assert.NoError(t, err)
+
+			credentials, err := credentialDB.FindByUserID(context.Background(), anotherUser.ID)
+			assert.NoError(t, err)
+			assert.Len(t, credentials, 1)
+			assert.Equal(t, credential.ID, credentials[0].ID)
+		})
+
+		t.Run("should return empty list if user has no credentials", func(t *testing.T) {
+			credentials, err := credentialDB.FindByUserID(context.Background(), 0)
+			assert.NoError(t, err)
+			assert.Empty(t, credentials)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Run("should delete the credential", func(t *testing.T) {
+			credential := newCredential()
+			err := credentialDB.Create(context.Background(), credential)
+			assert.NoError(t, err)
+
+			err = credentialDB.Delete(context.Background(), user.ID, credential.ID)
+			assert.NoError(t, err)
+
+			err = credentialDB.Delete(context.Background(), user.ID, credential.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+
+		t.Run("should not delete credential of another user", func(t *testing.T) {
+			credential := newCredential()
+			err := credentialDB.Create(context.Background(), credential)
+			assert.NoError(t, err)
+
+			err = credentialDB.Delete(context.Background(), user.ID+1000, credential.ID)
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	})
+}
@@ -1,11 +1,17 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"github.com/google/wire"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/health"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 // ProvideDSN provides the DSN from the config.
@@ -13,40 +19,133 @@ func ProvideDSN(cfg *config.Config) config.DSN {
 	return cfg.DSN
 }
 
-// ProvideConnection provides a new database connection.
-func ProvideConnection(dsn config.DSN) (*gorm.DB, error) {
+// ProvideAutoMigrate provides the flag to apply pending migrations on startup from the config.
+func ProvideAutoMigrate(cfg *config.Config) config.DBAutoMigrate {
+	return cfg.DBAutoMigrate
+}
+
+// ProvideConnection provides a new database connection, that is closed on shutdown.
+// It refuses to connect to the database with pending or dirty migrations, unless autoMigrate is set.
+func ProvideConnection(
+	ctx context.Context,
+	dsn config.DSN,
+	autoMigrate config.DBAutoMigrate,
+	lc *lifecycle.Manager,
+) (*gorm.DB, error) {
+	conn, err := Connect(dsn)
+	if err != nil {
+		return nil, err
+	}
+	closeOnStop(lc, conn)
+
+	if err := migrate(ctx, conn, bool(autoMigrate)); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// Connect connects to the database without checking the schema, e.g. to run the migrations.
+func Connect(dsn config.DSN) (*gorm.DB, error) {
 	conn, err := connectToPG(string(dsn))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrFailedToConnectDatabase, err)
 	}
 
-	// Migrate the schema
-	// TODO: add external migrator, do not use AutoMigrate in production
-	err = conn.AutoMigrate(&models.User{}, &models.Post{}, &models.Subscriber{})
+	return conn, nil
+}
+
+// ProvideMigrator provides the Migrator with a new database connection, that is closed on shutdown.
+func ProvideMigrator(dsn config.DSN, lc *lifecycle.Manager) (*Migrator, error) {
+	conn, err := Connect(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrFailedToMigrateDatabase, err)
+		return nil, err
 	}
+	closeOnStop(lc, conn)
 
-	return conn, nil
+	return NewMigrator(conn)
+}
+
+// closeOnStop closes the connection pool on shutdown, after the requests and workers using it are stopped.
+func closeOnStop(lc *lifecycle.Manager, conn *gorm.DB) {
+	lc.OnStop(lifecycle.StageClose, "database", func(_ context.Context) error {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return fmt.Errorf("get database: %w", err)
+		}
+
+		return sqlDB.Close()
+	})
 }
 
-// ProvideModels provides the models.
-func ProvideModels(conn *gorm.DB) *Models {
+// ProvideModels provides the models with the posts, that can be cached.
+// The created posts are counted in the metrics, whether they are published with the API or imported.
+func ProvideModels(conn *gorm.DB, posts models.PostRepositoryInterface, m *metrics.Metrics) *Models {
 	return NewModels(
 		models.NewUserRepository(conn),
-		models.NewPostRepository(conn),
+		&publishedPosts{PostRepositoryInterface: posts, metrics: m},
 		models.NewSubscribersRepository(conn),
+		models.NewMagicLinkRepository(conn),
+		models.NewWebAuthnCredentialRepository(conn),
+		models.NewRecoveryCodeRepository(conn),
+		models.NewRateLimitRepository(conn),
+		models.NewWebAuthnChallengeRepository(conn),
 	)
 }
 
-// ProvideDatabase provides a new database connection.
-func ProvideDatabase(conn *gorm.DB, models ModelsInterface) (*Database, error) {
+// publishedPosts is the models.PostRepositoryInterface, that counts the created posts.
+type publishedPosts struct {
+	models.PostRepositoryInterface
+	metrics *metrics.Metrics
+}
+
+// Create creates a new Post and counts it as published.
+func (p *publishedPosts) Create(ctx context.Context, post *models.Post) error {
+	if err := p.PostRepositoryInterface.Create(ctx, post); err != nil {
+		return err
+	}
+	p.metrics.PostPublished()
+
+	return nil
+}
+
+// ProvideDatabase provides a new database connection and registers its readiness checks, pool stats metrics
+// and the spans of the queries.
+func ProvideDatabase(
+	conn *gorm.DB,
+	models ModelsInterface,
+	hs *health.Service,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+) (*Database, error) {
+	if err := registerHealthChecks(hs, conn); err != nil {
+		return nil, err
+	}
+
+	// Note: the query variables are not recorded to keep the emails and tokens out of the traces
+	if err := conn.Use(tracing.NewPlugin(
+		tracing.WithTracerProvider(tp),
+		tracing.WithoutQueryVariables(),
+		tracing.WithoutMetrics(),
+	)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToConnectDatabase, err)
+	}
+
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToConnectDatabase, err)
+	}
+	if err := m.RegisterDB(sqlDB); err != nil {
+		return nil, err
+	}
+
 	return NewDatabase(conn, models), nil
 }
 
 // ProviderSet is a wire provider set that provides the database connection.
 var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
 	ProvideDSN,
+	ProvideAutoMigrate,
 	ProvideConnection,
 	ProvideModels,
 	ProvideDatabase,
@@ -1,10 +1,20 @@
 package db
 
 import (
+	"context"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/health"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	modelsMock "github.com/samgozman/go-bloggy/mocks/db/models"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestProvideDSN(t *testing.T) {
@@ -17,25 +27,77 @@ func TestProvideDSN(t *testing.T) {
 	})
 }
 
+func TestProvideAutoMigrate(t *testing.T) {
+	t.Run("ProvideAutoMigrate", func(t *testing.T) {
+		cfg := &config.Config{
+			DBAutoMigrate: true,
+		}
+		got := ProvideAutoMigrate(cfg)
+		assert.Equal(t, cfg.DBAutoMigrate, got)
+	})
+}
+
 func TestProvideModels(t *testing.T) {
 	t.Run("ProvideModels", func(t *testing.T) {
 		conn := &gorm.DB{}
-		got := ProvideModels(conn)
+		posts := models.NewPostRepository(conn)
+		m := metrics.New(&metrics.Config{})
+		got := ProvideModels(conn, posts, m)
 		assert.NotNil(t, got)
 		assert.NotNil(t, got.Users())
-		assert.NotNil(t, got.Posts())
+		assert.Equal(t, &publishedPosts{PostRepositoryInterface: posts, metrics: m}, got.Posts())
 		assert.NotNil(t, got.Subscribers())
+		assert.NotNil(t, got.MagicLinks())
+		assert.NotNil(t, got.WebAuthnCredentials())
+		assert.NotNil(t, got.RecoveryCodes())
+		assert.NotNil(t, got.WebAuthnChallenges())
 	})
 }
 
+func TestPublishedPosts(t *testing.T) {
+	posts := modelsMock.NewMockPostRepositoryInterface(t)
+	posts.On("Create", context.Background(), &models.Post{Slug: "published"}).Return(nil)
+	posts.On("Create", context.Background(), &models.Post{Slug: "duplicate"}).Return(models.ErrDuplicate)
+
+	m := metrics.New(&metrics.Config{})
+	p := &publishedPosts{PostRepositoryInterface: posts, metrics: m}
+
+	assert.NoError(t, p.Create(context.Background(), &models.Post{Slug: "published"}))
+	assert.ErrorIs(t, p.Create(context.Background(), &models.Post{Slug: "duplicate"}), models.ErrDuplicate)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), "bloggy_posts_published_total 1")
+}
+
 func TestProvideDatabase(t *testing.T) {
 	t.Run("ProvideDatabase", func(t *testing.T) {
-		conn := &gorm.DB{}
+		// Note: the connection is not established until the first query
+		conn, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+			DisableAutomaticPing: true,
+		})
+		assert.NoError(t, err)
+
 		models := &Models{}
-		got, err := ProvideDatabase(conn, models)
+		hs := health.NewService(time.Second, time.Second)
+		m := metrics.New(&metrics.Config{})
+		got, err := ProvideDatabase(conn, models, hs, m, noop.NewTracerProvider())
 		assert.Nil(t, err)
 		assert.NotNil(t, got)
 		assert.Equal(t, conn, got.GetConn())
 		assert.Equal(t, models, got.models)
+
+		// Note: there is no database on localhost
+		report := hs.Ready(context.Background())
+		assert.False(t, report.Ready)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, "database", report.Checks[0].Name)
+		assert.Equal(t, "migrations", report.Checks[1].Name)
+
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Contains(t, rec.Body.String(), `go_sql_max_open_connections{db_name="postgres"}`)
+
+		assert.Contains(t, conn.Config.Plugins, "otelgorm")
 	})
 }
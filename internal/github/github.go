@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/samgozman/go-bloggy/internal/logging"
 	"io"
 	"net/http"
 	"net/url"
@@ -17,15 +18,18 @@ type Service struct {
 	clientSecret string
 	oAuthAPIURL  string
 	userAPIURL   string
+	httpClient   *http.Client
 }
 
-// NewService creates a new GitHub Service instance with the given client ID and client secret.
-func NewService(clientID, clientSecret string) *Service {
+// NewService creates a new GitHub Service instance with the given client ID and client secret,
+// that calls the GitHub API with the httpClient.
+func NewService(clientID, clientSecret string, httpClient *http.Client) *Service {
 	return &Service{
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		oAuthAPIURL:  "https://github.com/login/oauth/access_token",
 		userAPIURL:   "https://api.github.com/user",
+		httpClient:   httpClient,
 	}
 }
 
@@ -56,14 +60,14 @@ func (g *Service) ExchangeCodeForToken(ctx context.Context, code string) (string
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req) //nolint:bodyclose
+	resp, err := g.httpClient.Do(req) //nolint:bodyclose
 	if err != nil {
 		return "", fmt.Errorf("error doing request: %w", err)
 	}
 	defer func(b io.ReadCloser) {
 		e := b.Close()
 		if e != nil {
-			fmt.Printf("error closing response body: %v\n", e)
+			logging.FromContext(ctx).Warn("[github] Error closing response body", "error", e)
 		}
 	}(resp.Body)
 
@@ -74,7 +78,6 @@ func (g *Service) ExchangeCodeForToken(ctx context.Context, code string) (string
 
 	values, err := url.ParseQuery(string(body))
 	if err != nil {
-		fmt.Println(err)
 		return "", fmt.Errorf("error parsing response body: %w", err)
 	}
 
@@ -106,14 +109,14 @@ func (g *Service) GetUserInfo(ctx context.Context, token string) (*UserInfo, err
 
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	resp, err := http.DefaultClient.Do(req) //nolint:bodyclose
+	resp, err := g.httpClient.Do(req) //nolint:bodyclose
 	if err != nil {
 		return nil, fmt.Errorf("error doing request: %w", err)
 	}
 	defer func(b io.ReadCloser) {
 		e := b.Close()
 		if e != nil {
-			fmt.Printf("error closing response body: %v\n", e)
+			logging.FromContext(ctx).Warn("[github] Error closing response body", "error", e)
 		}
 	}(resp.Body)
 
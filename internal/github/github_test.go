@@ -10,12 +10,13 @@ import (
 )
 
 func Test_NewGitHub(t *testing.T) {
-	g := NewService("dummyClientID", "dummyClientSecret")
+	g := NewService("dummyClientID", "dummyClientSecret", http.DefaultClient)
 
 	assert.Equal(t, "dummyClientID", g.clientID)
 	assert.Equal(t, "dummyClientSecret", g.clientSecret)
 	assert.Equal(t, "https://github.com/login/oauth/access_token", g.oAuthAPIURL)
 	assert.Equal(t, "https://api.github.com/user", g.userAPIURL)
+	assert.Equal(t, http.DefaultClient, g.httpClient)
 }
 
 func Test_GetUserInfo(t *testing.T) {
@@ -69,6 +70,7 @@ func Test_GetUserInfo(t *testing.T) {
 			clientID:     "dummyClientID",
 			clientSecret: "dummyClientSecret",
 			userAPIURL:   server.URL,
+			httpClient:   http.DefaultClient,
 		}
 
 		// Call GetUserInfo
@@ -132,6 +134,7 @@ func Test_GetUserInfo(t *testing.T) {
 			clientID:     "dummyClientID",
 			clientSecret: "dummyClientSecret",
 			userAPIURL:   server.URL,
+			httpClient:   http.DefaultClient,
 		}
 
 		// Call GetUserInfo
@@ -162,6 +165,7 @@ func Test_ExchangeCodeForToken(t *testing.T) {
 			clientID:     "dummyClientID",
 			clientSecret: "dummyClientSecret",
 			oAuthAPIURL:  server.URL,
+			httpClient:   http.DefaultClient,
 		}
 
 		token, err := g.ExchangeCodeForToken(context.Background(), "dummyCode")
@@ -183,6 +187,7 @@ func Test_ExchangeCodeForToken(t *testing.T) {
 			clientID:     "dummyClientID",
 			clientSecret: "dummyClientSecret",
 			oAuthAPIURL:  server.URL,
+			httpClient:   http.DefaultClient,
 		}
 
 		token, err := g.ExchangeCodeForToken(context.Background(), "dummyCode")
@@ -3,6 +3,8 @@ package github
 import (
 	"github.com/google/wire"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config is a struct that holds the configuration for the GitHub service.
@@ -19,14 +21,18 @@ func ProvideConfig(cfg *config.Config) *Config {
 	}
 }
 
-// ProvideService is a Wire provider function that creates a Service.
-func ProvideService(cfg *Config) *Service {
-	return NewService(cfg.ClientID, cfg.ClientSecret)
+// ProvideService is a Wire provider function that creates a Service, which calls to GitHub are traced.
+// It returns nil if the GitHub sign in is not configured.
+func ProvideService(cfg *Config, tp trace.TracerProvider) ServiceInterface {
+	if cfg.ClientID == "" {
+		return nil
+	}
+
+	return newTracedService(NewService(cfg.ClientID, cfg.ClientSecret, tracing.HTTPClient(tp)), tp)
 }
 
 // ProviderSet is a Wire provider set that includes all the providers from the github package.
 var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
 	ProvideConfig,
 	ProvideService,
-	wire.Bind(new(ServiceInterface), new(*Service)),
 )
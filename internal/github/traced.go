@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/samgozman/go-bloggy/internal/github"
+
+// tracedService records the span for each call of the wrapped service.
+// Note: the code and the token are secrets, so they are not recorded.
+type tracedService struct {
+	service ServiceInterface
+	tracer  trace.Tracer
+}
+
+func newTracedService(service ServiceInterface, tp trace.TracerProvider) *tracedService {
+	return &tracedService{
+		service: service,
+		tracer:  tp.Tracer(tracerName),
+	}
+}
+
+func (s *tracedService) ExchangeCodeForToken(ctx context.Context, code string) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "github.ExchangeCodeForToken", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	token, err := s.service.ExchangeCodeForToken(ctx, code)
+	record(span, err)
+
+	return token, err
+}
+
+func (s *tracedService) GetUserInfo(ctx context.Context, token string) (*UserInfo, error) {
+	ctx, span := s.tracer.Start(ctx, "github.GetUserInfo", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	userInfo, err := s.service.GetUserInfo(ctx, token)
+	record(span, err)
+
+	return userInfo, err
+}
+
+// record marks the span as failed with the err, if any.
+func record(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
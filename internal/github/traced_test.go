@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProvideService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("Traceparent")
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	s := ProvideService(&Config{ClientID: "id", ClientSecret: "secret"}, tp)
+	s.(*tracedService).service.(*Service).userAPIURL = server.URL
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	_, err := s.GetUserInfo(ctx, "token")
+	parent.End()
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3, "HTTP, service and parent spans")
+
+	httpSpan, serviceSpan := spans[0], spans[1]
+	assert.Equal(t, "github.GetUserInfo", serviceSpan.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, serviceSpan.Status().Code)
+	assert.Equal(t, serviceSpan.SpanContext().SpanID(), httpSpan.Parent().SpanID())
+	assert.Contains(t, traceparent, parent.SpanContext().TraceID().String(), "trace context is propagated")
+}
+
+func TestProvideService_NotConfigured(t *testing.T) {
+	s := ProvideService(&Config{}, sdktrace.NewTracerProvider())
+	assert.Nil(t, s)
+}
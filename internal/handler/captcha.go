@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/captcha"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
+)
+
+// errInvalidCaptcha is returned if the captcha token is rejected by the provider.
+var errInvalidCaptcha = errors.New("invalid captcha")
+
+// PostCaptchaChallenge handles the request to issue a new proof-of-work challenge.
+func (h *Handler) PostCaptchaChallenge(ctx echo.Context) error {
+	if h.proofOfWork == nil {
+		return captchaNotEnabled(ctx)
+	}
+
+	challenge, err := h.proofOfWork.NewChallenge()
+	if err != nil {
+		logError(ctx, errCreateChallenge, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateChallenge,
+			Message: "Error while creating captcha challenge",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, api.CaptchaChallengeResponse{
+		Challenge:  challenge.Challenge,
+		Algorithm:  challenge.Algorithm,
+		Difficulty: challenge.Difficulty,
+		ExpiresAt:  challenge.ExpiresAt,
+	})
+}
+
+// PostCaptchaVerify handles the request to exchange the proof-of-work solution for the captcha token.
+func (h *Handler) PostCaptchaVerify(ctx echo.Context) error {
+	if h.proofOfWork == nil {
+		return captchaNotEnabled(ctx)
+	}
+
+	var req api.CaptchaVerifyRequest
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.Challenge == "" || req.Nonce == "" {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Challenge and nonce fields are required",
+		})
+	}
+
+	token, err := h.proofOfWork.Redeem(req.Challenge, req.Nonce)
+	if err != nil {
+		if errors.Is(err, captcha.ErrInvalidSolution) {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errValidationCaptcha,
+				Message: "Invalid solution",
+			})
+		}
+
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errInvalidChallenge,
+			Message: "Challenge is invalid, expired or already solved",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, api.CaptchaVerifyResponse{
+		Token: token,
+	})
+}
+
+// verifyCaptcha verifies the captcha token sent by the client with the configured provider.
+func (h *Handler) verifyCaptcha(ctx echo.Context, token string) error {
+	result, err := h.captchaVerifier.Verify(ctx.Request().Context(), token, ctx.RealIP())
+	if err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return errInvalidCaptcha
+	}
+
+	return nil
+}
+
+// captchaError responds with the error returned from Handler.verifyCaptcha.
+func (h *Handler) captchaError(ctx echo.Context, err error) error {
+	if errors.Is(err, errInvalidCaptcha) {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errValidationCaptcha,
+			Message: "Invalid captcha",
+		})
+	}
+
+	logError(ctx, errVerifyCaptcha, err)
+	return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+		Code:    errVerifyCaptcha,
+		Message: "Error verifying captcha",
+	})
+}
+
+func captchaNotEnabled(ctx echo.Context) error {
+	return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+		Code:    errCaptchaNotEnabled,
+		Message: "Proof-of-work captcha is not enabled",
+	})
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/captcha"
+	captchaMock "github.com/samgozman/go-bloggy/mocks/captcha"
+	testcaptcha "github.com/samgozman/go-bloggy/testutils/test-captcha"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// solveChallenge finds the nonce of the proof-of-work challenge by brute force, like the client does.
+func solveChallenge(challenge api.CaptchaChallengeResponse) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		hash := sha256.Sum256([]byte(challenge.Challenge + nonce))
+
+		zeros := 0
+		for _, b := range hash {
+			zeros += bits.LeadingZeros8(b)
+			if b != 0 {
+				break
+			}
+		}
+
+		if zeros >= challenge.Difficulty {
+			return nonce
+		}
+	}
+}
+
+func Test_Captcha(t *testing.T) {
+	pow := captcha.NewProofOfWork("test-key", 8)
+	e, _ := newTestHandlers(t, handlerOptions{captcha: pow, proofOfWork: pow})
+
+	newChallenge := func(t *testing.T) api.CaptchaChallengeResponse {
+		t.Helper()
+
+		res := testutil.NewRequest().Post("/captcha/challenge").GoWithHTTPHandler(t, e)
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.CaptchaChallengeResponse
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+
+		return body
+	}
+
+	verify := func(t *testing.T, challenge, nonce string) *testutil.CompletedRequest {
+		t.Helper()
+
+		rb, _ := json.Marshal(api.CaptchaVerifyRequest{
+			Challenge: challenge,
+			Nonce:     nonce,
+		})
+
+		return testutil.NewRequest().
+			WithHeader("Content-Type", "application/json").
+			Post("/captcha/verify").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+	}
+
+	t.Run("200 - challenge", func(t *testing.T) {
+		challenge := newChallenge(t)
+
+		assert.NotEmpty(t, challenge.Challenge)
+		assert.Equal(t, "SHA-256", challenge.Algorithm)
+		assert.Equal(t, 8, challenge.Difficulty)
+	})
+
+	t.Run("200 - token is accepted by subscription", func(t *testing.T) {
+		challenge := newChallenge(t)
+
+		res := verify(t, challenge.Challenge, solveChallenge(challenge))
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.CaptchaVerifyResponse
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.NotEmpty(t, body.Token)
+
+		result, err := pow.Verify(context.Background(), body.Token, "")
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("400 - invalid solution", func(t *testing.T) {
+		hard := captcha.NewProofOfWork("test-key", 256)
+		e, _ := newTestHandlers(t, handlerOptions{captcha: hard, proofOfWork: hard})
+
+		challenge, err := hard.NewChallenge()
+		assert.NoError(t, err)
+
+		rb, _ := json.Marshal(api.CaptchaVerifyRequest{
+			Challenge: challenge.Challenge,
+			Nonce:     "0",
+		})
+		res := testutil.NewRequest().
+			WithHeader("Content-Type", "application/json").
+			Post("/captcha/verify").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.Equal(t, errValidationCaptcha, body.Code)
+	})
+
+	t.Run("400 - challenge already solved", func(t *testing.T) {
+		challenge := newChallenge(t)
+		nonce := solveChallenge(challenge)
+
+		res := verify(t, challenge.Challenge, nonce)
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		res = verify(t, challenge.Challenge, nonce)
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.Equal(t, errInvalidChallenge, body.Code)
+	})
+
+	t.Run("400 - missing fields", func(t *testing.T) {
+		res := verify(t, "", "")
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.Equal(t, errBodyValidation, body.Code)
+	})
+
+	t.Run("404 - proof-of-work is not enabled", func(t *testing.T) {
+		e, _ := newTestHandlers(t, handlerOptions{captcha: captchaMock.NewMockVerifierInterface(t)})
+
+		// Note: the body is valid, so the request is not rejected by the spec validation
+		for _, path := range []string{"/captcha/challenge", "/captcha/verify"} {
+			res := testutil.NewRequest().
+				Post(path).
+				WithJsonBody(api.CaptchaVerifyRequest{Challenge: "challenge", Nonce: "0"}).
+				GoWithHTTPHandler(t, e)
+			assert.Equal(t, http.StatusNotFound, res.Code())
+
+			var body api.RequestError
+			assert.NoError(t, res.UnmarshalBodyToObject(&body))
+			assert.Equal(t, errCaptchaNotEnabled, body.Code)
+		}
+	})
+}
+
+func Test_PostSubscribers_Captcha(t *testing.T) {
+	request := func(t *testing.T, e http.Handler, token string) *testutil.CompletedRequest {
+		t.Helper()
+
+		rb, _ := json.Marshal(api.CreateSubscriberRequest{
+			Email:   "some@email.com",
+			Captcha: token,
+		})
+
+		return testutil.NewRequest().
+			WithHeader("Content-Type", "application/json").
+			WithHeader("X-Real-Ip", "10.0.0.1").
+			Post("/subscribers").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+	}
+
+	t.Run("400 - rejected by remote provider", func(t *testing.T) {
+		server := testcaptcha.NewServer(testcaptcha.ReCaptcha, "secret")
+		defer server.Close()
+
+		e, _ := newTestHandlers(t, handlerOptions{
+			captcha: captcha.NewReCaptcha("secret", server.URL(), 0.5, http.DefaultClient),
+		})
+
+		res := request(t, e, server.Solve("subscribe", 0.1, "10.0.0.1"))
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.Equal(t, errValidationCaptcha, body.Code)
+	})
+
+	t.Run("400 - remote IP is passed to the provider", func(t *testing.T) {
+		verifier := captchaMock.NewMockVerifierInterface(t)
+		verifier.
+			On("Verify", mock.Anything, "some-captcha", "10.0.0.1").
+			Return(&captcha.Result{ErrorCodes: []string{captcha.ErrorCodeInvalidInput}}, nil).
+			Once()
+
+		e, _ := newTestHandlers(t, handlerOptions{captcha: verifier})
+		res := request(t, e, "some-captcha")
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+	})
+
+	t.Run("500 - provider is unavailable", func(t *testing.T) {
+		verifier := captchaMock.NewMockVerifierInterface(t)
+		verifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(nil, errors.New("unavailable")).
+			Once()
+
+		e, _ := newTestHandlers(t, handlerOptions{captcha: verifier})
+		res := request(t, e, "some-captcha")
+		assert.Equal(t, http.StatusInternalServerError, res.Code())
+
+		var body api.RequestError
+		assert.NoError(t, res.UnmarshalBodyToObject(&body))
+		assert.Equal(t, errVerifyCaptcha, body.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// postETag returns the strong ETag of the post, that is changed on every update of the post.
+// Note: the microseconds are used, as Postgres doesn't store the nanoseconds of the UpdatedAt.
+func postETag(post *models.Post) string {
+	return fmt.Sprintf(`"%d-%x"`, post.ID, post.UpdatedAt.UnixMicro())
+}
+
+// postsETag returns the strong ETag of the posts list, that is changed when any post is created, updated or deleted.
+func postsETag(lastUpdatedAt time.Time, count int64) string {
+	return fmt.Sprintf(`"posts-%d-%x"`, count, lastUpdatedAt.UnixMicro())
+}
+
+// notModified sets the ETag and Last-Modified headers of the response and reports if the client has the same version,
+// so 304 Not Modified can be sent instead of the body. If-None-Match takes precedence over If-Modified-Since.
+func notModified(ctx echo.Context, etag string, lastModified time.Time) bool {
+	h := ctx.Response().Header()
+	h.Set(headerETag, etag)
+	if !lastModified.IsZero() {
+		h.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	req := ctx.Request()
+	if ifNoneMatch := req.Header.Get(headerIfNoneMatch); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, etag, true)
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+
+	// Note: the header has the precision of seconds
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// preconditionFailed reports if the If-Match header is set and doesn't match the current etag of the resource,
+// i.e. the resource was changed since the client read it.
+func preconditionFailed(ctx echo.Context, etag string) bool {
+	ifMatch := ctx.Request().Header.Get(headerIfMatch)
+	if ifMatch == "" {
+		return false
+	}
+
+	return !matchETag(ifMatch, etag, false)
+}
+
+// matchETag reports if any of the comma-separated ETags of the header, or "*", matches the etag.
+// The weak comparison ignores the W/ prefix, the strong one never matches the weak ETags (RFC 9110, section 8.8.3.2).
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_postETag(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	post := &models.Post{ID: 1, UpdatedAt: updatedAt}
+
+	etag := postETag(post)
+	assert.Regexp(t, `^"1-[0-9a-f]+"$`, etag)
+
+	// Note: the ETag of the saved post is the same as of the post read from Postgres without the nanoseconds
+	assert.Equal(t, etag, postETag(&models.Post{ID: 1, UpdatedAt: updatedAt.Truncate(time.Microsecond)}))
+	assert.NotEqual(t, etag, postETag(&models.Post{ID: 1, UpdatedAt: updatedAt.Add(time.Microsecond)}))
+	assert.NotEqual(t, etag, postETag(&models.Post{ID: 2, UpdatedAt: updatedAt}))
+}
+
+func Test_postsETag(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	etag := postsETag(updatedAt, 10)
+	assert.NotEqual(t, etag, postsETag(updatedAt, 9), "the ETag is changed if the post is deleted")
+	assert.NotEqual(t, etag, postsETag(updatedAt.Add(time.Second), 10), "the ETag is changed if the post is updated")
+}
+
+func Test_notModified(t *testing.T) {
+	etag := `"1-5f"`
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	// check returns the result of notModified for the request with the headers and the response headers
+	check := func(headers map[string]string, lastModified time.Time) (bool, http.Header) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/first", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+
+		return notModified(echo.New().NewContext(req, rec), etag, lastModified), rec.Header()
+	}
+
+	t.Run("sets the validators", func(t *testing.T) {
+		ok, h := check(nil, lastModified)
+
+		assert.False(t, ok)
+		assert.Equal(t, etag, h.Get(headerETag))
+		assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", h.Get(echo.HeaderLastModified))
+	})
+
+	t.Run("no Last-Modified for zero time", func(t *testing.T) {
+		_, h := check(nil, time.Time{})
+
+		assert.Empty(t, h.Get(echo.HeaderLastModified))
+	})
+
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "matching If-None-Match", headers: map[string]string{headerIfNoneMatch: etag}, want: true},
+		{name: "If-None-Match list", headers: map[string]string{headerIfNoneMatch: `"0-1", "1-5f"`}, want: true},
+		{name: "weak If-None-Match", headers: map[string]string{headerIfNoneMatch: `W/"1-5f"`}, want: true},
+		{name: "any If-None-Match", headers: map[string]string{headerIfNoneMatch: "*"}, want: true},
+		{name: "changed If-None-Match", headers: map[string]string{headerIfNoneMatch: `"1-5e"`}, want: false},
+		{
+			name:    "If-Modified-Since of the same second",
+			headers: map[string]string{echo.HeaderIfModifiedSince: "Wed, 01 May 2024 12:00:00 GMT"},
+			want:    true,
+		},
+		{
+			name:    "If-Modified-Since before the change",
+			headers: map[string]string{echo.HeaderIfModifiedSince: "Wed, 01 May 2024 11:59:59 GMT"},
+			want:    false,
+		},
+		{
+			name:    "invalid If-Modified-Since",
+			headers: map[string]string{echo.HeaderIfModifiedSince: "yesterday"},
+			want:    false,
+		},
+		{
+			name: "If-None-Match takes precedence",
+			headers: map[string]string{
+				headerIfNoneMatch:          `"1-5e"`,
+				echo.HeaderIfModifiedSince: "Wed, 01 May 2024 12:00:00 GMT",
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, _ := check(tc.headers, lastModified)
+
+			assert.Equal(t, tc.want, ok)
+		})
+	}
+}
+
+func Test_preconditionFailed(t *testing.T) {
+	etag := `"1-5f"`
+
+	testCases := []struct {
+		name    string
+		ifMatch string
+		want    bool
+	}{
+		{name: "no If-Match", ifMatch: "", want: false},
+		{name: "matching If-Match", ifMatch: etag, want: false},
+		{name: "any If-Match", ifMatch: "*", want: false},
+		{name: "changed If-Match", ifMatch: `"1-5e"`, want: true},
+		{name: "weak If-Match never matches", ifMatch: `W/"1-5f"`, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/posts/first", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set(headerIfMatch, tc.ifMatch)
+			}
+			ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+			assert.Equal(t, tc.want, preconditionFailed(ctx, etag))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const headerLink = "Link"
+
+// errInvalidCursorValue is returned if the cursor can't be decoded, e.g. if it was changed by the client.
+var errInvalidCursorValue = errors.New("invalid cursor")
+
+// cursor is the JSON of the opaque cursor of the posts list.
+type cursor struct {
+	CreatedAt int64 `json:"t"` // CreatedAt is in microseconds, as Postgres doesn't store the nanoseconds
+	ID        int   `json:"id"`
+	Before    bool  `json:"b,omitempty"`
+}
+
+// encodeCursor encodes the c as the opaque cursor, that can be passed in the URL as is.
+func encodeCursor(c *models.PostCursor) string {
+	// Note: the cursor has no fields, that can't be encoded
+	data, _ := json.Marshal(cursor{CreatedAt: c.CreatedAt.UnixMicro(), ID: c.ID, Before: c.Before})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes the opaque cursor of encodeCursor.
+func decodeCursor(s string) (*models.PostCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidCursorValue, err)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidCursorValue, err)
+	}
+	if c.ID <= 0 {
+		return nil, errInvalidCursorValue
+	}
+
+	return &models.PostCursor{CreatedAt: time.UnixMicro(c.CreatedAt).UTC(), ID: c.ID, Before: c.Before}, nil
+}
+
+// pageLinks returns the Link header (RFC 8288) with the next and the previous pages of the page,
+// or an empty string if there are no such pages. The links are the u with the cursors instead of the page.
+func pageLinks(u *url.URL, page *models.PostPage) string {
+	links := make([]string, 0, 2)
+	for _, l := range []struct {
+		rel    string
+		cursor *models.PostCursor
+	}{
+		{rel: "next", cursor: page.Next},
+		{rel: "prev", cursor: page.Prev},
+	} {
+		if l.cursor == nil {
+			continue
+		}
+
+		q := u.Query()
+		q.Del("page")
+		q.Set("cursor", encodeCursor(l.cursor))
+		link := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, link.String(), l.rel))
+	}
+
+	return strings.Join(links, ", ")
+}
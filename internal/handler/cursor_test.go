@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_cursor(t *testing.T) {
+	t.Run("decode encoded cursor", func(t *testing.T) {
+		c := &models.PostCursor{
+			CreatedAt: time.Date(2024, 8, 1, 12, 30, 0, 123456000, time.UTC),
+			ID:        42,
+			Before:    true,
+		}
+
+		decoded, err := decodeCursor(encodeCursor(c))
+		assert.NoError(t, err)
+		assert.Equal(t, c, decoded)
+	})
+
+	t.Run("cursor is URL safe", func(t *testing.T) {
+		encoded := encodeCursor(&models.PostCursor{CreatedAt: time.Now(), ID: 1 << 30})
+		assert.Equal(t, url.QueryEscape(encoded), encoded)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, s := range []string{"", "not base64!", "bm90IGpzb24", "e30"} {
+			_, err := decodeCursor(s)
+			assert.ErrorIs(t, err, errInvalidCursorValue, s)
+		}
+	})
+}
+
+func Test_pageLinks(t *testing.T) {
+	u, _ := url.Parse("/posts?page=2&limit=1&keyword=go")
+	next := &models.PostCursor{CreatedAt: time.Now(), ID: 2}
+	prev := &models.PostCursor{CreatedAt: time.Now(), ID: 1, Before: true}
+
+	t.Run("next and prev", func(t *testing.T) {
+		links := pageLinks(u, &models.PostPage{Next: next, Prev: prev})
+		assert.Equal(t,
+			`</posts?cursor=`+encodeCursor(next)+`&keyword=go&limit=1>; rel="next", `+
+				`</posts?cursor=`+encodeCursor(prev)+`&keyword=go&limit=1>; rel="prev"`,
+			links,
+		)
+	})
+
+	t.Run("no pages", func(t *testing.T) {
+		assert.Empty(t, pageLinks(u, &models.PostPage{}))
+	})
+}
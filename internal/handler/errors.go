@@ -18,6 +18,12 @@ const (
 	errGetPosts              = "ERR_GET_POSTS"
 	errGetPostsCount         = "ERR_GET_POSTS_COUNT"
 	errUpdatePost            = "ERR_UPDATE_POST"
+	errPostChanged           = "ERR_POST_CHANGED"
+	errPreconditionRequired  = "ERR_PRECONDITION_REQUIRED"
+	errGetPostsLastUpdate    = "ERR_GET_POSTS_LAST_UPDATE"
+	errInvalidCursor         = "ERR_INVALID_CURSOR"
+	errGetPostsArchive       = "ERR_GET_POSTS_ARCHIVE"
+	errGetPostsRelated       = "ERR_GET_POSTS_RELATED"
 	errCreateSubscription    = "ERR_CREATE_SUBSCRIPTION"
 	errGetSubscription       = "ERR_GET_SUBSCRIPTION"
 	errDeleteSubscription    = "ERR_DELETE_SUBSCRIPTION"
@@ -27,4 +33,32 @@ const (
 	errUpdateSubscription    = "ERR_UPDATE_SUBSCRIPTION"
 	errSendConfirmationEmail = "ERR_SEND_CONFIRMATION_EMAIL"
 	errSendPostEmail         = "ERR_SEND_POST_EMAIL"
+	errGetUsers              = "ERR_GET_USERS"
+	errUserNotFound          = "ERR_USER_NOT_FOUND"
+	errDuplicateUser         = "ERR_DUPLICATE_USER"
+	errUpdateUser            = "ERR_UPDATE_USER"
+	errProviderNotFound      = "ERR_PROVIDER_NOT_FOUND"
+	errProviderUnavailable   = "ERR_PROVIDER_UNAVAILABLE"
+	errInvalidState          = "ERR_INVALID_STATE"
+	errCreateMagicLink       = "ERR_CREATE_MAGIC_LINK"
+	errInvalidMagicLink      = "ERR_INVALID_MAGIC_LINK"
+	errUseMagicLink          = "ERR_USE_MAGIC_LINK"
+	errSendMagicLinkEmail    = "ERR_SEND_MAGIC_LINK_EMAIL"
+	errInvalidMFAToken       = "ERR_INVALID_MFA_TOKEN"
+	errGetCredentials        = "ERR_GET_WEBAUTHN_CREDENTIALS"
+	errBeginWebAuthn         = "ERR_BEGIN_WEBAUTHN"
+	errInvalidCredential     = "ERR_INVALID_WEBAUTHN_CREDENTIAL"
+	errCreateCredential      = "ERR_CREATE_WEBAUTHN_CREDENTIAL"
+	errUpdateCredential      = "ERR_UPDATE_WEBAUTHN_CREDENTIAL"
+	errDuplicateCredential   = "ERR_DUPLICATE_WEBAUTHN_CREDENTIAL"
+	errCredentialNotFound    = "ERR_WEBAUTHN_CREDENTIAL_NOT_FOUND"
+	errDeleteCredential      = "ERR_DELETE_WEBAUTHN_CREDENTIAL"
+	errUseChallenge          = "ERR_USE_WEBAUTHN_CHALLENGE"
+	errInvalidRecoveryCode   = "ERR_INVALID_RECOVERY_CODE"
+	errUseRecoveryCode       = "ERR_USE_RECOVERY_CODE"
+	errCreateRecoveryCodes   = "ERR_CREATE_RECOVERY_CODES"
+	errVerifyCaptcha         = "ERR_VERIFY_CAPTCHA"
+	errCaptchaNotEnabled     = "ERR_CAPTCHA_NOT_ENABLED"
+	errCreateChallenge       = "ERR_CREATE_CAPTCHA_CHALLENGE"
+	errInvalidChallenge      = "ERR_INVALID_CAPTCHA_CHALLENGE"
 )
@@ -2,32 +2,72 @@ package handler
 
 import (
 	"github.com/google/wire"
+	"github.com/labstack/echo/v4"
 	oapi "github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/captcha"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/db"
 	"github.com/samgozman/go-bloggy/internal/github"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"github.com/samgozman/go-bloggy/internal/jwt"
+	"github.com/samgozman/go-bloggy/internal/logging"
 	mailer "github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/newsletter"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
+	"time"
 )
 
 type Config struct {
-	AdminsExternalIDs config.AdminsExternalIDs
+	AdminsExternalIDs   config.AdminsExternalIDs
+	SubscriberEmailRate ratelimit.Rate
+	MagicLinkEmailRate  ratelimit.Rate
+	MagicLinkIPRate     ratelimit.Rate
+	MFAUserRate         ratelimit.Rate
+	RelatedPosts        int
+	SessionTTL          time.Duration
+	RefreshTTL          time.Duration
+	MFATTL              time.Duration
 }
 
 // Handler for the service API endpoints.
 type Handler struct {
-	githubService     github.ServiceInterface
+	githubService     github.ServiceInterface // githubService is nil if the GitHub sign in is not configured
+	oidcService       oidc.ServiceInterface
 	jwtService        jwt.ServiceInterface
-	hcaptchaService   captcha.ClientInterface
+	webAuthnService   webauthn.ServiceInterface
+	captchaVerifier   captcha.VerifierInterface
+	proofOfWork       captcha.ProofOfWorkInterface // proofOfWork is nil if the proof-of-work captcha is not enabled
 	db                *db.Database
 	mailerService     mailer.ServiceInterface
+	newsletter        newsletter.ServiceInterface
+	health            health.ServiceInterface
+	metrics           *metrics.Metrics
 	adminsExternalIDs []string
+	relatedPosts      int           // relatedPosts is the maximal number of the related posts of a post
+	sessionTTL        time.Duration // sessionTTL is the expiration of the JWT token issued on sign in
+	refreshTTL        time.Duration // refreshTTL is the expiration of the refreshed JWT token
+	mfaTTL            time.Duration // mfaTTL is the expiration of the MFA token issued after the first factor
+
+	magicLinkEmailLimiter *ratelimit.Limiter // magicLinkEmailLimiter limits magic links sent per email
+	magicLinkIPLimiter    *ratelimit.Limiter // magicLinkIPLimiter limits magic link requests per IP address
+	mfaUserLimiter        *ratelimit.Limiter // mfaUserLimiter limits second factor attempts per user
+	subscriberLimiter     *ratelimit.Limiter // subscriberLimiter limits confirmation emails sent per email
 }
 
 func ProvideConfig(cfg *config.Config) *Config {
 	return &Config{
-		AdminsExternalIDs: cfg.AdminsExternalIDs,
+		AdminsExternalIDs:   cfg.AdminsExternalIDs,
+		SubscriberEmailRate: ratelimit.Rate(cfg.RateLimit.SubscriberEmail),
+		MagicLinkEmailRate:  ratelimit.Rate(cfg.RateLimit.MagicLinkEmail),
+		MagicLinkIPRate:     ratelimit.Rate(cfg.RateLimit.MagicLinkIP),
+		MFAUserRate:         ratelimit.Rate(cfg.RateLimit.MFAUser),
+		RelatedPosts:        cfg.RelatedPosts,
+		SessionTTL:          cfg.JWT.SessionTTL,
+		RefreshTTL:          cfg.JWT.RefreshTTL,
+		MFATTL:              cfg.JWT.MFATTL,
 	}
 }
 
@@ -37,16 +77,38 @@ func ProvideHandler(
 	g github.ServiceInterface,
 	j jwt.ServiceInterface,
 	db *db.Database,
-	h captcha.ClientInterface,
+	c captcha.VerifierInterface,
 	ms mailer.ServiceInterface,
+	o oidc.ServiceInterface,
+	w webauthn.ServiceInterface,
+	pow captcha.ProofOfWorkInterface,
+	n newsletter.ServiceInterface,
+	hs health.ServiceInterface,
+	m *metrics.Metrics,
+	rl ratelimit.Store,
 ) *Handler {
 	return &Handler{
 		githubService:     g,
+		oidcService:       o,
 		jwtService:        j,
+		webAuthnService:   w,
 		db:                db,
-		hcaptchaService:   h,
+		captchaVerifier:   c,
+		proofOfWork:       pow,
 		mailerService:     ms,
+		newsletter:        n,
+		health:            hs,
+		metrics:           m,
 		adminsExternalIDs: cfg.AdminsExternalIDs,
+		relatedPosts:      cfg.RelatedPosts,
+		sessionTTL:        cfg.SessionTTL,
+		refreshTTL:        cfg.RefreshTTL,
+		mfaTTL:            cfg.MFATTL,
+
+		magicLinkEmailLimiter: ratelimit.NewLimiter(rl, "magic-link-email", cfg.MagicLinkEmailRate),
+		magicLinkIPLimiter:    ratelimit.NewLimiter(rl, "magic-link-ip", cfg.MagicLinkIPRate),
+		mfaUserLimiter:        ratelimit.NewLimiter(rl, "mfa-user", cfg.MFAUserRate),
+		subscriberLimiter:     ratelimit.NewLimiter(rl, "subscriber-email", cfg.SubscriberEmailRate),
 	}
 }
 
@@ -56,3 +118,9 @@ var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
 	ProvideHandler,
 	wire.Bind(new(oapi.ServerInterface), new(*Handler)),
 )
+
+// logError logs the unexpected error with the logger of the request before responding with 500,
+// as the error itself is not exposed to the client.
+func logError(ctx echo.Context, code string, err error) {
+	logging.FromContext(ctx.Request().Context()).Error("Request failed", "code", code, "error", err)
+}
@@ -1,40 +1,204 @@
 package handler
 
 import (
+	"encoding/json"
 	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/testutil"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/captcha"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/github"
+	"github.com/samgozman/go-bloggy/internal/health"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/newsletter"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	"github.com/samgozman/go-bloggy/internal/server/middlewares"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
 	captchaMock "github.com/samgozman/go-bloggy/mocks/captcha"
 	mockGithub "github.com/samgozman/go-bloggy/mocks/github"
 	jwtMock "github.com/samgozman/go-bloggy/mocks/jwt"
 	mockMailer "github.com/samgozman/go-bloggy/mocks/mailer"
+	mockOIDC "github.com/samgozman/go-bloggy/mocks/oidc"
 )
 
+func init() {
+	// Note: the errors are served as the problem details, that are decoded like JSON
+	testutil.RegisterResponseHandler(problem.ContentType, func(_ string, r io.Reader, obj interface{}, _ bool) error {
+		return json.NewDecoder(r).Decode(obj)
+	})
+}
+
+// handlerOptions overrides the dependencies of the handlers in tests, the nil ones are mocked or use the defaults.
+type handlerOptions struct {
+	conn        *db.Database // conn is nil if the handlers are tested without the database
+	adminsIDs   []string
+	oidc        oidc.ServiceInterface
+	captcha     captcha.VerifierInterface
+	proofOfWork captcha.ProofOfWorkInterface
+	health      health.ServiceInterface
+	noGitHub    bool // noGitHub tests the handlers without the GitHub sign in configured
+}
+
+// handlerMocks are the mocked dependencies of the handlers, the overridden ones are not used by the handlers.
+type handlerMocks struct {
+	github  *mockGithub.MockServiceInterface
+	jwt     *jwtMock.MockServiceInterface
+	mailer  *mockMailer.MockServiceInterface
+	captcha *captchaMock.MockVerifierInterface
+	oidc    *mockOIDC.MockServiceInterface
+}
+
+// newTestHandlers creates a new echo instance and registers the handlers with the given dependencies for testing.
+func newTestHandlers(t *testing.T, opts handlerOptions) (*echo.Echo, *handlerMocks) {
+	m := &handlerMocks{
+		github:  mockGithub.NewMockServiceInterface(t),
+		jwt:     jwtMock.NewMockServiceInterface(t),
+		mailer:  mockMailer.NewMockServiceInterface(t),
+		captcha: captchaMock.NewMockVerifierInterface(t),
+		oidc:    mockOIDC.NewMockServiceInterface(t),
+	}
+
+	// Note: the default rate limits are used, e.g. to test the limit of the confirmation emails
+	defaults := config.Default()
+	defaults.AdminsExternalIDs = opts.adminsIDs
+	cfg := ProvideConfig(defaults)
+
+	var n newsletter.ServiceInterface
+	if opts.conn != nil {
+		n = newsletter.NewService(opts.conn.Models(), m.mailer)
+	}
+
+	var c captcha.VerifierInterface = m.captcha
+	if opts.captcha != nil {
+		c = opts.captcha
+	}
+
+	var o oidc.ServiceInterface = m.oidc
+	if opts.oidc != nil {
+		o = opts.oidc
+	}
+
+	hs := opts.health
+	if hs == nil {
+		hs = health.NewService(0, time.Second)
+	}
+
+	var g github.ServiceInterface = m.github
+	if opts.noGitHub {
+		g = nil
+	}
+
+	h := ProvideHandler(
+		cfg,
+		g,
+		m.jwt,
+		opts.conn,
+		c,
+		m.mailer,
+		o,
+		newTestWebAuthnService(t, opts.conn),
+		opts.proofOfWork,
+		n,
+		hs,
+		metrics.New(&metrics.Config{}),
+		ratelimit.NewMemoryStore(),
+	)
+
+	e := echo.New()
+	e.Use(newTestAuth(t, m.jwt))
+	e.Use(newTestValidation(t))
+
+	api.RegisterHandlers(e, h)
+
+	return e, m
+}
+
 // registerHandlers creates a new echo instance and registers the handlers for testing.
 func registerHandlers(t *testing.T, conn *db.Database, adminsIDs []string) (
 	s *echo.Echo,
 	githubService *mockGithub.MockServiceInterface,
 	jwtService *jwtMock.MockServiceInterface,
 	mailerService *mockMailer.MockServiceInterface,
-	captchaService *captchaMock.MockClientInterface,
+	captchaVerifier *captchaMock.MockVerifierInterface,
 ) {
-	// Create mocks
-	g := mockGithub.NewMockServiceInterface(t)
-	j := jwtMock.NewMockServiceInterface(t)
-	hc := captchaMock.NewMockClientInterface(t)
-	ms := mockMailer.NewMockServiceInterface(t)
+	e, m := newTestHandlers(t, handlerOptions{conn: conn, adminsIDs: adminsIDs})
 
-	// Create echo instance
-	e := echo.New()
-	cfg := ProvideConfig(&config.Config{AdminsExternalIDs: adminsIDs})
-	h := ProvideHandler(cfg, g, j, conn, hc, ms)
-	e.Use(middlewares.JWTAuth(j))
+	return e, m.github, m.jwt, m.mailer, m.captcha
+}
 
-	api.RegisterHandlers(e, h)
+// testAPIKey is the key accepted by the ApiKeyAuth security scheme in tests.
+const testAPIKey = "test-api-key"
+
+// testWebAuthnOrigin is the origin of the software authenticator used in tests.
+const testWebAuthnOrigin = "http://localhost"
+
+// newTestWebAuthnService creates a new WebAuthn service for the testWebAuthnOrigin.
+// The used challenges are stored in the database, if the conn is passed.
+func newTestWebAuthnService(t *testing.T, conn *db.Database) *webauthn.Service {
+	t.Helper()
+
+	var challenges webauthn.ChallengeStore = webauthn.NewMemoryChallengeStore()
+	if conn != nil {
+		challenges = conn.Models().WebAuthnChallenges()
+	}
+
+	s, err := webauthn.NewService(
+		"localhost",
+		"go-bloggy",
+		[]string{testWebAuthnOrigin},
+		"test-session-key",
+		challenges,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// newTestAuth creates the auth middleware for the embedded OpenAPI spec.
+func newTestAuth(t *testing.T, j *jwtMock.MockServiceInterface) echo.MiddlewareFunc {
+	t.Helper()
+
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := middlewares.Auth(spec, j, []string{testAPIKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return auth
+}
+
+// newTestValidation creates the validation middleware for the embedded OpenAPI spec,
+// that fails the test if the handler responds with something that doesn't match the spec.
+func newTestValidation(t *testing.T) echo.MiddlewareFunc {
+	t.Helper()
+
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validation, err := middlewares.Validation(spec, middlewares.ValidationConfig{
+		OnInvalidResponse: func(ctx echo.Context, err error) {
+			t.Errorf("%s %s responded with %d, that doesn't match the spec: %v",
+				ctx.Request().Method, ctx.Request().URL.Path, ctx.Response().Status, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	return e, g, j, ms, hc
+	return validation
 }
@@ -3,12 +3,51 @@ package handler
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"net/http"
+	"time"
 )
 
 // GetHealth returns health status of the service.
+// Deprecated: kept for the existing monitors, same as GetHealthLive.
 func (h *Handler) GetHealth(ctx echo.Context) error {
+	return h.GetHealthLive(ctx)
+}
+
+// GetHealthLive returns OK while the process is able to serve the requests.
+func (h *Handler) GetHealthLive(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, api.HealthCheckResponse{
 		Status: "OK",
 	})
 }
+
+// GetHealthReady returns the results of the readiness checks, with 503 status if any critical check is down.
+func (h *Handler) GetHealthReady(ctx echo.Context) error {
+	report := h.health.Ready(ctx.Request().Context())
+
+	checks := make([]api.HealthCheckResult, len(report.Checks))
+	for i, r := range report.Checks {
+		checks[i] = api.HealthCheckResult{
+			Name:      r.Name,
+			Status:    api.HealthCheckResultStatus(r.Status),
+			Critical:  r.Critical,
+			LatencyMs: float64(r.Latency) / float64(time.Millisecond),
+			CheckedAt: r.CheckedAt,
+		}
+		if r.Status == health.StatusDown {
+			checks[i].Error = &report.Checks[i].Error
+		}
+	}
+
+	if !report.Ready {
+		return ctx.JSON(http.StatusServiceUnavailable, api.ReadinessResponse{
+			Status: api.ReadinessFail,
+			Checks: checks,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, api.ReadinessResponse{
+		Status: api.ReadinessOK,
+		Checks: checks,
+	})
+}
@@ -1,22 +1,76 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"github.com/oapi-codegen/testutil"
 	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func Test_GetHealth(t *testing.T) {
 	e, _, _, _, _ := registerHandlers(t, nil, nil)
 
-	res := testutil.NewRequest().Get("/health").GoWithHTTPHandler(t, e)
+	for _, path := range []string{"/health", "/health/live"} {
+		t.Run(path, func(t *testing.T) {
+			res := testutil.NewRequest().Get(path).GoWithHTTPHandler(t, e)
 
-	var body api.HealthCheckResponse
-	err := res.UnmarshalBodyToObject(&body)
-	assert.NoError(t, err)
+			var body api.HealthCheckResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
 
-	assert.Equal(t, http.StatusOK, res.Code())
-	assert.Equal(t, "OK", body.Status)
+			assert.Equal(t, http.StatusOK, res.Code())
+			assert.Equal(t, "OK", body.Status)
+		})
+	}
+}
+
+func Test_GetHealthReady(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		hs := health.NewService(time.Second, time.Second)
+		hs.Register(health.NewChecker("database", func(_ context.Context) error { return nil }))
+		hs.RegisterOptional(health.NewChecker("mail", func(_ context.Context) error { return health.ErrDisabled }))
+		e, _ := newTestHandlers(t, handlerOptions{health: hs})
+
+		res := testutil.NewRequest().Get("/health/ready").GoWithHTTPHandler(t, e)
+
+		var body api.ReadinessResponse
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		assert.Equal(t, api.ReadinessOK, body.Status)
+		assert.Len(t, body.Checks, 2)
+		assert.Equal(t, "database", body.Checks[0].Name)
+		assert.Equal(t, api.HealthCheckUp, body.Checks[0].Status)
+		assert.True(t, body.Checks[0].Critical)
+		assert.Nil(t, body.Checks[0].Error)
+		assert.False(t, body.Checks[0].CheckedAt.IsZero())
+		assert.Equal(t, "mail", body.Checks[1].Name)
+		assert.Equal(t, api.HealthCheckDisabled, body.Checks[1].Status)
+		assert.False(t, body.Checks[1].Critical)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		hs := health.NewService(time.Second, time.Second)
+		hs.Register(health.NewChecker("database", func(_ context.Context) error {
+			return errors.New("connection refused")
+		}))
+		e, _ := newTestHandlers(t, handlerOptions{health: hs})
+
+		res := testutil.NewRequest().Get("/health/ready").GoWithHTTPHandler(t, e)
+
+		var body api.ReadinessResponse
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code())
+		assert.Equal(t, api.ReadinessFail, body.Status)
+		assert.Equal(t, api.HealthCheckDown, body.Checks[0].Status)
+		assert.Equal(t, "connection refused", *body.Checks[0].Error)
+	})
 }
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	"github.com/samgozman/go-bloggy/internal/problem"
 	"net/http"
 	"slices"
 	"strconv"
@@ -13,16 +18,23 @@ import (
 
 // PostLoginGithubAuthorize handles the request to authorize with GitHub.
 func (h *Handler) PostLoginGithubAuthorize(ctx echo.Context) error {
+	if h.githubService == nil {
+		return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+			Code:    errProviderNotFound,
+			Message: "GitHub sign in is not configured",
+		})
+	}
+
 	var req api.GitHubAuthRequestBody
 	if err := ctx.Bind(&req); err != nil {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: "Error binding request body",
 		})
 	}
 
 	if req.Code == "" {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errBodyValidation,
 			Message: "Code field is required",
 		})
@@ -30,7 +42,8 @@ func (h *Handler) PostLoginGithubAuthorize(ctx echo.Context) error {
 
 	token, err := h.githubService.ExchangeCodeForToken(ctx.Request().Context(), req.Code)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errExchangeCode, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errExchangeCode,
 			Message: "Error while exchanging GitHub code for token",
 		})
@@ -38,44 +51,120 @@ func (h *Handler) PostLoginGithubAuthorize(ctx echo.Context) error {
 
 	user, err := h.githubService.GetUserInfo(ctx.Request().Context(), token)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errGetUserInfo, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errGetUserInfo,
 			Message: "Error while getting user info from GitHub",
 		})
 	}
 
-	// Check if user is an admin
-	if !slices.Contains(h.adminsExternalIDs, strconv.Itoa(user.ID)) {
-		return ctx.JSON(http.StatusForbidden, api.RequestError{
-			Code:    errForbidden,
-			Message: "User is not an admin",
+	dbUser, err := h.authorizeUser(ctx.Request().Context(), models.GitHubAuthMethod, strconv.Itoa(user.ID), user.Login)
+	if err != nil {
+		if errors.Is(err, errUserNotAllowed) {
+			return problem.Respond(ctx, http.StatusForbidden, api.RequestError{
+				Code:    errForbidden,
+				Message: "User is not allowed to sign in",
+			})
+		}
+
+		logError(ctx, errCreateUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateUser,
+			Message: "Error while creating user",
 		})
 	}
 
-	// TODO: Store JWT expiration time in config
-	jwtToken, err := h.jwtService.CreateTokenString(strconv.Itoa(user.ID), time.Now().Add(2*time.Minute))
+	return h.loginResponse(ctx, dbUser)
+}
+
+// GetLoginProviderAuthorize handles the request to start authorization with an OpenID Connect provider.
+func (h *Handler) GetLoginProviderAuthorize(ctx echo.Context, provider string) error {
+	authRequest, err := h.oidcService.AuthCodeURL(ctx.Request().Context(), provider)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
-			Code:    errCreateToken,
-			Message: "Error while creating JWT token",
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+				Code:    errProviderNotFound,
+				Message: "Provider is not configured",
+			})
+		}
+
+		logError(ctx, errProviderUnavailable, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errProviderUnavailable,
+			Message: "Error while connecting to the provider",
 		})
 	}
 
-	err = h.db.Models().Users().Upsert(ctx.Request().Context(), &models.User{
-		ExternalID: strconv.Itoa(user.ID),
-		Login:      user.Login,
-		AuthMethod: models.GitHubAuthMethod,
+	return ctx.JSON(http.StatusOK, api.OIDCAuthURLResponse{
+		AuthorizationUrl: authRequest.URL,
+		State:            authRequest.State,
+		CodeVerifier:     authRequest.CodeVerifier,
 	})
+}
+
+// PostLoginProviderAuthorize handles the request to authorize with an OpenID Connect provider.
+func (h *Handler) PostLoginProviderAuthorize(ctx echo.Context, provider string) error {
+	var req api.OIDCAuthRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.Code == "" || req.State == "" || req.CodeVerifier == "" {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Code, state and code_verifier fields are required",
+		})
+	}
+
+	user, err := h.oidcService.Exchange(ctx.Request().Context(), provider, req.Code, req.State, req.CodeVerifier)
+	if err != nil {
+		switch {
+		case errors.Is(err, oidc.ErrUnknownProvider):
+			return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+				Code:    errProviderNotFound,
+				Message: "Provider is not configured",
+			})
+		case errors.Is(err, oidc.ErrInvalidState):
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errInvalidState,
+				Message: "Invalid or expired state",
+			})
+		case errors.Is(err, oidc.ErrDiscovery):
+			logError(ctx, errProviderUnavailable, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errProviderUnavailable,
+				Message: "Error while connecting to the provider",
+			})
+		default:
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errExchangeCode,
+				Message: "Error while exchanging the provider code for token",
+			})
+		}
+	}
+
+	// Note: subject is only unique within the provider, so the provider name is a part of the external ID
+	externalID := provider + ":" + user.Subject
+	dbUser, err := h.authorizeUser(ctx.Request().Context(), models.OIDCAuthMethod, externalID, user.Login)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		if errors.Is(err, errUserNotAllowed) {
+			return problem.Respond(ctx, http.StatusForbidden, api.RequestError{
+				Code:    errForbidden,
+				Message: "User is not allowed to sign in",
+			})
+		}
+
+		logError(ctx, errCreateUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errCreateUser,
 			Message: "Error while creating user",
 		})
 	}
 
-	return ctx.JSON(http.StatusOK, api.JWTToken{
-		Token: jwtToken,
-	})
+	return h.loginResponse(ctx, dbUser)
 }
 
 // PostLoginRefresh handles the request to refresh the JWT token.
@@ -83,7 +172,7 @@ func (h *Handler) PostLoginRefresh(ctx echo.Context) error {
 	token := ctx.Request().Header.Get("Authorization")
 	token = strings.TrimPrefix(token, "Bearer ")
 	if token == "" {
-		return ctx.JSON(http.StatusUnauthorized, api.RequestError{
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
 			Code:    errForbidden,
 			Message: "Authorization header is required",
 		})
@@ -91,16 +180,21 @@ func (h *Handler) PostLoginRefresh(ctx echo.Context) error {
 
 	userID, err := h.jwtService.ParseTokenString(token)
 	if err != nil {
-		return ctx.JSON(http.StatusUnauthorized, api.RequestError{
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
 			Code:    errForbidden,
 			Message: "Invalid token",
 		})
 	}
 
-	// TODO: Store JWT expiration time in config
-	newToken, err := h.jwtService.CreateTokenString(userID, time.Now().Add(2*time.Minute))
+	// Note: the token is valid until it expires, so the disabled users are rejected here to revoke their access
+	if _, err := h.activeUser(ctx.Request().Context(), userID); err != nil {
+		return h.userError(ctx, err)
+	}
+
+	newToken, err := h.jwtService.CreateTokenString(userID, time.Now().Add(h.refreshTTL))
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errCreateToken, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errCreateToken,
 			Message: "Error while creating JWT token",
 		})
@@ -110,3 +204,56 @@ func (h *Handler) PostLoginRefresh(ctx echo.Context) error {
 		Token: newToken,
 	})
 }
+
+// errUserNotAllowed is returned if the user is unknown, disabled or signs in with another auth method.
+var errUserNotAllowed = errors.New("user is not allowed to sign in")
+
+// authorizeUser checks that the user with the given external identity is allowed to sign in.
+// Invited users are activated on the first sign in. Users from the AdminsExternalIDs bootstrap list
+// are created (or promoted) as admins, unless they were disabled.
+func (h *Handler) authorizeUser(
+	ctx context.Context,
+	authMethod models.AuthMethod,
+	externalID, login string,
+) (*models.User, error) {
+	isBootstrapAdmin := slices.Contains(h.adminsExternalIDs, externalID)
+
+	user, err := h.db.Models().Users().GetByExternalID(ctx, externalID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			return nil, fmt.Errorf("get user: %w", err)
+		}
+		if !isBootstrapAdmin {
+			return nil, errUserNotAllowed
+		}
+
+		user = &models.User{
+			ExternalID: externalID,
+			Login:      login,
+			AuthMethod: authMethod,
+			Role:       models.AdminUserRole,
+			Status:     models.ActiveUserStatus,
+		}
+		if err := h.db.Models().Users().Upsert(ctx, user); err != nil {
+			return nil, fmt.Errorf("create user: %w", err)
+		}
+
+		return user, nil
+	}
+
+	if user.Status == models.DisabledUserStatus || user.AuthMethod != authMethod {
+		return nil, errUserNotAllowed
+	}
+
+	user.Login = login
+	user.Status = models.ActiveUserStatus
+	if isBootstrapAdmin {
+		user.Role = models.AdminUserRole
+	}
+
+	if err := h.db.Models().Users().Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("update user: %w", err)
+	}
+
+	return user, nil
+}
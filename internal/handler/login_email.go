@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	magicLinkScope = "magic-link" // magicLinkScope of the JWT in the magic link, so it can't be used for auth
+	magicLinkTTL   = 15 * time.Minute
+)
+
+// PostLoginEmail handles the request to send the magic link to the user email.
+func (h *Handler) PostLoginEmail(ctx echo.Context) error {
+	if allowed, retryAfter := h.magicLinkIPLimiter.Allow(ctx.Request().Context(), ctx.RealIP()); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
+	}
+
+	var req api.MagicLinkRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !models.IsValidEmail(email) {
+		return invalidEmail(ctx, "#/email", req.Email)
+	}
+
+	if allowed, retryAfter := h.magicLinkEmailLimiter.Allow(ctx.Request().Context(), email); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
+	}
+
+	user, err := h.db.Models().Users().GetByExternalID(ctx.Request().Context(), email)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		logError(ctx, errGetUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetUser,
+			Message: "Error getting user",
+		})
+	}
+
+	// Note: we shouldn't tell if the user exists for security reasons,
+	// so the errors of sending the link are only logged and the response is always the same.
+	if user == nil || user.AuthMethod != models.EmailAuthMethod || user.Status == models.DisabledUserStatus {
+		return ctx.NoContent(http.StatusAccepted)
+	}
+
+	link := models.MagicLink{
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(magicLinkTTL),
+	}
+	if err := h.db.Models().MagicLinks().Create(ctx.Request().Context(), &link); err != nil {
+		logError(ctx, errCreateMagicLink, err)
+		return ctx.NoContent(http.StatusAccepted)
+	}
+
+	token, err := h.jwtService.CreateScopedTokenString(magicLinkScope, link.ID.String(), link.ExpiresAt)
+	if err != nil {
+		logError(ctx, errCreateToken, err)
+		return ctx.NoContent(http.StatusAccepted)
+	}
+
+	if err := h.mailerService.SendMagicLinkEmail(ctx.Request().Context(), email, token); err != nil {
+		logError(ctx, errSendMagicLinkEmail, err)
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// PostLoginEmailVerify handles the request to exchange the magic link for the JWT token.
+func (h *Handler) PostLoginEmailVerify(ctx echo.Context) error {
+	if allowed, retryAfter := h.magicLinkIPLimiter.Allow(ctx.Request().Context(), ctx.RealIP()); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
+	}
+
+	var req api.MagicLinkVerifyRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.Token == "" {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Token field is required",
+		})
+	}
+
+	linkID, err := h.jwtService.ParseScopedTokenString(magicLinkScope, req.Token)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errInvalidMagicLink,
+			Message: "Magic link is invalid, expired or already used",
+		})
+	}
+
+	link, err := h.db.Models().MagicLinks().Use(ctx.Request().Context(), linkID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errInvalidMagicLink,
+				Message: "Magic link is invalid, expired or already used",
+			})
+		}
+
+		logError(ctx, errUseMagicLink, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUseMagicLink,
+			Message: "Error while using magic link",
+		})
+	}
+
+	linkUser, err := h.db.Models().Users().GetByID(ctx.Request().Context(), link.UserID)
+	if err != nil {
+		logError(ctx, errGetUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetUser,
+			Message: "Error getting user",
+		})
+	}
+
+	// Note: the user could be disabled after the link was sent
+	dbUser, err := h.authorizeUser(ctx.Request().Context(), models.EmailAuthMethod, linkUser.ExternalID, linkUser.Login)
+	if err != nil {
+		if errors.Is(err, errUserNotAllowed) {
+			return problem.Respond(ctx, http.StatusForbidden, api.RequestError{
+				Code:    errForbidden,
+				Message: "User is not allowed to sign in",
+			})
+		}
+
+		logError(ctx, errCreateUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateUser,
+			Message: "Error while creating user",
+		})
+	}
+
+	return h.loginResponse(ctx, dbUser)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_PostLoginEmail(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	createUser := func(t *testing.T, status models.UserStatus) *models.User {
+		t.Helper()
+
+		user := &models.User{
+			ExternalID: uuid.New().String() + "@example.com",
+			Login:      "guest",
+			AuthMethod: models.EmailAuthMethod,
+			Status:     status,
+		}
+		err := conn.Models().Users().Create(context.Background(), user)
+		assert.NoError(t, err)
+
+		return user
+	}
+
+	t.Run("202 - OK", func(t *testing.T) {
+		e, _, mockJwtService, mockMailerService, _ := registerHandlers(t, conn, nil)
+		user := createUser(t, models.InvitedUserStatus)
+
+		mockJwtService.
+			On("CreateScopedTokenString", magicLinkScope, mock.Anything, mock.Anything).
+			Return("magicToken", nil)
+		mockMailerService.
+			On("SendMagicLinkEmail", mock.Anything, user.ExternalID, "magicToken").
+			Return(nil)
+
+		rb, _ := json.Marshal(api.MagicLinkRequestBody{
+			Email: " " + user.ExternalID + " ",
+		})
+
+		res := testutil.NewRequest().
+			Post("/login/email").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusAccepted, res.Code())
+	})
+
+	t.Run("202 - should not send link to unknown or disabled user", func(t *testing.T) {
+		e, _, _, _, _ := registerHandlers(t, conn, nil)
+		disabled := createUser(t, models.DisabledUserStatus)
+
+		for _, email := range []string{"unknown@example.com", disabled.ExternalID} {
+			rb, _ := json.Marshal(api.MagicLinkRequestBody{Email: email})
+
+			res := testutil.NewRequest().
+				Post("/login/email").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusAccepted, res.Code())
+		}
+	})
+
+	t.Run("400 - invalid email", func(t *testing.T) {
+		e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+		rb, _ := json.Marshal(api.MagicLinkRequestBody{Email: "invalid"})
+
+		res := testutil.NewRequest().
+			Post("/login/email").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errValidationEmail, body.Code)
+	})
+
+	t.Run("429 - too many requests for the email", func(t *testing.T) {
+		e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+		rb, _ := json.Marshal(api.MagicLinkRequestBody{Email: "unknown@example.com"})
+
+		for range config.Default().RateLimit.MagicLinkEmail.Limit {
+			res := testutil.NewRequest().
+				Post("/login/email").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+			assert.Equal(t, http.StatusAccepted, res.Code())
+		}
+
+		res := testutil.NewRequest().
+			Post("/login/email").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusTooManyRequests, res.Code())
+		assert.NotEmpty(t, res.Recorder.Header().Get("Retry-After"))
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, ratelimit.ErrTooManyRequests, body.Code)
+	})
+
+	t.Run("429 - too many requests from the IP", func(t *testing.T) {
+		e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+		rate := config.Default().RateLimit.MagicLinkIP
+
+		var res *testutil.CompletedRequest
+		for i := range rate.Limit + 1 {
+			rb, _ := json.Marshal(api.MagicLinkRequestBody{Email: uuid.New().String() + "@example.com"})
+
+			res = testutil.NewRequest().
+				Post("/login/email").
+				WithHeader("Content-Type", "application/json").
+				WithHeader("X-Real-IP", "10.0.0.1").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+			if i < rate.Limit {
+				assert.Equal(t, http.StatusAccepted, res.Code())
+			}
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, res.Code())
+	})
+
+	t.Run("202 - should hide error sending email", func(t *testing.T) {
+		e, _, mockJwtService, mockMailerService, _ := registerHandlers(t, conn, nil)
+		user := createUser(t, models.ActiveUserStatus)
+
+		mockJwtService.
+			On("CreateScopedTokenString", magicLinkScope, mock.Anything, mock.Anything).
+			Return("magicToken", nil)
+		mockMailerService.
+			On("SendMagicLinkEmail", mock.Anything, user.ExternalID, "magicToken").
+			Return(errors.New("error"))
+
+		rb, _ := json.Marshal(api.MagicLinkRequestBody{Email: user.ExternalID})
+
+		res := testutil.NewRequest().
+			Post("/login/email").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusAccepted, res.Code())
+		mockMailerService.AssertExpectations(t)
+	})
+}
+
+func Test_PostLoginEmailVerify(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	createLink := func(t *testing.T, status models.UserStatus) (*models.User, *models.MagicLink) {
+		t.Helper()
+
+		user := &models.User{
+			ExternalID: uuid.New().String() + "@example.com",
+			Login:      "guest",
+			AuthMethod: models.EmailAuthMethod,
+			Status:     status,
+		}
+		err := conn.Models().Users().Create(context.Background(), user)
+		assert.NoError(t, err)
+
+		link := &models.MagicLink{
+			UserID:    user.ID,
+			ExpiresAt: time.Now().Add(magicLinkTTL),
+		}
+		err = conn.Models().MagicLinks().Create(context.Background(), link)
+		assert.NoError(t, err)
+
+		return user, link
+	}
+
+	verify := func(t *testing.T, e http.Handler, token string) *testutil.CompletedRequest {
+		t.Helper()
+
+		rb, _ := json.Marshal(api.MagicLinkVerifyRequestBody{Token: token})
+
+		return testutil.NewRequest().
+			Post("/login/email/verify").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+	}
+
+	t.Run("200 - OK and link is single-use", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		user, link := createLink(t, models.InvitedUserStatus)
+
+		mockJwtService.
+			On("ParseScopedTokenString", magicLinkScope, "magicToken").
+			Return(link.ID.String(), nil)
+		mockJwtService.
+			On("CreateTokenString", user.ExternalID, mock.Anything).
+			Return("someToken", nil).
+			Once()
+
+		res := verify(t, e, "magicToken")
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.JWTToken
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, "someToken", body.Token)
+
+		dbUser, err := conn.Models().Users().GetByID(context.Background(), user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ActiveUserStatus, dbUser.Status)
+
+		res = verify(t, e, "magicToken")
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var errBody api.RequestError
+		err = res.UnmarshalBodyToObject(&errBody)
+		assert.NoError(t, err)
+		assert.Equal(t, errInvalidMagicLink, errBody.Code)
+	})
+
+	t.Run("400 - invalid token", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+
+		mockJwtService.
+			On("ParseScopedTokenString", magicLinkScope, "invalid").
+			Return("", errors.New("invalid token"))
+
+		res := verify(t, e, "invalid")
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errInvalidMagicLink, body.Code)
+	})
+
+	t.Run("403 - user was disabled after the link was sent", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		_, link := createLink(t, models.DisabledUserStatus)
+
+		mockJwtService.
+			On("ParseScopedTokenString", magicLinkScope, "magicToken").
+			Return(link.ID.String(), nil)
+
+		res := verify(t, e, "magicToken")
+		assert.Equal(t, http.StatusForbidden, res.Code())
+	})
+}
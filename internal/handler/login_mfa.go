@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// mfaScope of the JWT issued after the first factor, so it can't be used for auth.
+const mfaScope = "mfa"
+
+// errInvalidMFA is returned if the MFA token is invalid or the user is not allowed to sign in anymore.
+var errInvalidMFA = errors.New("invalid MFA token")
+
+// PostLoginWebauthnBegin handles the request to start the passkey assertion after the first factor.
+func (h *Handler) PostLoginWebauthnBegin(ctx echo.Context) error {
+	var req api.MFABeginRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.MfaToken == "" {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "MFA token field is required",
+		})
+	}
+
+	user, err := h.mfaUser(ctx, req.MfaToken)
+	if err != nil {
+		return h.mfaError(ctx, err)
+	}
+
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	ceremony, err := h.webAuthnService.BeginLogin(user, credentials)
+	if err != nil {
+		logError(ctx, errBeginWebAuthn, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errBeginWebAuthn,
+			Message: "Error starting passkey assertion",
+		})
+	}
+
+	return h.ceremonyResponse(ctx, ceremony.Options, ceremony.Session)
+}
+
+// PostLoginWebauthnFinish handles the request to exchange the passkey assertion for the JWT token.
+func (h *Handler) PostLoginWebauthnFinish(ctx echo.Context) error {
+	var req api.MFAWebAuthnFinishRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.MfaToken == "" || req.Session == "" || len(req.Credential) == 0 {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "MFA token, session and credential fields are required",
+		})
+	}
+
+	user, err := h.mfaUser(ctx, req.MfaToken)
+	if err != nil {
+		return h.mfaError(ctx, err)
+	}
+
+	if allowed, retryAfter := h.mfaUserLimiter.Allow(ctx.Request().Context(), strconv.Itoa(user.ID)); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
+	}
+
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	// Note: the credential is passed as is, so it is marshalled back to the format expected by the library
+	response, err := json.Marshal(req.Credential)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Invalid credential",
+		})
+	}
+
+	credential, err := h.webAuthnService.FinishLogin(ctx.Request().Context(), user, credentials, req.Session, response)
+	if errors.Is(err, webauthn.ErrUseChallenge) {
+		logError(ctx, errUseChallenge, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUseChallenge,
+			Message: "Error verifying passkey",
+		})
+	}
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errInvalidCredential,
+			Message: "Passkey assertion is invalid or expired",
+		})
+	}
+
+	if err := h.db.Models().WebAuthnCredentials().Update(ctx.Request().Context(), credential); err != nil {
+		logError(ctx, errUpdateCredential, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUpdateCredential,
+			Message: "Error updating passkey",
+		})
+	}
+
+	return h.tokenResponse(ctx, user)
+}
+
+// PostLoginRecovery handles the request to exchange the recovery code for the JWT token.
+func (h *Handler) PostLoginRecovery(ctx echo.Context) error {
+	var req api.MFARecoveryRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.MfaToken == "" || req.Code == "" {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "MFA token and code fields are required",
+		})
+	}
+
+	user, err := h.mfaUser(ctx, req.MfaToken)
+	if err != nil {
+		return h.mfaError(ctx, err)
+	}
+
+	if allowed, retryAfter := h.mfaUserLimiter.Allow(ctx.Request().Context(), strconv.Itoa(user.ID)); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
+	}
+
+	err = h.db.Models().RecoveryCodes().Use(ctx.Request().Context(), user.ID, hashRecoveryCode(req.Code))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errInvalidRecoveryCode,
+				Message: "Recovery code is invalid or already used",
+			})
+		}
+
+		logError(ctx, errUseRecoveryCode, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUseRecoveryCode,
+			Message: "Error while using recovery code",
+		})
+	}
+
+	return h.tokenResponse(ctx, user)
+}
+
+// loginResponse responds to the user that passed the first factor.
+// Users with passkeys get the MFA token, that can only be exchanged for the JWT token with the second factor.
+func (h *Handler) loginResponse(ctx echo.Context, user *models.User) error {
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	if len(credentials) == 0 {
+		return h.tokenResponse(ctx, user)
+	}
+
+	mfaToken, err := h.jwtService.CreateScopedTokenString(mfaScope, user.ExternalID, time.Now().Add(h.mfaTTL))
+	if err != nil {
+		logError(ctx, errCreateToken, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateToken,
+			Message: "Error while creating MFA token",
+		})
+	}
+
+	mfaRequired := true
+	return ctx.JSON(http.StatusOK, api.JWTToken{
+		Token:       mfaToken,
+		MfaRequired: &mfaRequired,
+	})
+}
+
+// tokenResponse responds with the JWT token for the authenticated user.
+func (h *Handler) tokenResponse(ctx echo.Context, user *models.User) error {
+	jwtToken, err := h.jwtService.CreateTokenString(user.ExternalID, time.Now().Add(h.sessionTTL))
+	if err != nil {
+		logError(ctx, errCreateToken, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateToken,
+			Message: "Error while creating JWT token",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, api.JWTToken{
+		Token: jwtToken,
+	})
+}
+
+// mfaUser returns the user that passed the first factor with the given MFA token.
+func (h *Handler) mfaUser(ctx echo.Context, mfaToken string) (*models.User, error) {
+	externalID, err := h.jwtService.ParseScopedTokenString(mfaScope, mfaToken)
+	if err != nil {
+		return nil, errInvalidMFA
+	}
+
+	user, err := h.db.Models().Users().GetByExternalID(ctx.Request().Context(), externalID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, errInvalidMFA
+		}
+
+		return nil, err
+	}
+
+	// Note: the user could be disabled after the first factor
+	if user.Status != models.ActiveUserStatus {
+		return nil, errInvalidMFA
+	}
+
+	return user, nil
+}
+
+// mfaError responds with the error returned from Handler.mfaUser.
+func (h *Handler) mfaError(ctx echo.Context, err error) error {
+	if errors.Is(err, errInvalidMFA) {
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
+			Code:    errInvalidMFAToken,
+			Message: "MFA token is invalid or expired",
+		})
+	}
+
+	logError(ctx, errGetUser, err)
+	return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+		Code:    errGetUser,
+		Message: "Error getting user",
+	})
+}
+
+// ceremonyResponse responds with the WebAuthn options for the client and the signed session.
+func (h *Handler) ceremonyResponse(ctx echo.Context, options json.RawMessage, session string) error {
+	var opts map[string]interface{}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		logError(ctx, errBeginWebAuthn, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errBeginWebAuthn,
+			Message: "Error encoding WebAuthn options",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, api.WebAuthnCeremonyResponse{
+		Options: opts,
+		Session: session,
+	})
+}
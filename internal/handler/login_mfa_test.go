@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/github"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	testwebauthn "github.com/samgozman/go-bloggy/testutils/test-webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+const mfaToken = "mfaToken"
+
+// createAdminWithPasskey creates an admin with a passkey registered by the software authenticator.
+func createAdminWithPasskey(t *testing.T, conn *db.Database, externalID string) (
+	*models.User,
+	*testwebauthn.Authenticator,
+) {
+	t.Helper()
+
+	admin := &models.User{
+		ExternalID: externalID,
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      uuid.New().String(),
+		Role:       models.AdminUserRole,
+	}
+	err := conn.Models().Users().Create(context.Background(), admin)
+	assert.NoError(t, err)
+
+	authenticator, err := testwebauthn.NewAuthenticator(testWebAuthnOrigin)
+	assert.NoError(t, err)
+
+	s := newTestWebAuthnService(t, conn)
+	ceremony, err := s.BeginRegistration(admin, nil)
+	assert.NoError(t, err)
+
+	response, err := authenticator.Create(ceremony.Options)
+	assert.NoError(t, err)
+
+	credential, err := s.FinishRegistration(context.Background(), admin, nil, ceremony.Session, response)
+	assert.NoError(t, err)
+
+	err = conn.Models().WebAuthnCredentials().Create(context.Background(), credential)
+	assert.NoError(t, err)
+
+	return admin, authenticator
+}
+
+func Test_LoginMFA(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	// beginLogin starts the assertion and returns the ceremony
+	beginLogin := func(t *testing.T, e http.Handler) api.WebAuthnCeremonyResponse {
+		t.Helper()
+
+		rb, _ := json.Marshal(api.MFABeginRequestBody{MfaToken: mfaToken})
+
+		res := testutil.NewRequest().
+			Post("/login/webauthn/begin").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.WebAuthnCeremonyResponse
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, body.Session)
+
+		return body
+	}
+
+	// finishLogin signs the ceremony options with the authenticator and sends the assertion
+	finishLogin := func(
+		t *testing.T,
+		e http.Handler,
+		authenticator *testwebauthn.Authenticator,
+		ceremony api.WebAuthnCeremonyResponse,
+	) *testutil.CompletedRequest {
+		t.Helper()
+
+		options, _ := json.Marshal(ceremony.Options)
+		response, err := authenticator.Get(options)
+		assert.NoError(t, err)
+
+		var credential map[string]interface{}
+		err = json.Unmarshal(response, &credential)
+		assert.NoError(t, err)
+
+		rb, _ := json.Marshal(api.MFAWebAuthnFinishRequestBody{
+			MfaToken:   mfaToken,
+			Session:    ceremony.Session,
+			Credential: credential,
+		})
+
+		return testutil.NewRequest().
+			Post("/login/webauthn/finish").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+	}
+
+	t.Run("PostLoginGithubAuthorize", func(t *testing.T) {
+		t.Run("200 - should require second factor for admin with passkey", func(t *testing.T) {
+			ghUser := &github.UserInfo{
+				ID:    rand.Int(), //nolint:gosec
+				Login: uuid.New().String(),
+			}
+			ghUserID := strconv.Itoa(ghUser.ID)
+			createAdminWithPasskey(t, conn, ghUserID)
+
+			e, mockGithubService, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockGithubService.On("ExchangeCodeForToken", mock.Anything, "123").Return("someToken", nil)
+			mockGithubService.On("GetUserInfo", mock.Anything, "someToken").Return(ghUser, nil)
+			mockJwtService.On("CreateScopedTokenString", mfaScope, ghUserID, mock.Anything).Return(mfaToken, nil)
+
+			rb, _ := json.Marshal(api.GitHubAuthRequestBody{Code: "123"})
+
+			res := testutil.NewRequest().
+				Post("/login/github/authorize").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+			mockJwtService.AssertNotCalled(t, "CreateTokenString", mock.Anything, mock.Anything)
+
+			var body api.JWTToken
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, mfaToken, body.Token)
+			assert.NotNil(t, body.MfaRequired)
+			assert.True(t, *body.MfaRequired)
+		})
+	})
+
+	t.Run("PostLoginWebauthn", func(t *testing.T) {
+		t.Run("200 - OK", func(t *testing.T) {
+			admin, authenticator := createAdminWithPasskey(t, conn, uuid.New().String())
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+			mockJwtService.On("CreateTokenString", admin.ExternalID, mock.Anything).Return("someToken", nil)
+
+			res := finishLogin(t, e, authenticator, beginLogin(t, e))
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			var body api.JWTToken
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, "someToken", body.Token)
+			assert.Nil(t, body.MfaRequired)
+
+			credentials, err := conn.Models().WebAuthnCredentials().FindByUserID(context.Background(), admin.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, authenticator.SignCount, credentials[0].SignCount)
+			assert.NotNil(t, credentials[0].LastUsedAt)
+		})
+
+		t.Run("400 - replayed session", func(t *testing.T) {
+			admin, authenticator := createAdminWithPasskey(t, conn, uuid.New().String())
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+			mockJwtService.On("CreateTokenString", admin.ExternalID, mock.Anything).Return("someToken", nil)
+
+			ceremony := beginLogin(t, e)
+			res := finishLogin(t, e, authenticator, ceremony)
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			res = finishLogin(t, e, authenticator, ceremony)
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errInvalidCredential, body.Code)
+		})
+
+		t.Run("400 - unknown passkey", func(t *testing.T) {
+			admin, _ := createAdminWithPasskey(t, conn, uuid.New().String())
+			another, err := testwebauthn.NewAuthenticator(testWebAuthnOrigin)
+			assert.NoError(t, err)
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+
+			res := finishLogin(t, e, another, beginLogin(t, e))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err = res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errInvalidCredential, body.Code)
+		})
+
+		t.Run("400 - validation error", func(t *testing.T) {
+			e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+			rb, _ := json.Marshal(api.MFAWebAuthnFinishRequestBody{MfaToken: mfaToken})
+
+			res := testutil.NewRequest().
+				Post("/login/webauthn/finish").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+		})
+
+		t.Run("401 - invalid MFA token", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return("", errors.New("invalid token"))
+
+			rb, _ := json.Marshal(api.MFABeginRequestBody{MfaToken: mfaToken})
+
+			res := testutil.NewRequest().
+				Post("/login/webauthn/begin").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errInvalidMFAToken, body.Code)
+		})
+
+		t.Run("401 - disabled user", func(t *testing.T) {
+			admin, _ := createAdminWithPasskey(t, conn, uuid.New().String())
+			admin.Status = models.DisabledUserStatus
+			err := conn.Models().Users().Update(context.Background(), admin)
+			assert.NoError(t, err)
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+
+			rb, _ := json.Marshal(api.MFABeginRequestBody{MfaToken: mfaToken})
+
+			res := testutil.NewRequest().
+				Post("/login/webauthn/begin").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code())
+		})
+	})
+
+	t.Run("PostLoginRecovery", func(t *testing.T) {
+		t.Run("200 - OK", func(t *testing.T) {
+			admin, _ := createAdminWithPasskey(t, conn, uuid.New().String())
+			err := conn.Models().RecoveryCodes().Replace(
+				context.Background(),
+				admin.ID,
+				[]string{hashRecoveryCode("abcd-efgh-ijkl-mnop")},
+			)
+			assert.NoError(t, err)
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+			mockJwtService.On("CreateTokenString", admin.ExternalID, mock.Anything).Return("someToken", nil)
+
+			rb, _ := json.Marshal(api.MFARecoveryRequestBody{MfaToken: mfaToken, Code: " ABCDEFGH-ijkl-mnop"})
+
+			res := testutil.NewRequest().
+				Post("/login/recovery").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			// Code can be used only once
+			res = testutil.NewRequest().
+				Post("/login/recovery").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err = res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errInvalidRecoveryCode, body.Code)
+		})
+
+		t.Run("429 - too many attempts for the user", func(t *testing.T) {
+			admin, _ := createAdminWithPasskey(t, conn, uuid.New().String())
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseScopedTokenString", mfaScope, mfaToken).Return(admin.ExternalID, nil)
+
+			rb, _ := json.Marshal(api.MFARecoveryRequestBody{MfaToken: mfaToken, Code: "invalid"})
+
+			for range config.Default().RateLimit.MFAUser.Limit {
+				res := testutil.NewRequest().
+					Post("/login/recovery").
+					WithHeader("Content-Type", "application/json").
+					WithBody(rb).
+					GoWithHTTPHandler(t, e)
+				assert.Equal(t, http.StatusBadRequest, res.Code())
+			}
+
+			res := testutil.NewRequest().
+				Post("/login/recovery").
+				WithHeader("Content-Type", "application/json").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusTooManyRequests, res.Code())
+			assert.NotEmpty(t, res.Recorder.Header().Get("Retry-After"))
+		})
+	})
+}
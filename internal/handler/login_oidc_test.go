@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/oidc"
+	mockOIDC "github.com/samgozman/go-bloggy/mocks/oidc"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	testoidc "github.com/samgozman/go-bloggy/testutils/test-oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetLoginProviderAuthorize(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		o := mockOIDC.NewMockServiceInterface(t)
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: o})
+
+		o.On("AuthCodeURL", mock.Anything, "gitlab").Return(&oidc.AuthRequest{
+			URL:          "https://gitlab.com/oauth/authorize?state=someState",
+			State:        "someState",
+			CodeVerifier: "someVerifier",
+		}, nil)
+
+		res := testutil.NewRequest().
+			Get("/login/gitlab/authorize").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.OIDCAuthURLResponse
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://gitlab.com/oauth/authorize?state=someState", body.AuthorizationUrl)
+		assert.Equal(t, "someState", body.State)
+		assert.Equal(t, "someVerifier", body.CodeVerifier)
+	})
+
+	t.Run("should return 404 for unknown provider", func(t *testing.T) {
+		o := mockOIDC.NewMockServiceInterface(t)
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: o})
+
+		o.On("AuthCodeURL", mock.Anything, "unknown").Return(nil, oidc.ErrUnknownProvider)
+
+		res := testutil.NewRequest().
+			Get("/login/unknown/authorize").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotFound, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errProviderNotFound, body.Code)
+	})
+
+	t.Run("should return 500 if provider is unavailable", func(t *testing.T) {
+		o := mockOIDC.NewMockServiceInterface(t)
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: o})
+
+		o.On("AuthCodeURL", mock.Anything, "gitlab").Return(nil, errors.Join(oidc.ErrDiscovery, errors.New("timeout")))
+
+		res := testutil.NewRequest().
+			Get("/login/gitlab/authorize").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusInternalServerError, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errProviderUnavailable, body.Code)
+	})
+}
+
+func Test_PostLoginProviderAuthorize(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	issuer, err := testoidc.NewIssuer("client-id", "client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer issuer.Close()
+
+	newService := func() *oidc.Service {
+		return oidc.NewService([]oidc.ProviderConfig{
+			{
+				Name:         "test",
+				IssuerURL:    issuer.URL(),
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURL:  "https://example.com/login/test/callback",
+				Scopes:       []string{"openid", "profile", "email"},
+			},
+		}, "state-secret")
+	}
+
+	// authorize goes through the full flow: gets authorization URL from the API,
+	// signs in the user in the fake issuer and returns the request body with the code.
+	authorize := func(t *testing.T, e http.Handler, subject string, claims map[string]any) []byte {
+		t.Helper()
+
+		res := testutil.NewRequest().
+			Get("/login/test/authorize").
+			GoWithHTTPHandler(t, e)
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var authURL api.OIDCAuthURLResponse
+		err := res.UnmarshalBodyToObject(&authURL)
+		assert.NoError(t, err)
+
+		code, err := issuer.Authorize(authURL.AuthorizationUrl, subject, claims)
+		assert.NoError(t, err)
+
+		rb, _ := json.Marshal(api.OIDCAuthRequestBody{
+			Code:         code,
+			State:        authURL.State,
+			CodeVerifier: authURL.CodeVerifier,
+		})
+
+		return rb
+	}
+
+	t.Run("OK for bootstrap admin", func(t *testing.T) {
+		subject := uuid.New().String()
+		externalID := "test:" + subject
+		e, m := newTestHandlers(t, handlerOptions{conn: conn, adminsIDs: []string{externalID}, oidc: newService()})
+		mockJwtService := m.jwt
+
+		mockJwtService.
+			On("CreateTokenString", externalID, mock.Anything).
+			Return("someToken", nil)
+
+		rb := authorize(t, e, subject, map[string]any{"preferred_username": "jdoe"})
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var body api.JWTToken
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, "someToken", body.Token)
+
+		dbUser, err := conn.Models().Users().GetByExternalID(context.Background(), externalID)
+		assert.NoError(t, err)
+		assert.Equal(t, "jdoe", dbUser.Login)
+		assert.Equal(t, models.OIDCAuthMethod, dbUser.AuthMethod)
+		assert.Equal(t, models.AdminUserRole, dbUser.Role)
+	})
+
+	t.Run("should activate invited user", func(t *testing.T) {
+		subject := uuid.New().String()
+		externalID := "test:" + subject
+		e, m := newTestHandlers(t, handlerOptions{conn: conn, oidc: newService()})
+		mockJwtService := m.jwt
+
+		err := conn.Models().Users().Create(context.Background(), &models.User{
+			ExternalID: externalID,
+			Login:      externalID,
+			AuthMethod: models.OIDCAuthMethod,
+			Status:     models.InvitedUserStatus,
+		})
+		assert.NoError(t, err)
+
+		mockJwtService.
+			On("CreateTokenString", externalID, mock.Anything).
+			Return("someToken", nil)
+
+		rb := authorize(t, e, subject, map[string]any{"email": "jdoe@example.com", "email_verified": true})
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		dbUser, err := conn.Models().Users().GetByExternalID(context.Background(), externalID)
+		assert.NoError(t, err)
+		assert.Equal(t, "jdoe@example.com", dbUser.Login)
+		assert.Equal(t, models.ActiveUserStatus, dbUser.Status)
+		assert.Equal(t, models.AuthorUserRole, dbUser.Role)
+	})
+
+	t.Run("should return 403 for unknown user", func(t *testing.T) {
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: newService()})
+
+		rb := authorize(t, e, uuid.New().String(), nil)
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusForbidden, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errForbidden, body.Code)
+	})
+
+	t.Run("should return 400 for token with invalid audience", func(t *testing.T) {
+		subject := uuid.New().String()
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, adminsIDs: []string{"test:" + subject}, oidc: newService()})
+
+		rb := authorize(t, e, subject, map[string]any{"aud": "another-client"})
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errExchangeCode, body.Code)
+	})
+
+	t.Run("should return 400 for tampered state", func(t *testing.T) {
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: newService()})
+
+		rb, _ := json.Marshal(api.OIDCAuthRequestBody{
+			Code:         "123",
+			State:        "eyJwIjoidGVzdCJ9.c2lnbmF0dXJl",
+			CodeVerifier: "verifier",
+		})
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errInvalidState, body.Code)
+	})
+
+	t.Run("should return 400 on empty body fields", func(t *testing.T) {
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, oidc: newService()})
+
+		rb, _ := json.Marshal(api.OIDCAuthRequestBody{Code: "123"})
+
+		res := testutil.NewRequest().
+			Post("/login/test/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errBodyValidation, body.Code)
+	})
+}
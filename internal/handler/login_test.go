@@ -8,6 +8,7 @@ import (
 	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/db/models"
 	"github.com/samgozman/go-bloggy/internal/github"
+	"github.com/samgozman/go-bloggy/internal/server/middlewares"
 	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -70,6 +71,8 @@ func Test_PostLoginGithubAuthorize(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, ghUserID, dbUser.ExternalID)
 		assert.Equal(t, ghUser.Login, dbUser.Login)
+		assert.Equal(t, models.AdminUserRole, dbUser.Role)
+		assert.Equal(t, models.ActiveUserStatus, dbUser.Status)
 	})
 
 	t.Run("should work for existing user", func(t *testing.T) {
@@ -123,6 +126,98 @@ func Test_PostLoginGithubAuthorize(t *testing.T) {
 		assert.Equal(t, "someToken", body.Token)
 	})
 
+	t.Run("should activate invited user", func(t *testing.T) {
+		e, mockGithubService, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+
+		ghUser := &github.UserInfo{
+			ID:    rand.Int(), //nolint:gosec
+			Login: uuid.New().String(),
+		}
+		ghUserID := strconv.Itoa(ghUser.ID)
+
+		err := conn.Models().Users().Create(context.Background(), &models.User{
+			ExternalID: ghUserID,
+			Login:      ghUserID,
+			AuthMethod: models.GitHubAuthMethod,
+			Role:       models.AuthorUserRole,
+			Status:     models.InvitedUserStatus,
+		})
+		assert.NoError(t, err)
+
+		mockGithubService.
+			On("ExchangeCodeForToken", mock.Anything, "123").
+			Return("someToken", nil)
+
+		mockGithubService.
+			On("GetUserInfo", mock.Anything, "someToken").
+			Return(ghUser, nil)
+
+		mockJwtService.
+			On("CreateTokenString", ghUserID, mock.Anything).
+			Return("someToken", nil)
+
+		rb, _ := json.Marshal(api.GitHubAuthRequestBody{
+			Code: "123",
+		})
+
+		res := testutil.NewRequest().
+			Post("/login/github/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		dbUser, err := conn.Models().Users().GetByExternalID(context.Background(), ghUserID)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ActiveUserStatus, dbUser.Status)
+		assert.Equal(t, models.AuthorUserRole, dbUser.Role)
+		assert.Equal(t, ghUser.Login, dbUser.Login)
+	})
+
+	t.Run("Auth forbidden for disabled user", func(t *testing.T) {
+		adminExternalID := rand.Int() //nolint:gosec
+		e, mockGithubService, _, _, _ := registerHandlers(t, conn, []string{strconv.Itoa(adminExternalID)})
+
+		// Note: disabled in DB wins over the bootstrap list
+		err := conn.Models().Users().Create(context.Background(), &models.User{
+			ExternalID: strconv.Itoa(adminExternalID),
+			Login:      "testUser",
+			AuthMethod: models.GitHubAuthMethod,
+			Role:       models.AdminUserRole,
+			Status:     models.DisabledUserStatus,
+		})
+		assert.NoError(t, err)
+
+		mockGithubService.
+			On("ExchangeCodeForToken", mock.Anything, "123").
+			Return("someToken", nil)
+
+		mockGithubService.
+			On("GetUserInfo", mock.Anything, "someToken").
+			Return(&github.UserInfo{
+				ID:    adminExternalID,
+				Login: "testUser",
+			}, nil)
+
+		rb, _ := json.Marshal(api.GitHubAuthRequestBody{
+			Code: "123",
+		})
+
+		res := testutil.NewRequest().
+			Post("/login/github/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusForbidden, res.Code())
+
+		var body api.RequestError
+		err = res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errForbidden, body.Code)
+	})
+
 	t.Run("ValidationError", func(t *testing.T) {
 		adminExternalID := rand.Int() //nolint:gosec
 		e, _, _, _, _ := registerHandlers(t, conn, []string{strconv.Itoa(adminExternalID)})
@@ -147,6 +242,25 @@ func Test_PostLoginGithubAuthorize(t *testing.T) {
 		assert.Equal(t, "Code field is required", body.Message)
 	})
 
+	t.Run("Not Found if GitHub is not configured", func(t *testing.T) {
+		e, _ := newTestHandlers(t, handlerOptions{conn: conn, noGitHub: true})
+
+		rb, _ := json.Marshal(api.GitHubAuthRequestBody{Code: "123"})
+
+		res := testutil.NewRequest().
+			Post("/login/github/authorize").
+			WithHeader("Content-Type", "application/json").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotFound, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errProviderNotFound, body.Code)
+	})
+
 	t.Run("GitHub ExchangeCodeForToken error", func(t *testing.T) {
 		adminExternalID := rand.Int() //nolint:gosec
 		e, mockGithubService, _, _, _ := registerHandlers(t, conn, []string{strconv.Itoa(adminExternalID)})
@@ -225,8 +339,8 @@ func Test_PostLoginGithubAuthorize(t *testing.T) {
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
 
-		assert.Equal(t, errRequestBodyBinding, body.Code)
-		assert.Equal(t, "Error binding request body", body.Message)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "#", *(*body.Errors)[0].Pointer)
 	})
 
 	t.Run("JWTService CreateTokenString error", func(t *testing.T) {
@@ -302,20 +416,42 @@ func Test_PostLoginGithubAuthorize(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, errForbidden, body.Code)
-		assert.Equal(t, "User is not an admin", body.Message)
+		assert.Equal(t, "User is not allowed to sign in", body.Message)
 	})
 }
 
 func Test_PostLoginRefresh(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	user := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testUser",
+	}
+	err := conn.Models().Users().Create(context.Background(), user)
+	assert.NoError(t, err)
+
+	disabled := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testDisabled",
+		Status:     models.DisabledUserStatus,
+	}
+	err = conn.Models().Users().Create(context.Background(), disabled)
+	assert.NoError(t, err)
+
 	t.Run("OK", func(t *testing.T) {
-		e, _, mockJwtService, _, _ := registerHandlers(t, nil, nil)
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
 
 		mockJwtService.
 			On("ParseTokenString", "token").
-			Return("123", nil)
+			Return(user.ExternalID, nil)
 
 		mockJwtService.
-			On("CreateTokenString", "123", mock.Anything).
+			On("CreateTokenString", user.ExternalID, mock.Anything).
 			Return("someToken", nil)
 
 		res := testutil.NewRequest().
@@ -370,15 +506,59 @@ func Test_PostLoginRefresh(t *testing.T) {
 		assert.Equal(t, "Invalid token", body.Message)
 	})
 
+	t.Run("Forbidden disabled user", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+
+		mockJwtService.
+			On("ParseTokenString", "token").
+			Return(disabled.ExternalID, nil)
+
+		res := testutil.NewRequest().
+			Post("/login/refresh").
+			WithJWSAuth("token").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusForbidden, res.Code())
+		mockJwtService.AssertNotCalled(t, "CreateTokenString", mock.Anything, mock.Anything)
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, errForbidden, body.Code)
+		assert.Equal(t, "User is disabled", body.Message)
+	})
+
+	t.Run("Unauthorized unknown user", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+
+		mockJwtService.
+			On("ParseTokenString", "token").
+			Return(uuid.New().String(), nil)
+
+		res := testutil.NewRequest().
+			Post("/login/refresh").
+			WithJWSAuth("token").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, errUnauthorized, body.Code)
+	})
+
 	t.Run("CreateTokenString error", func(t *testing.T) {
-		e, _, mockJwtService, _, _ := registerHandlers(t, nil, nil)
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
 
 		mockJwtService.
 			On("ParseTokenString", "token").
-			Return("123", nil)
+			Return(user.ExternalID, nil)
 
 		mockJwtService.
-			On("CreateTokenString", "123", mock.Anything).
+			On("CreateTokenString", user.ExternalID, mock.Anything).
 			Return("", assert.AnError)
 
 		res := testutil.NewRequest().
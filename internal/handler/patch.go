@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"strings"
+)
+
+// patchPost applies the JSON Merge Patch to the post as it's sent with PUT, so the result is updated the same way.
+func patchPost(post *models.Post, patch []byte) (api.PutPostRequest, error) {
+	current := api.PutPostRequest{
+		Title:       post.Title,
+		Description: post.Description,
+		Content:     post.Content,
+		Version:     post.Version,
+	}
+	if post.Keywords != "" {
+		keywords := strings.Split(post.Keywords, ",")
+		current.Keywords = &keywords
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return api.PutPostRequest{}, fmt.Errorf("error encoding post: %w", err)
+	}
+
+	doc, err = mergePatch(doc, patch)
+	if err != nil {
+		return api.PutPostRequest{}, err
+	}
+
+	var req api.PutPostRequest
+	if err := json.Unmarshal(doc, &req); err != nil {
+		return api.PutPostRequest{}, fmt.Errorf("error decoding patched post: %w", err)
+	}
+
+	return req, nil
+}
+
+// hasPatchVersion reports if the merge patch of the post has the version, that was read by the client.
+// The malformed patch is reported by patchPost.
+func hasPatchVersion(patch []byte) bool {
+	var p struct {
+		Version *int `json:"version"`
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return false
+	}
+
+	return p.Version != nil
+}
+
+// mergePatch applies the JSON Merge Patch (RFC 7396) to the doc: the objects are merged recursively,
+// the null removes the member and any other value, including the arrays, replaces the target.
+func mergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding patch: %w", err)
+	}
+
+	merged, err := json.Marshal(mergeValue(target, p))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding patched document: %w", err)
+	}
+
+	return merged, nil
+}
+
+// mergeValue merges the patch into the target, see mergePatch.
+func mergeValue(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// decodeJSON decodes the data, keeping the numbers as they are.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("invalid JSON: unexpected data after the value")
+	}
+
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_mergePatch(t *testing.T) {
+	// Note: the examples of RFC 7396, Appendix A
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"a":1,"e":null}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			got, err := mergePatch([]byte(tt.doc), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("invalid patch", func(t *testing.T) {
+		for _, patch := range []string{``, `{"a":`, `{} {}`} {
+			_, err := mergePatch([]byte(`{}`), []byte(patch))
+			assert.Error(t, err, patch)
+		}
+	})
+}
+
+func Test_patchPost(t *testing.T) {
+	post := &models.Post{
+		Title:       "Title",
+		Description: "Description",
+		Content:     "Content",
+		Keywords:    "go,openapi",
+		Version:     3,
+	}
+
+	t.Run("only given fields are changed", func(t *testing.T) {
+		req, err := patchPost(post, []byte(`{"keywords":["go"]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, api.PutPostRequest{
+			Title:       "Title",
+			Description: "Description",
+			Content:     "Content",
+			Keywords:    &[]string{"go"},
+			Version:     3,
+		}, req)
+	})
+
+	t.Run("null removes the keywords", func(t *testing.T) {
+		req, err := patchPost(post, []byte(`{"keywords":null,"version":2}`))
+		assert.NoError(t, err)
+		assert.Nil(t, req.Keywords)
+		assert.Equal(t, 2, req.Version)
+	})
+
+	t.Run("patch of another type", func(t *testing.T) {
+		_, err := patchPost(post, []byte(`["title"]`))
+		assert.Error(t, err)
+	})
+}
+
+func Test_hasPatchVersion(t *testing.T) {
+	assert.True(t, hasPatchVersion([]byte(`{"title":"Title","version":2}`)))
+	assert.False(t, hasPatchVersion([]byte(`{"title":"Title"}`)))
+	assert.False(t, hasPatchVersion([]byte(`{"version":null}`)))
+	assert.False(t, hasPatchVersion([]byte(`["version"]`)))
+}
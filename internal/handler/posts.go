@@ -6,11 +6,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/db/models"
-	mailer "github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/newsletter"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"io"
 	"net/http"
-	"regexp"
 	"strings"
-	"time"
 )
 
 func (h *Handler) PostPosts(ctx echo.Context) error {
@@ -22,7 +22,7 @@ func (h *Handler) PostPosts(ctx echo.Context) error {
 			errorMessage = fmt.Sprintf("%v", echoErr.Message)
 		}
 
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
 		})
@@ -34,7 +34,7 @@ func (h *Handler) PostPosts(ctx echo.Context) error {
 	}
 
 	if externalUserID == "" {
-		return ctx.JSON(http.StatusUnauthorized, api.RequestError{
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
 			Code:    errUnauthorized,
 			Message: "Unauthorized",
 		})
@@ -42,12 +42,16 @@ func (h *Handler) PostPosts(ctx echo.Context) error {
 
 	user, err := h.db.Models().Users().GetByExternalID(ctx.Request().Context(), externalUserID)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errGetUser,
 			Message: "Post author is not found",
 		})
 	}
 
+	if user.Status != models.ActiveUserStatus {
+		return h.userError(ctx, errUserNotAllowed)
+	}
+
 	var keywords string
 	if req.Keywords != nil && len(*req.Keywords) > 0 {
 		keywords = (*req.Keywords)[0]
@@ -68,24 +72,23 @@ func (h *Handler) PostPosts(ctx echo.Context) error {
 	if err := h.db.Models().Posts().Create(ctx.Request().Context(), &post); err != nil {
 		switch {
 		case errors.Is(err, models.ErrDuplicate):
-			return ctx.JSON(http.StatusConflict, api.RequestError{
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
 				Code:    errDuplicatePost,
 				Message: "Post with this URL slug already exists",
 			})
 		case errors.Is(err, models.ErrValidationFailed):
-			return ctx.JSON(http.StatusBadRequest, api.RequestError{
-				Code:    errValidationFailed,
-				Message: "Post validation failed",
-			})
+			return validationFailed(ctx, "Post validation failed", err)
 
 		default:
-			return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+			logError(ctx, errCreatePost, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 				Code:    errCreatePost,
 				Message: "Error creating post",
 			})
 		}
 	}
 
+	ctx.Response().Header().Set(headerETag, postETag(&post))
 	return ctx.JSON(http.StatusCreated, api.PostResponse{
 		Id:          post.ID,
 		Title:       post.Title,
@@ -96,25 +99,23 @@ func (h *Handler) PostPosts(ctx echo.Context) error {
 		ReadingTime: post.ReadingTime,
 		CreatedAt:   post.CreatedAt,
 		UpdatedAt:   post.UpdatedAt,
+		Version:     post.Version,
 	})
 }
 
 func (h *Handler) GetPostsSlug(ctx echo.Context, slug string) error {
-	if !regexp.MustCompile(`^[a-z0-9-]+$`).MatchString(slug) {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errParamValidation,
-			Message: "Slug is empty",
-		})
-	}
-
 	post, err := h.db.Models().Posts().GetBySlug(ctx.Request().Context(), slug)
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, api.RequestError{
+		return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
 			Code:    errPostNotFound,
 			Message: "Post not found",
 		})
 	}
 
+	if notModified(ctx, postETag(post), post.UpdatedAt) {
+		return ctx.NoContent(http.StatusNotModified)
+	}
+
 	var keywords []string
 	if post.Keywords != "" {
 		keywords = strings.Split(post.Keywords, ",")
@@ -132,77 +133,178 @@ func (h *Handler) GetPostsSlug(ctx echo.Context, slug string) error {
 		ReadingTime: post.ReadingTime,
 		CreatedAt:   post.CreatedAt,
 		UpdatedAt:   post.UpdatedAt,
+		Version:     post.Version,
 	})
 }
 
 func (h *Handler) GetPosts(ctx echo.Context, params api.GetPostsParams) error {
-	limit := 10
-	page := 1
+	var filter models.PostFilter
+	if params.Keyword != nil {
+		filter.Keyword = *params.Keyword
+	}
+
+	if params.From != nil {
+		filter.From = *params.From
+	}
+
+	if params.To != nil {
+		filter.To = *params.To
+	}
+
+	if params.Author != nil {
+		filter.Author = *params.Author
+	}
+
+	filter.Sent = params.Sent
+
+	return h.listPosts(ctx, filter, pageParams{
+		Page:   params.Page,
+		Limit:  params.Limit,
+		Cursor: params.Cursor,
+		Sort:   (*string)(params.Sort),
+	})
+}
+
+// pageParams are the query params of the page of the lists of posts.
+type pageParams struct {
+	Page   *int
+	Limit  *int
+	Cursor *string
+	Sort   *string
+}
+
+// listPosts responds with the page of the posts matching the filter, with the cursors of the adjacent pages.
+func (h *Handler) listPosts(ctx echo.Context, filter models.PostFilter, params pageParams) error {
+	// Note: the params are validated by the spec, only the defaults are set here
+	q := &models.PostQuery{
+		Filter: filter,
+		Sort:   models.PostSortNewest,
+		Limit:  20,
+		Page:   1,
+	}
 	if params.Limit != nil {
-		limit = *params.Limit
+		q.Limit = *params.Limit
 	}
 
 	if params.Page != nil {
-		page = *params.Page
+		q.Page = *params.Page
 	}
 
-	if limit < 1 || limit > 25 {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errParamValidation,
-			Message: "Limit must be between 1 and 25",
-		})
+	if params.Sort != nil {
+		q.Sort = models.PostSort(*params.Sort)
 	}
 
-	if page < 1 {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errParamValidation,
-			Message: "Page must be greater than 0",
+	if params.Cursor != nil {
+		if params.Page != nil {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errParamValidation,
+				Message: "Page and cursor can't be used together",
+			})
+		}
+
+		c, err := decodeCursor(*params.Cursor)
+		if err != nil {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errInvalidCursor,
+				Message: "Invalid cursor, use the cursor of the previous response as is",
+			})
+		}
+		q.Cursor = c
+	}
+
+	if written, err := h.postsNotModified(ctx); written {
+		return err
+	}
+
+	page, err := h.db.Models().Posts().FindPage(ctx.Request().Context(), q)
+	if err != nil {
+		logError(ctx, errGetPosts, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetPosts,
+			Message: "Error getting posts",
 		})
 	}
 
+	postsItems := make([]api.PostsListItem, 0, len(page.Posts))
+	for _, post := range page.Posts {
+		postsItems = append(postsItems, postsListItem(post))
+	}
+
+	res := api.PostsListResponse{
+		Posts: postsItems,
+		Total: int(page.Total),
+	}
+	if page.Next != nil {
+		next := encodeCursor(page.Next)
+		res.Next = &next
+	}
+	if page.Prev != nil {
+		prev := encodeCursor(page.Prev)
+		res.Prev = &prev
+	}
+	if links := pageLinks(ctx.Request().URL, page); links != "" {
+		ctx.Response().Header().Set(headerLink, links)
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// postsListItem returns the item of the lists of posts.
+func postsListItem(post *models.Post) api.PostsListItem {
+	var keywords []string
+	if post.Keywords != "" {
+		keywords = strings.Split(post.Keywords, ",")
+	} else {
+		keywords = []string{}
+	}
+
+	return api.PostsListItem{
+		Title:               post.Title,
+		Slug:                post.Slug,
+		Description:         post.Description,
+		Keywords:            &keywords,
+		ReadingTime:         post.ReadingTime,
+		CreatedAt:           post.CreatedAt,
+		SentToSubscribersAt: post.SentToSubscribersAt,
+	}
+}
+
+// postsNotModified sets the validators of the lists of posts and responds with 304 Not Modified,
+// if the client has the same version, or with the error. It returns true if the response is written.
+//
+// Note: the validators are checked before the posts are queried, so the unchanged list is not read at all.
+// The validators are the same for any list, as they are changed on any change of the posts.
+func (h *Handler) postsNotModified(ctx echo.Context) (bool, error) {
 	count, err := h.db.Models().Posts().Count(ctx.Request().Context())
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errGetPostsCount, err)
+		return true, problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errGetPostsCount,
 			Message: "Error getting posts",
 		})
 	}
 
-	posts, err := h.db.Models().Posts().FindAll(ctx.Request().Context(), page, limit)
+	lastUpdatedAt, err := h.db.Models().Posts().LastUpdatedAt(ctx.Request().Context())
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
-			Code:    errGetPosts,
+		logError(ctx, errGetPostsLastUpdate, err)
+		return true, problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetPostsLastUpdate,
 			Message: "Error getting posts",
 		})
 	}
 
-	postsItems := make([]api.PostsListItem, 0, len(posts))
-	for _, post := range posts {
-		var keywords []string
-		if post.Keywords != "" {
-			keywords = strings.Split(post.Keywords, ",")
-		} else {
-			keywords = []string{}
-		}
-
-		postsItems = append(postsItems, api.PostsListItem{
-			Title:               post.Title,
-			Slug:                post.Slug,
-			Description:         post.Description,
-			Keywords:            &keywords,
-			ReadingTime:         post.ReadingTime,
-			CreatedAt:           post.CreatedAt,
-			SentToSubscribersAt: post.SentToSubscribersAt,
-		})
+	if notModified(ctx, postsETag(lastUpdatedAt, count), lastUpdatedAt) {
+		return true, ctx.NoContent(http.StatusNotModified)
 	}
 
-	return ctx.JSON(http.StatusOK, api.PostsListResponse{
-		Posts: postsItems,
-		Total: int(count),
-	})
+	return false, nil
 }
 
 func (h *Handler) PutPostsSlug(ctx echo.Context, slug string) error {
+	if _, err := h.currentUser(ctx); err != nil {
+		return h.userError(ctx, err)
+	}
+
 	var req api.PutPostRequest
 	if err := ctx.Bind(&req); err != nil {
 		var errorMessage string
@@ -211,7 +313,7 @@ func (h *Handler) PutPostsSlug(ctx echo.Context, slug string) error {
 			errorMessage = fmt.Sprintf("%v", echoErr.Message)
 		}
 
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
 		})
@@ -219,12 +321,75 @@ func (h *Handler) PutPostsSlug(ctx echo.Context, slug string) error {
 
 	post, err := h.db.Models().Posts().GetBySlug(ctx.Request().Context(), slug)
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, api.RequestError{
+		return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
 			Code:    errPostNotFound,
 			Message: "Post not found",
 		})
 	}
 
+	if preconditionFailed(ctx, postETag(post)) {
+		return problem.Respond(ctx, http.StatusPreconditionFailed, api.RequestError{
+			Code:           errPostChanged,
+			Message:        "Post was changed since it was read, reload it and try again",
+			CurrentVersion: &post.Version,
+		})
+	}
+
+	return h.updatePost(ctx, post, req)
+}
+
+func (h *Handler) PatchPostsSlug(ctx echo.Context, slug string) error {
+	if _, err := h.currentUser(ctx); err != nil {
+		return h.userError(ctx, err)
+	}
+
+	patch, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: fmt.Sprintf("Error binding request body: %v", err),
+		})
+	}
+
+	post, err := h.db.Models().Posts().GetBySlug(ctx.Request().Context(), slug)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+			Code:    errPostNotFound,
+			Message: "Post not found",
+		})
+	}
+
+	// Note: the patch is applied to the current post, so without the version it would overwrite the concurrent changes
+	if ctx.Request().Header.Get(headerIfMatch) == "" && !hasPatchVersion(patch) {
+		return problem.Respond(ctx, http.StatusPreconditionRequired, api.RequestError{
+			Code:           errPreconditionRequired,
+			Message:        "If-Match header or version is required to not overwrite the concurrent changes",
+			CurrentVersion: &post.Version,
+		})
+	}
+
+	if preconditionFailed(ctx, postETag(post)) {
+		return problem.Respond(ctx, http.StatusPreconditionFailed, api.RequestError{
+			Code:           errPostChanged,
+			Message:        "Post was changed since it was read, reload it and try again",
+			CurrentVersion: &post.Version,
+		})
+	}
+
+	req, err := patchPost(post, patch)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: fmt.Sprintf("Error binding request body: %v", err),
+		})
+	}
+
+	return h.updatePost(ctx, post, req)
+}
+
+// updatePost updates the post with the req of PUT or the patched one of PATCH and responds with the updated post.
+func (h *Handler) updatePost(ctx echo.Context, post *models.Post, req api.PutPostRequest) error {
+	post.Version = req.Version
 	post.Title = req.Title
 	post.Description = req.Description
 	post.Content = req.Content
@@ -242,24 +407,29 @@ func (h *Handler) PutPostsSlug(ctx echo.Context, slug string) error {
 	if err := h.db.Models().Posts().Update(ctx.Request().Context(), post); err != nil {
 		switch {
 		case errors.Is(err, models.ErrDuplicate):
-			return ctx.JSON(http.StatusConflict, api.RequestError{
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
 				Code:    errDuplicatePost,
 				Message: "Post with this URL slug already exists",
 			})
-		case errors.Is(err, models.ErrValidationFailed):
-			return ctx.JSON(http.StatusBadRequest, api.RequestError{
-				Code:    errValidationFailed,
-				Message: "Post validation failed",
+		case errors.Is(err, models.ErrConflict):
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
+				Code:           errPostChanged,
+				Message:        "Post was changed since it was read, reload it and try again",
+				CurrentVersion: &post.Version,
 			})
+		case errors.Is(err, models.ErrValidationFailed):
+			return validationFailed(ctx, "Post validation failed", err)
 
 		default:
-			return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+			logError(ctx, errUpdatePost, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 				Code:    errUpdatePost,
 				Message: "Error updating post",
 			})
 		}
 	}
 
+	ctx.Response().Header().Set(headerETag, postETag(post))
 	return ctx.JSON(http.StatusOK, api.PostResponse{
 		Id:          post.ID,
 		Title:       post.Title,
@@ -270,73 +440,55 @@ func (h *Handler) PutPostsSlug(ctx echo.Context, slug string) error {
 		ReadingTime: post.ReadingTime,
 		CreatedAt:   post.CreatedAt,
 		UpdatedAt:   post.UpdatedAt,
+		Version:     post.Version,
 	})
 }
 
 func (h *Handler) PostPostsSlugSendEmail(ctx echo.Context, slug string) error {
-	post, err := h.db.Models().Posts().GetBySlug(ctx.Request().Context(), slug)
-	if err != nil {
-		return ctx.JSON(http.StatusNotFound, api.RequestError{
-			Code:    errPostNotFound,
-			Message: "Post not found",
-		})
-	}
-
-	// check if post was already sent to subscribers
-	if !post.SentToSubscribersAt.IsZero() {
-		return ctx.JSON(http.StatusConflict, api.RequestError{
-			Code:    errPostAlreadySent,
-			Message: "Post was already sent to subscribers. This can be done only once.",
-		})
-	}
-
-	// Get subscribers
-	subs, err := h.db.Models().Subscribers().GetConfirmed(ctx.Request().Context())
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
-			Code:    errGetSubscription,
-			Message: "Error getting subscribers",
-		})
-	}
-
-	if len(subs) == 0 {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errGetSubscription,
-			Message: "No subscribers to send the post to.",
-		})
-	}
-
-	// Transform subscribers
-	mailerSubs := make([]*mailer.Subscriber, 0, len(subs))
-	for _, sub := range subs {
-		mailerSubs = append(mailerSubs, &mailer.Subscriber{
-			Email: sub.Email,
-			ID:    sub.ID.String(),
-		})
-	}
-
-	// Send email to subscribers
-	err = h.mailerService.SendPostEmail(&mailer.PostEmailSend{
-		To:          mailerSubs,
-		Title:       post.Title,
-		Description: post.Description,
-		Slug:        post.Slug,
-	})
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
-			Code:    errSendPostEmail,
-			Message: "Error sending post email",
-		})
+	// Note: the requests with the API keys of the automation are not made by the users
+	if ctx.Get("externalUserID") != nil {
+		if _, err := h.currentUser(ctx); err != nil {
+			return h.userError(ctx, err)
+		}
 	}
 
-	// Update post
-	post.SentToSubscribersAt = time.Now()
-	err = h.db.Models().Posts().Update(ctx.Request().Context(), post)
+	_, err := h.newsletter.SendPost(ctx.Request().Context(), slug)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
-			Code:    errUpdatePost,
-			Message: "Error updating post",
-		})
+		switch {
+		case errors.Is(err, newsletter.ErrPostNotFound):
+			return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+				Code:    errPostNotFound,
+				Message: "Post not found",
+			})
+		case errors.Is(err, newsletter.ErrPostAlreadySent):
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
+				Code:    errPostAlreadySent,
+				Message: "Post was already sent to subscribers. This can be done only once.",
+			})
+		case errors.Is(err, newsletter.ErrGetSubscribers):
+			logError(ctx, errGetSubscription, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errGetSubscription,
+				Message: "Error getting subscribers",
+			})
+		case errors.Is(err, newsletter.ErrNoSubscribers):
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+				Code:    errGetSubscription,
+				Message: "No subscribers to send the post to.",
+			})
+		case errors.Is(err, newsletter.ErrSendPostEmail):
+			logError(ctx, errSendPostEmail, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errSendPostEmail,
+				Message: "Error sending post email",
+			})
+		default:
+			logError(ctx, errUpdatePost, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errUpdatePost,
+				Message: "Error updating post",
+			})
+		}
 	}
 
 	return ctx.NoContent(http.StatusCreated)
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
+	"time"
+)
+
+func (h *Handler) GetPostsArchive(ctx echo.Context) error {
+	if written, err := h.postsNotModified(ctx); written {
+		return err
+	}
+
+	months, err := h.db.Models().Posts().Archive(ctx.Request().Context())
+	if err != nil {
+		logError(ctx, errGetPostsArchive, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetPostsArchive,
+			Message: "Error getting posts archive",
+		})
+	}
+
+	res := api.PostsArchiveResponse{
+		Months: make([]api.PostsArchiveMonth, 0, len(months)),
+	}
+	for _, m := range months {
+		res.Months = append(res.Months, api.PostsArchiveMonth{
+			Year:  m.Year,
+			Month: m.Month,
+			Count: int(m.Count),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) GetPostsArchiveYearMonth(
+	ctx echo.Context,
+	year, month int,
+	params api.GetPostsArchiveYearMonthParams,
+) error {
+	// Note: the year and the month are validated by the spec, the month is in UTC as in the archive
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	return h.listPosts(ctx, models.PostFilter{From: from, To: from.AddDate(0, 1, 0)}, pageParams{
+		Page:   params.Page,
+		Limit:  params.Limit,
+		Cursor: params.Cursor,
+		Sort:   (*string)(params.Sort),
+	})
+}
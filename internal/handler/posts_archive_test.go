@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/server/middlewares"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandler_GetPostsArchive(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	// create user for test
+	user := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testUser",
+	}
+	err := conn.Models().Users().Upsert(context.Background(), user)
+	assert.NoError(t, err)
+
+	e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+	t.Run("200 - OK - no posts", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var archive api.PostsArchiveResponse
+		err := res.UnmarshalBodyToObject(&archive)
+		assert.NoError(t, err)
+		assert.Empty(t, archive.Months)
+	})
+
+	// create posts for test
+	posts := make([]*models.Post, 0, 3)
+	for _, createdAt := range []time.Time{
+		time.Date(2024, 7, 31, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 8, 20, 12, 0, 0, 0, time.UTC),
+	} {
+		post := &models.Post{
+			UserID:      user.ID,
+			Title:       "Test Title",
+			Slug:        uuid.New().String(),
+			Content:     "Test Content",
+			Description: "Test Description",
+			CreatedAt:   createdAt,
+		}
+		err = conn.Models().Posts().Create(context.Background(), post)
+		assert.NoError(t, err)
+		posts = append(posts, post)
+	}
+
+	t.Run("200 - OK", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		assert.NotEmpty(t, res.Recorder.Header().Get(headerETag))
+
+		var archive api.PostsArchiveResponse
+		err := res.UnmarshalBodyToObject(&archive)
+		assert.NoError(t, err)
+		assert.Equal(t, []api.PostsArchiveMonth{
+			{Year: 2024, Month: 8, Count: 2},
+			{Year: 2024, Month: 7, Count: 1},
+		}, archive.Months)
+	})
+
+	t.Run("304 - Not Modified", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive").
+			GoWithHTTPHandler(t, e)
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"/archive").
+			WithHeader(headerIfNoneMatch, res.Recorder.Header().Get(headerETag)).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotModified, res.Code())
+	})
+
+	t.Run("200 - OK - posts of the month", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive/2024/8").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var postsRes api.PostsListResponse
+		err := res.UnmarshalBodyToObject(&postsRes)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, postsRes.Total)
+		if assert.Len(t, postsRes.Posts, 2) {
+			assert.Equal(t, posts[2].Slug, postsRes.Posts[0].Slug)
+			assert.Equal(t, posts[1].Slug, postsRes.Posts[1].Slug)
+		}
+	})
+
+	t.Run("200 - OK - posts of the month with cursor", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive/2024/8?limit=1&sort=oldest").
+			GoWithHTTPHandler(t, e)
+
+		var first api.PostsListResponse
+		err := res.UnmarshalBodyToObject(&first)
+		assert.NoError(t, err)
+		assert.Equal(t, posts[1].Slug, first.Posts[0].Slug)
+		assert.Contains(t, res.Recorder.Header().Get(headerLink), "</posts/archive/2024/8?cursor=")
+		if !assert.NotNil(t, first.Next) {
+			return
+		}
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"/archive/2024/8?limit=1&sort=oldest&cursor="+*first.Next).
+			GoWithHTTPHandler(t, e)
+
+		var second api.PostsListResponse
+		err = res.UnmarshalBodyToObject(&second)
+		assert.NoError(t, err)
+		assert.Equal(t, posts[2].Slug, second.Posts[0].Slug)
+		assert.Nil(t, second.Next)
+	})
+
+	t.Run("200 - OK - no posts in the month", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive/2024/9").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var postsRes api.PostsListResponse
+		err := res.UnmarshalBodyToObject(&postsRes)
+		assert.NoError(t, err)
+		assert.Zero(t, postsRes.Total)
+		assert.Empty(t, postsRes.Posts)
+	})
+
+	t.Run("400 - ErrRequestValidation - month", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/archive/2024/13").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "month", *(*body.Errors)[0].Parameter)
+	})
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
+)
+
+func (h *Handler) GetPostsSlugRelated(ctx echo.Context, slug string) error {
+	if _, err := h.db.Models().Posts().GetBySlug(ctx.Request().Context(), slug); err != nil {
+		return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+			Code:    errPostNotFound,
+			Message: "Post not found",
+		})
+	}
+
+	// Note: the related posts are changed only with the posts, so the validators of the lists are used
+	if written, err := h.postsNotModified(ctx); written {
+		return err
+	}
+
+	posts, err := h.db.Models().Posts().Related(ctx.Request().Context(), slug, h.relatedPosts)
+	if err != nil {
+		logError(ctx, errGetPostsRelated, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetPostsRelated,
+			Message: "Error getting related posts",
+		})
+	}
+
+	res := api.PostsRelatedResponse{
+		Posts: make([]api.PostsListItem, 0, len(posts)),
+	}
+	for _, post := range posts {
+		res.Posts = append(res.Posts, postsListItem(post))
+	}
+
+	return ctx.JSON(http.StatusOK, res)
+}
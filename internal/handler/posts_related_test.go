@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandler_GetPostsSlugRelated(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	// create user for test
+	user := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testUser",
+	}
+	err := conn.Models().Users().Upsert(context.Background(), user)
+	assert.NoError(t, err)
+
+	e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+	// create posts for test, the default number of the related posts is 3
+	for _, post := range []*models.Post{
+		{Slug: "source", Keywords: "go,openapi"},
+		{Slug: "two-keywords", Keywords: "openapi,go"},
+		{Slug: "one-keyword", Keywords: "go"},
+		{Slug: "another-keyword", Keywords: "openapi"},
+		{Slug: "no-keywords", Keywords: "rust"},
+	} {
+		post.UserID = user.ID
+		post.Title = uuid.New().String()
+		post.Description = uuid.New().String()
+		post.Content = "Test Content"
+		err = conn.Models().Posts().Create(context.Background(), post)
+		assert.NoError(t, err)
+	}
+
+	t.Run("200 - OK", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/source/related").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		assert.NotEmpty(t, res.Recorder.Header().Get(headerETag))
+
+		var related api.PostsRelatedResponse
+		err := res.UnmarshalBodyToObject(&related)
+		assert.NoError(t, err)
+		assert.Len(t, related.Posts, 3)
+		assert.Equal(t, "two-keywords", related.Posts[0].Slug)
+		assert.Equal(t, []string{"openapi", "go"}, *related.Posts[0].Keywords)
+		assert.NotContains(t, []string{related.Posts[1].Slug, related.Posts[2].Slug}, "no-keywords")
+	})
+
+	t.Run("200 - OK - no related posts", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/no-keywords/related").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var related api.PostsRelatedResponse
+		err := res.UnmarshalBodyToObject(&related)
+		assert.NoError(t, err)
+		assert.Empty(t, related.Posts)
+	})
+
+	t.Run("304 - Not Modified", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/source/related").
+			GoWithHTTPHandler(t, e)
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"/source/related").
+			WithHeader(headerIfNoneMatch, res.Recorder.Header().Get(headerETag)).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotModified, res.Code())
+	})
+
+	t.Run("404 - Not Found", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/unknown/related").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotFound, res.Code())
+	})
+}
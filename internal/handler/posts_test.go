@@ -9,10 +9,12 @@ import (
 	"github.com/oapi-codegen/testutil"
 	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/server/middlewares"
 	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
+	"net/url"
 	"strconv"
 	"testing"
 	"time"
@@ -72,6 +74,7 @@ func Test_PostPosts(t *testing.T) {
 		assert.NotEmpty(t, post.Id)
 		assert.NotEmpty(t, post.CreatedAt)
 		assert.NotEmpty(t, post.UpdatedAt)
+		assert.Equal(t, 1, post.Version)
 
 		// check that post is in the database
 		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), req.Slug)
@@ -85,7 +88,7 @@ func Test_PostPosts(t *testing.T) {
 		assert.NotEmpty(t, postFromDB.UpdatedAt)
 	})
 
-	t.Run("400 - errRequestBodyBinding - ErrUnsupportedMediaType", func(t *testing.T) {
+	t.Run("400 - ErrRequestValidation - no Content-Type", func(t *testing.T) {
 		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
 		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
 
@@ -111,8 +114,8 @@ func Test_PostPosts(t *testing.T) {
 		var body api.RequestError
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
-		assert.Equal(t, errRequestBodyBinding, body.Code)
-		assert.Equal(t, body.Message, fmt.Sprintf("Error binding request body: %v", echo.ErrUnsupportedMediaType.Message))
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "#", *(*body.Errors)[0].Pointer)
 	})
 
 	t.Run("400 - errGetUser", func(t *testing.T) {
@@ -213,6 +216,8 @@ func Test_PostPosts(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, errValidationFailed, body.Code)
 		assert.Equal(t, "Post validation failed", body.Message)
+		assert.Equal(t, "#/keywords", *(*body.Errors)[0].Pointer)
+		assert.Equal(t, models.ErrPostWrongKeywordsString.Error(), *(*body.Errors)[0].Code)
 	})
 }
 
@@ -301,6 +306,26 @@ func TestHandler_GetPostsSlug(t *testing.T) {
 		assert.NotEmpty(t, postRes.UpdatedAt)
 	})
 
+	t.Run("304 - Not Modified", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"/test-slug").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		etag := res.Recorder.Header().Get(headerETag)
+		assert.NotEmpty(t, etag)
+		assert.NotEmpty(t, res.Recorder.Header().Get(echo.HeaderLastModified))
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"/test-slug").
+			WithHeader(headerIfNoneMatch, etag).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotModified, res.Code())
+		assert.Equal(t, etag, res.Recorder.Header().Get(headerETag))
+		assert.Empty(t, res.Recorder.Body.String())
+	})
+
 	t.Run("404 - Not Found", func(t *testing.T) {
 		res := testutil.NewRequest().
 			Get(basePostsPath+"/not-found-slug").
@@ -315,7 +340,7 @@ func TestHandler_GetPostsSlug(t *testing.T) {
 		assert.Equal(t, "Post not found", body.Message)
 	})
 
-	t.Run("400 - errParamValidation", func(t *testing.T) {
+	t.Run("400 - ErrRequestValidation", func(t *testing.T) {
 		res := testutil.NewRequest().
 			Get(basePostsPath+"/&kek*").
 			GoWithHTTPHandler(t, e)
@@ -325,8 +350,8 @@ func TestHandler_GetPostsSlug(t *testing.T) {
 		var body api.RequestError
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
-		assert.Equal(t, errParamValidation, body.Code)
-		assert.Equal(t, "Slug is empty", body.Message)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "slug", *(*body.Errors)[0].Parameter)
 	})
 }
 
@@ -388,6 +413,32 @@ func TestHandler_GetPosts(t *testing.T) {
 		assert.Len(t, postsRes.Posts, 2)
 	})
 
+	t.Run("304 - Not Modified", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		etag := res.Recorder.Header().Get(headerETag)
+		lastModified := res.Recorder.Header().Get(echo.HeaderLastModified)
+		assert.NotEmpty(t, etag)
+
+		res = testutil.NewRequest().
+			Get(basePostsPath).
+			WithHeader(headerIfNoneMatch, etag).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotModified, res.Code())
+		assert.Empty(t, res.Recorder.Body.String())
+
+		res = testutil.NewRequest().
+			Get(basePostsPath).
+			WithHeader(echo.HeaderIfModifiedSince, lastModified).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusNotModified, res.Code())
+	})
+
 	t.Run("OK - with limit and offset", func(t *testing.T) {
 		res := testutil.NewRequest().
 			Get(basePostsPath+"?limit=1&page=1").
@@ -425,9 +476,104 @@ func TestHandler_GetPosts(t *testing.T) {
 		assert.Len(t, postsRes.Posts, 0)
 	})
 
-	t.Run("400 - errParamValidation - limit", func(t *testing.T) {
+	t.Run("OK - with cursors", func(t *testing.T) {
 		res := testutil.NewRequest().
-			Get(basePostsPath+"?limit=0").
+			Get(basePostsPath+"?limit=1").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var first api.PostsListResponse
+		err := res.UnmarshalBodyToObject(&first)
+		assert.NoError(t, err)
+		assert.Equal(t, posts[1].Slug, first.Posts[0].Slug)
+		assert.Nil(t, first.Prev)
+		if !assert.NotNil(t, first.Next) {
+			return
+		}
+		assert.Equal(t,
+			fmt.Sprintf(`<%s?cursor=%s&limit=1>; rel="next"`, basePostsPath, *first.Next),
+			res.Recorder.Header().Get(headerLink),
+		)
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"?limit=1&cursor="+*first.Next).
+			GoWithHTTPHandler(t, e)
+
+		var second api.PostsListResponse
+		err = res.UnmarshalBodyToObject(&second)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, second.Total)
+		assert.Equal(t, posts[0].Slug, second.Posts[0].Slug)
+		assert.Nil(t, second.Next)
+		if !assert.NotNil(t, second.Prev) {
+			return
+		}
+		assert.Contains(t, res.Recorder.Header().Get(headerLink), `rel="prev"`)
+
+		res = testutil.NewRequest().
+			Get(basePostsPath+"?limit=1&cursor="+*second.Prev).
+			GoWithHTTPHandler(t, e)
+
+		var prev api.PostsListResponse
+		err = res.UnmarshalBodyToObject(&prev)
+		assert.NoError(t, err)
+		assert.Equal(t, posts[1].Slug, prev.Posts[0].Slug)
+		assert.Nil(t, prev.Prev)
+		assert.NotNil(t, prev.Next)
+	})
+
+	t.Run("OK - with filters", func(t *testing.T) {
+		tests := []struct {
+			query string
+			slugs []string
+		}{
+			{query: "sort=oldest", slugs: []string{posts[0].Slug, posts[1].Slug}},
+			{query: "keyword=test2&author=testUser&sent=true", slugs: []string{posts[1].Slug, posts[0].Slug}},
+			{query: "keyword=test", slugs: []string{}},
+			{query: "author=unknown", slugs: []string{}},
+			{query: "sent=false", slugs: []string{}},
+			{query: "from=" + url.QueryEscape(posts[1].CreatedAt.Format(time.RFC3339Nano)), slugs: []string{posts[1].Slug}},
+			{query: "to=" + url.QueryEscape(posts[1].CreatedAt.Format(time.RFC3339Nano)), slugs: []string{posts[0].Slug}},
+		}
+
+		for _, tt := range tests {
+			res := testutil.NewRequest().
+				Get(basePostsPath+"?"+tt.query).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code(), tt.query)
+
+			var postsRes api.PostsListResponse
+			err := res.UnmarshalBodyToObject(&postsRes)
+			assert.NoError(t, err)
+
+			slugs := make([]string, 0, len(postsRes.Posts))
+			for _, post := range postsRes.Posts {
+				slugs = append(slugs, post.Slug)
+			}
+			assert.Equal(t, tt.slugs, slugs, tt.query)
+			assert.Equal(t, len(tt.slugs), postsRes.Total, tt.query)
+		}
+	})
+
+	t.Run("400 - errInvalidCursor", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"?cursor=invalid").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errInvalidCursor, body.Code)
+	})
+
+	t.Run("400 - errParamValidation - page and cursor", func(t *testing.T) {
+		cursor := encodeCursor(&models.PostCursor{CreatedAt: posts[1].CreatedAt, ID: posts[1].ID})
+		res := testutil.NewRequest().
+			Get(basePostsPath+"?page=2&cursor="+cursor).
 			GoWithHTTPHandler(t, e)
 
 		assert.Equal(t, http.StatusBadRequest, res.Code())
@@ -436,10 +582,24 @@ func TestHandler_GetPosts(t *testing.T) {
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
 		assert.Equal(t, errParamValidation, body.Code)
-		assert.Equal(t, "Limit must be between 1 and 25", body.Message)
 	})
 
-	t.Run("400 - errParamValidation - page", func(t *testing.T) {
+	t.Run("400 - ErrRequestValidation - limit", func(t *testing.T) {
+		res := testutil.NewRequest().
+			Get(basePostsPath+"?limit=0").
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "limit", *(*body.Errors)[0].Parameter)
+		assert.Equal(t, "number must be at least 1", (*body.Errors)[0].Detail)
+	})
+
+	t.Run("400 - ErrRequestValidation - page", func(t *testing.T) {
 		res := testutil.NewRequest().
 			Get(basePostsPath+"?page=0").
 			GoWithHTTPHandler(t, e)
@@ -449,8 +609,9 @@ func TestHandler_GetPosts(t *testing.T) {
 		var body api.RequestError
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
-		assert.Equal(t, errParamValidation, body.Code)
-		assert.Equal(t, "Page must be greater than 0", body.Message)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "page", *(*body.Errors)[0].Parameter)
+		assert.Equal(t, "number must be at least 1", (*body.Errors)[0].Detail)
 	})
 }
 
@@ -491,6 +652,7 @@ func TestHandler_PutPostsSlug(t *testing.T) {
 			Content:     "New Content to read in 1 second",
 			Description: "New Description",
 			Keywords:    &[]string{"new1", "new2"},
+			Version:     post.Version,
 		}
 
 		reqBody, _ := json.Marshal(req)
@@ -517,6 +679,7 @@ func TestHandler_PutPostsSlug(t *testing.T) {
 		assert.NotEmpty(t, postRes.Id)
 		assert.NotEmpty(t, postRes.CreatedAt)
 		assert.NotEmpty(t, postRes.UpdatedAt)
+		assert.Equal(t, post.Version+1, postRes.Version)
 
 		// check that post is in the database
 		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
@@ -530,7 +693,7 @@ func TestHandler_PutPostsSlug(t *testing.T) {
 		assert.NotEmpty(t, postFromDB.UpdatedAt)
 	})
 
-	t.Run("400 - errRequestBodyBinding - ErrUnsupportedMediaType", func(t *testing.T) {
+	t.Run("400 - ErrRequestValidation - no Content-Type", func(t *testing.T) {
 		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
 		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
 
@@ -555,8 +718,106 @@ func TestHandler_PutPostsSlug(t *testing.T) {
 		var body api.RequestError
 		err := res.UnmarshalBodyToObject(&body)
 		assert.NoError(t, err)
-		assert.Equal(t, errRequestBodyBinding, body.Code)
-		assert.Equal(t, fmt.Sprintf("Error binding request body: %v", echo.ErrUnsupportedMediaType.Message), body.Message)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+		assert.Equal(t, "#", *(*body.Errors)[0].Pointer)
+	})
+
+	t.Run("OK - If-Match", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, []string{strconv.Itoa(user.ID)})
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := testutil.NewRequest().
+			Get(basePath+post.Slug).
+			GoWithHTTPHandler(t, e)
+		etag := res.Recorder.Header().Get(headerETag)
+
+		var current api.PostResponse
+		err := res.UnmarshalBodyToObject(&current)
+		assert.NoError(t, err)
+
+		req := api.PutPostRequest{
+			Title:       "If-Match Title",
+			Content:     "New Content to read in 1 second",
+			Description: "New Description",
+			Version:     current.Version,
+		}
+
+		res = testutil.NewRequest().
+			Put(basePath+post.Slug).
+			WithHeader(headerIfMatch, etag).
+			WithJsonBody(req).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		newETag := res.Recorder.Header().Get(headerETag)
+		assert.NotEqual(t, etag, newETag)
+
+		// the ETag of the response is the same as of the updated post
+		res = testutil.NewRequest().
+			Get(basePath+post.Slug).
+			GoWithHTTPHandler(t, e)
+		assert.Equal(t, newETag, res.Recorder.Header().Get(headerETag))
+	})
+
+	t.Run("412 - errPostChanged", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		req := api.PutPostRequest{
+			Title:       "Stale Title",
+			Content:     "Test Content",
+			Description: "Test Description",
+		}
+
+		res := testutil.NewRequest().
+			Put(basePath+post.Slug).
+			WithHeader(headerIfMatch, `"0-0"`).
+			WithJsonBody(req).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPostChanged, body.Code)
+
+		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		assert.NotEqual(t, req.Title, postFromDB.Title)
+		assert.Equal(t, postFromDB.Version, *body.CurrentVersion)
+	})
+
+	t.Run("409 - errPostChanged", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		req := api.PutPostRequest{
+			Title:       "Stale Title",
+			Content:     "Test Content",
+			Description: "Test Description",
+			Version:     1, // the post was already updated by the previous tests
+		}
+
+		res := testutil.NewRequest().
+			Put(basePath+post.Slug).
+			WithJsonBody(req).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusConflict, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPostChanged, body.Code)
+
+		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		assert.NotEqual(t, req.Title, postFromDB.Title)
+		assert.Equal(t, postFromDB.Version, *body.CurrentVersion)
 	})
 
 	t.Run("404 - errPostNotFound", func(t *testing.T) {
@@ -614,6 +875,215 @@ func TestHandler_PutPostsSlug(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, errValidationFailed, body.Code)
 		assert.Equal(t, "Post validation failed", body.Message)
+		assert.Equal(t, "#/keywords", *(*body.Errors)[0].Pointer)
+		assert.Equal(t, models.ErrPostWrongKeywordsString.Error(), *(*body.Errors)[0].Code)
+	})
+}
+
+func TestHandler_PatchPostsSlug(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	// create user for test
+	user := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testUser",
+	}
+	err := conn.Models().Users().Upsert(context.Background(), user)
+	assert.NoError(t, err)
+
+	basePath := basePostsPath + "/"
+	// create post for test
+	post := &models.Post{
+		UserID:      user.ID,
+		Title:       "Test Title",
+		Slug:        "patch-slug",
+		Content:     "Test Content to read in 1 second",
+		Description: "Test Description",
+		Keywords:    "test1,test2",
+	}
+	err = conn.Models().Posts().Create(context.Background(), post)
+	assert.NoError(t, err)
+
+	// patch sends the merge patch of the post
+	patch := func(e *echo.Echo, slug, body string) *testutil.CompletedRequest {
+		return testutil.NewRequest().
+			Patch(basePath+slug).
+			WithContentType(middlewares.MIMEApplicationMergePatchJSON).
+			WithBody([]byte(body)).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+	}
+	// current returns the post from the database, e.g. to patch its current version
+	current := func(t *testing.T) *models.Post {
+		t.Helper()
+
+		p, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		return p
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, post.Slug, fmt.Sprintf(`{"keywords": ["new1", "new2", "new3"], "version": %d}`, current(t).Version))
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		assert.NotEmpty(t, res.Recorder.Header().Get(headerETag))
+
+		var postRes api.PostResponse
+		err := res.UnmarshalBodyToObject(&postRes)
+		assert.NoError(t, err)
+		assert.Equal(t, post.Title, postRes.Title)
+		assert.Equal(t, post.Content, postRes.Content)
+		assert.Equal(t, &[]string{"new1", "new2", "new3"}, postRes.Keywords)
+		assert.Equal(t, post.Version+1, postRes.Version)
+
+		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		assert.Equal(t, post.Title, postFromDB.Title)
+		assert.Equal(t, post.Description, postFromDB.Description)
+		assert.Equal(t, post.Content, postFromDB.Content)
+		assert.Equal(t, "new1,new2,new3", postFromDB.Keywords)
+	})
+
+	t.Run("OK - remove keywords", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, post.Slug, fmt.Sprintf(`{"keywords": null, "title": "New Title", "version": %d}`, current(t).Version))
+
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		assert.Equal(t, "New Title", postFromDB.Title)
+		assert.Empty(t, postFromDB.Keywords)
+	})
+
+	t.Run("400 - ErrRequestValidation - not a merge patch", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := testutil.NewRequest().
+			Patch(basePath+post.Slug).
+			WithJsonBody(map[string]string{"title": "JSON Title"}).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, middlewares.ErrRequestValidation, body.Code)
+	})
+
+	t.Run("400 - errValidationFailed", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, post.Slug, fmt.Sprintf(`{"title": "", "version": %d}`, current(t).Version))
+
+		assert.Equal(t, http.StatusBadRequest, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errValidationFailed, body.Code)
+		assert.Equal(t, models.ErrPostTitleRequired.Error(), *(*body.Errors)[0].Code)
+	})
+
+	t.Run("409 - errPostChanged", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, post.Slug, `{"title": "Stale Title", "version": 1}`)
+
+		assert.Equal(t, http.StatusConflict, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPostChanged, body.Code)
+
+		postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+		assert.NoError(t, err)
+		assert.NotEqual(t, "Stale Title", postFromDB.Title)
+		assert.Equal(t, postFromDB.Version, *body.CurrentVersion)
+	})
+
+	t.Run("OK - If-Match", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := testutil.NewRequest().
+			Patch(basePath+post.Slug).
+			WithContentType(middlewares.MIMEApplicationMergePatchJSON).
+			WithHeader(headerIfMatch, postETag(current(t))).
+			WithBody([]byte(`{"description": "New Description"}`)).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusOK, res.Code())
+		assert.Equal(t, "New Description", current(t).Description)
+	})
+
+	t.Run("412 - errPostChanged", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		stale := *current(t)
+		stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+
+		res := testutil.NewRequest().
+			Patch(basePath+post.Slug).
+			WithContentType(middlewares.MIMEApplicationMergePatchJSON).
+			WithHeader(headerIfMatch, postETag(&stale)).
+			WithBody([]byte(`{"title": "Stale Title"}`)).
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPostChanged, body.Code)
+	})
+
+	t.Run("428 - errPreconditionRequired", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, post.Slug, `{"title": "Unconditional Title"}`)
+
+		assert.Equal(t, http.StatusPreconditionRequired, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPreconditionRequired, body.Code)
+		assert.Equal(t, current(t).Version, *body.CurrentVersion)
+		assert.NotEqual(t, "Unconditional Title", current(t).Title)
+	})
+
+	t.Run("404 - errPostNotFound", func(t *testing.T) {
+		e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+		res := patch(e, "not-found-slug", `{"title": "Title"}`)
+
+		assert.Equal(t, http.StatusNotFound, res.Code())
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, errPostNotFound, body.Code)
 	})
 }
 
@@ -653,7 +1123,7 @@ func TestHandler_PostPostsSlugSendEmail(t *testing.T) {
 		})
 		assert.NoError(t, err)
 
-		mockMailerService.On("SendPostEmail", mock.Anything).Return(nil)
+		mockMailerService.On("SendPostEmail", mock.Anything, mock.Anything).Return(nil)
 
 		res := testutil.NewRequest().
 			Post(basePostsPath+"/"+post.Slug+"/send-email").
@@ -665,6 +1135,29 @@ func TestHandler_PostPostsSlugSendEmail(t *testing.T) {
 		mockJwtService.AssertExpectations(t)
 	})
 
+	t.Run("201 - OK with API key", func(t *testing.T) {
+		e, _, _, mockMailerService, _ := registerHandlers(t, conn, nil)
+
+		p := &models.Post{
+			UserID:      user.ID,
+			Title:       "Test Title",
+			Slug:        uuid.New().String(),
+			Content:     "Test Content to read in 1 second",
+			Description: "Test Description",
+		}
+		assert.NoError(t, conn.Models().Posts().Create(context.Background(), p))
+
+		mockMailerService.On("SendPostEmail", mock.Anything, mock.Anything).Return(nil)
+
+		res := testutil.NewRequest().
+			Post(basePostsPath+"/"+p.Slug+"/send-email").
+			WithHeader("X-API-Key", testAPIKey).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusCreated, res.Code())
+		mockMailerService.AssertExpectations(t)
+	})
+
 	t.Run("409 - errPostAlreadySent", func(t *testing.T) {
 		e, _, mockJwtService, mockMailerService, _ := registerHandlers(t, conn, []string{strconv.Itoa(user.ID)})
 		mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
@@ -685,7 +1178,7 @@ func TestHandler_PostPostsSlugSendEmail(t *testing.T) {
 			WithJWSAuth(jwtToken).
 			GoWithHTTPHandler(t, e)
 
-		mockMailerService.AssertNotCalled(t, "SendPostEmail", mock.Anything)
+		mockMailerService.AssertNotCalled(t, "SendPostEmail", mock.Anything, mock.Anything)
 
 		assert.Equal(t, http.StatusConflict, res.Code())
 
@@ -717,3 +1210,91 @@ func TestHandler_PostPostsSlugSendEmail(t *testing.T) {
 		mockJwtService.AssertExpectations(t)
 	})
 }
+
+func TestHandler_Posts_DisabledUser(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	// create user for test, that is disabled after the post is created
+	user := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testUser",
+	}
+	assert.NoError(t, conn.Models().Users().Upsert(context.Background(), user))
+
+	post := &models.Post{
+		UserID:      user.ID,
+		Title:       "Test Title",
+		Slug:        uuid.New().String(),
+		Content:     "Test Content to read in 1 second",
+		Description: "Test Description",
+	}
+	assert.NoError(t, conn.Models().Posts().Create(context.Background(), post))
+
+	user.Status = models.DisabledUserStatus
+	assert.NoError(t, conn.Models().Users().Update(context.Background(), user))
+
+	postBody, _ := json.Marshal(api.PostRequest{
+		Title:       "Test Title",
+		Slug:        uuid.New().String(),
+		Content:     "Test Content",
+		Description: "Test Description",
+	})
+	putBody, _ := json.Marshal(api.PutPostRequest{
+		Title:       "New Title",
+		Content:     post.Content,
+		Description: post.Description,
+		Version:     post.Version,
+	})
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        []byte
+	}{
+		{name: "PostPosts", method: http.MethodPost, path: basePostsPath, contentType: "application/json", body: postBody},
+		{
+			name:        "PutPostsSlug",
+			method:      http.MethodPut,
+			path:        basePostsPath + "/" + post.Slug,
+			contentType: "application/json",
+			body:        putBody,
+		},
+		{
+			name:        "PatchPostsSlug",
+			method:      http.MethodPatch,
+			path:        basePostsPath + "/" + post.Slug,
+			contentType: middlewares.MIMEApplicationMergePatchJSON,
+			body:        []byte(`{"title": "New Title"}`),
+		},
+		{name: "PostPostsSlugSendEmail", method: http.MethodPost, path: basePostsPath + "/" + post.Slug + "/send-email"},
+	} {
+		t.Run("403 - "+tc.name, func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(user.ExternalID, nil)
+
+			req := testutil.NewRequest().WithMethod(tc.method, tc.path).WithJWSAuth(jwtToken)
+			if tc.body != nil {
+				req = req.WithHeader("Content-Type", tc.contentType).WithBody(tc.body)
+			}
+			res := req.GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusForbidden, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errForbidden, body.Code)
+		})
+	}
+
+	postFromDB, err := conn.Models().Posts().GetBySlug(context.Background(), post.Slug)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Title", postFromDB.Title)
+	assert.True(t, postFromDB.SentToSubscribersAt.IsZero())
+}
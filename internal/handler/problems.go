@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
+)
+
+// fieldError is the field of the request body, which is invalid if the model returns the err.
+type fieldError struct {
+	err     error
+	pointer string
+	detail  string
+}
+
+// fieldErrors are the validation errors of the models, that are caused by the request body.
+var fieldErrors = []fieldError{ //nolint:gochecknoglobals // constant
+	{err: models.ErrPostURLRequired, pointer: "#/slug", detail: "slug is required"},
+	{err: models.ErrPostInvalidSlug, pointer: "#/slug", detail: "slug must contain only lowercase letters, digits and dashes"},
+	{err: models.ErrPostReservedSlug, pointer: "#/slug", detail: "slug is reserved"},
+	{err: models.ErrPostTitleRequired, pointer: "#/title", detail: "title is required"},
+	{err: models.ErrPostDescriptionRequired, pointer: "#/description", detail: "description is required"},
+	{err: models.ErrPostContentRequired, pointer: "#/content", detail: "content is required"},
+	{err: models.ErrPostWrongKeywordsString, pointer: "#/keywords", detail: "keywords must not be empty"},
+	{err: models.ErrUserLoginRequired, pointer: "#/login", detail: "login is required"},
+	{err: models.ErrUserExternalIDRequired, pointer: "#/external_id", detail: "external ID is required"},
+	{err: models.ErrUserAuthMethodRequired, pointer: "#/auth_method", detail: "auth method is required"},
+	{err: models.ErrUserInvalidRole, pointer: "#/role", detail: "role must be admin or author"},
+	{err: models.ErrUserInvalidEmail, pointer: "#/external_id", detail: "external ID must be a valid email"},
+}
+
+// validationFailed responds with 400 and the field of the request body, that failed the validation of the model,
+// e.g. the slug for models.ErrPostInvalidSlug.
+func validationFailed(ctx echo.Context, message string, err error) error {
+	p := api.RequestError{
+		Code:    errValidationFailed,
+		Message: message,
+	}
+
+	for _, field := range fieldErrors {
+		if errors.Is(err, field.err) {
+			p.Detail = message + ": " + field.detail
+			p.Errors = &[]api.FieldError{{
+				Pointer: &field.pointer,
+				Detail:  field.detail,
+				Code:    stringPtr(field.err.Error()),
+			}}
+			break
+		}
+	}
+
+	return problem.Respond(ctx, http.StatusBadRequest, p)
+}
+
+// invalidEmail responds with 400 for the invalid email in the field of the request body, e.g. "#/email".
+func invalidEmail(ctx echo.Context, pointer, email string) error {
+	return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+		Code:    errValidationEmail,
+		Message: "Invalid email: " + email,
+		Errors: &[]api.FieldError{{
+			Pointer: &pointer,
+			Detail:  "must be a valid email",
+			Code:    stringPtr(errValidationEmail),
+		}},
+	})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
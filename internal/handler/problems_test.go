@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_validationFailed(t *testing.T) {
+	// respond returns the problem of the validationFailed response for the err
+	respond := func(t *testing.T, err error) api.RequestError {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/posts", nil), rec)
+		assert.NoError(t, validationFailed(ctx, "Post validation failed", err))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body api.RequestError
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+		return body
+	}
+
+	t.Run("known field", func(t *testing.T) {
+		body := respond(t, fmt.Errorf("%w: %w", models.ErrValidationFailed, models.ErrPostInvalidSlug))
+
+		assert.Equal(t, errValidationFailed, body.Code)
+		assert.Equal(t, "Post validation failed", body.Message)
+		assert.Equal(t, "Post validation failed: slug must contain only lowercase letters, digits and dashes", body.Detail)
+		if assert.NotNil(t, body.Errors) && assert.Len(t, *body.Errors, 1) {
+			field := (*body.Errors)[0]
+			assert.Equal(t, "#/slug", *field.Pointer)
+			assert.Equal(t, "ERR_POST_INVALID_SLUG", *field.Code)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		body := respond(t, fmt.Errorf("%w: %w", models.ErrValidationFailed, errors.New("invalid value")))
+
+		assert.Equal(t, errValidationFailed, body.Code)
+		assert.Equal(t, "Post validation failed", body.Detail)
+		assert.Nil(t, body.Errors)
+	})
+}
+
+func Test_invalidEmail(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/subscribers", nil), rec)
+	assert.NoError(t, invalidEmail(ctx, "#/email", "invalid"))
+
+	var body api.RequestError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, errValidationEmail, body.Code)
+	assert.Equal(t, "Invalid email: invalid", body.Message)
+	if assert.NotNil(t, body.Errors) && assert.Len(t, *body.Errors, 1) {
+		assert.Equal(t, "#/email", *(*body.Errors)[0].Pointer)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	"net/http"
-	"regexp"
+	"strings"
 )
 
 func (h *Handler) PostSubscribers(ctx echo.Context) error {
@@ -21,25 +23,25 @@ func (h *Handler) PostSubscribers(ctx echo.Context) error {
 			errorMessage = fmt.Sprintf("%v", echoErr.Message)
 		}
 
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
 		})
 	}
 
-	if hr := h.hcaptchaService.VerifyToken(req.Captcha); !hr.Success {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errValidationCaptcha,
-			Message: "Invalid captcha",
-		})
+	if err := h.verifyCaptcha(ctx, req.Captcha); err != nil {
+		return h.captchaError(ctx, err)
 	}
 
 	// validate email
-	if !isValidEmail(req.Email) {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errValidationEmail,
-			Message: fmt.Sprintf("Invalid email: %v", req.Email),
-		})
+	if !models.IsValidEmail(req.Email) {
+		return invalidEmail(ctx, "#/email", req.Email)
+	}
+
+	// Note: each subscription sends the confirmation email, so the same email can't be flooded from many IPs
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if allowed, retryAfter := h.subscriberLimiter.Allow(ctx.Request().Context(), email); !allowed {
+		return ratelimit.TooManyRequests(ctx, retryAfter)
 	}
 
 	subscription := models.Subscriber{
@@ -49,17 +51,21 @@ func (h *Handler) PostSubscribers(ctx echo.Context) error {
 	if err := h.db.Models().Subscribers().Create(ctx.Request().Context(), &subscription); err != nil {
 		// Note: we shouldn't tell duplicate error to the user for security reasons
 		if !errors.Is(err, models.ErrDuplicate) {
-			return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+			logError(ctx, errCreateSubscription, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 				Code:    errCreateSubscription,
 				Message: "Error creating subscription",
 			})
 		}
+	} else {
+		h.metrics.SubscriptionCreated()
 	}
 
 	// Note: for confirmation code can be used internal ID of the subscription just for simplicity
-	err := h.mailerService.SendConfirmationEmail(req.Email, subscription.ID.String())
+	err := h.mailerService.SendConfirmationEmail(ctx.Request().Context(), req.Email, subscription.ID.String())
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errSendConfirmationEmail, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errSendConfirmationEmail,
 			Message: "Error sending confirmation email",
 		})
@@ -77,7 +83,7 @@ func (h *Handler) DeleteSubscribers(ctx echo.Context) error {
 			errorMessage = fmt.Sprintf("%v", echoErr.Message)
 		}
 
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
 		})
@@ -85,18 +91,20 @@ func (h *Handler) DeleteSubscribers(ctx echo.Context) error {
 
 	subscriptionID, err := h.db.Models().Subscribers().GetByID(ctx.Request().Context(), req.SubscriptionId)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errGetSubscription,
 			Message: "Subscriber is not found or error getting subscription by ID",
 		})
 	}
 
 	if err := h.db.Models().Subscribers().Delete(ctx.Request().Context(), subscriptionID.ID.String()); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errDeleteSubscription, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errDeleteSubscription,
 			Message: "Error deleting subscription",
 		})
 	}
+	h.metrics.SubscriptionDeleted()
 
 	if hub := sentryecho.GetHubFromContext(ctx); hub != nil {
 		hub.WithScope(func(scope *sentry.Scope) {
@@ -119,23 +127,20 @@ func (h *Handler) PostSubscribersConfirm(ctx echo.Context) error {
 			errorMessage = fmt.Sprintf("%v", echoErr.Message)
 		}
 
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errRequestBodyBinding,
 			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
 		})
 	}
 
-	if hr := h.hcaptchaService.VerifyToken(req.Captcha); !hr.Success {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
-			Code:    errValidationCaptcha,
-			Message: "Invalid captcha",
-		})
+	if err := h.verifyCaptcha(ctx, req.Captcha); err != nil {
+		return h.captchaError(ctx, err)
 	}
 
 	// Note: Token is used as subscription ID for simplicity
 	subscription, err := h.db.Models().Subscribers().GetByID(ctx.Request().Context(), req.Token)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, api.RequestError{
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
 			Code:    errGetSubscription,
 			Message: "Subscriber is not found or error getting subscription by ID",
 		})
@@ -147,16 +152,13 @@ func (h *Handler) PostSubscribersConfirm(ctx echo.Context) error {
 
 	subscription.IsConfirmed = true
 	if err := h.db.Models().Subscribers().Update(ctx.Request().Context(), subscription); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, api.RequestError{
+		logError(ctx, errUpdateSubscription, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
 			Code:    errUpdateSubscription,
 			Message: "Error updating subscription",
 		})
 	}
+	h.metrics.SubscriptionConfirmed()
 
 	return ctx.NoContent(http.StatusOK)
 }
-
-func isValidEmail(email string) bool {
-	re := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,24}$`)
-	return re.MatchString(email)
-}
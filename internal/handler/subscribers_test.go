@@ -3,10 +3,11 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"github.com/kataras/hcaptcha"
 	"github.com/oapi-codegen/testutil"
 	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/captcha"
 	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,7 +22,7 @@ func Test_PostSubscribers(t *testing.T) {
 	}
 
 	t.Run("Created", func(t *testing.T) {
-		e, _, _, mockMailerService, mockHcaptchaService := registerHandlers(t, conn, nil)
+		e, _, _, mockMailerService, mockCaptchaVerifier := registerHandlers(t, conn, nil)
 
 		rb, _ := json.Marshal(api.CreateSubscriberRequest{
 			Email:   "some@email.com",
@@ -29,13 +30,13 @@ func Test_PostSubscribers(t *testing.T) {
 		})
 
 		mockMailerService.
-			On("SendConfirmationEmail", "some@email.com", mock.Anything).
+			On("SendConfirmationEmail", mock.Anything, "some@email.com", mock.Anything).
 			Return(nil).
 			Once()
 
-		mockHcaptchaService.
-			On("VerifyToken", "some-captcha").Return(hcaptcha.Response{
-			Success: true}, nil).
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
 			Once()
 
 		res := testutil.NewRequest().
@@ -54,21 +55,21 @@ func Test_PostSubscribers(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, emails, "some@email.com")
 		mockMailerService.AssertExpectations(t)
-		mockHcaptchaService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
 	})
 
 	t.Run("BadRequest", func(t *testing.T) {
-		e, _, _, mockMailerService, mockHcaptchaService := registerHandlers(t, conn, nil)
+		e, _, _, mockMailerService, mockCaptchaVerifier := registerHandlers(t, conn, nil)
 
 		rb, _ := json.Marshal(api.CreateSubscriberRequest{
 			Email:   "invalid-email",
 			Captcha: "some-captcha",
 		})
 
-		mockMailerService.AssertNotCalled(t, "SendConfirmationEmail", mock.Anything, mock.Anything)
-		mockHcaptchaService.
-			On("VerifyToken", "some-captcha").Return(hcaptcha.Response{
-			Success: true}, nil).
+		mockMailerService.AssertNotCalled(t, "SendConfirmationEmail", mock.Anything, mock.Anything, mock.Anything)
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
 			Once()
 
 		res := testutil.NewRequest().
@@ -80,7 +81,51 @@ func Test_PostSubscribers(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.Code())
 
 		mockMailerService.AssertExpectations(t)
-		mockHcaptchaService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
+	})
+
+	t.Run("429 - too many confirmation emails for the email", func(t *testing.T) {
+		e, _, _, mockMailerService, mockCaptchaVerifier := registerHandlers(t, conn, nil)
+
+		rb, _ := json.Marshal(api.CreateSubscriberRequest{
+			Email:   "flooded@email.com",
+			Captcha: "some-captcha",
+		})
+
+		// Note: the default rate is 3 emails per hour
+		mockMailerService.
+			On("SendConfirmationEmail", mock.Anything, "flooded@email.com", mock.Anything).
+			Return(nil).
+			Times(3)
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
+			Times(4)
+
+		for range 3 {
+			res := testutil.NewRequest().
+				WithHeader("Content-Type", "application/json").
+				Post("/subscribers").
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+			assert.Equal(t, http.StatusCreated, res.Code())
+		}
+
+		res := testutil.NewRequest().
+			WithHeader("Content-Type", "application/json").
+			Post("/subscribers").
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+
+		assert.Equal(t, http.StatusTooManyRequests, res.Code())
+		assert.NotEmpty(t, res.Recorder.Header().Get("Retry-After"))
+
+		var body api.RequestError
+		err := res.UnmarshalBodyToObject(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, ratelimit.ErrTooManyRequests, body.Code)
+		mockMailerService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
 	})
 }
 
@@ -149,7 +194,7 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 	}
 
 	t.Run("OK - NoContent", func(t *testing.T) {
-		e, _, _, _, mockHcaptchaService := registerHandlers(t, conn, nil)
+		e, _, _, _, mockCaptchaVerifier := registerHandlers(t, conn, nil)
 
 		sub := models.Subscriber{
 			Email:       "some@email.space",
@@ -165,9 +210,9 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 			Captcha: "some-captcha",
 		})
 
-		mockHcaptchaService.
-			On("VerifyToken", "some-captcha").Return(hcaptcha.Response{
-			Success: true}, nil).
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
 			Once()
 
 		res := testutil.NewRequest().
@@ -183,20 +228,20 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 		assert.NoError(t, err)
 		assert.True(t, retrievedSubscription.IsConfirmed)
 
-		mockHcaptchaService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
 	})
 
 	t.Run("StatusBadRequest - not found", func(t *testing.T) {
-		e, _, _, _, mockHcaptchaService := registerHandlers(t, conn, nil)
+		e, _, _, _, mockCaptchaVerifier := registerHandlers(t, conn, nil)
 
 		rb, _ := json.Marshal(api.ConfirmSubscriberRequest{
 			Token:   "ce247e1d-a371-42fc-b36b-26b566c0096c",
 			Captcha: "some-captcha",
 		})
 
-		mockHcaptchaService.
-			On("VerifyToken", "some-captcha").Return(hcaptcha.Response{
-			Success: true}, nil).
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
 			Once()
 
 		res := testutil.NewRequest().
@@ -207,11 +252,11 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, res.Code())
 
-		mockHcaptchaService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
 	})
 
 	t.Run("OK - if already confirmed", func(t *testing.T) {
-		e, _, _, _, mockHcaptchaService := registerHandlers(t, conn, nil)
+		e, _, _, _, mockCaptchaVerifier := registerHandlers(t, conn, nil)
 
 		sub := models.Subscriber{
 			Email:       "some2@email.space",
@@ -227,9 +272,9 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 			Captcha: "some-captcha",
 		})
 
-		mockHcaptchaService.
-			On("VerifyToken", "some-captcha").Return(hcaptcha.Response{
-			Success: true}, nil).
+		mockCaptchaVerifier.
+			On("Verify", mock.Anything, "some-captcha", mock.Anything).
+			Return(&captcha.Result{Success: true}, nil).
 			Once()
 
 		res := testutil.NewRequest().
@@ -240,6 +285,6 @@ func Test_PostSubscribersConfirm(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, res.Code())
 
-		mockHcaptchaService.AssertExpectations(t)
+		mockCaptchaVerifier.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
+)
+
+// errUnauthenticated is returned if the request has no user attached by the Auth middleware.
+var errUnauthenticated = errors.New("request is not authenticated")
+
+func (h *Handler) GetUsers(ctx echo.Context) error {
+	if _, err := h.currentAdmin(ctx); err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	users, err := h.db.Models().Users().FindAll(ctx.Request().Context())
+	if err != nil {
+		logError(ctx, errGetUsers, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetUsers,
+			Message: "Error getting users",
+		})
+	}
+
+	usersItems := make([]api.UserResponse, 0, len(users))
+	for _, user := range users {
+		usersItems = append(usersItems, userResponse(user))
+	}
+
+	return ctx.JSON(http.StatusOK, api.UsersListResponse{
+		Users: usersItems,
+	})
+}
+
+func (h *Handler) PostUsers(ctx echo.Context) error {
+	if _, err := h.currentAdmin(ctx); err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	var req api.InviteUserRequest
+	if err := ctx.Bind(&req); err != nil {
+		var errorMessage string
+		var echoErr *echo.HTTPError
+		if errors.As(err, &echoErr) {
+			errorMessage = fmt.Sprintf("%v", echoErr.Message)
+		}
+
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: fmt.Sprintf("Error binding request body: %v", errorMessage),
+		})
+	}
+
+	var login string
+	if req.Login != nil {
+		login = *req.Login
+	}
+
+	role := models.AuthorUserRole
+	if req.Role != nil {
+		role = models.UserRole(*req.Role)
+	}
+
+	user, err := models.NewInvitedUser(models.AuthMethod(req.AuthMethod), req.ExternalId, login, role)
+	if err != nil {
+		return invalidEmail(ctx, "#/external_id", req.ExternalId)
+	}
+
+	if err := h.db.Models().Users().Create(ctx.Request().Context(), user); err != nil {
+		switch {
+		case errors.Is(err, models.ErrDuplicate):
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
+				Code:    errDuplicateUser,
+				Message: "User with this external ID already exists",
+			})
+		case errors.Is(err, models.ErrValidationFailed):
+			return validationFailed(ctx, "User validation failed", err)
+
+		default:
+			logError(ctx, errCreateUser, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errCreateUser,
+				Message: "Error while creating user",
+			})
+		}
+	}
+
+	return ctx.JSON(http.StatusCreated, userResponse(user))
+}
+
+func (h *Handler) PostUsersIdDisable(ctx echo.Context, id int) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	if admin.ID == id {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errParamValidation,
+			Message: "Admin can't disable itself",
+		})
+	}
+
+	return h.setUserStatus(ctx, id, models.DisabledUserStatus)
+}
+
+func (h *Handler) PostUsersIdEnable(ctx echo.Context, id int) error {
+	if _, err := h.currentAdmin(ctx); err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	return h.setUserStatus(ctx, id, models.ActiveUserStatus)
+}
+
+// setUserStatus updates the status of the user with the given ID and responds with the updated user.
+func (h *Handler) setUserStatus(ctx echo.Context, id int, status models.UserStatus) error {
+	user, err := h.db.Models().Users().GetByID(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+				Code:    errUserNotFound,
+				Message: "User not found",
+			})
+		}
+
+		logError(ctx, errGetUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetUser,
+			Message: "Error getting user",
+		})
+	}
+
+	user.Status = status
+	if err := h.db.Models().Users().Update(ctx.Request().Context(), user); err != nil {
+		logError(ctx, errUpdateUser, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUpdateUser,
+			Message: "Error updating user",
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, userResponse(user))
+}
+
+// currentUser returns the active user that made the request.
+func (h *Handler) currentUser(ctx echo.Context) (*models.User, error) {
+	var externalUserID string
+	if s := ctx.Get("externalUserID"); s != nil {
+		externalUserID = s.(string)
+	}
+
+	return h.activeUser(ctx.Request().Context(), externalUserID)
+}
+
+// activeUser returns the user with the external ID, if the user is active.
+func (h *Handler) activeUser(ctx context.Context, externalUserID string) (*models.User, error) {
+	if externalUserID == "" {
+		return nil, errUnauthenticated
+	}
+
+	user, err := h.db.Models().Users().GetByExternalID(ctx, externalUserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnauthenticated, err)
+	}
+
+	if user.Status != models.ActiveUserStatus {
+		return nil, errUserNotAllowed
+	}
+
+	return user, nil
+}
+
+// currentAdmin returns the active admin that made the request.
+func (h *Handler) currentAdmin(ctx echo.Context) (*models.User, error) {
+	user, err := h.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin() {
+		return nil, errUserNotAllowed
+	}
+
+	return user, nil
+}
+
+// adminError responds with the error returned from Handler.currentAdmin.
+func (h *Handler) adminError(ctx echo.Context, err error) error {
+	if errors.Is(err, errUnauthenticated) {
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
+			Code:    errUnauthorized,
+			Message: "Unauthorized",
+		})
+	}
+
+	return problem.Respond(ctx, http.StatusForbidden, api.RequestError{
+		Code:    errForbidden,
+		Message: "Only admins are allowed to manage users",
+	})
+}
+
+// userError responds with the error returned from Handler.currentUser.
+func (h *Handler) userError(ctx echo.Context, err error) error {
+	if errors.Is(err, errUnauthenticated) {
+		return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
+			Code:    errUnauthorized,
+			Message: "Unauthorized",
+		})
+	}
+
+	return problem.Respond(ctx, http.StatusForbidden, api.RequestError{
+		Code:    errForbidden,
+		Message: "User is disabled",
+	})
+}
+
+func userResponse(user *models.User) api.UserResponse {
+	return api.UserResponse{
+		Id:         user.ID,
+		ExternalId: user.ExternalID,
+		AuthMethod: string(user.AuthMethod),
+		Login:      user.Login,
+		Role:       api.UserRole(user.Role),
+		Status:     api.UserStatus(user.Status),
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+	}
+}
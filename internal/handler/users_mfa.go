@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/webauthn"
+	"net/http"
+	"strings"
+)
+
+const (
+	recoveryCodesCount = 10
+	recoveryCodeBytes  = 10 // recoveryCodeBytes is encoded to 16 chars of base32
+	recoveryCodeGroup  = 4  // recoveryCodeGroup is the number of chars between the dashes
+)
+
+// GetUsersMeWebauthnCredentials handles the request to list the passkeys of the current admin.
+func (h *Handler) GetUsersMeWebauthnCredentials(ctx echo.Context) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), admin.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	items := make([]api.WebAuthnCredentialResponse, 0, len(credentials))
+	for _, credential := range credentials {
+		items = append(items, credentialResponse(credential))
+	}
+
+	return ctx.JSON(http.StatusOK, api.WebAuthnCredentialsListResponse{
+		Credentials: items,
+	})
+}
+
+// PostUsersMeWebauthnCredentialsBegin handles the request to start the passkey registration.
+func (h *Handler) PostUsersMeWebauthnCredentialsBegin(ctx echo.Context) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), admin.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	ceremony, err := h.webAuthnService.BeginRegistration(admin, credentials)
+	if err != nil {
+		logError(ctx, errBeginWebAuthn, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errBeginWebAuthn,
+			Message: "Error starting passkey registration",
+		})
+	}
+
+	return h.ceremonyResponse(ctx, ceremony.Options, ceremony.Session)
+}
+
+// PostUsersMeWebauthnCredentialsFinish handles the request to store the passkey of the current admin.
+func (h *Handler) PostUsersMeWebauthnCredentialsFinish(ctx echo.Context) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	var req api.WebAuthnRegistrationFinishRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errRequestBodyBinding,
+			Message: "Error binding request body",
+		})
+	}
+
+	if req.Session == "" || len(req.Credential) == 0 {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Session and credential fields are required",
+		})
+	}
+
+	credentials, err := h.db.Models().WebAuthnCredentials().FindByUserID(ctx.Request().Context(), admin.ID)
+	if err != nil {
+		logError(ctx, errGetCredentials, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errGetCredentials,
+			Message: "Error getting passkeys",
+		})
+	}
+
+	response, err := json.Marshal(req.Credential)
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errBodyValidation,
+			Message: "Invalid credential",
+		})
+	}
+
+	credential, err := h.webAuthnService.FinishRegistration(ctx.Request().Context(), admin, credentials, req.Session, response)
+	if errors.Is(err, webauthn.ErrUseChallenge) {
+		logError(ctx, errUseChallenge, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errUseChallenge,
+			Message: "Error verifying passkey",
+		})
+	}
+	if err != nil {
+		return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+			Code:    errInvalidCredential,
+			Message: "Passkey attestation is invalid or expired",
+		})
+	}
+
+	credential.Name = "Passkey"
+	if req.Name != nil && *req.Name != "" {
+		credential.Name = *req.Name
+	}
+
+	if err := h.db.Models().WebAuthnCredentials().Create(ctx.Request().Context(), credential); err != nil {
+		if errors.Is(err, models.ErrDuplicate) {
+			return problem.Respond(ctx, http.StatusConflict, api.RequestError{
+				Code:    errDuplicateCredential,
+				Message: "Passkey is already registered",
+			})
+		}
+
+		logError(ctx, errCreateCredential, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateCredential,
+			Message: "Error while creating passkey",
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, credentialResponse(credential))
+}
+
+// DeleteUsersMeWebauthnCredentialsId handles the request to delete the passkey of the current admin.
+func (h *Handler) DeleteUsersMeWebauthnCredentialsId(ctx echo.Context, id int) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	if err := h.db.Models().WebAuthnCredentials().Delete(ctx.Request().Context(), admin.ID, id); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return problem.Respond(ctx, http.StatusNotFound, api.RequestError{
+				Code:    errCredentialNotFound,
+				Message: "Passkey not found",
+			})
+		}
+
+		logError(ctx, errDeleteCredential, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errDeleteCredential,
+			Message: "Error while deleting passkey",
+		})
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// PostUsersMeRecoveryCodes handles the request to generate new recovery codes for the current admin.
+func (h *Handler) PostUsersMeRecoveryCodes(ctx echo.Context) error {
+	admin, err := h.currentAdmin(ctx)
+	if err != nil {
+		return h.adminError(ctx, err)
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			logError(ctx, errCreateRecoveryCodes, err)
+			return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+				Code:    errCreateRecoveryCodes,
+				Message: "Error while generating recovery codes",
+			})
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := h.db.Models().RecoveryCodes().Replace(ctx.Request().Context(), admin.ID, hashes); err != nil {
+		logError(ctx, errCreateRecoveryCodes, err)
+		return problem.Respond(ctx, http.StatusInternalServerError, api.RequestError{
+			Code:    errCreateRecoveryCodes,
+			Message: "Error while creating recovery codes",
+		})
+	}
+
+	return ctx.JSON(http.StatusCreated, api.RecoveryCodesResponse{
+		Codes: codes,
+	})
+}
+
+func credentialResponse(credential *models.WebAuthnCredential) api.WebAuthnCredentialResponse {
+	return api.WebAuthnCredentialResponse{
+		Id:         credential.ID,
+		Name:       credential.Name,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+// generateRecoveryCode returns a random code in the "abcd-efgh-ijkl-mnop" format.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	encoded := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+
+	groups := make([]string, 0, len(encoded)/recoveryCodeGroup)
+	for i := 0; i < len(encoded); i += recoveryCodeGroup {
+		groups = append(groups, encoded[i:i+recoveryCodeGroup])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// hashRecoveryCode returns the hash of the code to store in the database.
+// The code is normalized first, so it could be entered without dashes or in upper case.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(hash[:])
+}
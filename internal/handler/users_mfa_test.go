@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	testwebauthn "github.com/samgozman/go-bloggy/testutils/test-webauthn"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+const baseCredentialsPath = "/users/me/webauthn/credentials"
+
+func Test_UsersMFA(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	admin := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testAdmin",
+		Role:       models.AdminUserRole,
+	}
+	err := conn.Models().Users().Create(context.Background(), admin)
+	assert.NoError(t, err)
+
+	author := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testAuthor",
+	}
+	err = conn.Models().Users().Create(context.Background(), author)
+	assert.NoError(t, err)
+
+	// register creates a new passkey for the admin with the software authenticator
+	register := func(t *testing.T, e http.Handler, name string) *testutil.CompletedRequest {
+		t.Helper()
+
+		res := testutil.NewRequest().
+			Post(baseCredentialsPath+"/begin").
+			WithJWSAuth(jwtToken).
+			GoWithHTTPHandler(t, e)
+		assert.Equal(t, http.StatusOK, res.Code())
+
+		var ceremony api.WebAuthnCeremonyResponse
+		err := res.UnmarshalBodyToObject(&ceremony)
+		assert.NoError(t, err)
+
+		authenticator, err := testwebauthn.NewAuthenticator(testWebAuthnOrigin)
+		assert.NoError(t, err)
+
+		options, _ := json.Marshal(ceremony.Options)
+		response, err := authenticator.Create(options)
+		assert.NoError(t, err)
+
+		var credential map[string]interface{}
+		err = json.Unmarshal(response, &credential)
+		assert.NoError(t, err)
+
+		rb, _ := json.Marshal(api.WebAuthnRegistrationFinishRequestBody{
+			Name:       &name,
+			Session:    ceremony.Session,
+			Credential: credential,
+		})
+
+		return testutil.NewRequest().
+			Post(baseCredentialsPath+"/finish").
+			WithHeader("Content-Type", "application/json").
+			WithJWSAuth(jwtToken).
+			WithBody(rb).
+			GoWithHTTPHandler(t, e)
+	}
+
+	t.Run("PostUsersMeWebauthnCredentials", func(t *testing.T) {
+		t.Run("201 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := register(t, e, "YubiKey")
+
+			assert.Equal(t, http.StatusCreated, res.Code())
+
+			var body api.WebAuthnCredentialResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.NotZero(t, body.Id)
+			assert.Equal(t, "YubiKey", body.Name)
+			assert.Nil(t, body.LastUsedAt)
+		})
+
+		t.Run("400 - invalid session", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			rb, _ := json.Marshal(api.WebAuthnRegistrationFinishRequestBody{
+				Session:    "invalid",
+				Credential: map[string]interface{}{"id": "invalid"},
+			})
+
+			res := testutil.NewRequest().
+				Post(baseCredentialsPath+"/finish").
+				WithHeader("Content-Type", "application/json").
+				WithJWSAuth(jwtToken).
+				WithBody(rb).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errInvalidCredential, body.Code)
+		})
+
+		t.Run("403 - not an admin", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(author.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post(baseCredentialsPath+"/begin").
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusForbidden, res.Code())
+		})
+	})
+
+	t.Run("GetUsersMeWebauthnCredentials", func(t *testing.T) {
+		t.Run("200 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			assert.Equal(t, http.StatusCreated, register(t, e, "Phone").Code())
+
+			res := testutil.NewRequest().
+				Get(baseCredentialsPath).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			var body api.WebAuthnCredentialsListResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, body.Credentials)
+			assert.Equal(t, "Phone", body.Credentials[len(body.Credentials)-1].Name)
+		})
+
+		t.Run("401 - no token", func(t *testing.T) {
+			e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+			res := testutil.NewRequest().
+				Get(baseCredentialsPath).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code())
+		})
+	})
+
+	t.Run("DeleteUsersMeWebauthnCredentialsId", func(t *testing.T) {
+		t.Run("204 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			var credential api.WebAuthnCredentialResponse
+			err := register(t, e, "Laptop").UnmarshalBodyToObject(&credential)
+			assert.NoError(t, err)
+
+			res := testutil.NewRequest().
+				Delete(fmt.Sprintf("%s/%d", baseCredentialsPath, credential.Id)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusNoContent, res.Code())
+		})
+
+		t.Run("404 - passkey of another user", func(t *testing.T) {
+			another, _ := createAdminWithPasskey(t, conn, uuid.New().String())
+			credentials, err := conn.Models().WebAuthnCredentials().FindByUserID(context.Background(), another.ID)
+			assert.NoError(t, err)
+
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Delete(fmt.Sprintf("%s/%d", baseCredentialsPath, credentials[0].ID)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusNotFound, res.Code())
+		})
+	})
+
+	t.Run("PostUsersMeRecoveryCodes", func(t *testing.T) {
+		t.Run("201 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post("/users/me/recovery-codes").
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusCreated, res.Code())
+
+			var body api.RecoveryCodesResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Len(t, body.Codes, recoveryCodesCount)
+			assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, body.Codes[0])
+
+			count, err := conn.Models().RecoveryCodes().CountUnused(context.Background(), admin.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(recoveryCodesCount), count)
+		})
+
+		t.Run("403 - not an admin", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(author.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post("/users/me/recovery-codes").
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusForbidden, res.Code())
+		})
+	})
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/testutil"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	testmodels "github.com/samgozman/go-bloggy/testutils/test-models"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const baseUsersPath = "/users"
+
+func Test_Users(t *testing.T) {
+	conn, errDB := testmodels.InitDatabaseWithModelsTest()
+	if errDB != nil {
+		t.Fatal(errDB)
+	}
+
+	admin := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testAdmin",
+		Role:       models.AdminUserRole,
+	}
+	err := conn.Models().Users().Create(context.Background(), admin)
+	assert.NoError(t, err)
+
+	author := &models.User{
+		ExternalID: uuid.New().String(),
+		AuthMethod: models.GitHubAuthMethod,
+		Login:      "testAuthor",
+	}
+	err = conn.Models().Users().Create(context.Background(), author)
+	assert.NoError(t, err)
+
+	t.Run("GetUsers", func(t *testing.T) {
+		t.Run("200 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Get(baseUsersPath).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			var body api.UsersListResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, len(body.Users), 2)
+		})
+
+		t.Run("401 - no token", func(t *testing.T) {
+			e, _, _, _, _ := registerHandlers(t, conn, nil)
+
+			res := testutil.NewRequest().
+				Get(baseUsersPath).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code())
+		})
+
+		t.Run("403 - not an admin", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(author.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Get(baseUsersPath).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusForbidden, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errForbidden, body.Code)
+		})
+	})
+
+	t.Run("PostUsers", func(t *testing.T) {
+		t.Run("201 - Created", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			role := api.Author
+			req := api.InviteUserRequest{
+				ExternalId: uuid.New().String(),
+				AuthMethod: string(models.GitHubAuthMethod),
+				Role:       &role,
+			}
+			reqBody, _ := json.Marshal(req)
+
+			res := testutil.NewRequest().
+				Post(baseUsersPath).
+				WithHeader("Content-Type", "application/json").
+				WithBody(reqBody).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusCreated, res.Code())
+
+			var body api.UserResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, req.ExternalId, body.ExternalId)
+			assert.Equal(t, req.ExternalId, body.Login)
+			assert.Equal(t, api.Invited, body.Status)
+			assert.Equal(t, api.Author, body.Role)
+			assert.NotEmpty(t, body.Id)
+		})
+
+		t.Run("201 - Created email user with normalized email", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			email := uuid.New().String() + "@example.com"
+			reqBody, _ := json.Marshal(api.InviteUserRequest{
+				ExternalId: " " + strings.ToUpper(email),
+				AuthMethod: string(models.EmailAuthMethod),
+			})
+
+			res := testutil.NewRequest().
+				Post(baseUsersPath).
+				WithHeader("Content-Type", "application/json").
+				WithBody(reqBody).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusCreated, res.Code())
+
+			var body api.UserResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, email, body.ExternalId)
+		})
+
+		t.Run("400 - invalid email for email user", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			reqBody, _ := json.Marshal(api.InviteUserRequest{
+				ExternalId: "invalid",
+				AuthMethod: string(models.EmailAuthMethod),
+			})
+
+			res := testutil.NewRequest().
+				Post(baseUsersPath).
+				WithHeader("Content-Type", "application/json").
+				WithBody(reqBody).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errValidationEmail, body.Code)
+		})
+
+		t.Run("409 - errDuplicateUser", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			reqBody, _ := json.Marshal(api.InviteUserRequest{
+				ExternalId: author.ExternalID,
+				AuthMethod: string(models.GitHubAuthMethod),
+			})
+
+			res := testutil.NewRequest().
+				Post(baseUsersPath).
+				WithHeader("Content-Type", "application/json").
+				WithBody(reqBody).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusConflict, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errDuplicateUser, body.Code)
+		})
+
+		t.Run("400 - errValidationFailed", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			reqBody, _ := json.Marshal(api.InviteUserRequest{
+				ExternalId: uuid.New().String(),
+				AuthMethod: "",
+			})
+
+			res := testutil.NewRequest().
+				Post(baseUsersPath).
+				WithHeader("Content-Type", "application/json").
+				WithBody(reqBody).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errValidationFailed, body.Code)
+			assert.Equal(t, "#/auth_method", *(*body.Errors)[0].Pointer)
+		})
+	})
+
+	t.Run("PostUsersIdDisable and PostUsersIdEnable", func(t *testing.T) {
+		t.Run("200 - OK", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post(fmt.Sprintf("%s/%d/disable", baseUsersPath, author.ID)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			var body api.UserResponse
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, api.Disabled, body.Status)
+
+			res = testutil.NewRequest().
+				Post(fmt.Sprintf("%s/%d/enable", baseUsersPath, author.ID)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusOK, res.Code())
+
+			err = res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, api.Active, body.Status)
+		})
+
+		t.Run("400 - admin can't disable itself", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post(fmt.Sprintf("%s/%d/disable", baseUsersPath, admin.ID)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code())
+		})
+
+		t.Run("404 - errUserNotFound", func(t *testing.T) {
+			e, _, mockJwtService, _, _ := registerHandlers(t, conn, nil)
+			mockJwtService.On("ParseTokenString", jwtToken).Return(admin.ExternalID, nil)
+
+			res := testutil.NewRequest().
+				Post(fmt.Sprintf("%s/%d/enable", baseUsersPath, 0)).
+				WithJWSAuth(jwtToken).
+				GoWithHTTPHandler(t, e)
+
+			assert.Equal(t, http.StatusNotFound, res.Code())
+
+			var body api.RequestError
+			err := res.UnmarshalBodyToObject(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, errUserNotFound, body.Code)
+		})
+	})
+}
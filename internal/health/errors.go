@@ -0,0 +1,8 @@
+package health
+
+import "errors"
+
+var (
+	ErrDisabled = errors.New("check is disabled")
+	ErrTimeout  = errors.New("check timed out")
+)
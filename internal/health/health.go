@@ -0,0 +1,184 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status of the single check.
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDisabled Status = "disabled" // StatusDisabled is reported if the check returns ErrDisabled.
+)
+
+// Checker checks a dependency of the service for the readiness probe.
+// Each subsystem implements its own Checker and registers it in the Service from its Wire provider.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// NewChecker creates a Checker from the function.
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return &funcChecker{name: name, check: check}
+}
+
+type funcChecker struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (c *funcChecker) Name() string                    { return c.name }
+func (c *funcChecker) Check(ctx context.Context) error { return c.check(ctx) }
+
+// Result of the single check.
+type Result struct {
+	Name      string
+	Status    Status
+	Critical  bool          // Critical is true if the service is not ready while the check is down.
+	Latency   time.Duration // Latency of the check when it was performed, the result may be cached.
+	Error     string        // Error is the reason of the StatusDown.
+	CheckedAt time.Time
+}
+
+// Report is the readiness of the service with the results of all checks in the order of registration.
+type Report struct {
+	Ready  bool
+	Checks []Result
+}
+
+type ServiceInterface interface {
+	Ready(ctx context.Context) *Report
+}
+
+// Service runs the registered checks for the readiness probe.
+// Results are cached for the ttl, so frequent probes from multiple sources do not hammer the dependencies.
+type Service struct {
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu     sync.Mutex
+	checks []*check
+}
+
+// check is the registered Checker with its cached result.
+// Note: mu is held while checking, so concurrent probes wait for the same result instead of checking again.
+type check struct {
+	checker  Checker
+	critical bool
+
+	mu     sync.Mutex
+	result *Result
+}
+
+// NewService creates a new Service that caches the results for the ttl and fails the checks after the timeout.
+func NewService(ttl, timeout time.Duration) *Service {
+	return &Service{
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+// Register adds the critical check, the service is not ready while it's down.
+func (s *Service) Register(c Checker) {
+	s.register(c, true)
+}
+
+// RegisterOptional adds the check that is only reported, e.g. for the dependency needed by a few endpoints.
+func (s *Service) RegisterOptional(c Checker) {
+	s.register(c, false)
+}
+
+func (s *Service) register(c Checker, critical bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checks = append(s.checks, &check{checker: c, critical: critical})
+}
+
+// Ready runs all checks concurrently, reusing the results not older than the ttl.
+func (s *Service) Ready(ctx context.Context) *Report {
+	s.mu.Lock()
+	checks := make([]*check, len(s.checks))
+	copy(checks, s.checks)
+	s.mu.Unlock()
+
+	report := &Report{
+		Ready:  true,
+		Checks: make([]Result, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = s.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range report.Checks {
+		if r.Critical && r.Status == StatusDown {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+// run returns the cached result of the check or performs it with the timeout.
+func (s *Service) run(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.result != nil && time.Since(c.result.CheckedAt) < s.ttl {
+		return *c.result
+	}
+
+	// Note: the check is not bound to the request, so the probe that gave up does not fail the cached result
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.check(ctx, c.checker)
+
+	result := &Result{
+		Name:      c.checker.Name(),
+		Status:    StatusUp,
+		Critical:  c.critical,
+		Latency:   time.Since(start),
+		CheckedAt: start,
+	}
+	switch {
+	case errors.Is(err, ErrDisabled):
+		result.Status = StatusDisabled
+	case err != nil:
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	c.result = result
+
+	return *result
+}
+
+// check calls the Checker, giving up after the ctx is done even if the Checker ignores it.
+func (s *Service) check(ctx context.Context, c Checker) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestService_Ready(t *testing.T) {
+	t.Run("ready if all checks are up", func(t *testing.T) {
+		s := NewService(time.Minute, time.Second)
+		s.Register(NewChecker("database", func(_ context.Context) error { return nil }))
+		s.RegisterOptional(NewChecker("mail", func(_ context.Context) error { return ErrDisabled }))
+
+		report := s.Ready(context.Background())
+
+		assert.True(t, report.Ready)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, "database", report.Checks[0].Name)
+		assert.Equal(t, StatusUp, report.Checks[0].Status)
+		assert.True(t, report.Checks[0].Critical)
+		assert.Equal(t, "mail", report.Checks[1].Name)
+		assert.Equal(t, StatusDisabled, report.Checks[1].Status)
+		assert.False(t, report.Checks[1].Critical)
+		assert.Empty(t, report.Checks[1].Error)
+	})
+
+	t.Run("not ready if the critical check is down", func(t *testing.T) {
+		s := NewService(time.Minute, time.Second)
+		s.Register(NewChecker("database", func(_ context.Context) error { return errors.New("connection refused") }))
+
+		report := s.Ready(context.Background())
+
+		assert.False(t, report.Ready)
+		assert.Equal(t, StatusDown, report.Checks[0].Status)
+		assert.Equal(t, "connection refused", report.Checks[0].Error)
+	})
+
+	t.Run("ready if the optional check is down", func(t *testing.T) {
+		s := NewService(time.Minute, time.Second)
+		s.RegisterOptional(NewChecker("mail", func(_ context.Context) error { return errors.New("unauthorized") }))
+
+		report := s.Ready(context.Background())
+
+		assert.True(t, report.Ready)
+		assert.Equal(t, StatusDown, report.Checks[0].Status)
+	})
+
+	t.Run("caches the results for the ttl", func(t *testing.T) {
+		s := NewService(50*time.Millisecond, time.Second)
+		var calls atomic.Int32
+		s.Register(NewChecker("database", func(_ context.Context) error {
+			calls.Add(1)
+			return nil
+		}))
+
+		first := s.Ready(context.Background())
+		second := s.Ready(context.Background())
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, first.Checks[0].CheckedAt, second.Checks[0].CheckedAt)
+
+		time.Sleep(60 * time.Millisecond)
+		s.Ready(context.Background())
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("times out the slow check", func(t *testing.T) {
+		s := NewService(time.Minute, 10*time.Millisecond)
+		release := make(chan struct{})
+		defer close(release)
+		s.Register(NewChecker("database", func(_ context.Context) error {
+			<-release
+			return nil
+		}))
+
+		report := s.Ready(context.Background())
+
+		assert.False(t, report.Ready)
+		assert.Equal(t, ErrTimeout.Error(), report.Checks[0].Error)
+	})
+}
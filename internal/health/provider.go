@@ -0,0 +1,23 @@
+package health
+
+import (
+	"time"
+
+	"github.com/google/wire"
+)
+
+const (
+	resultTTL    = 5 * time.Second // resultTTL is the time to reuse the check results for the following probes.
+	checkTimeout = 3 * time.Second // checkTimeout is the time after which the check is reported as down.
+)
+
+// ProvideService is a Wire provider function that creates a Service for the subsystems to register their checks.
+func ProvideService() *Service {
+	return NewService(resultTTL, checkTimeout)
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the health package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideService,
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
@@ -27,10 +27,26 @@ func NewService(signKey string) *Service {
 type ServiceInterface interface {
 	CreateTokenString(userID string, expiresAt time.Time) (jwtToken string, err error)
 	ParseTokenString(tokenString string) (externalUserID string, err error)
+	CreateScopedTokenString(scope, subject string, expiresAt time.Time) (jwtToken string, err error)
+	ParseScopedTokenString(scope, tokenString string) (subject string, err error)
 }
 
 // CreateTokenString creates a JWT token string with the given sign key and expiration time.
 func (s *Service) CreateTokenString(userID string, expiresAt time.Time) (jwtToken string, err error) {
+	return s.createTokenString(userID, nil, expiresAt)
+}
+
+// CreateScopedTokenString creates a JWT token string that is only valid for the given scope,
+// e.g. for the magic link. Scoped tokens are rejected by ParseTokenString, so they can't be used for auth.
+func (s *Service) CreateScopedTokenString(scope, subject string, expiresAt time.Time) (jwtToken string, err error) {
+	return s.createTokenString(subject, jwtgo.ClaimStrings{scope}, expiresAt)
+}
+
+func (s *Service) createTokenString(
+	userID string,
+	audience jwtgo.ClaimStrings,
+	expiresAt time.Time,
+) (jwtToken string, err error) {
 	if expiresAt.Before(time.Now()) {
 		return "", ErrExpiresAtMustBeInTheFuture
 	}
@@ -44,6 +60,7 @@ func (s *Service) CreateTokenString(userID string, expiresAt time.Time) (jwtToke
 			IssuedAt:  jwtgo.NewNumericDate(time.Now()),
 			NotBefore: jwtgo.NewNumericDate(time.Now()),
 			Issuer:    "go-bloggy",
+			Audience:  audience,
 		},
 	}
 
@@ -65,6 +82,23 @@ func (s *Service) ParseTokenString(tokenString string) (externalUserID string, e
 		return "", fmt.Errorf("%w: %w", ErrErrorParsingToken, err)
 	}
 
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid || len(claims.Audience) > 0 {
+		return "", ErrInvalidToken
+	}
+
+	return claims.UserID, nil
+}
+
+// ParseScopedTokenString parses a JWT token string created for the given scope and returns its subject.
+func (s *Service) ParseScopedTokenString(scope, tokenString string) (subject string, err error) {
+	token, err := jwtgo.ParseWithClaims(tokenString, &Claims{}, func(token *jwtgo.Token) (interface{}, error) {
+		return []byte(s.signKey), nil
+	}, jwtgo.WithAudience(scope))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrErrorParsingToken, err)
+	}
+
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
 		return "", ErrInvalidToken
@@ -95,6 +95,46 @@ func Test_ParseTokenString(t *testing.T) {
 	})
 }
 
+func Test_ParseScopedTokenString(t *testing.T) {
+	service := NewService("testKey")
+
+	t.Run("OK", func(t *testing.T) {
+		token, err := service.CreateScopedTokenString("magic-link", "linkID", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		subject, err := service.ParseScopedTokenString("magic-link", token)
+		assert.NoError(t, err)
+		assert.Equal(t, "linkID", subject)
+	})
+
+	t.Run("another scope", func(t *testing.T) {
+		token, err := service.CreateScopedTokenString("magic-link", "linkID", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		subject, err := service.ParseScopedTokenString("mfa", token)
+		assert.Empty(t, subject)
+		assert.ErrorIs(t, err, ErrErrorParsingToken)
+	})
+
+	t.Run("auth token can't be used as scoped token", func(t *testing.T) {
+		token, err := service.CreateTokenString("testUser", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		subject, err := service.ParseScopedTokenString("magic-link", token)
+		assert.Empty(t, subject)
+		assert.ErrorIs(t, err, ErrErrorParsingToken)
+	})
+
+	t.Run("scoped token can't be used as auth token", func(t *testing.T) {
+		token, err := service.CreateScopedTokenString("magic-link", "linkID", time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		userID, err := service.ParseTokenString(token)
+		assert.Empty(t, userID)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
 func Test_NewService(t *testing.T) {
 	service := NewService("testKey")
 	assert.Equal(t, "testKey", service.signKey)
@@ -0,0 +1,7 @@
+package lifecycle
+
+import "errors"
+
+var (
+	ErrStopTimeout = errors.New("subsystem did not stop in time")
+)
@@ -0,0 +1,158 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Stage of the shutdown. Hooks are stopped stage by stage, in the order of the constants.
+type Stage int
+
+const (
+	StageServer  Stage = iota // StageServer stops accepting new requests and drains the in-flight ones.
+	StageWorkers              // StageWorkers stops the background workers started with Manager.Go.
+	StageFlush                // StageFlush flushes the buffered data, e.g. the events reported to Sentry.
+	StageClose                // StageClose closes the connections, e.g. the database pool.
+)
+
+// StopFunc stops the subsystem. The context is cancelled when the shutdown timeout is exceeded.
+type StopFunc func(ctx context.Context) error
+
+type hook struct {
+	stage Stage
+	name  string
+	stop  StopFunc
+}
+
+// Manager runs the application until the termination signal and then stops its subsystems in order.
+// Providers register the StopFunc of their subsystems with OnStop, so new subsystems plug in without changes in main.
+type Manager struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	hooks []hook
+
+	workersCtx    context.Context //nolint:containedctx // cancelled to stop the workers
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// NewManager creates a new Manager, that waits for the subsystems to stop for up to the timeout.
+func NewManager(timeout time.Duration) *Manager {
+	workersCtx, cancelWorkers := context.WithCancel(context.Background())
+
+	m := &Manager{
+		timeout:       timeout,
+		workersCtx:    workersCtx,
+		cancelWorkers: cancelWorkers,
+	}
+	m.OnStop(StageWorkers, "workers", m.stopWorkers)
+
+	return m
+}
+
+// OnStop registers the StopFunc of the subsystem to be called on shutdown.
+// Hooks of the same stage are called in the reverse order of registration, like deferred calls.
+func (m *Manager) OnStop(stage Stage, name string, stop StopFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook{stage: stage, name: name, stop: stop})
+}
+
+// Go starts the background worker. Its context is cancelled on StageWorkers and the shutdown waits for it to return.
+func (m *Manager) Go(name string, worker func(ctx context.Context) error) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+
+		if err := worker(m.workersCtx); err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("[lifecycle] Worker failed", "worker", name, "error", err)
+		}
+	}()
+}
+
+// Run calls start (e.g. to start the HTTP server) and blocks until SIGINT, SIGTERM, the ctx is done
+// or start returns. Then all subsystems are stopped with Shutdown.
+// If start returns an error before the shutdown, it's returned along with the shutdown errors.
+func (m *Manager) Run(ctx context.Context, start func() error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	startErr := make(chan error, 1)
+	go func() {
+		startErr <- start()
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		slog.Info("[lifecycle] Shutting down...")
+	case err = <-startErr:
+		if err != nil {
+			err = fmt.Errorf("start: %w", err)
+		}
+	}
+
+	return errors.Join(err, m.Shutdown(context.Background()))
+}
+
+// Shutdown stops all subsystems stage by stage within the timeout and returns the errors of all failed hooks.
+// Only the first call stops the subsystems, the following calls return the same result.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.shutdownOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		hooks := make([]hook, len(m.hooks))
+		copy(hooks, m.hooks)
+		m.mu.Unlock()
+
+		// Note: reverse first, so the stable sort keeps the hooks of the same stage in the reverse order
+		for i, j := 0, len(hooks)-1; i < j; i, j = i+1, j-1 {
+			hooks[i], hooks[j] = hooks[j], hooks[i]
+		}
+		sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].stage < hooks[j].stage })
+
+		var errs []error
+		for _, h := range hooks {
+			if err := h.stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stop %s: %w", h.name, err))
+			}
+		}
+
+		m.shutdownErr = errors.Join(errs...)
+	})
+
+	return m.shutdownErr
+}
+
+// stopWorkers cancels the context of the workers and waits for them to return.
+func (m *Manager) stopWorkers(ctx context.Context) error {
+	m.cancelWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrStopTimeout, ctx.Err())
+	}
+}
@@ -0,0 +1,149 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Run("stops the hooks stage by stage", func(t *testing.T) {
+		m := NewManager(time.Second)
+
+		var mu sync.Mutex
+		var stopped []string
+		record := func(name string) StopFunc {
+			return func(_ context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				stopped = append(stopped, name)
+				return nil
+			}
+		}
+
+		// Note: the database is registered before the server, like Wire constructs them
+		m.OnStop(StageClose, "database", record("database"))
+		m.OnStop(StageFlush, "sentry", record("sentry"))
+		m.OnStop(StageServer, "http server", record("http server"))
+		m.OnStop(StageClose, "cache", record("cache"))
+		m.Go("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			_ = record("worker")(ctx)
+			return ctx.Err()
+		})
+
+		err := m.Shutdown(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"http server", "worker", "sentry", "cache", "database"}, stopped)
+	})
+
+	t.Run("returns the errors of all hooks", func(t *testing.T) {
+		m := NewManager(time.Second)
+		errFirst := errors.New("first")
+		errSecond := errors.New("second")
+
+		m.OnStop(StageServer, "first", func(_ context.Context) error { return errFirst })
+		m.OnStop(StageClose, "second", func(_ context.Context) error { return errSecond })
+
+		err := m.Shutdown(context.Background())
+
+		assert.ErrorIs(t, err, errFirst)
+		assert.ErrorIs(t, err, errSecond)
+		assert.ErrorContains(t, err, "stop first: first")
+	})
+
+	t.Run("stops only once", func(t *testing.T) {
+		m := NewManager(time.Second)
+		calls := 0
+		m.OnStop(StageClose, "database", func(_ context.Context) error {
+			calls++
+			return nil
+		})
+
+		assert.NoError(t, m.Shutdown(context.Background()))
+		assert.NoError(t, m.Shutdown(context.Background()))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("times out the stuck workers", func(t *testing.T) {
+		m := NewManager(10 * time.Millisecond)
+		release := make(chan struct{})
+		defer close(release)
+
+		m.Go("stuck", func(_ context.Context) error {
+			<-release
+			return nil
+		})
+
+		var deadline bool
+		m.OnStop(StageClose, "database", func(ctx context.Context) error {
+			_, deadline = ctx.Deadline()
+			return nil
+		})
+
+		err := m.Shutdown(context.Background())
+
+		assert.ErrorIs(t, err, ErrStopTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, deadline, "the following stages are still called")
+	})
+}
+
+func TestManager_Run(t *testing.T) {
+	t.Run("shuts down on SIGTERM", func(t *testing.T) {
+		m := NewManager(time.Second)
+		stopped := make(chan struct{})
+		m.OnStop(StageServer, "http server", func(_ context.Context) error {
+			close(stopped)
+			return nil
+		})
+
+		started := make(chan struct{})
+		go func() {
+			<-started
+			// Note: give Run the time to subscribe to the signal
+			time.Sleep(50 * time.Millisecond)
+			_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+		}()
+
+		err := m.Run(context.Background(), func() error {
+			close(started)
+			<-stopped
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("shuts down when the ctx is done", func(t *testing.T) {
+		m := NewManager(time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := m.Run(ctx, func() error {
+			select {}
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("shuts down if start fails", func(t *testing.T) {
+		m := NewManager(time.Second)
+		errStart := errors.New("address already in use")
+		closed := false
+		m.OnStop(StageClose, "database", func(_ context.Context) error {
+			closed = true
+			return nil
+		})
+
+		err := m.Run(context.Background(), func() error { return errStart })
+
+		assert.ErrorIs(t, err, errStart)
+		assert.True(t, closed)
+	})
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestGormLogger_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), New(&Config{Format: FormatJSON}, &buf).With("request_id", "1"))
+	l := NewGormLogger(100 * time.Millisecond)
+	fc := func() (string, int64) { return `SELECT * FROM "users" WHERE external_id = $1`, 0 }
+
+	t.Run("failed query", func(t *testing.T) {
+		l.Trace(ctx, time.Now(), fc, errors.New("connection refused"))
+
+		r := record(t, &buf)
+		assert.Equal(t, "Query failed", r["msg"])
+		assert.Equal(t, "1", r["request_id"])
+		assert.Equal(t, `SELECT * FROM "users" WHERE external_id = $1`, r["sql"])
+		assert.Equal(t, "connection refused", r["error"])
+	})
+
+	t.Run("slow query", func(t *testing.T) {
+		l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+
+		r := record(t, &buf)
+		assert.Equal(t, "Slow query", r["msg"])
+		assert.Equal(t, "WARN", r["level"])
+	})
+
+	t.Run("not found and fast queries are not logged", func(t *testing.T) {
+		l.Trace(ctx, time.Now(), fc, gorm.ErrRecordNotFound)
+		l.Trace(ctx, time.Now(), fc, nil)
+
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("all queries on the debug level", func(t *testing.T) {
+		ctx := WithLogger(context.Background(), New(&Config{Format: FormatJSON, Level: slog.LevelDebug}, &buf))
+		l.Trace(ctx, time.Now(), fc, nil)
+
+		assert.Equal(t, "Query", record(t, &buf)["msg"])
+	})
+}
+
+func TestGormLogger_ParamsFilter(t *testing.T) {
+	sql, params := NewGormLogger(0).ParamsFilter(context.Background(), "SELECT $1", "john@example.com")
+
+	assert.Equal(t, "SELECT $1", sql)
+	assert.Nil(t, params)
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// Formats of the Config.
+const (
+	FormatJSON = "json" // FormatJSON is for production, where the logs are collected and parsed.
+	FormatText = "text" // FormatText is for development, where the logs are read in the terminal.
+)
+
+// Config of the logger.
+type Config struct {
+	Format string
+	Level  slog.Level
+	Redact bool // Redact masks the emails and removes the tokens from the logs, see ReplaceAttr.
+}
+
+// New creates the logger writing to the w in the format from the Config.
+func New(cfg *Config, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+	if cfg.Redact {
+		opts.ReplaceAttr = ReplaceAttr
+	}
+
+	if cfg.Format == FormatText {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+type loggerKey struct{}
+
+// WithLogger returns the ctx with the logger, e.g. with the request ID of the request.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of the ctx or the default one, if the ctx has no logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// With returns the ctx with the logger of the ctx that includes the args in each record, e.g. the user ID.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRequestIDLength limits the request ID from the client, so it can't flood the logs.
+const maxRequestIDLength = 128
+
+// Middleware adds the request-scoped logger to the request context and logs the request when it's done.
+// The logger includes the request ID, the operation ID from the spec and the trace ID, if the request is traced.
+// The request ID is taken from the X-Request-ID header, if it's valid, or generated, and returned in the response.
+func Middleware(logger *slog.Logger, spec *openapi3.T) echo.MiddlewareFunc {
+	operations := api.NewOperations(spec)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			req := ctx.Request()
+
+			id := requestID(req.Header.Get(echo.HeaderXRequestID))
+			ctx.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			args := []any{"request_id", id, "operation", operations.ID(ctx)}
+			if span := trace.SpanContextFromContext(req.Context()); span.IsValid() {
+				args = append(args, "trace_id", span.TraceID().String())
+			}
+			ctx.SetRequest(req.WithContext(WithLogger(req.Context(), logger.With(args...))))
+
+			err := next(ctx)
+			if err != nil {
+				// Note: let echo write the error response to log its status code, like the metrics middleware
+				ctx.Error(err)
+			}
+
+			level := slog.LevelInfo
+			if ctx.Response().Status >= 500 {
+				level = slog.LevelError
+			}
+
+			// Note: the logger is taken from the request again, as it can be extended by the handler, e.g. with the user ID
+			reqCtx := ctx.Request().Context()
+			FromContext(reqCtx).LogAttrs(reqCtx, level, "Request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("status", ctx.Response().Status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes_out", ctx.Response().Size),
+				slog.String("remote_ip", ctx.RealIP()),
+			)
+
+			return err
+		}
+	}
+}
+
+// requestID returns the id from the client, if it's valid, or a new one.
+func requestID(id string) string {
+	if id == "" || len(id) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+
+	for _, c := range id {
+		if !isRequestIDChar(c) {
+			return uuid.NewString()
+		}
+	}
+
+	return id
+}
+
+func isRequestIDChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+		c == '-' || c == '_' || c == '.' || c == ':'
+}
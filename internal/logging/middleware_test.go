@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	spec, err := api.GetSwagger()
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(Middleware(New(&Config{Format: FormatJSON}, &buf), spec))
+	e.GET("/posts/:slug", func(ctx echo.Context) error {
+		req := ctx.Request()
+		ctx.SetRequest(req.WithContext(With(req.Context(), "user_id", "42")))
+		FromContext(ctx.Request().Context()).Info("Handling")
+
+		return ctx.NoContent(http.StatusOK)
+	})
+	e.GET("/health", func(_ echo.Context) error {
+		return errors.New("database is down")
+	})
+
+	t.Run("honours the request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/first", nil)
+		req.Header.Set(echo.HeaderXRequestID, "request-1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "request-1", rec.Header().Get(echo.HeaderXRequestID))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+		buf.Reset()
+
+		for _, line := range lines {
+			buf.WriteString(line)
+			r := record(t, &buf)
+			assert.Equal(t, "request-1", r["request_id"])
+			assert.Equal(t, "GetPostsSlug", r["operation"])
+			assert.Equal(t, "42", r["user_id"])
+		}
+	})
+
+	t.Run("generates the request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(echo.HeaderXRequestID, "invalid id\n")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(echo.HeaderXRequestID)
+		assert.Len(t, id, 36)
+
+		r := record(t, &buf)
+		assert.Equal(t, "Request", r["msg"])
+		assert.Equal(t, "ERROR", r["level"])
+		assert.Equal(t, id, r["request_id"])
+		assert.Equal(t, "GetHealth", r["operation"])
+		assert.InDelta(t, http.StatusInternalServerError, r["status"], 0)
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		r := record(t, &buf)
+		assert.Equal(t, api.UnknownOperation, r["operation"])
+		assert.InDelta(t, http.StatusNotFound, r["status"], 0)
+	})
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values of the secrets.
+const redacted = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`) //nolint:gochecknoglobals // constant
+	jwtPattern   = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]+`)                        //nolint:gochecknoglobals // constant
+)
+
+// secretKeys are the keys of the attributes, which values are always redacted, e.g. "token" or "refresh_token".
+var secretKeys = []string{"token", "password", "secret", "authorization", "api_key", "cookie"} //nolint:gochecknoglobals // constant
+
+// ReplaceAttr redacts the secrets of the attribute for slog.HandlerOptions:
+// the values of the secretKeys are removed, the emails are masked to keep only the first letter and the domain,
+// and the JWT tokens are removed from all strings and errors, including the message.
+func ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if isSecretKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch a.Value.Kind() { //nolint:exhaustive // only the strings can contain the secrets
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+
+	return a
+}
+
+// Redact masks the emails and removes the JWT tokens from the s, e.g. "john@example.com" to "j***@example.com".
+func Redact(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		at := strings.LastIndex(email, "@")
+		return email[:1] + "***" + email[at:]
+	})
+
+	return jwtPattern.ReplaceAllString(s, redacted)
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if key == secret || strings.HasSuffix(key, "_"+secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,25 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/samgozman/go-bloggy/internal/config"
+)
+
+// Setup sets the default logger from the config, so the logs of all commands are in the same format.
+// Note: the subsystems log with FromContext, which falls back to the default logger outside the requests.
+func Setup(cfg *config.Config) *slog.Logger {
+	var level slog.Level
+	// Note: the level is validated with the config
+	_ = level.UnmarshalText([]byte(cfg.Log.Level))
+
+	logger := New(&Config{
+		Format: cfg.Log.Format,
+		Level:  level,
+		Redact: cfg.Log.Redact,
+	}, os.Stdout)
+	slog.SetDefault(logger)
+
+	return logger
+}
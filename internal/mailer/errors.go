@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrSendConfirmationMail = errors.New("error sending confirmation mail")
 	ErrSendPostMail         = errors.New("error sending post mail")
+	ErrSendMagicLinkMail    = errors.New("error sending magic link mail")
+	ErrListSenders          = errors.New("error listing senders")
+	ErrSenderNotActive      = errors.New("sender is not active")
 )
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+)
+
+// instrumentedService counts the sent and failed emails of the wrapped service.
+type instrumentedService struct {
+	service types.ServiceInterface
+	metrics *metrics.Metrics
+}
+
+func newInstrumentedService(service types.ServiceInterface, m *metrics.Metrics) *instrumentedService {
+	return &instrumentedService{
+		service: service,
+		metrics: m,
+	}
+}
+
+func (s *instrumentedService) SendConfirmationEmail(ctx context.Context, to, confirmationID string) error {
+	err := s.service.SendConfirmationEmail(ctx, to, confirmationID)
+	s.record(metrics.EmailConfirmation, 1, err)
+
+	return err
+}
+
+func (s *instrumentedService) SendPostEmail(ctx context.Context, pe *types.PostEmailSend) error {
+	err := s.service.SendPostEmail(ctx, pe)
+	s.record(metrics.EmailPost, len(pe.To), err)
+
+	return err
+}
+
+func (s *instrumentedService) SendMagicLinkEmail(ctx context.Context, to, token string) error {
+	err := s.service.SendMagicLinkEmail(ctx, to, token)
+	s.record(metrics.EmailMagicLink, 1, err)
+
+	return err
+}
+
+func (s *instrumentedService) record(emailType string, count int, err error) {
+	if err != nil {
+		s.metrics.EmailFailed(emailType, count)
+		return
+	}
+
+	s.metrics.EmailSent(emailType, count)
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockMailer "github.com/samgozman/go-bloggy/mocks/mailer"
+)
+
+func TestInstrumentedService(t *testing.T) {
+	mockService := mockMailer.NewMockServiceInterface(t)
+	m := metrics.New(&metrics.Config{})
+	s := newInstrumentedService(mockService, m)
+
+	pe := &types.PostEmailSend{To: []*types.Subscriber{{ID: "1"}, {ID: "2"}}}
+	mockService.On("SendPostEmail", mock.Anything, pe).Return(nil)
+	mockService.On("SendConfirmationEmail", mock.Anything, "test@example.com", "123").Return(errors.New("error"))
+	mockService.On("SendMagicLinkEmail", mock.Anything, "test@example.com", "token").Return(nil)
+
+	assert.NoError(t, s.SendPostEmail(context.Background(), pe))
+	assert.Error(t, s.SendConfirmationEmail(context.Background(), "test@example.com", "123"))
+	assert.NoError(t, s.SendMagicLinkEmail(context.Background(), "test@example.com", "token"))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `bloggy_emails_total{result="sent",type="post"} 2`)
+	assert.Contains(t, rec.Body.String(), `bloggy_emails_total{result="failed",type="confirmation"} 1`)
+	assert.Contains(t, rec.Body.String(), `bloggy_emails_total{result="sent",type="magic_link"} 1`)
+}
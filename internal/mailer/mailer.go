@@ -1,9 +1,12 @@
 package mailer
 
 import (
+	"context"
 	"fmt"
 	"github.com/mailjet/mailjet-apiv3-go/v4"
+	"github.com/mailjet/mailjet-apiv3-go/v4/resources"
 	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"net/http"
 )
 
 type Service struct {
@@ -11,14 +14,18 @@ type Service struct {
 	options *types.Options
 }
 
-func NewService(publicKey, privateKey string, options *types.Options) *Service {
+// NewService creates a new Service, that calls the Mailjet API with the httpClient.
+func NewService(publicKey, privateKey string, options *types.Options, httpClient *http.Client) *Service {
+	client := mailjet.NewMailjetClient(publicKey, privateKey)
+	client.SetClient(httpClient)
+
 	return &Service{
-		client:  mailjet.NewMailjetClient(publicKey, privateKey),
+		client:  client,
 		options: options,
 	}
 }
 
-func (s *Service) SendConfirmationEmail(to, confirmationID string) error {
+func (s *Service) SendConfirmationEmail(ctx context.Context, to, confirmationID string) error {
 	messagesInfo := []mailjet.InfoMessagesV31{
 		{
 			From: &mailjet.RecipientV31{
@@ -40,7 +47,7 @@ func (s *Service) SendConfirmationEmail(to, confirmationID string) error {
 		},
 	}
 
-	_, err := s.client.SendMailV31(&mailjet.MessagesV31{Info: messagesInfo})
+	_, err := s.client.SendMailV31(&mailjet.MessagesV31{Info: messagesInfo}, mailjet.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrSendConfirmationMail, err)
 	}
@@ -48,7 +55,7 @@ func (s *Service) SendConfirmationEmail(to, confirmationID string) error {
 	return nil
 }
 
-func (s *Service) SendPostEmail(pe *types.PostEmailSend) error {
+func (s *Service) SendPostEmail(ctx context.Context, pe *types.PostEmailSend) error {
 	messageFrom := mailjet.RecipientV31{
 		Email: s.options.FromEmail,
 		Name:  s.options.FromName,
@@ -76,10 +83,64 @@ func (s *Service) SendPostEmail(pe *types.PostEmailSend) error {
 		}
 	}
 
-	_, err := s.client.SendMailV31(&mailjet.MessagesV31{Info: messagesInfo})
+	_, err := s.client.SendMailV31(&mailjet.MessagesV31{Info: messagesInfo}, mailjet.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrSendPostMail, err)
 	}
 
 	return nil
 }
+
+func (s *Service) SendMagicLinkEmail(ctx context.Context, to, token string) error {
+	messagesInfo := []mailjet.InfoMessagesV31{
+		{
+			From: &mailjet.RecipientV31{
+				Email: s.options.FromEmail,
+				Name:  s.options.FromName,
+			},
+			To: &mailjet.RecipientsV31{
+				mailjet.RecipientV31{
+					Email: to,
+				},
+			},
+			Subject:          "Your sign in link",
+			TemplateID:       s.options.MagicLinkTemplateID,
+			TemplateLanguage: true,
+			Variables: map[string]interface{}{
+				"magic_link": s.options.MagicLinkTemplateURLParam + token,
+			},
+		},
+	}
+
+	_, err := s.client.SendMailV31(&mailjet.MessagesV31{Info: messagesInfo}, mailjet.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSendMagicLinkMail, err)
+	}
+
+	return nil
+}
+
+// Name returns the name of the health check.
+func (s *Service) Name() string {
+	return "mail"
+}
+
+// Check verifies the Mailjet credentials and that the FromEmail sender is active, e.g. for the readiness probe.
+func (s *Service) Check(ctx context.Context) error {
+	var senders []resources.Sender
+	_, _, err := s.client.List("sender", &senders,
+		mailjet.Filter("Email", s.options.FromEmail),
+		mailjet.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrListSenders, err)
+	}
+
+	for _, sender := range senders {
+		if sender.Status == "Active" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrSenderNotActive, s.options.FromEmail)
+}
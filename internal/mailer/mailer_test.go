@@ -1,11 +1,14 @@
 package mailer
 
 import (
+	"context"
 	"errors"
 	"github.com/mailjet/mailjet-apiv3-go/v4"
+	"github.com/mailjet/mailjet-apiv3-go/v4/resources"
 	"github.com/samgozman/go-bloggy/internal/mailer/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"net/http"
 	"testing"
 
 	mockMailer "github.com/samgozman/go-bloggy/mocks/mailer"
@@ -14,24 +17,24 @@ import (
 func TestService_SendConfirmationEmail(t *testing.T) {
 	t.Run("OK", func(t *testing.T) {
 		mockClient := mockMailer.NewMockMailjetInterface(t)
-		s := NewService("", "", &types.Options{})
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
 		s.client = mockClient
 
-		mockClient.On("SendMailV31", mock.Anything).Return(&mailjet.ResultsV31{}, nil)
+		mockClient.On("SendMailV31", mock.Anything, mock.Anything).Return(&mailjet.ResultsV31{}, nil)
 
-		err := s.SendConfirmationEmail("test@example.com", "123")
+		err := s.SendConfirmationEmail(context.Background(), "test@example.com", "123")
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
 	})
 
 	t.Run("Error", func(t *testing.T) {
 		mockClient := mockMailer.NewMockMailjetInterface(t)
-		s := NewService("", "", &types.Options{})
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
 		s.client = mockClient
 
-		mockClient.On("SendMailV31", mock.Anything).Return(&mailjet.ResultsV31{}, errors.New("error"))
+		mockClient.On("SendMailV31", mock.Anything, mock.Anything).Return(&mailjet.ResultsV31{}, errors.New("error"))
 
-		err := s.SendConfirmationEmail("test@example.com", "123")
+		err := s.SendConfirmationEmail(context.Background(), "test@example.com", "123")
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrSendConfirmationMail)
 		mockClient.AssertExpectations(t)
@@ -41,12 +44,12 @@ func TestService_SendConfirmationEmail(t *testing.T) {
 func TestService_SendPostEmail(t *testing.T) {
 	t.Run("OK", func(t *testing.T) {
 		mockClient := mockMailer.NewMockMailjetInterface(t)
-		s := NewService("", "", &types.Options{})
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
 		s.client = mockClient
 
-		mockClient.On("SendMailV31", mock.Anything).Return(&mailjet.ResultsV31{}, nil)
+		mockClient.On("SendMailV31", mock.Anything, mock.Anything).Return(&mailjet.ResultsV31{}, nil)
 
-		err := s.SendPostEmail(&types.PostEmailSend{
+		err := s.SendPostEmail(context.Background(), &types.PostEmailSend{
 			To: []*types.Subscriber{
 				{
 					ID:    "123",
@@ -63,12 +66,12 @@ func TestService_SendPostEmail(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		mockClient := mockMailer.NewMockMailjetInterface(t)
-		s := NewService("", "", &types.Options{})
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
 		s.client = mockClient
 
-		mockClient.On("SendMailV31", mock.Anything).Return(&mailjet.ResultsV31{}, errors.New("error"))
+		mockClient.On("SendMailV31", mock.Anything, mock.Anything).Return(&mailjet.ResultsV31{}, errors.New("error"))
 
-		err := s.SendPostEmail(&types.PostEmailSend{
+		err := s.SendPostEmail(context.Background(), &types.PostEmailSend{
 			To: []*types.Subscriber{
 				{
 					ID:    "123",
@@ -84,3 +87,78 @@ func TestService_SendPostEmail(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestService_SendMagicLinkEmail(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		mockClient := mockMailer.NewMockMailjetInterface(t)
+		s := NewService("", "", &types.Options{
+			MagicLinkTemplateID:       1,
+			MagicLinkTemplateURLParam: "https://example.com/login/email?token=",
+		}, http.DefaultClient)
+		s.client = mockClient
+
+		mockClient.On("SendMailV31", mock.MatchedBy(func(m *mailjet.MessagesV31) bool {
+			return m.Info[0].Variables["magic_link"] == "https://example.com/login/email?token=123"
+		}), mock.Anything).Return(&mailjet.ResultsV31{}, nil)
+
+		err := s.SendMagicLinkEmail(context.Background(), "test@example.com", "123")
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockClient := mockMailer.NewMockMailjetInterface(t)
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
+		s.client = mockClient
+
+		mockClient.On("SendMailV31", mock.Anything, mock.Anything).Return(&mailjet.ResultsV31{}, errors.New("error"))
+
+		err := s.SendMagicLinkEmail(context.Background(), "test@example.com", "123")
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrSendMagicLinkMail)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestService_Check(t *testing.T) {
+	setSenders := func(senders ...resources.Sender) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*[]resources.Sender) = senders
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		mockClient := mockMailer.NewMockMailjetInterface(t)
+		s := NewService("", "", &types.Options{FromEmail: "blog@example.com"}, http.DefaultClient)
+		s.client = mockClient
+
+		mockClient.On("List", "sender", mock.Anything, mock.Anything, mock.Anything).
+			Run(setSenders(resources.Sender{Email: "blog@example.com", Status: "Active"})).
+			Return(1, 1, nil)
+
+		assert.NoError(t, s.Check(context.Background()))
+	})
+
+	t.Run("sender is not active", func(t *testing.T) {
+		mockClient := mockMailer.NewMockMailjetInterface(t)
+		s := NewService("", "", &types.Options{FromEmail: "blog@example.com"}, http.DefaultClient)
+		s.client = mockClient
+
+		mockClient.On("List", "sender", mock.Anything, mock.Anything, mock.Anything).
+			Run(setSenders(resources.Sender{Email: "blog@example.com", Status: "Inactive"})).
+			Return(1, 1, nil)
+
+		assert.ErrorIs(t, s.Check(context.Background()), ErrSenderNotActive)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockClient := mockMailer.NewMockMailjetInterface(t)
+		s := NewService("", "", &types.Options{}, http.DefaultClient)
+		s.client = mockClient
+
+		mockClient.On("List", "sender", mock.Anything, mock.Anything, mock.Anything).
+			Return(0, 0, errors.New("unauthorized"))
+
+		assert.ErrorIs(t, s.Check(context.Background()), ErrListSenders)
+	})
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/samgozman/go-bloggy/internal/health"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+)
+
+// NopService only logs the emails instead of sending them, used if the mail is disabled.
+type NopService struct{}
+
+// NewNopService creates a new NopService.
+func NewNopService() *NopService {
+	return &NopService{}
+}
+
+func (s *NopService) SendConfirmationEmail(ctx context.Context, to, _ string) error {
+	logging.FromContext(ctx).Info("[mailer] Mail is disabled, confirmation email is not sent", "to", to)
+	return nil
+}
+
+func (s *NopService) SendPostEmail(ctx context.Context, pe *types.PostEmailSend) error {
+	logging.FromContext(ctx).Info("[mailer] Mail is disabled, post email is not sent", "slug", pe.Slug, "recipients", len(pe.To))
+	return nil
+}
+
+func (s *NopService) SendMagicLinkEmail(ctx context.Context, to, _ string) error {
+	logging.FromContext(ctx).Info("[mailer] Mail is disabled, magic link email is not sent", "to", to)
+	return nil
+}
+
+// Name returns the name of the health check.
+func (s *NopService) Name() string {
+	return "mail"
+}
+
+// Check reports the mail as disabled.
+func (s *NopService) Check(_ context.Context) error {
+	return health.ErrDisabled
+}
@@ -3,11 +3,16 @@ package mailer
 import (
 	"github.com/google/wire"
 	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/health"
 	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config is a struct that holds all the configuration for mailer.
 type Config struct {
+	Enabled    bool
 	PublicKey  string
 	PrivateKey string
 	Options    *types.Options
@@ -16,6 +21,7 @@ type Config struct {
 // ProvideConfig is a wire provider function for mailer.Config.
 func ProvideConfig(cfg *config.Config) *Config {
 	return &Config{
+		Enabled:    cfg.MailerJet.Enabled,
 		PublicKey:  cfg.MailerJet.PublicKey,
 		PrivateKey: cfg.MailerJet.PrivateKey,
 		Options: &types.Options{
@@ -26,18 +32,37 @@ func ProvideConfig(cfg *config.Config) *Config {
 			PostTemplateID:               cfg.MailerJet.PostTemplateID,
 			PostTemplateURLParam:         cfg.MailerJet.PostTemplateURLParam,
 			UnsubscribeURLParam:          cfg.MailerJet.UnsubscribeURLParam,
+			MagicLinkTemplateID:          cfg.MailerJet.MagicLinkTemplateID,
+			MagicLinkTemplateURLParam:    cfg.MailerJet.MagicLinkTemplateURLParam,
 		},
 	}
 }
 
 // ProvideService is a wire provider function for mailer.Service.
-func ProvideService(cfg *Config) *Service {
-	return NewService(cfg.PublicKey, cfg.PrivateKey, cfg.Options)
+// It returns NopService if the mail is disabled, otherwise the sent and failed emails are counted in the metrics
+// and traced along with the Mailjet API calls.
+// The mail health check is optional, as only the subscriptions and the sign in with email depend on it.
+func ProvideService(
+	cfg *Config,
+	hs *health.Service,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+) types.ServiceInterface {
+	if !cfg.Enabled {
+		s := NewNopService()
+		hs.RegisterOptional(s)
+
+		return s
+	}
+
+	s := NewService(cfg.PublicKey, cfg.PrivateKey, cfg.Options, tracing.HTTPClient(tp))
+	hs.RegisterOptional(s)
+
+	return newTracedService(newInstrumentedService(s, m), tp)
 }
 
 // ProviderSet is a wire.ProviderSet for mailer package.
 var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
 	ProvideConfig,
 	ProvideService,
-	wire.Bind(new(types.ServiceInterface), new(*Service)),
 )
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"github.com/samgozman/go-bloggy/internal/health"
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+	"testing"
+	"time"
+)
+
+func TestProvideService(t *testing.T) {
+	t.Run("Mailjet", func(t *testing.T) {
+		hs := health.NewService(time.Second, time.Second)
+		got := ProvideService(&Config{Enabled: true, Options: &types.Options{}}, hs, metrics.New(&metrics.Config{}),
+			noop.NewTracerProvider())
+		assert.IsType(t, &tracedService{}, got)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		hs := health.NewService(time.Second, time.Second)
+		got := ProvideService(&Config{Enabled: false}, hs, metrics.New(&metrics.Config{}), noop.NewTracerProvider())
+		assert.IsType(t, &NopService{}, got)
+
+		report := hs.Ready(context.Background())
+		assert.True(t, report.Ready)
+		assert.Equal(t, "mail", report.Checks[0].Name)
+		assert.Equal(t, health.StatusDisabled, report.Checks[0].Status)
+
+		assert.NoError(t, got.SendConfirmationEmail(context.Background(), "test@example.com", "123"))
+		assert.NoError(t, got.SendPostEmail(context.Background(), &types.PostEmailSend{Slug: "test"}))
+		assert.NoError(t, got.SendMagicLinkEmail(context.Background(), "test@example.com", "token"))
+	})
+}
@@ -0,0 +1,65 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/samgozman/go-bloggy/internal/mailer"
+
+// tracedService records the span for each email of the wrapped service.
+// Note: the recipients are not recorded, only their number, to keep the emails out of the traces.
+type tracedService struct {
+	service types.ServiceInterface
+	tracer  trace.Tracer
+}
+
+func newTracedService(service types.ServiceInterface, tp trace.TracerProvider) *tracedService {
+	return &tracedService{
+		service: service,
+		tracer:  tp.Tracer(tracerName),
+	}
+}
+
+func (s *tracedService) SendConfirmationEmail(ctx context.Context, to, confirmationID string) error {
+	ctx, span := s.start(ctx, "mailer.SendConfirmationEmail", 1)
+	defer span.End()
+
+	return record(span, s.service.SendConfirmationEmail(ctx, to, confirmationID))
+}
+
+func (s *tracedService) SendPostEmail(ctx context.Context, pe *types.PostEmailSend) error {
+	ctx, span := s.start(ctx, "mailer.SendPostEmail", len(pe.To))
+	defer span.End()
+	span.SetAttributes(attribute.String("post.slug", pe.Slug))
+
+	return record(span, s.service.SendPostEmail(ctx, pe))
+}
+
+func (s *tracedService) SendMagicLinkEmail(ctx context.Context, to, token string) error {
+	ctx, span := s.start(ctx, "mailer.SendMagicLinkEmail", 1)
+	defer span.End()
+
+	return record(span, s.service.SendMagicLinkEmail(ctx, to, token))
+}
+
+func (s *tracedService) start(ctx context.Context, name string, recipients int) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("mail.recipients", recipients)),
+	)
+}
+
+// record marks the span as failed with the err, if any, and returns the err.
+func record(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+
+	mockMailer "github.com/samgozman/go-bloggy/mocks/mailer"
+)
+
+func TestTracedService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	mockService := mockMailer.NewMockServiceInterface(t)
+	s := newTracedService(mockService, tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	pe := &types.PostEmailSend{To: []*types.Subscriber{{ID: "1"}, {ID: "2"}}, Slug: "test-post"}
+	mockService.On("SendPostEmail", mock.Anything, pe).Return(nil)
+	mockService.On("SendConfirmationEmail", mock.Anything, "test@example.com", "123").Return(errors.New("error"))
+
+	assert.NoError(t, s.SendPostEmail(ctx, pe))
+	assert.Error(t, s.SendConfirmationEmail(ctx, "test@example.com", "123"))
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	post := spans[0]
+	assert.Equal(t, "mailer.SendPostEmail", post.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), post.Parent().SpanID())
+	assert.Contains(t, post.Attributes(), attribute.Int("mail.recipients", 2))
+	assert.Contains(t, post.Attributes(), attribute.String("post.slug", "test-post"))
+	assert.Equal(t, codes.Unset, post.Status().Code)
+
+	confirmation := spans[1]
+	assert.Equal(t, "mailer.SendConfirmationEmail", confirmation.Name())
+	assert.Equal(t, codes.Error, confirmation.Status().Code)
+	for _, a := range confirmation.Attributes() {
+		assert.NotEqual(t, "test@example.com", a.Value.Emit(), "the recipient is not recorded")
+	}
+}
@@ -1,14 +1,20 @@
 package types
 
-import "github.com/mailjet/mailjet-apiv3-go/v4"
+import (
+	"context"
+
+	"github.com/mailjet/mailjet-apiv3-go/v4"
+)
 
 type MailjetInterface interface {
 	SendMailV31(data *mailjet.MessagesV31, options ...mailjet.RequestOptions) (*mailjet.ResultsV31, error)
+	List(resource string, resp interface{}, options ...mailjet.RequestOptions) (count, total int, err error)
 }
 
 type ServiceInterface interface {
-	SendConfirmationEmail(to, confirmationID string) error
-	SendPostEmail(pe *PostEmailSend) error
+	SendConfirmationEmail(ctx context.Context, to, confirmationID string) error
+	SendPostEmail(ctx context.Context, pe *PostEmailSend) error
+	SendMagicLinkEmail(ctx context.Context, to, token string) error
 }
 
 type PostEmailSend struct {
@@ -31,4 +37,6 @@ type Options struct {
 	PostTemplateID               int
 	PostTemplateURLParam         string
 	UnsubscribeURLParam          string
+	MagicLinkTemplateID          int
+	MagicLinkTemplateURLParam    string
 }
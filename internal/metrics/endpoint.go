@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+)
+
+// Config of the /metrics endpoint.
+type Config struct {
+	Token string // Token is required as the bearer token to scrape the metrics, if set.
+	Port  string // Port serves the metrics on the separate port instead of the API port, if set.
+}
+
+// Mount adds the /metrics route to the API server, unless the metrics are served on the separate port.
+// Note: on the API port the endpoint is public, so it's only mounted if the token is set.
+func (m *Metrics) Mount(e *echo.Echo) {
+	if m.cfg.Port != "" {
+		return
+	}
+
+	if m.cfg.Token == "" {
+		slog.Info("[metrics] Endpoint is disabled, set the metrics token or port to expose it")
+		return
+	}
+
+	e.GET("/metrics", echo.WrapHandler(m.protectedHandler()))
+}
+
+// ListenAndServe serves /metrics on the separate port, if it's set, until the shutdown.
+// It returns error if the port can't be listened, e.g. it's already in use.
+func (m *Metrics) ListenAndServe(lc *lifecycle.Manager) error {
+	if m.cfg.Port == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", ":"+m.cfg.Port)
+	if err != nil {
+		return fmt.Errorf("listen metrics port: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.protectedHandler())
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("[metrics] Server failed", "error", err)
+		}
+	}()
+	lc.OnStop(lifecycle.StageServer, "metrics server", func(ctx context.Context) error {
+		return srv.Shutdown(ctx)
+	})
+
+	return nil
+}
+
+// protectedHandler returns the Handler that requires the bearer token, if it's set.
+func (m *Metrics) protectedHandler() http.Handler {
+	handler := m.Handler()
+	if m.cfg.Token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(m.cfg.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/samgozman/go-bloggy/internal/api"
+)
+
+const namespace = "bloggy"
+
+// Email types of the EmailSent and EmailFailed.
+const (
+	EmailConfirmation = "confirmation"
+	EmailPost         = "post"
+	EmailMagicLink    = "magic_link"
+)
+
+// Sources of the CacheInvalidated.
+const (
+	InvalidationLocal  = "local"  // InvalidationLocal is the change made by the instance itself.
+	InvalidationRemote = "remote" // InvalidationRemote is the change notified by another instance.
+)
+
+// Metrics collects the HTTP, database and domain metrics and exposes them in the Prometheus text format.
+// It uses its own registry instead of the global one, so the metrics can be tested in isolation.
+type Metrics struct {
+	cfg      *Config
+	registry *prometheus.Registry
+
+	httpDuration   *prometheus.HistogramVec
+	subscriptions  *prometheus.CounterVec
+	emails         *prometheus.CounterVec
+	postsPublished prometheus.Counter
+
+	cacheRequests      *prometheus.CounterVec
+	cacheInvalidations *prometheus.CounterVec
+}
+
+// New creates new Metrics with the Go runtime and process collectors.
+func New(cfg *Config) *Metrics {
+	m := &Metrics{
+		cfg:      cfg,
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by the OpenAPI operation ID and the status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "code"}),
+		subscriptions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "subscriptions_total",
+			Help:      "Number of the subscriptions by the event: created, confirmed or deleted.",
+		}, []string{"event"}),
+		emails: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "emails_total",
+			Help:      "Number of the emails by the type and the result: sent or failed.",
+		}, []string{"type", "result"}),
+		postsPublished: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_published_total",
+			Help:      "Number of the published posts.",
+		}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Number of the reads from the in-process cache by the cache name and the result: hit or miss.",
+		}, []string{"cache", "result"}),
+		cacheInvalidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_invalidations_total",
+			Help:      "Number of the invalidations of the in-process cache by the cache name and the source: local or remote.",
+		}, []string{"cache", "source"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.subscriptions,
+		m.emails,
+		m.postsPublished,
+		m.cacheRequests,
+		m.cacheInvalidations,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler that serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB adds the connection pool stats of the database from sql.DB.Stats, e.g. go_sql_open_connections.
+func (m *Metrics) RegisterDB(db *sql.DB) error {
+	if err := m.registry.Register(collectors.NewDBStatsCollector(db, "postgres")); err != nil {
+		return fmt.Errorf("register database stats: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Metrics) SubscriptionCreated()   { m.subscriptions.WithLabelValues("created").Inc() }
+func (m *Metrics) SubscriptionConfirmed() { m.subscriptions.WithLabelValues("confirmed").Inc() }
+func (m *Metrics) SubscriptionDeleted()   { m.subscriptions.WithLabelValues("deleted").Inc() }
+func (m *Metrics) PostPublished()         { m.postsPublished.Inc() }
+
+func (m *Metrics) CacheHit(cache string)  { m.cacheRequests.WithLabelValues(cache, "hit").Inc() }
+func (m *Metrics) CacheMiss(cache string) { m.cacheRequests.WithLabelValues(cache, "miss").Inc() }
+
+// CacheInvalidated counts the invalidation of the cache by the source, e.g. InvalidationRemote.
+func (m *Metrics) CacheInvalidated(cache, source string) {
+	m.cacheInvalidations.WithLabelValues(cache, source).Inc()
+}
+
+// EmailSent adds count emails of the type, e.g. EmailPost sent to all subscribers at once.
+func (m *Metrics) EmailSent(emailType string, count int) {
+	m.emails.WithLabelValues(emailType, "sent").Add(float64(count))
+}
+
+// EmailFailed adds count emails of the type that were not sent.
+func (m *Metrics) EmailFailed(emailType string, count int) {
+	m.emails.WithLabelValues(emailType, "failed").Add(float64(count))
+}
+
+// Middleware records the duration of the requests labelled by the operation ID from the spec.
+// Note: the operation is resolved by the route template, so the slugs and IDs do not blow up the cardinality.
+func (m *Metrics) Middleware(spec *openapi3.T) echo.MiddlewareFunc {
+	operations := api.NewOperations(spec)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+
+			err := next(ctx)
+			if err != nil {
+				// Note: let echo write the error response to record its status code, like the Logger middleware
+				ctx.Error(err)
+			}
+
+			m.httpDuration.
+				WithLabelValues(operations.ID(ctx), strconv.Itoa(ctx.Response().Status)).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
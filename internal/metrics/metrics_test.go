@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// scrape returns the metrics in the Prometheus text format.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	return rec.Body.String()
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	spec, err := api.GetSwagger()
+	assert.NoError(t, err)
+
+	m := New(&Config{})
+	e := echo.New()
+	e.Use(m.Middleware(spec))
+	e.GET("/posts/:slug", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+	e.GET("/health", func(_ echo.Context) error {
+		return echo.NewHTTPError(http.StatusServiceUnavailable)
+	})
+
+	for _, path := range []string{"/posts/first", "/posts/second", "/health", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics, `bloggy_http_request_duration_seconds_count{code="200",operation="GetPostsSlug"} 2`)
+	assert.Contains(t, metrics, `bloggy_http_request_duration_seconds_count{code="503",operation="GetHealth"} 1`)
+	assert.Contains(t, metrics, `bloggy_http_request_duration_seconds_count{code="404",operation="unknown"} 1`)
+	assert.NotContains(t, metrics, "first")
+}
+
+func TestMetrics_Counters(t *testing.T) {
+	m := New(&Config{})
+
+	m.SubscriptionCreated()
+	m.SubscriptionCreated()
+	m.SubscriptionConfirmed()
+	m.SubscriptionDeleted()
+	m.PostPublished()
+	m.EmailSent(EmailPost, 3)
+	m.EmailFailed(EmailConfirmation, 1)
+	m.CacheHit("posts")
+	m.CacheHit("posts")
+	m.CacheMiss("posts")
+	m.CacheInvalidated("posts", InvalidationRemote)
+
+	metrics := scrape(t, m)
+	assert.Contains(t, metrics, `bloggy_subscriptions_total{event="created"} 2`)
+	assert.Contains(t, metrics, `bloggy_subscriptions_total{event="confirmed"} 1`)
+	assert.Contains(t, metrics, `bloggy_subscriptions_total{event="deleted"} 1`)
+	assert.Contains(t, metrics, `bloggy_posts_published_total 1`)
+	assert.Contains(t, metrics, `bloggy_emails_total{result="sent",type="post"} 3`)
+	assert.Contains(t, metrics, `bloggy_emails_total{result="failed",type="confirmation"} 1`)
+	assert.Contains(t, metrics, `bloggy_cache_requests_total{cache="posts",result="hit"} 2`)
+	assert.Contains(t, metrics, `bloggy_cache_requests_total{cache="posts",result="miss"} 1`)
+	assert.Contains(t, metrics, `bloggy_cache_invalidations_total{cache="posts",source="remote"} 1`)
+}
+
+func TestMetrics_Mount(t *testing.T) {
+	t.Run("protected by the token", func(t *testing.T) {
+		e := echo.New()
+		New(&Config{Token: "secret"}).Mount(e)
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req.Header.Set(echo.HeaderAuthorization, "Bearer wrong")
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req.Header.Set(echo.HeaderAuthorization, "Bearer secret")
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "bloggy_posts_published_total")
+	})
+
+	t.Run("not mounted without the token", func(t *testing.T) {
+		e := echo.New()
+		New(&Config{}).Mount(e)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("not mounted with the separate port", func(t *testing.T) {
+		e := echo.New()
+		New(&Config{Token: "secret", Port: "9100"}).Mount(e)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestMetrics_ListenAndServe(t *testing.T) {
+	// Note: find a free port, the listener is closed right away
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(t, listener.Close())
+
+	lc := lifecycle.NewManager(time.Second)
+	m := New(&Config{Port: port})
+	assert.NoError(t, m.ListenAndServe(lc))
+
+	res, err := http.Get("http://127.0.0.1:" + port + "/metrics") //nolint:noctx // test request
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NoError(t, res.Body.Close())
+
+	t.Run("port in use", func(t *testing.T) {
+		assert.Error(t, New(&Config{Port: port}).ListenAndServe(lc))
+	})
+
+	assert.NoError(t, lc.Shutdown(context.Background()))
+
+	_, err = http.Get("http://127.0.0.1:" + port + "/metrics") //nolint:noctx // test request
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+)
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	return &Config{
+		Token: cfg.Metrics.Token,
+		Port:  cfg.Metrics.Port,
+	}
+}
+
+// ProvideMetrics is a Wire provider function that creates Metrics.
+func ProvideMetrics(cfg *Config) *Metrics {
+	return New(cfg)
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the metrics package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideMetrics,
+)
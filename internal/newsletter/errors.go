@@ -0,0 +1,12 @@
+package newsletter
+
+import "errors"
+
+var (
+	ErrPostNotFound    = errors.New("post not found")
+	ErrPostAlreadySent = errors.New("post was already sent to subscribers")
+	ErrNoSubscribers   = errors.New("no subscribers to send the post to")
+	ErrGetSubscribers  = errors.New("error getting subscribers")
+	ErrSendPostEmail   = errors.New("error sending post email")
+	ErrUpdatePost      = errors.New("error updating post")
+)
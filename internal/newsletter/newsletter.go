@@ -0,0 +1,95 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	mailer "github.com/samgozman/go-bloggy/internal/mailer/types"
+	"time"
+)
+
+// ServiceInterface is the interface for the newsletter Service.
+type ServiceInterface interface {
+	SendPost(ctx context.Context, slug string) (int, error)
+}
+
+// Service sends the posts to the confirmed subscribers.
+// It's shared by the API handler and the `newsletter send` command.
+type Service struct {
+	models        db.ModelsInterface
+	mailerService mailer.ServiceInterface
+}
+
+// NewService creates a new Service.
+func NewService(models db.ModelsInterface, mailerService mailer.ServiceInterface) *Service {
+	return &Service{
+		models:        models,
+		mailerService: mailerService,
+	}
+}
+
+// SendPost sends the post with the given slug to all confirmed subscribers and returns the number of recipients.
+// Each post can be sent only once, so the time of sending is saved to the post.
+func (s *Service) SendPost(ctx context.Context, slug string) (int, error) {
+	post, err := s.models.Posts().GetBySlug(ctx, slug)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrPostNotFound, err)
+	}
+
+	if !post.SentToSubscribersAt.IsZero() {
+		return 0, ErrPostAlreadySent
+	}
+
+	subs, err := s.models.Subscribers().GetConfirmed(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrGetSubscribers, err)
+	}
+
+	if len(subs) == 0 {
+		return 0, ErrNoSubscribers
+	}
+
+	mailerSubs := make([]*mailer.Subscriber, 0, len(subs))
+	for _, sub := range subs {
+		mailerSubs = append(mailerSubs, &mailer.Subscriber{
+			Email: sub.Email,
+			ID:    sub.ID.String(),
+		})
+	}
+
+	err = s.mailerService.SendPostEmail(ctx, &mailer.PostEmailSend{
+		To:          mailerSubs,
+		Title:       post.Title,
+		Description: post.Description,
+		Slug:        post.Slug,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrSendPostEmail, err)
+	}
+
+	if err := s.markSent(ctx, post); err != nil {
+		return len(mailerSubs), fmt.Errorf("%w: %w", ErrUpdatePost, err)
+	}
+
+	return len(mailerSubs), nil
+}
+
+// markSent saves the time of sending to the post. If the post was edited while it was sent,
+// it's read again and saved once more, so neither the edit nor the time of sending is lost.
+func (s *Service) markSent(ctx context.Context, post *models.Post) error {
+	post.SentToSubscribersAt = time.Now()
+	err := s.models.Posts().Update(ctx, post)
+	if !errors.Is(err, models.ErrConflict) {
+		return err
+	}
+
+	current, err := s.models.Posts().GetBySlug(ctx, post.Slug)
+	if err != nil {
+		return err
+	}
+	current.SentToSubscribersAt = post.SentToSubscribersAt
+
+	return s.models.Posts().Update(ctx, current)
+}
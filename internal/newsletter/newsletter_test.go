@@ -0,0 +1,122 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	mailer "github.com/samgozman/go-bloggy/internal/mailer/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+
+	modelsMock "github.com/samgozman/go-bloggy/mocks/db/models"
+	mockMailer "github.com/samgozman/go-bloggy/mocks/mailer"
+)
+
+// newTestService creates a new Service with the mocked posts and subscribers repositories.
+func newTestService(t *testing.T) (
+	*Service,
+	*modelsMock.MockPostRepositoryInterface,
+	*modelsMock.MockSubscriberRepositoryInterface,
+	*mockMailer.MockServiceInterface,
+) {
+	posts := modelsMock.NewMockPostRepositoryInterface(t)
+	subscribers := modelsMock.NewMockSubscriberRepositoryInterface(t)
+	ms := mockMailer.NewMockServiceInterface(t)
+
+	m := db.NewModels(nil, posts, subscribers, nil, nil, nil, nil, nil)
+
+	return NewService(m, ms), posts, subscribers, ms
+}
+
+func TestService_SendPost(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OK", func(t *testing.T) {
+		s, posts, subscribers, ms := newTestService(t)
+
+		post := &models.Post{Slug: "test-post", Title: "Title", Description: "Description"}
+		sub := &models.Subscriber{ID: uuid.New(), Email: "test@example.com", IsConfirmed: true}
+
+		posts.On("GetBySlug", ctx, post.Slug).Return(post, nil)
+		subscribers.On("GetConfirmed", ctx).Return([]*models.Subscriber{sub}, nil)
+		ms.On("SendPostEmail", mock.Anything, &mailer.PostEmailSend{
+			To:          []*mailer.Subscriber{{ID: sub.ID.String(), Email: sub.Email}},
+			Title:       post.Title,
+			Description: post.Description,
+			Slug:        post.Slug,
+		}).Return(nil)
+		posts.On("Update", ctx, post).Return(nil)
+
+		sent, err := s.SendPost(ctx, post.Slug)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.False(t, post.SentToSubscribersAt.IsZero())
+	})
+
+	t.Run("ErrPostNotFound", func(t *testing.T) {
+		s, posts, _, _ := newTestService(t)
+		posts.On("GetBySlug", ctx, "missing").Return(nil, models.ErrNotFound)
+
+		_, err := s.SendPost(ctx, "missing")
+		assert.ErrorIs(t, err, ErrPostNotFound)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("ErrPostAlreadySent", func(t *testing.T) {
+		s, posts, _, ms := newTestService(t)
+		post := &models.Post{Slug: "sent-post", SentToSubscribersAt: time.Now()}
+		posts.On("GetBySlug", ctx, post.Slug).Return(post, nil)
+
+		_, err := s.SendPost(ctx, post.Slug)
+		assert.ErrorIs(t, err, ErrPostAlreadySent)
+		ms.AssertNotCalled(t, "SendPostEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrNoSubscribers", func(t *testing.T) {
+		s, posts, subscribers, ms := newTestService(t)
+		posts.On("GetBySlug", ctx, "test-post").Return(&models.Post{Slug: "test-post"}, nil)
+		subscribers.On("GetConfirmed", ctx).Return([]*models.Subscriber{}, nil)
+
+		_, err := s.SendPost(ctx, "test-post")
+		assert.ErrorIs(t, err, ErrNoSubscribers)
+		ms.AssertNotCalled(t, "SendPostEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrSendPostEmail", func(t *testing.T) {
+		s, posts, subscribers, ms := newTestService(t)
+		post := &models.Post{Slug: "test-post"}
+		posts.On("GetBySlug", ctx, post.Slug).Return(post, nil)
+		subscribers.On("GetConfirmed", ctx).Return([]*models.Subscriber{{ID: uuid.New(), Email: "a@b.com"}}, nil)
+		ms.On("SendPostEmail", mock.Anything, mock.Anything).Return(errors.New("mailjet is down"))
+
+		_, err := s.SendPost(ctx, post.Slug)
+		assert.ErrorIs(t, err, ErrSendPostEmail)
+		assert.True(t, post.SentToSubscribersAt.IsZero())
+		posts.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("post edited while sending", func(t *testing.T) {
+		s, posts, subscribers, ms := newTestService(t)
+
+		post := &models.Post{Slug: "test-post", Title: "Title", Version: 1}
+		edited := &models.Post{Slug: "test-post", Title: "Edited", Version: 2}
+		sub := &models.Subscriber{ID: uuid.New(), Email: "test@example.com", IsConfirmed: true}
+
+		posts.On("GetBySlug", ctx, post.Slug).Return(post, nil).Once()
+		subscribers.On("GetConfirmed", ctx).Return([]*models.Subscriber{sub}, nil)
+		ms.On("SendPostEmail", mock.Anything, mock.Anything).Return(nil)
+		posts.On("Update", ctx, post).Return(models.ErrConflict).Once()
+		posts.On("GetBySlug", ctx, post.Slug).Return(edited, nil).Once()
+		posts.On("Update", ctx, edited).Return(nil).Once()
+
+		sent, err := s.SendPost(ctx, post.Slug)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, "Edited", edited.Title)
+		assert.False(t, edited.SentToSubscribersAt.IsZero())
+	})
+}
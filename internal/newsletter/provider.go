@@ -0,0 +1,18 @@
+package newsletter
+
+import (
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/db"
+	mailer "github.com/samgozman/go-bloggy/internal/mailer/types"
+)
+
+// ProvideService is a Wire provider function that creates a Service.
+func ProvideService(models db.ModelsInterface, mailerService mailer.ServiceInterface) *Service {
+	return NewService(models, mailerService)
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the newsletter package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideService,
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
@@ -0,0 +1,15 @@
+package oidc
+
+import "errors"
+
+var (
+	ErrUnknownProvider  = errors.New("unknown OpenID Connect provider")
+	ErrDiscovery        = errors.New("error discovering OpenID Connect provider")
+	ErrInvalidState     = errors.New("invalid or expired state")
+	ErrExchangeCode     = errors.New("error exchanging code for token")
+	ErrMissingIDToken   = errors.New("token response has no id_token")
+	ErrInvalidIDToken   = errors.New("invalid id_token")
+	ErrInvalidIDNonce   = errors.New("id_token nonce does not match the state")
+	ErrInvalidIDClaims  = errors.New("error parsing id_token claims")
+	ErrEmailNotVerified = errors.New("email is not verified by the provider")
+)
@@ -0,0 +1,228 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// ProviderConfig holds the configuration of a single OpenID Connect provider,
+// e.g. GitLab, Google, Keycloak or Authentik.
+type ProviderConfig struct {
+	Name         string   // Name of the provider used in the login URL, e.g. "gitlab" for /login/gitlab/authorize
+	IssuerURL    string   // IssuerURL is used to discover the provider via /.well-known/openid-configuration
+	ClientID     string   // ClientID of the OAuth application registered in the provider
+	ClientSecret string   // ClientSecret of the OAuth application registered in the provider
+	RedirectURL  string   // RedirectURL is the frontend page that receives the code from the provider
+	Scopes       []string // Scopes to request, "openid" is always added
+}
+
+// AuthRequest is the data needed by the client to start the authorization with the provider.
+type AuthRequest struct {
+	URL          string // URL of the provider authorization page to redirect the user to
+	State        string // State is passed through the provider and has to be sent back with the code
+	CodeVerifier string // CodeVerifier is the PKCE secret that the client has to send back with the code
+}
+
+// UserInfo is the user data from the verified ID token.
+type UserInfo struct {
+	Subject string // Subject is the ID of the user in the provider
+	Login   string // Login is the preferred username or email of the user
+	Email   string
+}
+
+// Service for authorization with the configured OpenID Connect providers.
+type Service struct {
+	providers map[string]*provider
+	stateKey  []byte // stateKey is the key used to sign the state.
+	now       func() time.Time
+}
+
+// NewService creates a new OpenID Connect Service for the given providers.
+// stateKey is used to sign the state passed through the provider.
+func NewService(providers []ProviderConfig, stateKey string) *Service {
+	s := &Service{
+		providers: make(map[string]*provider, len(providers)),
+		stateKey:  []byte(stateKey),
+		now:       time.Now,
+	}
+
+	for _, cfg := range providers {
+		s.providers[cfg.Name] = &provider{cfg: cfg}
+	}
+
+	return s
+}
+
+type ServiceInterface interface {
+	AuthCodeURL(ctx context.Context, providerName string) (*AuthRequest, error)
+	Exchange(ctx context.Context, providerName, code, state, codeVerifier string) (*UserInfo, error)
+}
+
+// AuthCodeURL returns the provider authorization URL with PKCE challenge, nonce and signed state.
+func (s *Service) AuthCodeURL(ctx context.Context, providerName string) (*AuthRequest, error) {
+	p, err := s.getProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	st, err := encodeState(s.stateKey, &state{
+		Provider:      providerName,
+		Nonce:         nonce,
+		CodeChallenge: oauth2.S256ChallengeFromVerifier(verifier),
+		ExpiresAt:     s.now().Add(stateTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthRequest{
+		URL:          p.oauth2.AuthCodeURL(st, oauth2.S256ChallengeOption(verifier), gooidc.Nonce(nonce)),
+		State:        st,
+		CodeVerifier: verifier,
+	}, nil
+}
+
+// Exchange checks the state, exchanges the code for tokens and verifies the ID token against the provider JWKS.
+func (s *Service) Exchange(ctx context.Context, providerName, code, rawState, codeVerifier string) (*UserInfo, error) {
+	st, err := decodeState(s.stateKey, rawState, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if st.Provider != providerName || st.CodeChallenge != oauth2.S256ChallengeFromVerifier(codeVerifier) {
+		return nil, ErrInvalidState
+	}
+
+	p, err := s.getProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExchangeCode, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if idToken.Nonce != st.Nonce {
+		return nil, ErrInvalidIDNonce
+	}
+
+	var claims struct {
+		PreferredUsername string `json:"preferred_username"`
+		Email             string `json:"email"`
+		EmailVerified     *bool  `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDClaims, err)
+	}
+
+	info := &UserInfo{
+		Subject: idToken.Subject,
+		Login:   claims.PreferredUsername,
+	}
+
+	// Note: unverified emails are not trusted, because some providers allow to set any email
+	if claims.Email != "" && (claims.EmailVerified == nil || *claims.EmailVerified) {
+		info.Email = claims.Email
+	}
+
+	if info.Login == "" {
+		info.Login = info.Email
+	}
+	if info.Login == "" {
+		info.Login = info.Subject
+	}
+
+	return info, nil
+}
+
+// getProvider returns the initialized provider by its name.
+func (s *Service) getProvider(ctx context.Context, name string) (*provider, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+
+	if err := p.init(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// provider is lazily discovered on the first use, so the server can start while the provider is down.
+type provider struct {
+	cfg ProviderConfig
+
+	mu       sync.Mutex
+	ready    bool
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func (p *provider) init(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ready {
+		return nil
+	}
+
+	// Note: the provider keeps the context to fetch JWKS later, so it must outlive the request
+	discovered, err := gooidc.NewProvider(context.WithoutCancel(ctx), p.cfg.IssuerURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrDiscovery, p.cfg.Name, err)
+	}
+
+	scopes := []string{gooidc.ScopeOpenID}
+	for _, scope := range p.cfg.Scopes {
+		if scope != gooidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     discovered.Endpoint(),
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       scopes,
+	}
+	p.verifier = discovered.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	p.ready = true
+
+	return nil
+}
+
+// randomString returns a random URL-safe string to be used as nonce.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random string: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,153 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	testoidc "github.com/samgozman/go-bloggy/testutils/test-oidc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	issuer, err := testoidc.NewIssuer("client-id", "client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer issuer.Close()
+
+	providers := []ProviderConfig{
+		{
+			Name:         "test",
+			IssuerURL:    issuer.URL(),
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			RedirectURL:  "https://example.com/callback",
+			Scopes:       []string{"profile", "email"},
+		},
+	}
+	newService := func() *Service {
+		return NewService(providers, "state-secret")
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+
+		u, err := url.Parse(authRequest.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, issuer.URL()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+		assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+		assert.Equal(t, authRequest.State, u.Query().Get("state"))
+		assert.NotEmpty(t, u.Query().Get("nonce"))
+		assert.NotEmpty(t, authRequest.CodeVerifier)
+
+		code, err := issuer.Authorize(authRequest.URL, "user-1", map[string]any{
+			"preferred_username": "jdoe",
+			"email":              "jdoe@example.com",
+			"email_verified":     true,
+		})
+		assert.NoError(t, err)
+
+		info, err := s.Exchange(context.Background(), "test", code, authRequest.State, authRequest.CodeVerifier)
+		assert.NoError(t, err)
+		assert.Equal(t, &UserInfo{Subject: "user-1", Login: "jdoe", Email: "jdoe@example.com"}, info)
+	})
+
+	t.Run("should fallback login to verified email and subject", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+		code, err := issuer.Authorize(authRequest.URL, "user-2", map[string]any{
+			"email":          "jdoe@example.com",
+			"email_verified": false,
+		})
+		assert.NoError(t, err)
+
+		info, err := s.Exchange(context.Background(), "test", code, authRequest.State, authRequest.CodeVerifier)
+		assert.NoError(t, err)
+		assert.Equal(t, &UserInfo{Subject: "user-2", Login: "user-2"}, info)
+	})
+
+	t.Run("should return error for unknown provider", func(t *testing.T) {
+		_, err := newService().AuthCodeURL(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrUnknownProvider)
+	})
+
+	t.Run("should return error if provider is unavailable", func(t *testing.T) {
+		s := NewService([]ProviderConfig{{Name: "down", IssuerURL: "http://127.0.0.1:1"}}, "state-secret")
+
+		_, err := s.AuthCodeURL(context.Background(), "down")
+		assert.ErrorIs(t, err, ErrDiscovery)
+	})
+
+	t.Run("should return error for wrong code verifier", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+		code, err := issuer.Authorize(authRequest.URL, "user-1", nil)
+		assert.NoError(t, err)
+
+		_, err = s.Exchange(context.Background(), "test", code, authRequest.State, "another-verifier")
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("should return error for state signed with another key", func(t *testing.T) {
+		authRequest, err := NewService(providers, "another-secret").
+			AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+
+		_, err = newService().Exchange(context.Background(), "test", "code", authRequest.State, authRequest.CodeVerifier)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("should return error for expired state", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+
+		s.now = func() time.Time { return time.Now().Add(stateTTL + time.Minute) }
+		_, err = s.Exchange(context.Background(), "test", "code", authRequest.State, authRequest.CodeVerifier)
+		assert.ErrorIs(t, err, ErrInvalidState)
+	})
+
+	t.Run("should return error for invalid code", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+
+		_, err = s.Exchange(context.Background(), "test", "invalid", authRequest.State, authRequest.CodeVerifier)
+		assert.ErrorIs(t, err, ErrExchangeCode)
+	})
+
+	t.Run("should return error for token with another audience", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+		code, err := issuer.Authorize(authRequest.URL, "user-1", map[string]any{"aud": "another-client"})
+		assert.NoError(t, err)
+
+		_, err = s.Exchange(context.Background(), "test", code, authRequest.State, authRequest.CodeVerifier)
+		assert.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+
+	t.Run("should return error for token with another nonce", func(t *testing.T) {
+		s := newService()
+
+		authRequest, err := s.AuthCodeURL(context.Background(), "test")
+		assert.NoError(t, err)
+		code, err := issuer.Authorize(authRequest.URL, "user-1", map[string]any{"nonce": "replayed"})
+		assert.NoError(t, err)
+
+		_, err = s.Exchange(context.Background(), "test", code, authRequest.State, authRequest.CodeVerifier)
+		assert.ErrorIs(t, err, ErrInvalidIDNonce)
+	})
+}
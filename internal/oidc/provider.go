@@ -0,0 +1,45 @@
+package oidc
+
+import (
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/signed"
+)
+
+// Config is a struct that holds the configuration for the OpenID Connect service.
+type Config struct {
+	Providers []ProviderConfig
+	StateKey  string
+}
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	providers := make([]ProviderConfig, 0, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		providers = append(providers, ProviderConfig{
+			Name:         p.Name,
+			IssuerURL:    p.IssuerURL,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+
+	return &Config{
+		Providers: providers,
+		StateKey:  signed.DeriveKey(string(cfg.JWTSecretKey), "oidc-state"),
+	}
+}
+
+// ProvideService is a Wire provider function that creates a Service.
+func ProvideService(cfg *Config) *Service {
+	return NewService(cfg.Providers, cfg.StateKey)
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the oidc package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideService,
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
@@ -0,0 +1,44 @@
+package oidc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samgozman/go-bloggy/internal/signed"
+)
+
+// stateTTL is how long the user has to complete the authorization with the provider.
+const stateTTL = 10 * time.Minute
+
+// state is carried through the provider redirect, so the backend doesn't have to store pending authorizations.
+// It is signed with HMAC, so it can't be forged by the client.
+type state struct {
+	Provider      string `json:"p"`
+	Nonce         string `json:"n"`
+	CodeChallenge string `json:"c"` // CodeChallenge is the S256 PKCE challenge of the code verifier
+	ExpiresAt     int64  `json:"e"`
+}
+
+// encodeState serializes and signs the state with the given key.
+func encodeState(key []byte, s *state) (string, error) {
+	encoded, err := signed.Encode(key, s)
+	if err != nil {
+		return "", fmt.Errorf("error encoding state: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// decodeState verifies the signature and expiration of the state and returns it.
+func decodeState(key []byte, raw string, now time.Time) (*state, error) {
+	var s state
+	if err := signed.Decode(key, raw, &s); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+
+	if now.Unix() > s.ExpiresAt {
+		return nil, ErrInvalidState
+	}
+
+	return &s, nil
+}
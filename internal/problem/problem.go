@@ -0,0 +1,82 @@
+// Package problem writes the errors as the problem details (RFC 9457).
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/logging"
+)
+
+// ContentType of the problem details responses.
+const ContentType = "application/problem+json"
+
+// typePrefix of the problem type URIs, the rest is the code, e.g. "urn:go-bloggy:problem:post-not-found".
+const typePrefix = "urn:go-bloggy:problem:"
+
+// Respond writes the p with the status as the problem details.
+// The type, title and status are set from the code and the status, and the detail is the message, if not set.
+func Respond(ctx echo.Context, status int, p api.RequestError) error {
+	p.Type = Type(p.Code)
+	p.Title = http.StatusText(status)
+	p.Status = status
+	if p.Detail == "" {
+		p.Detail = p.Message
+	}
+	if p.Message == "" {
+		p.Message = p.Detail
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("error encoding problem: %w", err)
+	}
+
+	return ctx.Blob(status, ContentType, body)
+}
+
+// Type returns the problem type URI of the code, e.g. "urn:go-bloggy:problem:post-not-found" for ERR_POST_NOT_FOUND.
+func Type(code string) string {
+	name := strings.TrimPrefix(code, "ERR_")
+	return typePrefix + strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// HTTPErrorHandler is the echo.HTTPErrorHandler, that writes the errors returned by the handlers
+// and by echo itself, e.g. 404 of the unknown route, as the problem details.
+// The message of the unexpected errors is not exposed, as it can contain the internal details.
+func HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	message := http.StatusText(status)
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		message = http.StatusText(status)
+		if m, ok := httpErr.Message.(string); ok && status < http.StatusInternalServerError {
+			message = m
+		}
+	}
+
+	if ctx.Request().Method == http.MethodHead {
+		err = ctx.NoContent(status)
+	} else {
+		err = Respond(ctx, status, api.RequestError{Code: Code(status), Message: message})
+	}
+	if err != nil {
+		logging.FromContext(ctx.Request().Context()).Error("Error response failed", "error", err)
+	}
+}
+
+// Code returns the generic code of the status, e.g. "ERR_NOT_FOUND" for 404.
+func Code(status int) string {
+	return "ERR_" + strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
@@ -0,0 +1,127 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samgozman/go-bloggy/internal/api"
+)
+
+func TestRespond(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/posts/slug", nil), rec)
+
+	err := Respond(ctx, http.StatusNotFound, api.RequestError{
+		Code:    "ERR_POST_NOT_FOUND",
+		Message: "Post not found",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get(echo.HeaderContentType))
+
+	var body api.RequestError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.RequestError{
+		Type:    "urn:go-bloggy:problem:post-not-found",
+		Title:   "Not Found",
+		Status:  http.StatusNotFound,
+		Detail:  "Post not found",
+		Code:    "ERR_POST_NOT_FOUND",
+		Message: "Post not found",
+	}, body)
+}
+
+func TestRespond_Detail(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/posts", nil), rec)
+
+	err := Respond(ctx, http.StatusBadRequest, api.RequestError{
+		Code:    "ERR_VALIDATION_FAILED",
+		Message: "Post validation failed",
+		Detail:  "Post validation failed: title is required",
+	})
+	assert.NoError(t, err)
+
+	var body api.RequestError
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Post validation failed", body.Message)
+	assert.Equal(t, "Post validation failed: title is required", body.Detail)
+}
+
+func TestType(t *testing.T) {
+	assert.Equal(t, "urn:go-bloggy:problem:too-many-requests", Type("ERR_TOO_MANY_REQUESTS"))
+	assert.Equal(t, "urn:go-bloggy:problem:invalid-challenge", Type("INVALID_CHALLENGE"))
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	// handle returns the response of the handler error for the request with the method
+	handle := func(method string, err error) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		HTTPErrorHandler(err, echo.New().NewContext(httptest.NewRequest(method, "/", nil), rec))
+
+		return rec
+	}
+
+	// decode returns the problem of the response
+	decode := func(t *testing.T, rec *httptest.ResponseRecorder) api.RequestError {
+		t.Helper()
+
+		var body api.RequestError
+		assert.Equal(t, ContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+		return body
+	}
+
+	t.Run("echo error", func(t *testing.T) {
+		rec := handle(http.MethodGet, echo.ErrNotFound)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		body := decode(t, rec)
+		assert.Equal(t, "ERR_NOT_FOUND", body.Code)
+		assert.Equal(t, "Not Found", body.Message)
+		assert.Equal(t, http.StatusNotFound, body.Status)
+	})
+
+	t.Run("echo error with message", func(t *testing.T) {
+		rec := handle(http.MethodGet, echo.NewHTTPError(http.StatusBadRequest, "Invalid format for parameter id"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		body := decode(t, rec)
+		assert.Equal(t, "ERR_BAD_REQUEST", body.Code)
+		assert.Equal(t, "Invalid format for parameter id", body.Message)
+	})
+
+	t.Run("unexpected error is not exposed", func(t *testing.T) {
+		rec := handle(http.MethodGet, errors.New("connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		body := decode(t, rec)
+		assert.Equal(t, "ERR_INTERNAL_SERVER_ERROR", body.Code)
+		assert.Equal(t, "Internal Server Error", body.Message)
+	})
+
+	t.Run("HEAD request", func(t *testing.T) {
+		rec := handle(http.MethodHead, echo.ErrNotFound)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("committed response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		assert.NoError(t, ctx.NoContent(http.StatusNoContent))
+
+		HTTPErrorHandler(echo.ErrNotFound, ctx)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+}
@@ -0,0 +1,12 @@
+package ratelimit
+
+import "errors"
+
+// ErrTooManyRequests is the code of the api.RequestError for the limited requests.
+const ErrTooManyRequests = "ERR_TOO_MANY_REQUESTS"
+
+var (
+	ErrTakeToken        = errors.New("error taking rate limit token")
+	ErrUnknownStore     = errors.New("unknown rate limit store")
+	ErrUnknownOperation = errors.New("unknown operation of the rate limit")
+)
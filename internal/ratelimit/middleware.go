@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware limits the requests per client IP address with the rates by the operation ID of the spec,
+// e.g. "PostSubscribers". The operations without the rate are not limited.
+// The limited requests are rejected with 429 Too Many Requests and the Retry-After header.
+// Note: the client IP is resolved by the echo.IPExtractor, so only the trusted proxies can set it.
+//
+// It returns error if any rate is set for the operation that is not in the spec,
+// so the limit is not silently disabled by a typo in the config.
+func Middleware(spec *openapi3.T, store Store, rates map[string]Rate) (echo.MiddlewareFunc, error) {
+	operations := api.NewOperations(spec)
+
+	known := make(map[string]bool, len(operations))
+	for _, id := range operations {
+		known[id] = true
+	}
+
+	limiters := make(map[string]*Limiter, len(rates))
+	for id, rate := range rates {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownOperation, id)
+		}
+		limiters[id] = NewLimiter(store, id, rate)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			limiter, ok := limiters[operations.ID(ctx)]
+			if !ok {
+				return next(ctx)
+			}
+
+			if allowed, retryAfter := limiter.Allow(ctx.Request().Context(), ctx.RealIP()); !allowed {
+				return TooManyRequests(ctx, retryAfter)
+			}
+
+			return next(ctx)
+		}
+	}, nil
+}
+
+// TooManyRequests responds with 429 and Retry-After header in seconds.
+func TooManyRequests(ctx echo.Context, retryAfter time.Duration) error {
+	ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+	return problem.Respond(ctx, http.StatusTooManyRequests, api.RequestError{
+		Code:    ErrTooManyRequests,
+		Message: "Too many requests, please try again later",
+	})
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"sync"
+	"time"
+)
+
+// PostgresStore keeps the buckets in the database, so the limits are shared between the replicas.
+// Note: the tokens are refilled by the clock of the replica, so the clocks of the replicas should be synchronized.
+type PostgresStore struct {
+	repo models.RateLimitRepositoryInterface
+	now  func() time.Time
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresStore creates a new PostgresStore with the buckets in the repo.
+func NewPostgresStore(repo models.RateLimitRepositoryInterface) *PostgresStore {
+	return &PostgresStore{
+		repo:      repo,
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+// Take takes a token from the bucket of the key, see Store.
+func (s *PostgresStore) Take(ctx context.Context, key string, rate Rate) (bool, time.Duration, error) {
+	now := s.now()
+	s.sweep(ctx, now)
+
+	var allowed bool
+	var retryAfter time.Duration
+	err := s.repo.Update(ctx, key, func(rb *models.RateLimitBucket) {
+		b := bucket{tokens: rb.Tokens, refilledAt: rb.RefilledAt}
+		allowed, retryAfter = b.take(rate, now)
+
+		rb.Tokens = b.tokens
+		rb.RefilledAt = b.refilledAt
+		rb.ExpiresAt = b.fullAt(rate)
+	})
+	if err != nil {
+		return false, 0, fmt.Errorf("%w: %w", ErrTakeToken, err)
+	}
+
+	return allowed, retryAfter, nil
+}
+
+// sweep deletes the buckets that are full again, at most once per sweepInterval by the replica.
+// The error is only logged, as the buckets are deleted on the next sweep.
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if _, err := s.repo.DeleteExpired(ctx, now); err != nil {
+		logging.FromContext(ctx).Warn("[ratelimit] Error deleting expired buckets", "error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// fakeRepository keeps the buckets in the map instead of the database.
+type fakeRepository struct {
+	buckets  map[string]models.RateLimitBucket
+	err      error
+	sweptAt  []time.Time
+	sweepErr error
+}
+
+func (r *fakeRepository) Update(_ context.Context, key string, update func(bucket *models.RateLimitBucket)) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = models.RateLimitBucket{Key: key}
+	}
+	update(&bucket)
+	r.buckets[key] = bucket
+
+	return nil
+}
+
+func (r *fakeRepository) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.sweptAt = append(r.sweptAt, now)
+
+	return 0, r.sweepErr
+}
+
+func TestPostgresStore_Take(t *testing.T) {
+	rate := Rate{Limit: 2, Period: time.Minute}
+
+	t.Run("OK", func(t *testing.T) {
+		now := time.Now()
+		repo := &fakeRepository{buckets: make(map[string]models.RateLimitBucket)}
+		s := NewPostgresStore(repo)
+		s.now = func() time.Time { return now }
+
+		allowed, _, err := s.Take(context.Background(), "key", rate)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, models.RateLimitBucket{
+			Key:        "key",
+			Tokens:     1,
+			RefilledAt: now,
+			ExpiresAt:  now.Add(30 * time.Second),
+		}, repo.buckets["key"])
+
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+
+		allowed, retryAfter, err := s.Take(context.Background(), "key", rate)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, retryAfter)
+
+		now = now.Add(30 * time.Second)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should return the error of the repository", func(t *testing.T) {
+		repo := &fakeRepository{err: models.ErrUpdateRateLimit}
+		s := NewPostgresStore(repo)
+
+		_, _, err := s.Take(context.Background(), "key", rate)
+		assert.ErrorIs(t, err, ErrTakeToken)
+		assert.ErrorIs(t, err, models.ErrUpdateRateLimit)
+	})
+
+	t.Run("should delete expired buckets once per interval", func(t *testing.T) {
+		now := time.Now()
+		repo := &fakeRepository{
+			buckets:  make(map[string]models.RateLimitBucket),
+			sweepErr: errors.New("connection refused"),
+		}
+		s := NewPostgresStore(repo)
+		s.now = func() time.Time { return now }
+		s.lastSweep = now
+
+		s.Take(context.Background(), "key", rate)
+		assert.Empty(t, repo.sweptAt)
+
+		now = now.Add(sweepInterval)
+		allowed, _, err := s.Take(context.Background(), "key", rate)
+		assert.NoError(t, err, "sweep errors are only logged")
+		assert.True(t, allowed)
+		s.Take(context.Background(), "key", rate)
+		assert.Equal(t, []time.Time{now}, repo.sweptAt)
+	})
+}
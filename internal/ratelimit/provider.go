@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db"
+)
+
+// Stores of the Config.
+const (
+	StoreMemory   = "memory"   // StoreMemory keeps the limits of the single instance.
+	StorePostgres = "postgres" // StorePostgres shares the limits between the replicas.
+)
+
+// Config of the rate limits of the public endpoints.
+type Config struct {
+	Store      string
+	Operations map[string]Rate // Operations are the rates per client IP by the operation ID, see Middleware.
+}
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	operations := make(map[string]Rate, len(cfg.RateLimit.Operations))
+	for id, rate := range cfg.RateLimit.Operations {
+		operations[id] = Rate(rate)
+	}
+
+	return &Config{
+		Store:      cfg.RateLimit.Store,
+		Operations: operations,
+	}
+}
+
+// ProvideStore is a Wire provider function that creates the Store of the Config.
+func ProvideStore(cfg *Config, models db.ModelsInterface) (Store, error) {
+	switch cfg.Store {
+	case StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore(models.RateLimits()), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStore, cfg.Store)
+	}
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the ratelimit package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideStore,
+)
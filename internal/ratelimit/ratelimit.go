@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"time"
+)
+
+// Rate is the number of requests allowed per period.
+// Requests are limited with a token bucket of Limit capacity, that is fully refilled during the Period.
+// The zero Limit disables the rate limit.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Store keeps the token buckets of the rate limits.
+type Store interface {
+	// Take takes a token from the bucket of the key, that is created full with the rate if it doesn't exist.
+	// If the bucket is empty, it returns false and the duration after which the next token will be available.
+	Take(ctx context.Context, key string, rate Rate) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Limiter is a token bucket rate limiter with a bucket per key (e.g. email or IP address) in the Store.
+type Limiter struct {
+	store Store
+	name  string // name is the prefix of the keys, so the limiters can share the Store
+	rate  Rate
+}
+
+// NewLimiter creates a new Limiter with the given rate and the buckets in the store.
+func NewLimiter(store Store, name string, rate Rate) *Limiter {
+	return &Limiter{
+		store: store,
+		name:  name,
+		rate:  rate,
+	}
+}
+
+// Allow takes a token from the bucket of the key.
+// If the bucket is empty, it returns false and the duration after which the next token will be available.
+// Note: if the Store fails, e.g. the database is not available, the request is allowed and the error is logged.
+func (l *Limiter) Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration) {
+	if l.rate.Limit <= 0 {
+		return true, 0
+	}
+
+	allowed, retryAfter, err := l.store.Take(ctx, l.name+":"+key, l.rate)
+	if err != nil {
+		logging.FromContext(ctx).Warn("[ratelimit] Error taking token, the request is allowed",
+			"limiter", l.name, "error", err)
+		return true, 0
+	}
+
+	return allowed, retryAfter
+}
+
+// bucket is the token bucket of the key.
+type bucket struct {
+	tokens     float64
+	refilledAt time.Time // refilledAt is zero for the new bucket
+}
+
+// take refills the bucket for the time elapsed since the last refill and takes a token from it.
+// If the bucket is empty, it returns false and the duration after which the next token will be available.
+func (b *bucket) take(rate Rate, now time.Time) (allowed bool, retryAfter time.Duration) {
+	if b.refilledAt.IsZero() {
+		b.tokens = float64(rate.Limit)
+	} else {
+		elapsed := now.Sub(b.refilledAt).Seconds()
+		b.tokens = min(float64(rate.Limit), b.tokens+elapsed*float64(rate.Limit)/rate.Period.Seconds())
+	}
+	b.refilledAt = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(rate.Period) / float64(rate.Limit))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// fullAt returns the time when the bucket is full again, so it can be removed from the Store.
+func (b *bucket) fullAt(rate Rate) time.Time {
+	missing := float64(rate.Limit) - b.tokens
+	return b.refilledAt.Add(time.Duration(missing * float64(rate.Period) / float64(rate.Limit)))
+}
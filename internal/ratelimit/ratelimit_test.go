@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	rate := Rate{Limit: 2, Period: time.Minute}
+
+	t.Run("OK", func(t *testing.T) {
+		now := time.Now()
+		s := NewMemoryStore()
+		s.now = func() time.Time { return now }
+
+		allowed, _, _ := s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+
+		allowed, retryAfter, err := s.Take(context.Background(), "key", rate)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 30*time.Second, retryAfter)
+
+		// Other keys have their own buckets
+		allowed, _, _ = s.Take(context.Background(), "another", rate)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should refill tokens over time", func(t *testing.T) {
+		now := time.Now()
+		s := NewMemoryStore()
+		s.now = func() time.Time { return now }
+
+		s.Take(context.Background(), "key", rate)
+		s.Take(context.Background(), "key", rate)
+		allowed, _, _ := s.Take(context.Background(), "key", rate)
+		assert.False(t, allowed)
+
+		now = now.Add(30 * time.Second)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.False(t, allowed)
+
+		// Bucket is never refilled over its capacity
+		now = now.Add(time.Hour)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.True(t, allowed)
+		allowed, _, _ = s.Take(context.Background(), "key", rate)
+		assert.False(t, allowed)
+	})
+
+	t.Run("should remove full buckets", func(t *testing.T) {
+		now := time.Now()
+		s := NewMemoryStore()
+		s.now = func() time.Time { return now }
+
+		s.Take(context.Background(), "key1", Rate{Limit: 1, Period: time.Minute})
+		s.Take(context.Background(), "key2", Rate{Limit: 1, Period: time.Hour})
+		assert.Len(t, s.buckets, 2)
+
+		now = now.Add(2 * time.Minute)
+		s.Take(context.Background(), "key3", Rate{Limit: 1, Period: time.Minute})
+		assert.Len(t, s.buckets, 2)
+		assert.Contains(t, s.buckets, "key2")
+	})
+}
+
+// storeFunc is the Store implemented by the function.
+type storeFunc func(ctx context.Context, key string, rate Rate) (bool, time.Duration, error)
+
+func (f storeFunc) Take(ctx context.Context, key string, rate Rate) (bool, time.Duration, error) {
+	return f(ctx, key, rate)
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Run("should prefix the keys with the name", func(t *testing.T) {
+		now := time.Now()
+		rate := Rate{Limit: 1, Period: time.Minute}
+		s := NewMemoryStore()
+		s.now = func() time.Time { return now }
+		first := NewLimiter(s, "first", rate)
+		second := NewLimiter(s, "second", rate)
+
+		allowed, _ := first.Allow(context.Background(), "key")
+		assert.True(t, allowed)
+		allowed, _ = second.Allow(context.Background(), "key")
+		assert.True(t, allowed)
+
+		allowed, retryAfter := first.Allow(context.Background(), "key")
+		assert.False(t, allowed)
+		assert.Equal(t, time.Minute, retryAfter)
+		assert.Contains(t, s.buckets, "first:key")
+	})
+
+	t.Run("should not limit with zero rate", func(t *testing.T) {
+		l := NewLimiter(storeFunc(func(context.Context, string, Rate) (bool, time.Duration, error) {
+			t.Fatal("store should not be called")
+			return false, 0, nil
+		}), "zero", Rate{})
+
+		allowed, _ := l.Allow(context.Background(), "key")
+		assert.True(t, allowed)
+	})
+
+	t.Run("should allow the request if the store fails", func(t *testing.T) {
+		l := NewLimiter(storeFunc(func(context.Context, string, Rate) (bool, time.Duration, error) {
+			return false, 0, errors.New("connection refused")
+		}), "failing", Rate{Limit: 1, Period: time.Minute})
+
+		allowed, retryAfter := l.Allow(context.Background(), "key")
+		assert.True(t, allowed)
+		assert.Zero(t, retryAfter)
+	})
+}
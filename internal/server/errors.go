@@ -0,0 +1,8 @@
+package server
+
+import "errors"
+
+var (
+	ErrSentryFlushTimeout  = errors.New("sentry events were not flushed in time")
+	ErrInvalidTrustedProxy = errors.New("invalid trusted proxy")
+)
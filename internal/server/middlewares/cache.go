@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"net/http"
+)
+
+// CacheControl is a middleware that sets the Cache-Control headers by the operation ID of the spec,
+// e.g. "public, max-age=60" for "GetPosts". The header is only set to the successful and 304 Not Modified responses,
+// so the errors are never cached. The operations without the header are not changed.
+//
+// It returns error if any header is set for the operation that is not in the spec.
+func CacheControl(spec *openapi3.T, headers map[string]string) (echo.MiddlewareFunc, error) {
+	operations := api.NewOperations(spec)
+
+	known := make(map[string]bool, len(operations))
+	for _, id := range operations {
+		known[id] = true
+	}
+
+	for id := range headers {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownOperation, id)
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			header, ok := headers[operations.ID(ctx)]
+			if !ok || header == "" {
+				return next(ctx)
+			}
+
+			res := ctx.Response()
+			res.Before(func() {
+				if res.Status < http.StatusBadRequest {
+					res.Header().Set(echo.HeaderCacheControl, header)
+				}
+			})
+
+			return next(ctx)
+		}
+	}, nil
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samgozman/go-bloggy/internal/api"
+)
+
+func Test_CacheControl(t *testing.T) {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serve sends the request to the route responding with the status and returns the response
+	serve := func(t *testing.T, path string, status int) *httptest.ResponseRecorder {
+		t.Helper()
+
+		middleware, err := CacheControl(spec, map[string]string{"GetPostsSlug": "public, max-age=60"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e := echo.New()
+		e.Use(middleware)
+		e.GET("/posts/:slug", func(ctx echo.Context) error { return ctx.NoContent(status) })
+		e.GET("/posts", func(ctx echo.Context) error { return ctx.NoContent(status) })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		rec := serve(t, "/posts/first", http.StatusOK)
+
+		assert.Equal(t, "public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		rec := serve(t, "/posts/first", http.StatusNotModified)
+
+		assert.Equal(t, "public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		rec := serve(t, "/posts/first", http.StatusNotFound)
+
+		assert.Empty(t, rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("operation without the header", func(t *testing.T) {
+		rec := serve(t, "/posts", http.StatusOK)
+
+		assert.Empty(t, rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		_, err := CacheControl(spec, map[string]string{"GetPost": "no-cache"})
+
+		assert.ErrorIs(t, err, ErrUnknownOperation)
+	})
+}
@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CORSConfig is the policy of the cross-origin requests.
+type CORSConfig struct {
+	AllowOrigins     []string // AllowOrigins are the exact origins, the "*." wildcard subdomains or "*" for any origin.
+	AllowMethods     []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS is a middleware that allows the cross-origin requests from the origins of the cfg.
+// The wildcard origin, e.g. "https://*.example.com", matches any subdomain of the domain with the same scheme and port,
+// but not the domain itself.
+func CORS(cfg CORSConfig) echo.MiddlewareFunc {
+	config := middleware.CORSConfig{
+		AllowMethods: cfg.AllowMethods,
+		AllowHeaders: []string{
+			echo.HeaderOrigin,
+			echo.HeaderContentType,
+			echo.HeaderAccept,
+			echo.HeaderAuthorization,
+			echo.HeaderXRequestID,
+			// Note: the conditional requests of the posts, e.g. to update the post only if it wasn't changed
+			"If-Match",
+			"If-None-Match",
+			echo.HeaderIfModifiedSince,
+		},
+		ExposeHeaders: []string{
+			echo.HeaderXRequestID,
+			echo.HeaderRetryAfter,
+			"ETag",
+			"Link", // Link has the cursors of the next and previous pages of the posts
+		},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	}
+
+	if slices.Contains(cfg.AllowOrigins, "*") {
+		config.AllowOrigins = []string{"*"}
+	} else {
+		config.AllowOriginFunc = originMatcher(cfg.AllowOrigins)
+	}
+
+	return middleware.CORSWithConfig(config)
+}
+
+// originMatcher returns the function that reports whether the origin is one of the origins,
+// either exactly or by the "*." wildcard of the subdomains.
+func originMatcher(origins []string) func(origin string) (bool, error) {
+	exact := make(map[string]bool)
+	var wildcards [][2]string // wildcards are the prefixes and suffixes around the "*", e.g. "https://" and ".example.com"
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		if prefix, suffix, ok := strings.Cut(origin, "*"); ok {
+			wildcards = append(wildcards, [2]string{prefix, suffix})
+		} else {
+			exact[origin] = true
+		}
+	}
+
+	return func(origin string) (bool, error) {
+		origin = strings.ToLower(origin)
+		if exact[origin] {
+			return true, nil
+		}
+
+		for _, w := range wildcards {
+			prefix, suffix := w[0], w[1]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+				isSubdomain(origin[len(prefix):len(origin)-len(suffix)]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// isSubdomain reports whether the s is the labels of the subdomain, e.g. "blog" or "eu.admin".
+// Note: it rejects the ports, paths and credentials, so the wildcard can't match the other host.
+func isSubdomain(s string) bool {
+	for _, label := range strings.Split(s, ".") {
+		if label == "" {
+			return false
+		}
+
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
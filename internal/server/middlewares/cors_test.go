@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_CORS(t *testing.T) {
+	// preflight sends the preflight request from the origin and returns the response
+	preflight := func(cfg CORSConfig, origin string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Use(CORS(cfg))
+		e.POST("/subscribers", func(ctx echo.Context) error { return ctx.NoContent(http.StatusCreated) })
+
+		req := httptest.NewRequest(http.MethodOptions, "/subscribers", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("allowed origins", func(t *testing.T) {
+		cfg := CORSConfig{
+			AllowOrigins: []string{"https://example.com", "https://*.example.com", "http://*.localhost:3000"},
+			AllowMethods: []string{http.MethodGet, http.MethodPost},
+			MaxAge:       time.Hour,
+		}
+
+		testCases := []struct {
+			origin  string
+			allowed bool
+		}{
+			{origin: "https://example.com", allowed: true},
+			{origin: "https://EXAMPLE.com", allowed: true},
+			{origin: "https://blog.example.com", allowed: true},
+			{origin: "https://eu.admin.example.com", allowed: true},
+			{origin: "http://admin.localhost:3000", allowed: true},
+			{origin: "http://example.com"},
+			{origin: "https://example.org"},
+			{origin: "https://.example.com"},
+			{origin: "https://evilexample.com"},
+			{origin: "https://evil.com:443.example.com"},
+			{origin: "https://evil.com/.example.com"},
+			{origin: "http://admin.localhost:3001"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.origin, func(t *testing.T) {
+				rec := preflight(cfg, tc.origin)
+
+				assert.Equal(t, http.StatusNoContent, rec.Code)
+				if !tc.allowed {
+					assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+					return
+				}
+
+				assert.Equal(t, tc.origin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+				assert.Equal(t, "GET,POST", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+				assert.Equal(t, "3600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+				assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+			})
+		}
+	})
+
+	t.Run("any origin", func(t *testing.T) {
+		rec := preflight(CORSConfig{AllowOrigins: []string{"*"}}, "https://example.com")
+
+		assert.Equal(t, "*", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		rec := preflight(CORSConfig{AllowOrigins: []string{"https://example.com"}, AllowCredentials: true},
+			"https://example.com")
+
+		assert.Equal(t, "https://example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	})
+
+	t.Run("conditional request headers", func(t *testing.T) {
+		rec := preflight(CORSConfig{AllowOrigins: []string{"*"}}, "https://example.com")
+
+		allowed := rec.Header().Get(echo.HeaderAccessControlAllowHeaders)
+		assert.Contains(t, allowed, "If-Match")
+		assert.Contains(t, allowed, "If-None-Match")
+		assert.Contains(t, allowed, "If-Modified-Since")
+	})
+
+	t.Run("exposed headers", func(t *testing.T) {
+		e := echo.New()
+		e.Use(CORS(CORSConfig{AllowOrigins: []string{"*"}}))
+		e.GET("/posts", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		req.Header.Set(echo.HeaderOrigin, "https://example.com")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		exposed := rec.Header().Get(echo.HeaderAccessControlExposeHeaders)
+		assert.Contains(t, exposed, echo.HeaderRetryAfter)
+		assert.Contains(t, exposed, "ETag")
+		assert.Contains(t, exposed, "Link")
+	})
+}
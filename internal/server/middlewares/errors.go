@@ -1,6 +1,18 @@
 package middlewares
 
+import "errors"
+
 const (
-	ErrAuthHeaderRequired = "authorization header is required"
-	ErrInvalidToken       = "invalid token"
+	ErrAuthHeaderRequired = "ERR_AUTH_HEADER_REQUIRED"
+	ErrInvalidToken       = "ERR_INVALID_TOKEN"
+	ErrAPIKeyRequired     = "ERR_API_KEY_REQUIRED"
+	ErrInvalidAPIKey      = "ERR_INVALID_API_KEY"
+	ErrRequestValidation  = "ERR_REQUEST_VALIDATION"
+)
+
+var (
+	ErrInvalidSpec         = errors.New("invalid OpenAPI spec")
+	ErrSecurityNotDeclared = errors.New("security is not declared for the operation")
+	ErrUnsupportedScheme   = errors.New("unsupported security scheme")
+	ErrUnknownOperation    = errors.New("unknown operation of the Cache-Control header")
 )
@@ -1,50 +1,210 @@
 package middlewares
 
 import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
 	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"net/http"
 	"strings"
 )
 
-// JWTAuth is a middleware that checks for JWT token in the request and validates it.
-// If the token is not present or invalid, it returns 401 Unauthorized.
-// If the token is valid, it adds user ID to the request context.
+// Auth is a middleware that authenticates the request by the security requirements
+// declared for the matching operation in the OpenAPI spec.
 //
-// It skips the middleware for all GET requests.
-func JWTAuth(jwtService jwtService) echo.MiddlewareFunc {
+// Operations with `security: []` are public. For the http bearer scheme the JWT token is validated
+// and user ID is added to the request context and its logger. For the apiKey scheme the key must be one of apiKeys.
+// If the operation declares several requirements, any of them is enough.
+// If the token or the key is not present or invalid, it returns 401 Unauthorized problem with the error code.
+//
+// It returns error if any operation in the spec has no security declared, so no route is public by accident.
+func Auth(spec *openapi3.T, jwtService jwtService, apiKeys []string) (echo.MiddlewareFunc, error) {
+	if err := checkSecurityDeclared(spec); err != nil {
+		return nil, err
+	}
+
+	router, err := newRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &authenticator{
+		jwtService: jwtService,
+		apiKeys:    apiKeys,
+	}
+	if spec.Components != nil {
+		a.schemes = spec.Components.SecuritySchemes
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			// Skip for all GET requests
-			if ctx.Request().Method == "GET" {
+			route, _, err := router.FindRoute(ctx.Request())
+			if err != nil {
+				// Let echo respond with 404 or 405 for unknown routes
 				return next(ctx)
 			}
 
-			// Skip for /login requests and /subscribers requests
-			if strings.HasPrefix(ctx.Request().URL.Path, "/login") ||
-				strings.HasPrefix(ctx.Request().URL.Path, "/subscribers") {
-				return next(ctx)
+			return a.authenticate(ctx, *route.Operation.Security, next)
+		}
+	}, nil
+}
+
+// newRouter returns the router, that finds the operations of the spec by the request path.
+func newRouter(spec *openapi3.T) (routers.Router, error) {
+	// Note: servers are removed to match the routes by path only, regardless of the host
+	routesSpec := *spec
+	routesSpec.Servers = nil
+
+	// TODO: Fix examples in the spec and enable validation
+	router, err := legacy.NewRouter(&routesSpec, openapi3.DisableExamplesValidation())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSpec, err)
+	}
+
+	return router, nil
+}
+
+type jwtService interface {
+	ParseTokenString(tokenString string) (externalUserID string, err error)
+}
+
+type authenticator struct {
+	jwtService jwtService
+	apiKeys    []string
+	schemes    openapi3.SecuritySchemes
+}
+
+// authenticate calls next if the request satisfies any of the security requirements.
+func (a *authenticator) authenticate(
+	ctx echo.Context,
+	security openapi3.SecurityRequirements,
+	next echo.HandlerFunc,
+) error {
+	if len(security) == 0 {
+		return next(ctx)
+	}
+
+	// Note: invalid credentials are reported over the missing ones,
+	// e.g. invalid API key if the operation also accepts the token, but it is not sent
+	var failure string
+	for _, requirement := range security {
+		if reason := a.check(ctx, requirement); reason != "" {
+			if failure == "" || (isMissingCredentials(failure) && !isMissingCredentials(reason)) {
+				failure = reason
 			}
+			continue
+		}
+
+		return next(ctx)
+	}
+
+	return problem.Respond(ctx, http.StatusUnauthorized, api.RequestError{
+		Code:    failure,
+		Message: authFailureMessages[failure],
+	})
+}
+
+// authFailureMessages are the messages of the authentication failures by their codes.
+var authFailureMessages = map[string]string{ //nolint:gochecknoglobals // constant
+	ErrAuthHeaderRequired: "Authorization header is required",
+	ErrInvalidToken:       "Invalid token",
+	ErrAPIKeyRequired:     "API key is required",
+	ErrInvalidAPIKey:      "Invalid API key",
+}
+
+// check returns the error code of the reason why the request doesn't satisfy all the schemes of the requirement,
+// or an empty string if it does.
+func (a *authenticator) check(ctx echo.Context, requirement openapi3.SecurityRequirement) string {
+	for name := range requirement {
+		scheme := a.schemes[name].Value
 
-			// get token from header
-			token := ctx.Request().Header.Get("Authorization")
+		switch scheme.Type {
+		case "http":
+			token := ctx.Request().Header.Get(echo.HeaderAuthorization)
 			token = strings.TrimPrefix(token, "Bearer ")
 			if token == "" {
-				return ctx.JSON(401, ErrAuthHeaderRequired)
+				return ErrAuthHeaderRequired
 			}
 
-			// parse token
-			externalUserID, err := jwtService.ParseTokenString(token)
+			externalUserID, err := a.jwtService.ParseTokenString(token)
 			if err != nil {
-				return ctx.JSON(401, ErrInvalidToken)
+				return ErrInvalidToken
 			}
 
-			// add user ID to context
 			ctx.Set("externalUserID", externalUserID)
+			ctx.SetRequest(ctx.Request().WithContext(logging.With(ctx.Request().Context(), "user_id", externalUserID)))
+		case "apiKey":
+			key := ctx.Request().Header.Get(scheme.Name)
+			if key == "" {
+				return ErrAPIKeyRequired
+			}
 
-			return next(ctx)
+			if !a.isValidAPIKey(key) {
+				return ErrInvalidAPIKey
+			}
 		}
 	}
+
+	return ""
 }
 
-type jwtService interface {
-	ParseTokenString(tokenString string) (externalUserID string, err error)
+func isMissingCredentials(reason string) bool {
+	return reason == ErrAuthHeaderRequired || reason == ErrAPIKeyRequired
+}
+
+func (a *authenticator) isValidAPIKey(key string) bool {
+	for _, apiKey := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSecurityDeclared checks that every operation declares security with the supported schemes.
+func checkSecurityDeclared(spec *openapi3.T) error {
+	for path, item := range spec.Paths.Map() {
+		for method, operation := range item.Operations() {
+			if operation.Security == nil {
+				return fmt.Errorf("%w: %s %s", ErrSecurityNotDeclared, method, path)
+			}
+
+			for _, requirement := range *operation.Security {
+				for name := range requirement {
+					if !isSupportedScheme(spec, name) {
+						return fmt.Errorf("%w: %s in %s %s", ErrUnsupportedScheme, name, method, path)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isSupportedScheme returns true for the http bearer and header apiKey schemes declared in the spec.
+func isSupportedScheme(spec *openapi3.T, name string) bool {
+	if spec.Components == nil {
+		return false
+	}
+
+	ref, ok := spec.Components.SecuritySchemes[name]
+	if !ok || ref.Value == nil {
+		return false
+	}
+
+	switch ref.Value.Type {
+	case "http":
+		return strings.EqualFold(ref.Value.Scheme, "bearer")
+	case "apiKey":
+		return ref.Value.In == "header"
+	}
+
+	return false
 }
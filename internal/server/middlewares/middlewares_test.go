@@ -1,25 +1,51 @@
 package middlewares
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/problem"
 	jwtMock "github.com/samgozman/go-bloggy/mocks/jwt"
 )
 
-func Test_JWTAuth(t *testing.T) {
-	mockJwtService := jwtMock.NewMockServiceInterface(t)
-	middleware := JWTAuth(mockJwtService)
+// assertFailure asserts that the response is the 401 problem with the code.
+func assertFailure(t *testing.T, rec *httptest.ResponseRecorder, code string) {
+	t.Helper()
 
-	t.Run("valid token", func(t *testing.T) {
-		mockJwtService.On("ParseTokenString", "validToken").Return("SuperUserID", nil)
+	var body api.RequestError
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
 
-		req := httptest.NewRequest(http.MethodPost, "/", nil)
-		req.Header.Set("Authorization", "Bearer validToken")
+	assert.Equal(t, problem.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, http.StatusUnauthorized, body.Status)
+	assert.Equal(t, code, body.Code)
+	assert.NotEmpty(t, body.Message)
+}
+
+func Test_Auth(t *testing.T) {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockJwtService := jwtMock.NewMockServiceInterface(t)
+	middleware, err := Auth(spec, mockJwtService, []string{"validKey"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serve calls the middleware with the request and returns the recorder and the context
+	serve := func(req *http.Request) (*httptest.ResponseRecorder, echo.Context) {
 		rec := httptest.NewRecorder()
 		ctx := echo.New().NewContext(req, rec)
 
@@ -27,68 +53,173 @@ func Test_JWTAuth(t *testing.T) {
 			return c.String(http.StatusOK, "test")
 		})(ctx)
 
+		return rec, ctx
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		mockJwtService.On("ParseTokenString", "validToken").Return("SuperUserID", nil)
+
+		var logs bytes.Buffer
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		req = req.WithContext(logging.WithLogger(req.Context(), slog.New(slog.NewJSONHandler(&logs, nil))))
+		req.Header.Set("Authorization", "Bearer validToken")
+		rec, ctx := serve(req)
+
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, "test", rec.Body.String())
 		assert.Equal(t, "SuperUserID", ctx.Get("externalUserID"))
+
+		logging.FromContext(ctx.Request().Context()).Info("test")
+		assert.Contains(t, logs.String(), `"user_id":"SuperUserID"`)
 	})
 
 	t.Run("invalid token", func(t *testing.T) {
 		mockJwtService.On("ParseTokenString", "invalidToken").Return("", echo.ErrUnauthorized)
 
-		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
 		req.Header.Set("Authorization", "Bearer invalidToken")
-		rec := httptest.NewRecorder()
-		ctx := echo.New().NewContext(req, rec)
-
-		_ = middleware(func(c echo.Context) error {
-			return c.String(http.StatusOK, "test")
-		})(ctx)
+		rec, _ := serve(req)
 
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Equal(t, fmt.Sprintf("\"%s\"\n", ErrInvalidToken), rec.Body.String())
+		assertFailure(t, rec, ErrInvalidToken)
 	})
 
 	t.Run("no token", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodPost, "/", nil)
-		rec := httptest.NewRecorder()
-		ctx := echo.New().NewContext(req, rec)
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		rec, _ := serve(req)
 
-		_ = middleware(func(c echo.Context) error {
-			return c.String(http.StatusOK, "test")
-		})(ctx)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assertFailure(t, rec, ErrAuthHeaderRequired)
+	})
+
+	t.Run("GET /users request requires token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		rec, _ := serve(req)
 
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Equal(t, fmt.Sprintf("\"%s\"\n", ErrAuthHeaderRequired), rec.Body.String())
+		assertFailure(t, rec, ErrAuthHeaderRequired)
 	})
 
-	t.Run("skip allowed paths", func(t *testing.T) {
-		t.Run("GET request", func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
-			rec := httptest.NewRecorder()
-			ctx := echo.New().NewContext(req, rec)
+	t.Run("API key", func(t *testing.T) {
+		t.Run("valid key", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts/test/send-email", nil)
+			req.Header.Set("X-API-Key", "validKey")
+			rec, ctx := serve(req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Nil(t, ctx.Get("externalUserID"))
+		})
+
+		t.Run("invalid key", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts/test/send-email", nil)
+			req.Header.Set("X-API-Key", "invalidKey")
+			rec, _ := serve(req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertFailure(t, rec, ErrInvalidAPIKey)
+		})
 
-			_ = middleware(func(c echo.Context) error {
-				return c.String(http.StatusOK, "test")
-			})(ctx)
+		t.Run("token is also accepted", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts/test/send-email", nil)
+			req.Header.Set("Authorization", "Bearer validToken")
+			rec, _ := serve(req)
 
 			assert.Equal(t, http.StatusOK, rec.Code)
-			assert.Equal(t, "test", rec.Body.String())
 		})
 
-		testCases := []string{"/login", "/subscribers"}
-		for _, path := range testCases {
-			t.Run(path, func(t *testing.T) {
-				req := httptest.NewRequest(http.MethodPost, path, nil)
-				rec := httptest.NewRecorder()
-				ctx := echo.New().NewContext(req, rec)
+		t.Run("key is not accepted by bearer only operation", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+			req.Header.Set("X-API-Key", "validKey")
+			rec, _ := serve(req)
 
-				_ = middleware(func(c echo.Context) error {
-					return c.String(http.StatusOK, "test")
-				})(ctx)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assertFailure(t, rec, ErrAuthHeaderRequired)
+		})
+	})
+
+	t.Run("skip public operations", func(t *testing.T) {
+		testCases := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodGet, "/health"},
+			{http.MethodGet, "/posts"},
+			{http.MethodGet, "/posts/some-slug"},
+			{http.MethodPost, "/login/github/authorize"},
+			{http.MethodPost, "/subscribers"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+				req := httptest.NewRequest(tc.method, tc.path, nil)
+				rec, _ := serve(req)
 
 				assert.Equal(t, http.StatusOK, rec.Code)
 				assert.Equal(t, "test", rec.Body.String())
 			})
 		}
 	})
+
+	t.Run("skip unknown routes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+		rec, _ := serve(req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func Test_Auth_SecurityDeclared(t *testing.T) {
+	t.Run("every operation in the API spec declares security", func(t *testing.T) {
+		spec, err := api.GetSwagger()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, checkSecurityDeclared(spec))
+	})
+
+	load := func(t *testing.T, data string) *openapi3.T {
+		t.Helper()
+
+		spec, err := openapi3.NewLoader().LoadFromData([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return spec
+	}
+
+	t.Run("should return error if security is not declared", func(t *testing.T) {
+		spec := load(t, `
+openapi: "3.0.0"
+info: {title: test, version: "1"}
+paths:
+  /drafts:
+    get:
+      responses:
+        '200': {description: OK}
+`)
+
+		_, err := Auth(spec, jwtMock.NewMockServiceInterface(t), nil)
+		assert.ErrorIs(t, err, ErrSecurityNotDeclared)
+	})
+
+	t.Run("should return error for unsupported scheme", func(t *testing.T) {
+		spec := load(t, `
+openapi: "3.0.0"
+info: {title: test, version: "1"}
+paths:
+  /drafts:
+    get:
+      security:
+        - BasicAuth: []
+      responses:
+        '200': {description: OK}
+components:
+  securitySchemes:
+    BasicAuth: {type: http, scheme: basic}
+`)
+
+		_, err := Auth(spec, jwtMock.NewMockServiceInterface(t), nil)
+		assert.ErrorIs(t, err, ErrUnsupportedScheme)
+	})
 }
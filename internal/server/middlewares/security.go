@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"time"
+)
+
+// headerPermissionsPolicy is not defined by echo.
+const headerPermissionsPolicy = "Permissions-Policy"
+
+// SecurityHeadersConfig are the values of the security headers, the empty ones are not set.
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration // HSTSMaxAge of Strict-Transport-Security, 0 disables it.
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	ContentSecurityPolicy string
+	ReferrerPolicy        string
+	PermissionsPolicy     string
+}
+
+// SecurityHeaders is a middleware that sets the security headers of the cfg to all responses,
+// including the errors, and X-Content-Type-Options: nosniff, so the JSON is never sniffed as HTML.
+// Note: the browsers ignore HSTS over plain HTTP, so it's safe to send it before the TLS termination.
+func SecurityHeaders(cfg SecurityHeadersConfig) echo.MiddlewareFunc {
+	headers := map[string]string{
+		echo.HeaderXContentTypeOptions:   "nosniff",
+		echo.HeaderContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		echo.HeaderReferrerPolicy:        cfg.ReferrerPolicy,
+		headerPermissionsPolicy:          cfg.PermissionsPolicy,
+	}
+
+	if cfg.HSTSMaxAge > 0 {
+		hsts := fmt.Sprintf("max-age=%d", int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+		headers[echo.HeaderStrictTransportSecurity] = hsts
+	}
+
+	for name, value := range headers {
+		if value == "" {
+			delete(headers, name)
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			h := ctx.Response().Header()
+			for name, value := range headers {
+				h.Set(name, value)
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_SecurityHeaders(t *testing.T) {
+	// serve sends the request to the route returning an error and returns the response headers
+	serve := func(cfg SecurityHeadersConfig) http.Header {
+		e := echo.New()
+		e.Use(SecurityHeaders(cfg))
+		e.GET("/posts", func(_ echo.Context) error { return echo.ErrInternalServerError })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+		return rec.Header()
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		h := serve(SecurityHeadersConfig{
+			HSTSMaxAge:            365 * 24 * time.Hour,
+			HSTSIncludeSubdomains: true,
+			HSTSPreload:           true,
+			ContentSecurityPolicy: "default-src 'none'",
+			ReferrerPolicy:        "no-referrer",
+			PermissionsPolicy:     "camera=()",
+		})
+
+		assert.Equal(t, "max-age=31536000; includeSubDomains; preload", h.Get(echo.HeaderStrictTransportSecurity))
+		assert.Equal(t, "nosniff", h.Get(echo.HeaderXContentTypeOptions))
+		assert.Equal(t, "default-src 'none'", h.Get(echo.HeaderContentSecurityPolicy))
+		assert.Equal(t, "no-referrer", h.Get(echo.HeaderReferrerPolicy))
+		assert.Equal(t, "camera=()", h.Get("Permissions-Policy"))
+	})
+
+	t.Run("empty values are not set", func(t *testing.T) {
+		h := serve(SecurityHeadersConfig{})
+
+		assert.Equal(t, "nosniff", h.Get(echo.HeaderXContentTypeOptions))
+		for _, name := range []string{
+			echo.HeaderStrictTransportSecurity,
+			echo.HeaderContentSecurityPolicy,
+			echo.HeaderReferrerPolicy,
+			"Permissions-Policy",
+		} {
+			assert.NotContains(t, h, name)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/problem"
+)
+
+// ValidationConfig of the Validation middleware.
+type ValidationConfig struct {
+	// OnInvalidResponse is called after the response that doesn't match the spec is sent, e.g. to log it or fail the test.
+	// The responses are only validated if it's set, as they have to be buffered.
+	OnInvalidResponse func(ctx echo.Context, err error)
+}
+
+// MIMEApplicationMergePatchJSON is the content type of the JSON Merge Patch (RFC 7396) requests.
+const MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+
+func init() {
+	// Note: kin-openapi decodes only the known content types, the merge patch is a JSON as well
+	openapi3filter.RegisterBodyDecoder(MIMEApplicationMergePatchJSON, openapi3filter.JSONBodyDecoder)
+}
+
+// Validation is a middleware that validates the requests against the matching operation in the OpenAPI spec.
+//
+// The path, query and header parameters and the body are validated, and the request that doesn't match the spec
+// is rejected with 400 Bad Request and the list of the invalid fields, before it reaches the handler.
+// The security is not validated, see Auth.
+//
+// If cfg.OnInvalidResponse is set, the status and the body of the responses are validated as well.
+// The server errors are not validated, as they are not declared for each operation.
+func Validation(spec *openapi3.T, cfg ValidationConfig) (echo.MiddlewareFunc, error) {
+	router, err := newRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		// Note: the defaults are set by the handlers, so the request is passed as is
+		SkipSettingDefaults:   true,
+		IncludeResponseStatus: true,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			route, pathParams, err := router.FindRoute(ctx.Request())
+			if err != nil {
+				// Let echo respond with 404 or 405 for unknown routes
+				return next(ctx)
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    ctx.Request(),
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(ctx.Request().Context(), input); err != nil {
+				return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{
+					Code:    ErrRequestValidation,
+					Message: "Request doesn't match the API spec",
+					Errors:  fieldErrors(err),
+				})
+			}
+
+			if cfg.OnInvalidResponse == nil {
+				return next(ctx)
+			}
+
+			return validateResponse(ctx, input, cfg.OnInvalidResponse, next)
+		}
+	}, nil
+}
+
+// validateResponse calls next with the response recorded, and calls onInvalid if it doesn't match the spec.
+func validateResponse(
+	ctx echo.Context,
+	input *openapi3filter.RequestValidationInput,
+	onInvalid func(ctx echo.Context, err error),
+	next echo.HandlerFunc,
+) error {
+	res := ctx.Response()
+	recorder := &responseRecorder{ResponseWriter: res.Writer}
+	res.Writer = recorder
+	defer func() { res.Writer = recorder.ResponseWriter }()
+
+	if err := next(ctx); err != nil {
+		// Note: the errors are written by echo later, e.g. 404 of the handler, so they can't be validated
+		return err
+	}
+
+	if !res.Committed || res.Status >= http.StatusInternalServerError {
+		return nil
+	}
+
+	output := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 res.Status,
+		Header:                 res.Header(),
+		Options:                input.Options,
+	}
+	output.SetBodyBytes(recorder.body.Bytes())
+	if err := openapi3filter.ValidateResponse(ctx.Request().Context(), output); err != nil {
+		onInvalid(ctx, err)
+	}
+
+	return nil
+}
+
+// responseRecorder writes the response and keeps a copy of the body to validate it.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// fieldErrors returns the invalid fields of the request validation error, sorted by the parameter and the pointer.
+func fieldErrors(err error) *[]api.FieldError {
+	details := collectFieldErrors(api.FieldError{}, err)
+	sort.SliceStable(details, func(i, j int) bool {
+		return fieldName(details[i]) < fieldName(details[j])
+	})
+
+	return &details
+}
+
+// collectFieldErrors flattens the err into the errors of the field, that is the parameter or
+// the JSON Pointer to the property of the body, e.g. "#/keywords/0".
+func collectFieldErrors(field api.FieldError, err error) []api.FieldError {
+	switch e := err.(type) { //nolint:errorlint // the errors of the validation are not wrapped
+	case openapi3.MultiError:
+		details := make([]api.FieldError, 0, len(e))
+		for _, err := range e {
+			details = append(details, collectFieldErrors(field, err)...)
+		}
+		return details
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			field = api.FieldError{Parameter: &e.Parameter.Name}
+		case e.RequestBody != nil:
+			pointer := "#"
+			field = api.FieldError{Pointer: &pointer}
+		}
+
+		switch e.Err.(type) { //nolint:errorlint // the errors of the validation are not wrapped
+		case openapi3.MultiError, *openapi3.SchemaError:
+			return collectFieldErrors(field, e.Err)
+		}
+
+		field.Detail = e.Reason
+		if e.Err != nil && e.Err.Error() != field.Detail {
+			field.Detail = strings.TrimPrefix(field.Detail+": "+e.Err.Error(), ": ")
+		}
+		return []api.FieldError{field}
+	case *openapi3.SchemaError:
+		if path := e.JSONPointer(); len(path) > 0 && field.Pointer != nil {
+			pointer := *field.Pointer
+			for _, key := range path {
+				pointer += "/" + pointerEscaper.Replace(key)
+			}
+			field.Pointer = &pointer
+		}
+
+		// Note: the reason never includes the value, so the secrets from the request are not returned
+		field.Detail = e.Reason
+		if field.Detail == "" {
+			field.Detail = "doesn't match the schema " + e.SchemaField
+		}
+		return []api.FieldError{field}
+	default:
+		field.Detail = err.Error()
+		return []api.FieldError{field}
+	}
+}
+
+// pointerEscaper escapes the keys of the JSON Pointer (RFC 6901).
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1") //nolint:gochecknoglobals // constant
+
+// fieldName returns the parameter or the pointer of the field.
+func fieldName(field api.FieldError) string {
+	switch {
+	case field.Parameter != nil:
+		return *field.Parameter
+	case field.Pointer != nil:
+		return *field.Pointer
+	}
+
+	return ""
+}
@@ -0,0 +1,226 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/samgozman/go-bloggy/internal/api"
+	"github.com/samgozman/go-bloggy/internal/problem"
+)
+
+func Test_Validation(t *testing.T) {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serve sends the request to the handler behind the middleware and returns the response
+	// with the errors of the response validation
+	serve := func(req *http.Request, handler echo.HandlerFunc) (*httptest.ResponseRecorder, []error) {
+		var invalid []error
+		middleware, err := Validation(spec, ValidationConfig{
+			OnInvalidResponse: func(_ echo.Context, err error) { invalid = append(invalid, err) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e := echo.New()
+		e.Use(middleware)
+		e.GET("/posts", handler)
+		e.GET("/posts/:slug", handler)
+		e.POST("/posts", handler)
+		e.PATCH("/posts/:slug", handler)
+		e.POST("/users/:id/disable", handler)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec, invalid
+	}
+
+	ok := func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusOK, api.PostsListResponse{Posts: []api.PostsListItem{}, Total: 0})
+	}
+
+	// details returns the field errors of the response
+	details := func(t *testing.T, rec *httptest.ResponseRecorder) []api.FieldError {
+		t.Helper()
+
+		var body api.RequestError
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, problem.ContentType, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, ErrRequestValidation, body.Code)
+		assert.Equal(t, http.StatusBadRequest, body.Status)
+		if body.Errors == nil {
+			return nil
+		}
+
+		return *body.Errors
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		rec, invalid := serve(httptest.NewRequest(http.MethodGet, "/posts?page=2&limit=25", nil), ok)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("invalid query parameters", func(t *testing.T) {
+		called := false
+		rec, _ := serve(httptest.NewRequest(http.MethodGet, "/posts?page=0&limit=26", nil), func(ctx echo.Context) error {
+			called = true
+			return ok(ctx)
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, called)
+
+		fields := details(t, rec)
+		if assert.Len(t, fields, 2) {
+			assert.Equal(t, api.FieldError{Parameter: ptr("limit"), Detail: "number must be at most 25"}, fields[0])
+			assert.Equal(t, api.FieldError{Parameter: ptr("page"), Detail: "number must be at least 1"}, fields[1])
+		}
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		rec, _ := serve(httptest.NewRequest(http.MethodPost, "/users/abc/disable", nil), ok)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		fields := details(t, rec)
+		if assert.Len(t, fields, 1) {
+			assert.Equal(t, ptr("id"), fields[0].Parameter)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		body := `{"title": "Title", "slug": "slug", "content": 1, "keywords": ["go", 2]}`
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec, _ := serve(req, ok)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		fields := details(t, rec)
+		if assert.Len(t, fields, 3) {
+			assert.Equal(t, api.FieldError{Pointer: ptr("#/content"), Detail: "value must be a string"}, fields[0])
+			assert.Equal(t, ptr("#/description"), fields[1].Pointer)
+			assert.Equal(t, api.FieldError{Pointer: ptr("#/keywords/1"), Detail: "value must be a string"}, fields[2])
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(`{"title":`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec, _ := serve(req, ok)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		fields := details(t, rec)
+		if assert.Len(t, fields, 1) {
+			assert.Equal(t, ptr("#"), fields[0].Pointer)
+		}
+	})
+
+	t.Run("merge patch body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/posts/slug", strings.NewReader(`{"keywords": null}`))
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationMergePatchJSON)
+		rec, _ := serve(req, func(ctx echo.Context) error { return ctx.NoContent(http.StatusNoContent) })
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPatch, "/posts/slug", strings.NewReader(`{"title": null, "slug": "new"}`))
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationMergePatchJSON)
+		rec, _ = serve(req, ok)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Len(t, details(t, rec), 2)
+	})
+
+	t.Run("body is passed to the handler", func(t *testing.T) {
+		body := `{"title": "Title", "slug": "slug", "description": "Description", "content": "Content"}`
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		var post api.PostRequest
+		rec, _ := serve(req, func(ctx echo.Context) error {
+			if err := ctx.Bind(&post); err != nil {
+				return err
+			}
+			return ctx.NoContent(http.StatusNoContent)
+		})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "Title", post.Title)
+	})
+
+	t.Run("unknown route is passed to echo", func(t *testing.T) {
+		rec, _ := serve(httptest.NewRequest(http.MethodGet, "/unknown", nil), ok)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid response body", func(t *testing.T) {
+		rec, invalid := serve(httptest.NewRequest(http.MethodGet, "/posts", nil), func(ctx echo.Context) error {
+			return ctx.JSON(http.StatusOK, map[string]any{"posts": []string{"post"}})
+		})
+
+		// Note: the response is sent as is, only reported
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, invalid, 1)
+	})
+
+	t.Run("problem details response", func(t *testing.T) {
+		_, invalid := serve(httptest.NewRequest(http.MethodGet, "/posts", nil), func(ctx echo.Context) error {
+			return problem.Respond(ctx, http.StatusBadRequest, api.RequestError{Code: "code", Message: "message"})
+		})
+
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("undeclared response status", func(t *testing.T) {
+		_, invalid := serve(httptest.NewRequest(http.MethodGet, "/posts", nil), func(ctx echo.Context) error {
+			return ctx.JSON(http.StatusConflict, api.RequestError{Code: "code", Message: "message"})
+		})
+
+		assert.Len(t, invalid, 1)
+	})
+
+	t.Run("server errors are not validated", func(t *testing.T) {
+		_, invalid := serve(httptest.NewRequest(http.MethodGet, "/posts", nil), func(ctx echo.Context) error {
+			return ctx.JSON(http.StatusInternalServerError, api.RequestError{Code: "code", Message: "message"})
+		})
+
+		assert.Empty(t, invalid)
+	})
+
+	t.Run("responses are not validated by default", func(t *testing.T) {
+		middleware, err := Validation(spec, ValidationConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e := echo.New()
+		e.Use(middleware)
+		e.GET("/posts", func(ctx echo.Context) error { return ctx.String(http.StatusTeapot, "test") })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+		assert.Equal(t, "test", rec.Body.String())
+	})
+}
+
+func ptr(s string) *string {
+	return &s
+}
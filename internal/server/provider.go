@@ -1,57 +1,207 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
 	"github.com/getsentry/sentry-go"
 	sentryecho "github.com/getsentry/sentry-go/echo"
 	"github.com/google/wire"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/samgozman/go-bloggy/internal/api"
 	"github.com/samgozman/go-bloggy/internal/config"
 	"github.com/samgozman/go-bloggy/internal/jwt"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/logging"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/problem"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
 	"github.com/samgozman/go-bloggy/internal/server/middlewares"
+	"github.com/samgozman/go-bloggy/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Config struct {
-	SentryDSN string
+	SentryDSN         string
+	APIKeys           []string
+	ServiceName       string
+	TrustedProxies    []string // TrustedProxies are the IPs or CIDRs of the reverse proxies, see ipExtractor.
+	CORS              middlewares.CORSConfig
+	Security          middlewares.SecurityHeadersConfig
+	CacheControl      map[string]string // CacheControl are the headers by the operation ID, see middlewares.CacheControl.
+	ValidateResponses bool              // ValidateResponses logs the responses that don't match the spec, see middlewares.Validation.
 }
 
 func ProvideConfig(cfg *config.Config) *Config {
 	return &Config{
-		SentryDSN: cfg.SentryDSN,
+		SentryDSN:      cfg.SentryDSN,
+		APIKeys:        cfg.APIKeys,
+		ServiceName:    cfg.Tracing.ServiceName,
+		TrustedProxies: cfg.RateLimit.TrustedProxies,
+		CORS: middlewares.CORSConfig{
+			AllowOrigins:     cfg.CORS.AllowOrigins,
+			AllowMethods:     cfg.CORS.AllowMethods,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		},
+		Security:          middlewares.SecurityHeadersConfig(cfg.SecurityHeaders),
+		CacheControl:      cfg.CacheControl,
+		ValidateResponses: cfg.ValidateResponses,
 	}
 }
 
 // ProvideServer is a provider for the echo server.
-func ProvideServer(cfg *Config, jwtService jwt.ServiceInterface) *echo.Echo {
-	if err := sentry.Init(sentry.ClientOptions{
-		Dsn:              cfg.SentryDSN,
-		AttachStacktrace: true,
-		EnableTracing:    true,
-		TracesSampleRate: 1.0,
-	}); err != nil {
-		fmt.Printf("Sentry initialization failed: %v\n", err)
+// On shutdown, the server drains the in-flight requests and then the Sentry events are flushed.
+func ProvideServer(
+	cfg *Config,
+	jwtService jwt.ServiceInterface,
+	lc *lifecycle.Manager,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+	rl *ratelimit.Config,
+	store ratelimit.Store,
+) (*echo.Echo, error) {
+	spec, err := api.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading OpenAPI spec: %w", err)
+	}
+
+	auth, err := middlewares.Auth(spec, jwtService, cfg.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var validationConfig middlewares.ValidationConfig
+	if cfg.ValidateResponses {
+		validationConfig.OnInvalidResponse = logInvalidResponse
+	}
+	validation, err := middlewares.Validation(spec, validationConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheControl, err := middlewares.CacheControl(spec, cfg.CacheControl)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit, err := ratelimit.Middleware(spec, store, rl.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := ipExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: Sentry is disabled if the DSN is not set
+	sentryEnabled := cfg.SentryDSN != ""
+	if sentryEnabled {
+		if err := sentry.Init(sentry.ClientOptions{
+			Dsn:              cfg.SentryDSN,
+			AttachStacktrace: true,
+			EnableTracing:    true,
+			TracesSampleRate: 1.0,
+		}); err != nil {
+			return nil, fmt.Errorf("error initializing Sentry: %w", err)
+		}
+		lc.OnStop(lifecycle.StageFlush, "sentry", flushSentry)
 	}
 
 	server := echo.New()
-	server.Use(middleware.Logger())
-	server.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		// TODO: Pass allowed origins from cfg
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{
-			echo.HeaderOrigin,
-			echo.HeaderContentType,
-			echo.HeaderAccept,
-			echo.HeaderAuthorization,
-		},
-	}))
-	server.Use(middlewares.JWTAuth(jwtService))
+	server.IPExtractor = extractor
+	server.HTTPErrorHandler = problem.HTTPErrorHandler
+	// Note: the span is started first, so the time spent in all middlewares is traced
+	server.Use(otelecho.Middleware(cfg.ServiceName,
+		otelecho.WithTracerProvider(tp),
+		otelecho.WithPropagators(tracing.Propagator()),
+		otelecho.WithSkipper(skipTracing),
+	))
+	server.Use(logging.Middleware(slog.Default(), spec))
+	server.Use(m.Middleware(spec))
+	server.Use(middlewares.SecurityHeaders(cfg.Security))
+	server.Use(middlewares.CORS(cfg.CORS))
+	server.Use(cacheControl)
+	server.Use(rateLimit)
+	server.Use(auth)
+	server.Use(validation)
 	server.Use(middleware.Recover())
 
 	// Add the Sentry middleware
-	server.Use(sentryecho.New(sentryecho.Options{}))
+	if sentryEnabled {
+		server.Use(sentryecho.New(sentryecho.Options{}))
+	}
+
+	m.Mount(server)
+	lc.OnStop(lifecycle.StageServer, "http server", server.Shutdown)
+
+	return server, nil
+}
+
+// ipExtractor returns the echo.IPExtractor of the client IP address, that is used for the rate limits and the logs.
+// Without the trustedProxies, the IP of the direct peer is used, so the clients can't spoof it with the headers.
+// Otherwise, the IP is taken from the X-Forwarded-For header, skipping the addresses of the trustedProxies.
+func ipExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// Note: the private networks are not trusted by default, as the clients can be in them too
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, proxy)
+			}
+			if v4 := ip.To4(); v4 != nil {
+				ip = v4
+			}
+			ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// logInvalidResponse logs the response that doesn't match the spec, so it can be fixed in the handler or the spec.
+func logInvalidResponse(ctx echo.Context, err error) {
+	reqCtx := ctx.Request().Context()
+	logging.FromContext(reqCtx).ErrorContext(reqCtx, "Response doesn't match the API spec",
+		"status", ctx.Response().Status, "error", err)
+}
+
+// skipTracing skips the probes and the metrics scrapes, that would flood the traces.
+func skipTracing(ctx echo.Context) bool {
+	path := ctx.Request().URL.Path
+	return strings.HasPrefix(path, "/health") || path == "/metrics"
+}
+
+// flushSentry waits for the buffered Sentry events to be sent until the ctx deadline.
+func flushSentry(ctx context.Context) error {
+	timeout := 2 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	if !sentry.Flush(timeout) {
+		return ErrSentryFlushTimeout
+	}
 
-	return server
+	return nil
 }
 
 var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
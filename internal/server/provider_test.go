@@ -1,8 +1,21 @@
 package server
 
 import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/samgozman/go-bloggy/internal/metrics"
+	"github.com/samgozman/go-bloggy/internal/ratelimit"
+	"github.com/samgozman/go-bloggy/internal/server/middlewares"
 	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	jwtMock "github.com/samgozman/go-bloggy/mocks/jwt"
 )
@@ -13,9 +26,169 @@ func TestProvideServer(t *testing.T) {
 		jwtService := jwtMock.NewMockServiceInterface(t)
 
 		// Act
-		got := ProvideServer(&Config{}, jwtService)
+		got, err := ProvideServer(&Config{}, jwtService, lifecycle.NewManager(time.Second), metrics.New(&metrics.Config{}),
+			noop.NewTracerProvider(), &ratelimit.Config{}, ratelimit.NewMemoryStore())
 
 		// Assert
+		assert.NoError(t, err)
 		assert.NotNil(t, got)
 	})
+
+	t.Run("invalid Sentry DSN", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+
+		_, err := ProvideServer(&Config{SentryDSN: "not a dsn"}, jwtService, lifecycle.NewManager(time.Second), metrics.New(&metrics.Config{}),
+			noop.NewTracerProvider(), &ratelimit.Config{}, ratelimit.NewMemoryStore())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("shuts down the server", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+		lc := lifecycle.NewManager(time.Second)
+
+		server, err := ProvideServer(&Config{}, jwtService, lc, metrics.New(&metrics.Config{}),
+			noop.NewTracerProvider(), &ratelimit.Config{}, ratelimit.NewMemoryStore())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			assert.Eventually(t, func() bool { return server.ListenerAddr() != nil }, time.Second, 10*time.Millisecond)
+			cancel()
+		}()
+
+		err = lc.Run(ctx, func() error {
+			return server.Start("127.0.0.1:0")
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("traces the requests", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+		server, err := ProvideServer(&Config{ServiceName: "go-bloggy"}, jwtService, lifecycle.NewManager(time.Second),
+			metrics.New(&metrics.Config{}), tp, &ratelimit.Config{}, ratelimit.NewMemoryStore())
+		assert.NoError(t, err)
+		server.GET("/posts/:slug", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+		server.GET("/health/live", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/posts/first", nil)
+		req.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		server.ServeHTTP(httptest.NewRecorder(), req)
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1, "the probes are not traced")
+		assert.Equal(t, "/posts/:slug", spans[0].Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	})
+
+	t.Run("unknown operation of the rate limit", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+
+		_, err := ProvideServer(&Config{}, jwtService, lifecycle.NewManager(time.Second), metrics.New(&metrics.Config{}),
+			noop.NewTracerProvider(), &ratelimit.Config{Operations: map[string]ratelimit.Rate{"PostSubscriber": {}}},
+			ratelimit.NewMemoryStore())
+
+		assert.ErrorIs(t, err, ratelimit.ErrUnknownOperation)
+	})
+
+	t.Run("unknown operation of the cache control", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+
+		_, err := ProvideServer(&Config{CacheControl: map[string]string{"GetPost": "no-cache"}}, jwtService,
+			lifecycle.NewManager(time.Second), metrics.New(&metrics.Config{}), noop.NewTracerProvider(), &ratelimit.Config{},
+			ratelimit.NewMemoryStore())
+
+		assert.ErrorIs(t, err, middlewares.ErrUnknownOperation)
+	})
+
+	t.Run("invalid trusted proxy", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+
+		_, err := ProvideServer(&Config{TrustedProxies: []string{"proxy"}}, jwtService, lifecycle.NewManager(time.Second),
+			metrics.New(&metrics.Config{}), noop.NewTracerProvider(), &ratelimit.Config{}, ratelimit.NewMemoryStore())
+
+		assert.ErrorIs(t, err, ErrInvalidTrustedProxy)
+	})
+
+	t.Run("validates the requests", func(t *testing.T) {
+		jwtService := jwtMock.NewMockServiceInterface(t)
+
+		server, err := ProvideServer(&Config{}, jwtService, lifecycle.NewManager(time.Second),
+			metrics.New(&metrics.Config{}), noop.NewTracerProvider(), &ratelimit.Config{}, ratelimit.NewMemoryStore())
+		assert.NoError(t, err)
+		server.GET("/posts", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts?limit=100", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), middlewares.ErrRequestValidation)
+	})
+
+	t.Run("limits the requests by the client IP", func(t *testing.T) {
+		testCases := []struct {
+			name           string
+			trustedProxies []string
+			remoteAddr     string
+			forwardedFor   []string // forwardedFor is the X-Forwarded-For header of each request
+			want           []int
+		}{
+			{
+				name:         "direct peer",
+				remoteAddr:   "203.0.113.1:1234",
+				forwardedFor: []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+				want:         []int{http.StatusCreated, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			},
+			{
+				name:           "trusted proxy",
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "10.0.0.1:1234",
+				forwardedFor:   []string{"198.51.100.1", "198.51.100.1", "198.51.100.2"},
+				want:           []int{http.StatusCreated, http.StatusTooManyRequests, http.StatusCreated},
+			},
+			{
+				name:           "untrusted proxy",
+				trustedProxies: []string{"10.0.0.1"},
+				remoteAddr:     "10.0.0.2:1234",
+				forwardedFor:   []string{"198.51.100.1", "198.51.100.2"},
+				want:           []int{http.StatusCreated, http.StatusTooManyRequests},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				jwtService := jwtMock.NewMockServiceInterface(t)
+				rl := &ratelimit.Config{Operations: map[string]ratelimit.Rate{
+					"PostSubscribers": {Limit: 1, Period: time.Hour},
+				}}
+
+				server, err := ProvideServer(&Config{TrustedProxies: tc.trustedProxies}, jwtService,
+					lifecycle.NewManager(time.Second), metrics.New(&metrics.Config{}), noop.NewTracerProvider(),
+					rl, ratelimit.NewMemoryStore())
+				assert.NoError(t, err)
+				server.POST("/subscribers", func(ctx echo.Context) error { return ctx.NoContent(http.StatusCreated) })
+
+				for i, forwardedFor := range tc.forwardedFor {
+					body := `{"email": "john@example.com", "captcha": "token"}`
+					req := httptest.NewRequest(http.MethodPost, "/subscribers", strings.NewReader(body))
+					req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+					req.RemoteAddr = tc.remoteAddr
+					req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+					rec := httptest.NewRecorder()
+					server.ServeHTTP(rec, req)
+
+					assert.Equal(t, tc.want[i], rec.Code, "request %d", i)
+					if rec.Code == http.StatusTooManyRequests {
+						assert.Equal(t, "3600", rec.Header().Get("Retry-After"))
+					}
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,64 @@
+// Package signed encodes the values as the tokens signed with HMAC-SHA256, e.g. the state passed through the client,
+// so the backend doesn't have to store it and the client can't forge it.
+package signed
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidToken is returned if the token is malformed or its signature doesn't match.
+var ErrInvalidToken = errors.New("invalid signed token")
+
+// DeriveKey returns the key of the purpose derived from the secret as HMAC-SHA256(secret, purpose),
+// so the tokens signed for one purpose can't be accepted for another one sharing the same secret.
+func DeriveKey(secret, purpose string) string {
+	return string(sign([]byte(secret), purpose))
+}
+
+// Encode serializes the v to JSON and signs it with the key.
+// The token is URL safe: the base64url payload and the signature separated by a dot.
+func Encode(key []byte, v any) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sign(key, encoded)), nil
+}
+
+// Decode verifies the signature of the token of Encode and deserializes it to the v.
+func Decode(key []byte, token string, v any) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, sign(key, encoded)) {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	return nil
+}
+
+func sign(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,58 @@
+package signed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Signed(t *testing.T) {
+	type value struct {
+		Name string `json:"n"`
+	}
+	key := []byte("test-key")
+
+	t.Run("decode encoded value", func(t *testing.T) {
+		token, err := Encode(key, &value{Name: "test"})
+		assert.NoError(t, err)
+
+		var decoded value
+		assert.NoError(t, Decode(key, token, &decoded))
+		assert.Equal(t, value{Name: "test"}, decoded)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		token, err := Encode(key, &value{Name: "test"})
+		assert.NoError(t, err)
+		payload, signature, _ := strings.Cut(token, ".")
+		forged, err := Encode([]byte("another-key"), &value{Name: "admin"})
+		assert.NoError(t, err)
+		forgedPayload, _, _ := strings.Cut(forged, ".")
+
+		for _, s := range []string{
+			"",
+			payload,
+			payload + ".",
+			payload + ".not base64!",
+			forgedPayload + "." + signature,
+			forged,
+		} {
+			var decoded value
+			assert.ErrorIs(t, Decode(key, s, &decoded), ErrInvalidToken, s)
+		}
+	})
+
+	t.Run("derived keys of different purposes", func(t *testing.T) {
+		state, session := DeriveKey("secret", "state"), DeriveKey("secret", "session")
+		assert.Equal(t, state, DeriveKey("secret", "state"))
+		assert.NotEqual(t, state, session)
+		assert.NotEqual(t, state, DeriveKey("another-secret", "state"))
+
+		token, err := Encode([]byte(state), &value{Name: "test"})
+		assert.NoError(t, err)
+
+		var decoded value
+		assert.ErrorIs(t, Decode([]byte(session), token, &decoded), ErrInvalidToken)
+	})
+}
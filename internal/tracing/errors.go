@@ -0,0 +1,9 @@
+package tracing
+
+import "errors"
+
+var (
+	ErrUnknownExporter = errors.New("unknown tracing exporter")
+	ErrCreateExporter  = errors.New("error creating tracing exporter")
+	ErrCreateResource  = errors.New("error creating tracing resource")
+)
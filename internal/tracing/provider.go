@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	return &Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRate:  cfg.Tracing.SampleRate,
+		ServiceName: cfg.Tracing.ServiceName,
+	}
+}
+
+// ProvideTracerProvider is a Wire provider function that creates a TracerProvider, that is flushed on shutdown.
+// It's also set as the global one, so the libraries that only use the globals are traced as well.
+func ProvideTracerProvider(ctx context.Context, cfg *Config, lc *lifecycle.Manager) (trace.TracerProvider, error) {
+	tp, err := New(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(Propagator())
+	lc.OnStop(lifecycle.StageFlush, "tracing", tp.Shutdown)
+
+	return tp, nil
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the tracing package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideTracerProvider,
+)
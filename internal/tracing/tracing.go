@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Exporters of the Config.
+const (
+	ExporterNone   = "none"   // ExporterNone disables the tracing.
+	ExporterOTLP   = "otlp"   // ExporterOTLP sends the spans to the OpenTelemetry collector over OTLP/HTTP.
+	ExporterStdout = "stdout" // ExporterStdout prints the spans to stdout for local debugging.
+)
+
+// Config of the tracing.
+type Config struct {
+	Exporter    string
+	Endpoint    string // Endpoint is the URL of the OTLP collector, e.g. "http://localhost:4318".
+	SampleRate  float64
+	ServiceName string
+}
+
+// TracerProvider creates the tracers of the instrumented subsystems and flushes their spans on Shutdown.
+type TracerProvider interface {
+	trace.TracerProvider
+	Shutdown(ctx context.Context) error
+}
+
+// New creates the TracerProvider exporting the spans with the exporter from the Config.
+// The root spans are sampled with the SampleRate, the child spans follow the decision of their parent,
+// so the traces started by the upstream services are kept whole.
+func New(ctx context.Context, cfg *Config) (TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return noopProvider{noop.NewTracerProvider()}, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		// Note: without the endpoint, the exporter reads OTEL_EXPORTER_OTLP_ENDPOINT or uses localhost:4318
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateExporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateResource, err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+	), nil
+}
+
+// Propagator reads and writes the W3C trace context and baggage headers of the requests.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// HTTPClient returns the client for the outbound calls, that creates the client spans
+// and passes the trace context to the called service.
+func HTTPClient(tp trace.TracerProvider) *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithTracerProvider(tp),
+			otelhttp.WithPropagators(Propagator()),
+		),
+	}
+}
+
+// noopProvider is the TracerProvider of the disabled tracing.
+type noopProvider struct {
+	noop.TracerProvider
+}
+
+func (noopProvider) Shutdown(_ context.Context) error { return nil }
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"github.com/samgozman/go-bloggy/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		tp, err := New(context.Background(), &Config{Exporter: ExporterNone})
+		assert.NoError(t, err)
+
+		_, span := tp.Tracer("test").Start(context.Background(), "test")
+		assert.False(t, span.SpanContext().IsValid(), "spans are not recorded")
+		assert.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	t.Run("otlp", func(t *testing.T) {
+		var requests int
+		collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "/v1/traces", req.URL.Path)
+			requests++
+			rw.WriteHeader(http.StatusOK)
+		}))
+		defer collector.Close()
+
+		tp, err := New(context.Background(), &Config{
+			Exporter:    ExporterOTLP,
+			Endpoint:    collector.URL + "/v1/traces",
+			SampleRate:  1,
+			ServiceName: "go-bloggy",
+		})
+		assert.NoError(t, err)
+		assert.IsType(t, &sdktrace.TracerProvider{}, tp)
+
+		_, span := tp.Tracer("test").Start(context.Background(), "test")
+		span.End()
+
+		// Note: the spans are batched until the shutdown
+		assert.NoError(t, tp.Shutdown(context.Background()))
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("not sampled", func(t *testing.T) {
+		tp, err := New(context.Background(), &Config{Exporter: ExporterStdout, SampleRate: 0, ServiceName: "go-bloggy"})
+		assert.NoError(t, err)
+
+		_, span := tp.Tracer("test").Start(context.Background(), "test")
+		assert.False(t, span.SpanContext().IsSampled())
+		assert.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := New(context.Background(), &Config{Exporter: "jaeger"})
+		assert.ErrorIs(t, err, ErrUnknownExporter)
+	})
+}
+
+func TestProvideTracerProvider(t *testing.T) {
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	lc := lifecycle.NewManager(time.Second)
+	tp, err := ProvideTracerProvider(context.Background(), &Config{Exporter: ExporterStdout, SampleRate: 1}, lc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, tp, otel.GetTracerProvider())
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+	assert.NoError(t, lc.Shutdown(context.Background()))
+}
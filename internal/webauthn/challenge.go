@@ -0,0 +1,51 @@
+package webauthn
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samgozman/go-bloggy/internal/db/models"
+)
+
+// ChallengeStore keeps the challenges of the finished ceremonies until they expire,
+// so the same session and authenticator response can't be used twice.
+type ChallengeStore interface {
+	// Use marks the challenge as used until the expiresAt.
+	// It returns models.ErrDuplicate if the challenge was already used.
+	Use(ctx context.Context, challenge string, expiresAt time.Time) error
+}
+
+// MemoryChallengeStore keeps the used challenges in memory, so they are not shared between the replicas.
+type MemoryChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]time.Time
+}
+
+// NewMemoryChallengeStore creates a new MemoryChallengeStore.
+func NewMemoryChallengeStore() *MemoryChallengeStore {
+	return &MemoryChallengeStore{
+		challenges: make(map[string]time.Time),
+	}
+}
+
+// Use marks the challenge as used, see ChallengeStore.
+func (s *MemoryChallengeStore) Use(_ context.Context, challenge string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for c, e := range s.challenges {
+		if !e.After(now) {
+			delete(s.challenges, c)
+		}
+	}
+
+	if _, ok := s.challenges[challenge]; ok {
+		return fmt.Errorf("%w: challenge was already used", models.ErrDuplicate)
+	}
+
+	s.challenges[challenge] = expiresAt
+	return nil
+}
@@ -0,0 +1,12 @@
+package webauthn
+
+import "errors"
+
+var (
+	ErrInvalidConfig   = errors.New("invalid WebAuthn config")
+	ErrInvalidSession  = errors.New("invalid or expired WebAuthn session")
+	ErrBeginCeremony   = errors.New("error starting WebAuthn ceremony")
+	ErrInvalidResponse = errors.New("invalid authenticator response")
+	ErrCloneWarning    = errors.New("authenticator sign counter is invalid, it could be cloned")
+	ErrUseChallenge    = errors.New("error storing WebAuthn challenge")
+)
@@ -0,0 +1,44 @@
+package webauthn
+
+import (
+	"github.com/google/wire"
+	"github.com/samgozman/go-bloggy/internal/config"
+	"github.com/samgozman/go-bloggy/internal/db"
+	"github.com/samgozman/go-bloggy/internal/signed"
+)
+
+// Config is a struct that holds the configuration for the WebAuthn service.
+type Config struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+	SessionKey    string
+}
+
+// ProvideConfig is a Wire provider function that creates a Config.
+func ProvideConfig(cfg *config.Config) *Config {
+	return &Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		SessionKey:    signed.DeriveKey(string(cfg.JWTSecretKey), "webauthn-session"),
+	}
+}
+
+// ProvideChallengeStore is a Wire provider function that creates the ChallengeStore shared between the replicas.
+func ProvideChallengeStore(models db.ModelsInterface) ChallengeStore {
+	return models.WebAuthnChallenges()
+}
+
+// ProvideService is a Wire provider function that creates a Service.
+func ProvideService(cfg *Config, challenges ChallengeStore) (*Service, error) {
+	return NewService(cfg.RPID, cfg.RPDisplayName, cfg.RPOrigins, cfg.SessionKey, challenges)
+}
+
+// ProviderSet is a Wire provider set that includes all the providers from the webauthn package.
+var ProviderSet = wire.NewSet( //nolint:gochecknoglobals // required by Wire
+	ProvideConfig,
+	ProvideChallengeStore,
+	ProvideService,
+	wire.Bind(new(ServiceInterface), new(*Service)),
+)
@@ -0,0 +1,47 @@
+package webauthn
+
+import (
+	"fmt"
+
+	gowebauthn "github.com/go-webauthn/webauthn/webauthn"
+	"github.com/samgozman/go-bloggy/internal/signed"
+)
+
+// ceremony kind is a part of the signed session, so the registration session can't be used for the login.
+const (
+	registrationCeremony = "registration"
+	loginCeremony        = "login"
+)
+
+// session is passed to the client between the begin and finish requests,
+// so the backend doesn't have to store pending ceremonies. It is signed with HMAC, so it can't be forged.
+// Its challenge is stored by the ChallengeStore once the ceremony is finished, so it can't be replayed.
+type session struct {
+	Kind string                 `json:"k"`
+	Data gowebauthn.SessionData `json:"d"`
+}
+
+// encodeSession serializes and signs the session with the given key.
+func encodeSession(key []byte, s *session) (string, error) {
+	encoded, err := signed.Encode(key, s)
+	if err != nil {
+		return "", fmt.Errorf("error encoding session: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// decodeSession verifies the signature and the kind of the session and returns its data.
+// Expiration of the session is checked by the WebAuthn library.
+func decodeSession(key []byte, raw, kind string) (*gowebauthn.SessionData, error) {
+	var s session
+	if err := signed.Decode(key, raw, &s); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSession, err)
+	}
+
+	if s.Kind != kind {
+		return nil, ErrInvalidSession
+	}
+
+	return &s.Data, nil
+}
@@ -0,0 +1,293 @@
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	gowebauthn "github.com/go-webauthn/webauthn/webauthn"
+	"github.com/samgozman/go-bloggy/internal/db/models"
+)
+
+// ceremonyTimeout is how long the user has to complete the registration or the login with the authenticator.
+const ceremonyTimeout = 5 * time.Minute
+
+// Ceremony is the data needed by the client to call the authenticator.
+type Ceremony struct {
+	Options json.RawMessage // Options for navigator.credentials.create() or navigator.credentials.get()
+	Session string          // Session is signed and has to be sent back with the authenticator response
+}
+
+// Service for registration and assertion of the WebAuthn credentials (passkeys).
+type Service struct {
+	webAuthn   *gowebauthn.WebAuthn
+	sessionKey []byte         // sessionKey is the key used to sign the session.
+	challenges ChallengeStore // challenges are the used challenges, so the sessions are single-use.
+}
+
+// NewService creates a new WebAuthn Service for the relying party (the blog admin panel).
+// sessionKey is used to sign the session passed through the client.
+func NewService(
+	rpID, rpDisplayName string,
+	rpOrigins []string,
+	sessionKey string,
+	challenges ChallengeStore,
+) (*Service, error) {
+	timeout := gowebauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    ceremonyTimeout,
+		TimeoutUVD: ceremonyTimeout,
+	}
+
+	w, err := gowebauthn.New(&gowebauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: rpDisplayName,
+		RPOrigins:     rpOrigins,
+		Timeouts: gowebauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	return &Service{
+		webAuthn:   w,
+		sessionKey: []byte(sessionKey),
+		challenges: challenges,
+	}, nil
+}
+
+type ServiceInterface interface {
+	BeginRegistration(user *models.User, credentials []*models.WebAuthnCredential) (*Ceremony, error)
+	FinishRegistration(
+		ctx context.Context,
+		user *models.User,
+		credentials []*models.WebAuthnCredential,
+		session string,
+		response []byte,
+	) (*models.WebAuthnCredential, error)
+	BeginLogin(user *models.User, credentials []*models.WebAuthnCredential) (*Ceremony, error)
+	FinishLogin(
+		ctx context.Context,
+		user *models.User,
+		credentials []*models.WebAuthnCredential,
+		session string,
+		response []byte,
+	) (*models.WebAuthnCredential, error)
+}
+
+// BeginRegistration starts the registration of a new credential for the user.
+// Already registered credentials are excluded, so the same authenticator can't be registered twice.
+func (s *Service) BeginRegistration(user *models.User, credentials []*models.WebAuthnCredential) (*Ceremony, error) {
+	u := newUser(user, credentials)
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	creation, data, err := s.webAuthn.BeginRegistration(u, gowebauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBeginCeremony, err)
+	}
+
+	return s.newCeremony(creation, registrationCeremony, data)
+}
+
+// FinishRegistration verifies the authenticator attestation and returns the new credential to be stored.
+// The session can be finished only once.
+func (s *Service) FinishRegistration(
+	ctx context.Context,
+	user *models.User,
+	credentials []*models.WebAuthnCredential,
+	session string,
+	response []byte,
+) (*models.WebAuthnCredential, error) {
+	data, err := decodeSession(s.sessionKey, session, registrationCeremony)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	credential, err := s.webAuthn.CreateCredential(newUser(user, credentials), *data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	if err := s.useChallenge(ctx, data); err != nil {
+		return nil, err
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+
+	return &models.WebAuthnCredential{
+		UserID:          user.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}, nil
+}
+
+// BeginLogin starts the assertion with one of the user credentials.
+func (s *Service) BeginLogin(user *models.User, credentials []*models.WebAuthnCredential) (*Ceremony, error) {
+	assertion, data, err := s.webAuthn.BeginLogin(newUser(user, credentials))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBeginCeremony, err)
+	}
+
+	return s.newCeremony(assertion, loginCeremony, data)
+}
+
+// FinishLogin verifies the authenticator assertion and returns the used credential
+// with the updated sign counter, that has to be stored. The session can be finished only once.
+func (s *Service) FinishLogin(
+	ctx context.Context,
+	user *models.User,
+	credentials []*models.WebAuthnCredential,
+	session string,
+	response []byte,
+) (*models.WebAuthnCredential, error) {
+	data, err := decodeSession(s.sessionKey, session, loginCeremony)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	credential, err := s.webAuthn.ValidateLogin(newUser(user, credentials), *data, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		return nil, ErrCloneWarning
+	}
+
+	if err := s.useChallenge(ctx, data); err != nil {
+		return nil, err
+	}
+
+	for _, c := range credentials {
+		if bytes.Equal(c.CredentialID, credential.ID) {
+			now := time.Now()
+			c.SignCount = credential.Authenticator.SignCount
+			c.BackupState = credential.Flags.BackupState
+			c.LastUsedAt = &now
+			return c, nil
+		}
+	}
+
+	// Note: should never happen, because the library checks that the credential belongs to the user
+	return nil, ErrInvalidResponse
+}
+
+// useChallenge marks the challenge of the verified session as used.
+// Note: it is called after the verification, so the invalid response doesn't burn the session,
+// and the store rejects the concurrent replays of the same session.
+func (s *Service) useChallenge(ctx context.Context, data *gowebauthn.SessionData) error {
+	err := s.challenges.Use(ctx, data.Challenge, data.Expires)
+	if errors.Is(err, models.ErrDuplicate) {
+		return fmt.Errorf("%w: %w", ErrInvalidSession, err)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUseChallenge, err)
+	}
+
+	return nil
+}
+
+func (s *Service) newCeremony(options any, kind string, data *gowebauthn.SessionData) (*Ceremony, error) {
+	rawOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBeginCeremony, err)
+	}
+
+	encoded, err := encodeSession(s.sessionKey, &session{Kind: kind, Data: *data})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBeginCeremony, err)
+	}
+
+	return &Ceremony{
+		Options: rawOptions,
+		Session: encoded,
+	}, nil
+}
+
+// user adapts models.User with its credentials to the gowebauthn.User interface.
+type user struct {
+	user        *models.User
+	credentials []gowebauthn.Credential
+}
+
+func newUser(u *models.User, credentials []*models.WebAuthnCredential) *user {
+	converted := make([]gowebauthn.Credential, 0, len(credentials))
+	for _, c := range credentials {
+		var transports []protocol.AuthenticatorTransport
+		if c.Transports != "" {
+			for _, t := range strings.Split(c.Transports, ",") {
+				transports = append(transports, protocol.AuthenticatorTransport(t))
+			}
+		}
+
+		converted = append(converted, gowebauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Flags: gowebauthn.CredentialFlags{
+				BackupEligible: c.BackupEligible,
+				BackupState:    c.BackupState,
+			},
+			Authenticator: gowebauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		})
+	}
+
+	return &user{user: u, credentials: converted}
+}
+
+// WebAuthnID is the user handle. Internal ID is used, because the external ID could be an email.
+func (u *user) WebAuthnID() []byte {
+	return []byte(strconv.Itoa(u.user.ID))
+}
+
+func (u *user) WebAuthnName() string {
+	return u.user.Login
+}
+
+func (u *user) WebAuthnDisplayName() string {
+	return u.user.Login
+}
+
+func (u *user) WebAuthnCredentials() []gowebauthn.Credential {
+	return u.credentials
+}
+
+func (u *user) WebAuthnIcon() string {
+	return ""
+}
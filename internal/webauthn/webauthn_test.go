@@ -0,0 +1,194 @@
+package webauthn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samgozman/go-bloggy/internal/db/models"
+	testwebauthn "github.com/samgozman/go-bloggy/testutils/test-webauthn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	s, err := NewService("localhost", "go-bloggy", []string{"http://localhost"}, "session-secret", NewMemoryChallengeStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &models.User{ID: 1, Login: "admin"}
+
+	// register creates a new credential with the software authenticator
+	register := func(t *testing.T) (*testwebauthn.Authenticator, *models.WebAuthnCredential) {
+		t.Helper()
+
+		authenticator, err := testwebauthn.NewAuthenticator("http://localhost")
+		assert.NoError(t, err)
+
+		ceremony, err := s.BeginRegistration(user, nil)
+		assert.NoError(t, err)
+
+		response, err := authenticator.Create(ceremony.Options)
+		assert.NoError(t, err)
+
+		credential, err := s.FinishRegistration(context.Background(), user, nil, ceremony.Session, response)
+		assert.NoError(t, err)
+
+		return authenticator, credential
+	}
+
+	t.Run("Registration", func(t *testing.T) {
+		t.Run("OK", func(t *testing.T) {
+			authenticator, credential := register(t)
+
+			assert.Equal(t, user.ID, credential.UserID)
+			assert.Equal(t, authenticator.CredentialID, credential.CredentialID)
+			assert.NotEmpty(t, credential.PublicKey)
+			assert.Equal(t, "none", credential.AttestationType)
+		})
+
+		t.Run("should exclude registered credentials", func(t *testing.T) {
+			_, credential := register(t)
+
+			ceremony, err := s.BeginRegistration(user, []*models.WebAuthnCredential{credential})
+			assert.NoError(t, err)
+			assert.Contains(t, string(ceremony.Options), "excludeCredentials")
+		})
+
+		t.Run("should return error for another origin", func(t *testing.T) {
+			authenticator, err := testwebauthn.NewAuthenticator("https://evil.example.com")
+			assert.NoError(t, err)
+
+			ceremony, err := s.BeginRegistration(user, nil)
+			assert.NoError(t, err)
+			response, err := authenticator.Create(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishRegistration(context.Background(), user, nil, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidResponse)
+		})
+
+		t.Run("should return error for tampered session", func(t *testing.T) {
+			authenticator, err := testwebauthn.NewAuthenticator("http://localhost")
+			assert.NoError(t, err)
+
+			ceremony, err := s.BeginRegistration(user, nil)
+			assert.NoError(t, err)
+			response, err := authenticator.Create(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishRegistration(context.Background(), user, nil, ceremony.Session+"x", response)
+			assert.ErrorIs(t, err, ErrInvalidSession)
+		})
+
+		t.Run("should return error for replayed session", func(t *testing.T) {
+			authenticator, err := testwebauthn.NewAuthenticator("http://localhost")
+			assert.NoError(t, err)
+
+			ceremony, err := s.BeginRegistration(user, nil)
+			assert.NoError(t, err)
+			response, err := authenticator.Create(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishRegistration(context.Background(), user, nil, ceremony.Session, response)
+			assert.NoError(t, err)
+
+			_, err = s.FinishRegistration(context.Background(), user, nil, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidSession)
+		})
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		t.Run("OK", func(t *testing.T) {
+			authenticator, credential := register(t)
+			credentials := []*models.WebAuthnCredential{credential}
+
+			ceremony, err := s.BeginLogin(user, credentials)
+			assert.NoError(t, err)
+
+			response, err := authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+
+			used, err := s.FinishLogin(context.Background(), user, credentials, ceremony.Session, response)
+			assert.NoError(t, err)
+			assert.Equal(t, credential, used)
+			assert.Equal(t, uint32(1), used.SignCount)
+			assert.NotNil(t, used.LastUsedAt)
+		})
+
+		t.Run("should return error for registration session", func(t *testing.T) {
+			authenticator, credential := register(t)
+			credentials := []*models.WebAuthnCredential{credential}
+
+			registration, err := s.BeginRegistration(user, credentials)
+			assert.NoError(t, err)
+			ceremony, err := s.BeginLogin(user, credentials)
+			assert.NoError(t, err)
+
+			response, err := authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishLogin(context.Background(), user, credentials, registration.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidSession)
+		})
+
+		t.Run("should return error for another user", func(t *testing.T) {
+			authenticator, credential := register(t)
+			credentials := []*models.WebAuthnCredential{credential}
+
+			ceremony, err := s.BeginLogin(user, credentials)
+			assert.NoError(t, err)
+			response, err := authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishLogin(context.Background(), &models.User{ID: 2}, credentials, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidResponse)
+		})
+
+		t.Run("should return error for unknown credential", func(t *testing.T) {
+			_, credential := register(t)
+			another, _ := register(t)
+
+			ceremony, err := s.BeginLogin(user, []*models.WebAuthnCredential{credential})
+			assert.NoError(t, err)
+			response, err := another.Get(ceremony.Options)
+			assert.NoError(t, err)
+
+			credentials := []*models.WebAuthnCredential{credential}
+			_, err = s.FinishLogin(context.Background(), user, credentials, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidResponse)
+		})
+
+		t.Run("should return error for replayed session", func(t *testing.T) {
+			authenticator, credential := register(t)
+			credentials := []*models.WebAuthnCredential{credential}
+
+			ceremony, err := s.BeginLogin(user, credentials)
+			assert.NoError(t, err)
+
+			response, err := authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+			_, err = s.FinishLogin(context.Background(), user, credentials, ceremony.Session, response)
+			assert.NoError(t, err)
+
+			// Note: the new assertion for the same challenge is valid for the authenticator, but not for the session
+			response, err = authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+			_, err = s.FinishLogin(context.Background(), user, credentials, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrInvalidSession)
+		})
+
+		t.Run("should return error for cloned authenticator", func(t *testing.T) {
+			authenticator, credential := register(t)
+			credential.SignCount = 10
+			credentials := []*models.WebAuthnCredential{credential}
+
+			ceremony, err := s.BeginLogin(user, credentials)
+			assert.NoError(t, err)
+			response, err := authenticator.Get(ceremony.Options)
+			assert.NoError(t, err)
+
+			_, err = s.FinishLogin(context.Background(), user, credentials, ceremony.Session, response)
+			assert.ErrorIs(t, err, ErrCloneWarning)
+		})
+	})
+}
@@ -7,6 +7,8 @@ import (
 
 	models "github.com/samgozman/go-bloggy/internal/db/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockPostRepositoryInterface is an autogenerated mock type for the PostRepositoryInterface type
@@ -14,6 +16,36 @@ type MockPostRepositoryInterface struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx
+func (_m *MockPostRepositoryInterface) Archive(ctx context.Context) ([]*models.ArchiveMonth, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Archive")
+	}
+
+	var r0 []*models.ArchiveMonth
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.ArchiveMonth, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.ArchiveMonth); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ArchiveMonth)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Count provides a mock function with given fields: ctx
 func (_m *MockPostRepositoryInterface) Count(ctx context.Context) (int64, error) {
 	ret := _m.Called(ctx)
@@ -90,6 +122,36 @@ func (_m *MockPostRepositoryInterface) FindAll(ctx context.Context, page int, pe
 	return r0, r1
 }
 
+// FindPage provides a mock function with given fields: ctx, q
+func (_m *MockPostRepositoryInterface) FindPage(ctx context.Context, q *models.PostQuery) (*models.PostPage, error) {
+	ret := _m.Called(ctx, q)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPage")
+	}
+
+	var r0 *models.PostPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PostQuery) (*models.PostPage, error)); ok {
+		return rf(ctx, q)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PostQuery) *models.PostPage); ok {
+		r0 = rf(ctx, q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PostPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PostQuery) error); ok {
+		r1 = rf(ctx, q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBySlug provides a mock function with given fields: ctx, slug
 func (_m *MockPostRepositoryInterface) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
 	ret := _m.Called(ctx, slug)
@@ -120,6 +182,64 @@ func (_m *MockPostRepositoryInterface) GetBySlug(ctx context.Context, slug strin
 	return r0, r1
 }
 
+// LastUpdatedAt provides a mock function with given fields: ctx
+func (_m *MockPostRepositoryInterface) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastUpdatedAt")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (time.Time, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) time.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Related provides a mock function with given fields: ctx, slug, limit
+func (_m *MockPostRepositoryInterface) Related(ctx context.Context, slug string, limit int) ([]*models.Post, error) {
+	ret := _m.Called(ctx, slug, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Related")
+	}
+
+	var r0 []*models.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*models.Post, error)); ok {
+		return rf(ctx, slug, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*models.Post); ok {
+		r0 = rf(ctx, slug, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, slug, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, p
 func (_m *MockPostRepositoryInterface) Update(ctx context.Context, p *models.Post) error {
 	ret := _m.Called(ctx, p)
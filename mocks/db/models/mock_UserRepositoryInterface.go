@@ -14,6 +14,54 @@ type MockUserRepositoryInterface struct {
 	mock.Mock
 }
 
+// Create provides a mock function with given fields: ctx, user
+func (_m *MockUserRepositoryInterface) Create(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields: ctx
+func (_m *MockUserRepositoryInterface) FindAll(ctx context.Context) ([]*models.User, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []*models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.User, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByExternalID provides a mock function with given fields: ctx, externalID
 func (_m *MockUserRepositoryInterface) GetByExternalID(ctx context.Context, externalID string) (*models.User, error) {
 	ret := _m.Called(ctx, externalID)
@@ -74,6 +122,24 @@ func (_m *MockUserRepositoryInterface) GetByID(ctx context.Context, id int) (*mo
 	return r0, r1
 }
 
+// Update provides a mock function with given fields: ctx, user
+func (_m *MockUserRepositoryInterface) Update(ctx context.Context, user *models.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Upsert provides a mock function with given fields: ctx, user
 func (_m *MockUserRepositoryInterface) Upsert(ctx context.Context, user *models.User) error {
 	ret := _m.Called(ctx, user)
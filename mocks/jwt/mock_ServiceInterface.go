@@ -13,6 +13,34 @@ type MockServiceInterface struct {
 	mock.Mock
 }
 
+// CreateScopedTokenString provides a mock function with given fields: scope, subject, expiresAt
+func (_m *MockServiceInterface) CreateScopedTokenString(scope string, subject string, expiresAt time.Time) (string, error) {
+	ret := _m.Called(scope, subject, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScopedTokenString")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) (string, error)); ok {
+		return rf(scope, subject, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) string); ok {
+		r0 = rf(scope, subject, expiresAt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(scope, subject, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTokenString provides a mock function with given fields: userID, expiresAt
 func (_m *MockServiceInterface) CreateTokenString(userID string, expiresAt time.Time) (string, error) {
 	ret := _m.Called(userID, expiresAt)
@@ -41,6 +69,34 @@ func (_m *MockServiceInterface) CreateTokenString(userID string, expiresAt time.
 	return r0, r1
 }
 
+// ParseScopedTokenString provides a mock function with given fields: scope, tokenString
+func (_m *MockServiceInterface) ParseScopedTokenString(scope string, tokenString string) (string, error) {
+	ret := _m.Called(scope, tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseScopedTokenString")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(scope, tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(scope, tokenString)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(scope, tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ParseTokenString provides a mock function with given fields: tokenString
 func (_m *MockServiceInterface) ParseTokenString(tokenString string) (string, error) {
 	ret := _m.Called(tokenString)
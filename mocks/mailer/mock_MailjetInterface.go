@@ -12,6 +12,48 @@ type MockMailjetInterface struct {
 	mock.Mock
 }
 
+// List provides a mock function with given fields: resource, resp, options
+func (_m *MockMailjetInterface) List(resource string, resp interface{}, options ...mailjet.RequestOptions) (int, int, error) {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, resource, resp)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, interface{}, ...mailjet.RequestOptions) (int, int, error)); ok {
+		return rf(resource, resp, options...)
+	}
+	if rf, ok := ret.Get(0).(func(string, interface{}, ...mailjet.RequestOptions) int); ok {
+		r0 = rf(resource, resp, options...)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, interface{}, ...mailjet.RequestOptions) int); ok {
+		r1 = rf(resource, resp, options...)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, interface{}, ...mailjet.RequestOptions) error); ok {
+		r2 = rf(resource, resp, options...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SendMailV31 provides a mock function with given fields: data, options
 func (_m *MockMailjetInterface) SendMailV31(data *mailjet.MessagesV31, options ...mailjet.RequestOptions) (*mailjet.ResultsV31, error) {
 	_va := make([]interface{}, len(options))
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	types "github.com/samgozman/go-bloggy/internal/mailer/types"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,17 +14,35 @@ type MockServiceInterface struct {
 	mock.Mock
 }
 
-// SendConfirmationEmail provides a mock function with given fields: to, confirmationID
-func (_m *MockServiceInterface) SendConfirmationEmail(to string, confirmationID string) error {
-	ret := _m.Called(to, confirmationID)
+// SendConfirmationEmail provides a mock function with given fields: ctx, to, confirmationID
+func (_m *MockServiceInterface) SendConfirmationEmail(ctx context.Context, to string, confirmationID string) error {
+	ret := _m.Called(ctx, to, confirmationID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendConfirmationEmail")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(to, confirmationID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, to, confirmationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendMagicLinkEmail provides a mock function with given fields: ctx, to, token
+func (_m *MockServiceInterface) SendMagicLinkEmail(ctx context.Context, to string, token string) error {
+	ret := _m.Called(ctx, to, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMagicLinkEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, to, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,17 +50,17 @@ func (_m *MockServiceInterface) SendConfirmationEmail(to string, confirmationID
 	return r0
 }
 
-// SendPostEmail provides a mock function with given fields: pe
-func (_m *MockServiceInterface) SendPostEmail(pe *types.PostEmailSend) error {
-	ret := _m.Called(pe)
+// SendPostEmail provides a mock function with given fields: ctx, pe
+func (_m *MockServiceInterface) SendPostEmail(ctx context.Context, pe *types.PostEmailSend) error {
+	ret := _m.Called(ctx, pe)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendPostEmail")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*types.PostEmailSend) error); ok {
-		r0 = rf(pe)
+	if rf, ok := ret.Get(0).(func(context.Context, *types.PostEmailSend) error); ok {
+		r0 = rf(ctx, pe)
 	} else {
 		r0 = ret.Error(0)
 	}
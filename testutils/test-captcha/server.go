@@ -0,0 +1,157 @@
+package testcaptcha
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Hostname reported for the solved tokens.
+const Hostname = "localhost"
+
+// Providers of the siteverify API, the same as captcha.Provider* names.
+const (
+	HCaptcha  = "hcaptcha"
+	Turnstile = "turnstile"
+	ReCaptcha = "recaptcha"
+)
+
+// Server is an in-process fake siteverify API of hCaptcha, Cloudflare Turnstile or Google reCAPTCHA v3.
+// Tokens are issued with Solve and, like in the real providers, can be verified only once.
+type Server struct {
+	server   *httptest.Server
+	provider string
+	secret   string
+
+	mu     sync.Mutex
+	tokens map[string]*solution
+}
+
+// solution is the token of the solved captcha, waiting to be verified.
+type solution struct {
+	action   string
+	score    float64
+	remoteIP string
+	solvedAt time.Time
+}
+
+// NewServer starts a new fake siteverify API of the provider (e.g. Turnstile) for the secret key.
+// The server must be closed with Close after the test.
+func NewServer(provider, secret string) *Server {
+	s := &Server{
+		provider: provider,
+		secret:   secret,
+		tokens:   make(map[string]*solution),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /siteverify", s.handleSiteVerify)
+	s.server = httptest.NewServer(mux)
+
+	return s
+}
+
+// URL of the siteverify endpoint.
+func (s *Server) URL() string {
+	return s.server.URL + "/siteverify"
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Solve simulates the user solving the captcha in the browser and returns the token.
+// The score and the action are only reported for reCAPTCHA v3 and Turnstile tokens.
+// If remoteIP is not empty, it must match the IP sent for verification.
+func (s *Server) Solve(action string, score float64, remoteIP string) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = &solution{
+		action:   action,
+		score:    score,
+		remoteIP: remoteIP,
+		solvedAt: time.Now(),
+	}
+
+	return token
+}
+
+func (s *Server) handleSiteVerify(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "error-codes": []string{"bad-request"}})
+		return
+	}
+
+	switch {
+	case r.PostForm.Get("secret") == "":
+		writeFailure(w, "missing-input-secret")
+		return
+	case r.PostForm.Get("secret") != s.secret:
+		writeFailure(w, "invalid-input-secret")
+		return
+	case r.PostForm.Get("response") == "":
+		writeFailure(w, "missing-input-response")
+		return
+	}
+
+	// Tokens are single use
+	token := r.PostForm.Get("response")
+	s.mu.Lock()
+	sol, ok := s.tokens[token]
+	delete(s.tokens, token)
+	s.mu.Unlock()
+
+	if !ok {
+		writeFailure(w, "invalid-input-response")
+		return
+	}
+
+	if sol.remoteIP != "" && sol.remoteIP != r.PostForm.Get("remoteip") {
+		writeFailure(w, "invalid-input-response")
+		return
+	}
+
+	body := map[string]any{
+		"success":      true,
+		"challenge_ts": sol.solvedAt.UTC().Format(time.RFC3339),
+		"hostname":     Hostname,
+		"error-codes":  []string{},
+	}
+
+	// Note: only the fields documented by the provider are returned
+	switch s.provider {
+	case ReCaptcha:
+		body["score"] = sol.score
+		body["action"] = sol.action
+	case Turnstile:
+		body["action"] = sol.action
+		body["cdata"] = ""
+	}
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+func writeFailure(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":     false,
+		"error-codes": []string{code},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Printf("error encoding response: %v\n", err)
+	}
+}
@@ -1,6 +1,7 @@
 package testmodels
 
 import (
+	"context"
 	"fmt"
 	"github.com/samgozman/go-bloggy/internal/db"
 	"github.com/samgozman/go-bloggy/internal/db/models"
@@ -13,9 +14,15 @@ func InitDatabaseWithModelsTest() (*db.Database, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error init test db: %w", err)
 	}
+	// Note: the same as the production connection, so the saved timestamps are the same as the stored ones
+	gormDB.Config.NowFunc = models.Now
 
-	err = gormDB.AutoMigrate(&models.User{}, &models.Post{}, &models.Subscriber{})
+	migrator, err := db.NewMigrator(gormDB)
 	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
@@ -23,6 +30,11 @@ func InitDatabaseWithModelsTest() (*db.Database, error) {
 		models.NewUserRepository(gormDB),
 		models.NewPostRepository(gormDB),
 		models.NewSubscribersRepository(gormDB),
+		models.NewMagicLinkRepository(gormDB),
+		models.NewWebAuthnCredentialRepository(gormDB),
+		models.NewRecoveryCodeRepository(gormDB),
+		models.NewRateLimitRepository(gormDB),
+		models.NewWebAuthnChallengeRepository(gormDB),
 	)
 
 	return db.NewDatabase(gormDB, m), nil
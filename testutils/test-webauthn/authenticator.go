@@ -0,0 +1,184 @@
+package testwebauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+// Authenticator flags, see https://www.w3.org/TR/webauthn-2/#flags
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+)
+
+// Authenticator is a software WebAuthn authenticator with a single ES256 credential for tests.
+// It implements the client (browser) part as well: it builds client data for the origin
+// and returns JSON responses in the same format as the browser would send them to the server.
+type Authenticator struct {
+	Origin       string
+	CredentialID []byte
+	SignCount    uint32
+
+	key *ecdsa.PrivateKey
+}
+
+// NewAuthenticator creates a new Authenticator for the given origin, e.g. "http://localhost".
+func NewAuthenticator(origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating key: %w", err)
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, fmt.Errorf("error generating credential ID: %w", err)
+	}
+
+	return &Authenticator{
+		Origin:       origin,
+		CredentialID: credentialID,
+		key:          key,
+	}, nil
+}
+
+// options is the part of the creation and request options used by the authenticator.
+type options struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+	} `json:"publicKey"`
+}
+
+// Create returns the attestation response ("none" format) for the creation options,
+// as navigator.credentials.create() would do.
+func (a *Authenticator) Create(creationOptions []byte) ([]byte, error) {
+	var opts options
+	if err := json.Unmarshal(creationOptions, &opts); err != nil {
+		return nil, fmt.Errorf("error parsing options: %w", err)
+	}
+
+	clientData, err := a.clientData("webauthn.create", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding public key: %w", err)
+	}
+
+	// Attested credential data: AAGUID (zeroes), credential ID length, credential ID and COSE public key
+	attestedData := make([]byte, 16, 16+2+len(a.CredentialID)+len(publicKey))
+	attestedData = binary.BigEndian.AppendUint16(attestedData, uint16(len(a.CredentialID))) //nolint:gosec
+	attestedData = append(attestedData, a.CredentialID...)
+	attestedData = append(attestedData, publicKey...)
+
+	authData := a.authData(opts.PublicKey.RP.ID, flagUserPresent|flagUserVerified|flagAttestedCredentialData)
+	authData = append(authData, attestedData...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding attestation object: %w", err)
+	}
+
+	return a.response(map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"attestationObject": encode(attestationObject),
+	})
+}
+
+// Get returns the assertion response for the request options, as navigator.credentials.get() would do.
+func (a *Authenticator) Get(requestOptions []byte) ([]byte, error) {
+	var opts options
+	if err := json.Unmarshal(requestOptions, &opts); err != nil {
+		return nil, fmt.Errorf("error parsing options: %w", err)
+	}
+
+	clientData, err := a.clientData("webauthn.get", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.SignCount++
+	authData := a.authData(opts.PublicKey.RPID, flagUserPresent|flagUserVerified)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("error signing assertion: %w", err)
+	}
+
+	return a.response(map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+	})
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	if challenge == "" {
+		return nil, errors.New("challenge is required")
+	}
+
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding client data: %w", err)
+	}
+
+	return data, nil
+}
+
+// authData returns the authenticator data: RP ID hash, flags and sign counter.
+func (a *Authenticator) authData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func (a *Authenticator) response(response map[string]string) ([]byte, error) {
+	data, err := json.Marshal(map[string]any{
+		"id":       encode(a.CredentialID),
+		"rawId":    encode(a.CredentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding response: %w", err)
+	}
+
+	return data, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}